@@ -19,6 +19,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
@@ -53,6 +55,9 @@ type WatchAllInstancesRequest api.WatchAllInstancesRequest
 // WatchAllServicesRequest is the request to watch services
 type WatchAllServicesRequest api.WatchAllServicesRequest
 
+// ForceCircuitBreakerRequest is the request to force the circuit breaker status of an instance
+type ForceCircuitBreakerRequest api.ForceCircuitBreakerRequest
+
 // ConsumerAPI 主调端API方法.
 type ConsumerAPI interface {
 	api.SDKOwner
@@ -66,6 +71,9 @@ type ConsumerAPI interface {
 	GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
 	// UpdateServiceCallResult 上报服务调用结果
 	UpdateServiceCallResult(req *ServiceCallResult) error
+	// ForceCircuitBreaker 手动设置实例的熔断状态，有效期内熔断器不再对该实例进行状态转换，
+	// 设置为Close时有效期内实例也不会被熔断
+	ForceCircuitBreaker(req *ForceCircuitBreakerRequest) error
 	// WatchService 订阅服务消息
 	WatchService(req *WatchServiceRequest) (*model.WatchServiceResponse, error)
 	// GetServices 根据业务同步获取批量服务
@@ -76,6 +84,16 @@ type ConsumerAPI interface {
 	WatchAllInstances(req *WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error)
 	// WatchAllServices 监听服务列表变更事件
 	WatchAllServices(req *WatchAllServicesRequest) (*model.WatchAllServicesResponse, error)
+	// GetOneInstanceWithContext 同GetOneInstance，ctx取消或超时后不再等待及重试
+	GetOneInstanceWithContext(ctx context.Context, req *GetOneInstanceRequest) (*model.OneInstanceResponse, error)
+	// GetInstancesWithContext 同GetInstances，ctx取消或超时后不再等待及重试
+	GetInstancesWithContext(ctx context.Context, req *GetInstancesRequest) (*model.InstancesResponse, error)
+	// GetAllInstancesWithContext 同GetAllInstances，ctx取消或超时后不再等待及重试
+	GetAllInstancesWithContext(ctx context.Context, req *GetAllInstancesRequest) (*model.InstancesResponse, error)
+	// GetRouteRuleWithContext 同GetRouteRule，ctx取消或超时后不再等待及重试
+	GetRouteRuleWithContext(ctx context.Context, req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
+	// GetServicesWithContext 同GetServices，ctx取消或超时后不再等待及重试
+	GetServicesWithContext(ctx context.Context, req *GetServicesRequest) (*model.ServicesResponse, error)
 	// Destroy 销毁API，销毁后无法再进行调用
 	Destroy()
 }
@@ -106,6 +124,19 @@ type ProviderAPI interface {
 	// Heartbeat
 	// 心跳上报
 	Heartbeat(instance *InstanceHeartbeatRequest) error
+	// RegisterInstanceWithContext
+	// 同RegisterInstance，ctx取消或超时后不再重试
+	RegisterInstanceWithContext(ctx context.Context,
+		instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// RegisterWithContext
+	// 同Register，ctx取消或超时后不再重试
+	RegisterWithContext(ctx context.Context, instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// DeregisterWithContext
+	// 同Deregister，ctx取消或超时后不再重试
+	DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error
+	// HeartbeatWithContext
+	// 同Heartbeat，ctx取消或超时后不再重试
+	HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error
 	// Destroy
 	// 销毁API，销毁后无法再进行调用
 	Destroy()
@@ -122,6 +153,8 @@ type LimitAPI interface {
 	api.SDKOwner
 	// GetQuota the interface obtains only one quota at a time
 	GetQuota(request QuotaRequest) (QuotaFuture, error)
+	// GetQuotaWithContext 同GetQuota，ctx取消或超时后不再等待限流规则的加载
+	GetQuotaWithContext(ctx context.Context, request QuotaRequest) (QuotaFuture, error)
 	// Destroy the api is destroyed and cannot be called again
 	Destroy()
 }
@@ -134,11 +167,34 @@ func NewQuotaRequest() QuotaRequest {
 // ConfigFile config
 type ConfigFile model.ConfigFile
 
+// ConfigKVFile structured config file in yaml, json or properties format
+type ConfigKVFile model.ConfigKVFile
+
+// ConfigFileGroup config group, notify when files in the group are added, removed or republished
+type ConfigFileGroup model.ConfigFileGroup
+
+// ConfigFileRequest request to create or update config file
+type ConfigFileRequest api.ConfigFileRequest
+
 // ConfigAPI api for configuration files.
 type ConfigAPI interface {
 	api.SDKOwner
 	// GetConfigFile obtaining the configuration file
 	GetConfigFile(namespace, fileGroup, fileName string) (ConfigFile, error)
+	// GetConfigKVFile obtaining the structured configuration file, format is detected by file extension
+	GetConfigKVFile(namespace, fileGroup, fileName string) (ConfigKVFile, error)
+	// GetConfigKVFileWithFormat obtaining the structured configuration file with the specified format
+	GetConfigKVFileWithFormat(namespace, fileGroup, fileName string, format model.ConfigFileFormat) (ConfigKVFile, error)
+	// GetConfigGroup obtaining the configuration group and watching files in it
+	GetConfigGroup(namespace, group string) (ConfigFileGroup, error)
+	// CreateConfigFile create the configuration file
+	CreateConfigFile(req *ConfigFileRequest) error
+	// UpdateConfigFile update the configuration file, it takes effect after published
+	UpdateConfigFile(req *ConfigFileRequest) error
+	// PublishConfigFile publish the configuration file
+	PublishConfigFile(namespace, fileGroup, fileName string) error
+	// DeleteConfigFile delete the configuration file
+	DeleteConfigFile(namespace, fileGroup, fileName string) error
 }
 
 // RouterAPI routing api methods
@@ -19,6 +19,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -108,6 +109,15 @@ func checkAvailable(owner SDKOwner) error {
 	return nil
 }
 
+// checkContext 校验调用方上下文，各个WithContext接口只把ctx作为参数传递给流程引擎，
+// 不会写入用户传入的请求对象，请求对象复用时不会携带之前调用的ctx
+func checkContext(ctx context.Context) error {
+	if ctx == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil, "context can not be nil")
+	}
+	return nil
+}
+
 // sdkContext SDK上下文实现
 type sdkContext struct {
 	config       config.Configuration
@@ -19,11 +19,31 @@ package api
 
 import "github.com/polarismesh/polaris-go/pkg/model"
 
+// ConfigFileRequest 创建、更新配置文件的请求
+type ConfigFileRequest struct {
+	model.ConfigFileRequest
+}
+
 // ConfigFileAPI 配置文件的 API
 type ConfigFileAPI interface {
 	SDKOwner
 	// GetConfigFile 获取配置文件
 	GetConfigFile(namespace, fileGroup, fileName string) (model.ConfigFile, error)
+	// GetConfigKVFile 获取结构化配置文件，根据文件名后缀判断格式，支持 yaml、yml、json、properties
+	GetConfigKVFile(namespace, fileGroup, fileName string) (model.ConfigKVFile, error)
+	// GetConfigKVFileWithFormat 按照指定的格式获取结构化配置文件
+	GetConfigKVFileWithFormat(namespace, fileGroup, fileName string,
+		format model.ConfigFileFormat) (model.ConfigKVFile, error)
+	// GetConfigGroup 获取配置分组，可以通过监听分组变更发现分组下新增、删除以及重新发布的配置文件
+	GetConfigGroup(namespace, group string) (model.ConfigFileGroup, error)
+	// CreateConfigFile 创建配置文件，配置文件已存在时返回 ErrCodeConfigFileConflict
+	CreateConfigFile(req *ConfigFileRequest) error
+	// UpdateConfigFile 更新配置文件，更新后需要发布才会推送给客户端，配置文件不存在时返回 ErrCodeConfigFileNotFound
+	UpdateConfigFile(req *ConfigFileRequest) error
+	// PublishConfigFile 发布配置文件
+	PublishConfigFile(namespace, fileGroup, fileName string) error
+	// DeleteConfigFile 删除配置文件，北极星GRPC配置连接器不支持删除，调用时返回错误
+	DeleteConfigFile(namespace, fileGroup, fileName string) error
 }
 
 var (
@@ -57,6 +57,84 @@ func (c *configFileAPI) GetConfigFile(namespace, fileGroup, fileName string) (mo
 	return c.context.GetEngine().SyncGetConfigFile(namespace, fileGroup, fileName)
 }
 
+// GetConfigKVFile 获取结构化配置文件
+func (c *configFileAPI) GetConfigKVFile(namespace, fileGroup, fileName string) (model.ConfigKVFile, error) {
+	return c.context.GetEngine().SyncGetConfigKVFile(namespace, fileGroup, fileName, model.ConfigFileFormatUnknown)
+}
+
+// GetConfigKVFileWithFormat 按照指定的格式获取结构化配置文件
+func (c *configFileAPI) GetConfigKVFileWithFormat(namespace, fileGroup, fileName string,
+	format model.ConfigFileFormat) (model.ConfigKVFile, error) {
+	return c.context.GetEngine().SyncGetConfigKVFile(namespace, fileGroup, fileName, format)
+}
+
+// GetConfigGroup 获取配置分组
+func (c *configFileAPI) GetConfigGroup(namespace, group string) (model.ConfigFileGroup, error) {
+	if err := checkAvailable(c); err != nil {
+		return nil, err
+	}
+	if len(namespace) == 0 || len(group) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"namespace and group of config group should not be empty")
+	}
+	return c.context.GetEngine().SyncGetConfigGroup(namespace, group)
+}
+
+// CreateConfigFile 创建配置文件
+func (c *configFileAPI) CreateConfigFile(req *ConfigFileRequest) error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	return c.context.GetEngine().SyncCreateConfigFile(&req.ConfigFileRequest)
+}
+
+// UpdateConfigFile 更新配置文件
+func (c *configFileAPI) UpdateConfigFile(req *ConfigFileRequest) error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	return c.context.GetEngine().SyncUpdateConfigFile(&req.ConfigFileRequest)
+}
+
+// PublishConfigFile 发布配置文件
+func (c *configFileAPI) PublishConfigFile(namespace, fileGroup, fileName string) error {
+	metadata, err := c.toMetadata(namespace, fileGroup, fileName)
+	if err != nil {
+		return err
+	}
+	return c.context.GetEngine().SyncPublishConfigFile(metadata)
+}
+
+// DeleteConfigFile 删除配置文件
+func (c *configFileAPI) DeleteConfigFile(namespace, fileGroup, fileName string) error {
+	metadata, err := c.toMetadata(namespace, fileGroup, fileName)
+	if err != nil {
+		return err
+	}
+	return c.context.GetEngine().SyncDeleteConfigFile(metadata)
+}
+
+func (c *configFileAPI) toMetadata(namespace, fileGroup, fileName string) (model.ConfigFileMetadata, error) {
+	if err := checkAvailable(c); err != nil {
+		return nil, err
+	}
+	metadata := &model.DefaultConfigFileMetadata{
+		Namespace: namespace,
+		FileGroup: fileGroup,
+		FileName:  fileName,
+	}
+	if err := model.ValidateConfigFileMetadata(metadata); err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "fail to validate config file: ")
+	}
+	return metadata, nil
+}
+
 // SDKContext 获取SDK上下文
 func (c *configFileAPI) SDKContext() SDKContext {
 	return c.context
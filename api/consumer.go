@@ -18,6 +18,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -128,6 +130,11 @@ type WatchAllServicesRequest struct {
 	model.WatchAllServicesRequest
 }
 
+// ForceCircuitBreakerRequest 手动设置实例熔断状态的请求
+type ForceCircuitBreakerRequest struct {
+	model.ForceCircuitBreakerRequest
+}
+
 // ConsumerAPI 主调端API方法
 type ConsumerAPI interface {
 	SDKOwner
@@ -141,6 +148,10 @@ type ConsumerAPI interface {
 	GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
 	// UpdateServiceCallResult 上报服务调用结果
 	UpdateServiceCallResult(req *ServiceCallResult) error
+	// ForceCircuitBreaker 手动设置实例的熔断状态，用于故障期间在主调端立即摘除或者恢复实例，
+	// 有效期内熔断器不再对该实例进行状态转换，状态变更以operator作为熔断器名进行上报；
+	// 注意设置为Close同样会固定状态，有效期内即使调用持续失败，实例也不会被熔断器熔断
+	ForceCircuitBreaker(req *ForceCircuitBreakerRequest) error
 	// Destroy 销毁API，销毁后无法再进行调用
 	Destroy()
 	// Deprecated: please use WatchAllInstances instead
@@ -154,6 +165,16 @@ type ConsumerAPI interface {
 	WatchAllInstances(req *WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error)
 	// WatchAllServices 监听服务列表变更事件
 	WatchAllServices(req *WatchAllServicesRequest) (*model.WatchAllServicesResponse, error)
+	// GetOneInstanceWithContext 同GetOneInstance，ctx取消或超时后不再等待及重试
+	GetOneInstanceWithContext(ctx context.Context, req *GetOneInstanceRequest) (*model.OneInstanceResponse, error)
+	// GetInstancesWithContext 同GetInstances，ctx取消或超时后不再等待及重试
+	GetInstancesWithContext(ctx context.Context, req *GetInstancesRequest) (*model.InstancesResponse, error)
+	// GetAllInstancesWithContext 同GetAllInstances，ctx取消或超时后不再等待及重试
+	GetAllInstancesWithContext(ctx context.Context, req *GetAllInstancesRequest) (*model.InstancesResponse, error)
+	// GetRouteRuleWithContext 同GetRouteRule，ctx取消或超时后不再等待及重试
+	GetRouteRuleWithContext(ctx context.Context, req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error)
+	// GetServicesWithContext 同GetServices，ctx取消或超时后不再等待及重试
+	GetServicesWithContext(ctx context.Context, req *GetServicesRequest) (*model.ServicesResponse, error)
 }
 
 var (
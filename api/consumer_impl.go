@@ -18,6 +18,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/go-multierror"
@@ -36,102 +37,158 @@ type consumerAPI struct {
 
 // GetOneInstance sync get one instance after load balance
 func (c *consumerAPI) GetOneInstance(req *GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	return c.GetOneInstanceWithContext(context.Background(), req)
+}
+
+// GetInstances syncs get one instance after route
+func (c *consumerAPI) GetInstances(req *GetInstancesRequest) (*model.InstancesResponse, error) {
+	return c.GetInstancesWithContext(context.Background(), req)
+}
+
+// GetAllInstances 获取完整的服务列表
+func (c *consumerAPI) GetAllInstances(req *GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	return c.GetAllInstancesWithContext(context.Background(), req)
+}
+
+// UpdateServiceCallResult update the service call error code and delay
+func (c *consumerAPI) UpdateServiceCallResult(req *ServiceCallResult) error {
 	if err := checkAvailable(c); err != nil {
-		return nil, err
+		return err
 	}
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return err
 	}
-	req.convert()
-	return c.context.GetEngine().SyncGetOneInstance(&req.GetOneInstanceRequest)
+	return c.context.GetEngine().SyncUpdateServiceCallResult(&req.ServiceCallResult)
 }
 
-// GetInstances syncs get one instance after route
-func (c *consumerAPI) GetInstances(req *GetInstancesRequest) (*model.InstancesResponse, error) {
+// ForceCircuitBreaker 手动设置实例的熔断状态
+func (c *consumerAPI) ForceCircuitBreaker(req *ForceCircuitBreakerRequest) error {
 	if err := checkAvailable(c); err != nil {
-		return nil, err
+		return err
 	}
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return err
 	}
-	req.convert()
-	return c.context.GetEngine().SyncGetInstances(&req.GetInstancesRequest)
+	return c.context.GetEngine().SyncForceCircuitBreaker(&req.ForceCircuitBreakerRequest)
 }
 
-// GetAllInstances 获取完整的服务列表
-func (c *consumerAPI) GetAllInstances(req *GetAllInstancesRequest) (*model.InstancesResponse, error) {
+// GetRouteRule 同步获取服务路由规则
+func (c *consumerAPI) GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
+	return c.GetRouteRuleWithContext(context.Background(), req)
+}
+
+// GetServices 同步获取批量服务
+func (c *consumerAPI) GetServices(req *GetServicesRequest) (*model.ServicesResponse, error) {
+	return c.GetServicesWithContext(context.Background(), req)
+}
+
+// InitCalleeService 初始化服务运行中需要的被调服务
+func (c *consumerAPI) InitCalleeService(req *InitCalleeServiceRequest) error {
+	if err := checkAvailable(c); err != nil {
+		return err
+	}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	return c.context.GetEngine().InitCalleeService(&req.InitCalleeServiceRequest)
+}
+
+// WatchAllInstances 监听服务实例变更事件
+func (c *consumerAPI) WatchAllInstances(req *WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error) {
 	if err := checkAvailable(c); err != nil {
 		return nil, err
 	}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
-	return c.context.GetEngine().SyncGetAllInstances(&req.GetAllInstancesRequest)
+	return c.context.GetEngine().WatchAllInstances(&req.WatchAllInstancesRequest)
 }
 
-// UpdateServiceCallResult update the service call error code and delay
-func (c *consumerAPI) UpdateServiceCallResult(req *ServiceCallResult) error {
+func (c *consumerAPI) WatchAllServices(req *WatchAllServicesRequest) (*model.WatchAllServicesResponse, error) {
 	if err := checkAvailable(c); err != nil {
-		return err
+		return nil, err
 	}
 	if err := req.Validate(); err != nil {
-		return err
+		return nil, err
 	}
-	return c.context.GetEngine().SyncUpdateServiceCallResult(&req.ServiceCallResult)
+	return c.context.GetEngine().WatchAllServices(&req.WatchAllServicesRequest)
 }
 
-// GetRouteRule 同步获取服务路由规则
-func (c *consumerAPI) GetRouteRule(req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
+// GetOneInstanceWithContext 同步获取负载均衡后的单个服务实例，ctx取消或超时后不再等待及重试
+func (c *consumerAPI) GetOneInstanceWithContext(
+	ctx context.Context, req *GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
 	if err := checkAvailable(c); err != nil {
 		return nil, err
 	}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
-	return c.context.GetEngine().SyncGetServiceRule(model.EventRouting, &req.GetServiceRuleRequest)
+	req.convert()
+	return c.context.GetEngine().SyncGetOneInstance(ctx, &req.GetOneInstanceRequest)
 }
 
-// GetServices 同步获取批量服务
-func (c *consumerAPI) GetServices(req *GetServicesRequest) (*model.ServicesResponse, error) {
+// GetInstancesWithContext 同步获取经过路由后的服务实例，ctx取消或超时后不再等待及重试
+func (c *consumerAPI) GetInstancesWithContext(
+	ctx context.Context, req *GetInstancesRequest) (*model.InstancesResponse, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
 	if err := checkAvailable(c); err != nil {
 		return nil, err
 	}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
-	return c.context.GetEngine().SyncGetServices(model.EventServices, &req.GetServicesRequest)
+	req.convert()
+	return c.context.GetEngine().SyncGetInstances(ctx, &req.GetInstancesRequest)
 }
 
-// InitCalleeService 初始化服务运行中需要的被调服务
-func (c *consumerAPI) InitCalleeService(req *InitCalleeServiceRequest) error {
+// GetAllInstancesWithContext 获取完整的服务列表，ctx取消或超时后不再等待及重试
+func (c *consumerAPI) GetAllInstancesWithContext(
+	ctx context.Context, req *GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
 	if err := checkAvailable(c); err != nil {
-		return err
+		return nil, err
 	}
 	if err := req.Validate(); err != nil {
-		return err
+		return nil, err
 	}
-	return c.context.GetEngine().InitCalleeService(&req.InitCalleeServiceRequest)
+	return c.context.GetEngine().SyncGetAllInstances(ctx, &req.GetAllInstancesRequest)
 }
 
-// WatchAllInstances 监听服务实例变更事件
-func (c *consumerAPI) WatchAllInstances(req *WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error) {
+// GetRouteRuleWithContext 同步获取服务路由规则，ctx取消或超时后不再等待及重试
+func (c *consumerAPI) GetRouteRuleWithContext(
+	ctx context.Context, req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
 	if err := checkAvailable(c); err != nil {
 		return nil, err
 	}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
-	return c.context.GetEngine().WatchAllInstances(&req.WatchAllInstancesRequest)
+	return c.context.GetEngine().SyncGetServiceRule(ctx, model.EventRouting, &req.GetServiceRuleRequest)
 }
 
-func (c *consumerAPI) WatchAllServices(req *WatchAllServicesRequest) (*model.WatchAllServicesResponse, error) {
+// GetServicesWithContext 同步获取批量服务，ctx取消或超时后不再等待及重试
+func (c *consumerAPI) GetServicesWithContext(
+	ctx context.Context, req *GetServicesRequest) (*model.ServicesResponse, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
 	if err := checkAvailable(c); err != nil {
 		return nil, err
 	}
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
-	return c.context.GetEngine().WatchAllServices(&req.WatchAllServicesRequest)
+	return c.context.GetEngine().SyncGetServices(ctx, model.EventServices, &req.GetServicesRequest)
 }
 
 // SDKContext 获取SDK上下文
@@ -18,6 +18,7 @@
 package api
 
 import (
+	"context"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -81,6 +82,8 @@ type LimitAPI interface {
 	SDKOwner
 	// GetQuota 获取限流配额，一次接口只获取一个配额
 	GetQuota(request QuotaRequest) (QuotaFuture, error)
+	// GetQuotaWithContext 同GetQuota，ctx取消或超时后不再等待限流规则的加载
+	GetQuotaWithContext(ctx context.Context, request QuotaRequest) (QuotaFuture, error)
 	// Destroy 销毁API，销毁后无法再进行调用
 	Destroy()
 }
@@ -18,6 +18,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
@@ -34,14 +36,26 @@ func (c *limitAPI) SDKContext() SDKContext {
 
 // GetQuota 获取限流配额
 func (c *limitAPI) GetQuota(request QuotaRequest) (QuotaFuture, error) {
+	return c.GetQuotaWithContext(context.Background(), request)
+}
+
+// GetQuotaWithContext 获取限流配额，ctx取消或超时后不再等待限流规则的加载
+func (c *limitAPI) GetQuotaWithContext(ctx context.Context, request QuotaRequest) (QuotaFuture, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
 	if err := checkAvailable(c); err != nil {
 		return nil, err
 	}
-	mRequest := request.(*model.QuotaRequestImpl)
+	mRequest, ok := request.(*model.QuotaRequestImpl)
+	if !ok {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"QuotaRequest should be created by NewQuotaRequest, got %T", request)
+	}
 	if err := mRequest.Validate(); err != nil {
 		return nil, err
 	}
-	return c.context.GetEngine().AsyncGetQuota(mRequest)
+	return c.context.GetEngine().AsyncGetQuota(ctx, mRequest)
 }
 
 // Destroy 销毁API
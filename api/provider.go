@@ -18,6 +18,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
@@ -52,6 +54,16 @@ type ProviderAPI interface {
 	// Heartbeat the heartbeat report
 	// Deprecated: Use RegisterInstance instead.
 	Heartbeat(instance *InstanceHeartbeatRequest) error
+	// RegisterInstanceWithContext 同RegisterInstance，ctx取消或超时后不再重试
+	RegisterInstanceWithContext(ctx context.Context,
+		instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// RegisterWithContext 同Register，ctx取消或超时后不再重试
+	// Deprecated: Use RegisterInstanceWithContext instead.
+	RegisterWithContext(ctx context.Context, instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error)
+	// DeregisterWithContext 同Deregister，ctx取消或超时后不再重试
+	DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error
+	// HeartbeatWithContext 同Heartbeat，ctx取消或超时后不再重试
+	HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error
 	// Destroy the api is destroyed and cannot be called again
 	Destroy()
 }
@@ -18,6 +18,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	_ "github.com/polarismesh/polaris-go/pkg/plugin/register"
@@ -32,47 +34,81 @@ type providerAPI struct {
 // the Instance ID field in Instance is filled
 // minimum supported version of polaris-server is v1.10.0
 func (c *providerAPI) RegisterInstance(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return c.RegisterInstanceWithContext(context.Background(), instance)
+}
+
+// Register 同步注册服务，服务注册成功后会填充instance中的InstanceId字段
+// 用户可保持该instance对象用于反注册和心跳上报
+func (c *providerAPI) Register(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return c.RegisterWithContext(context.Background(), instance)
+}
+
+// Deregister 同步反注册服务
+func (c *providerAPI) Deregister(instance *InstanceDeRegisterRequest) error {
+	return c.DeregisterWithContext(context.Background(), instance)
+}
+
+// Heartbeat 心跳上报
+func (c *providerAPI) Heartbeat(instance *InstanceHeartbeatRequest) error {
+	return c.HeartbeatWithContext(context.Background(), instance)
+}
+
+// RegisterInstanceWithContext 同RegisterInstance，ctx取消或超时后不再重试
+func (c *providerAPI) RegisterInstanceWithContext(ctx context.Context,
+	instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
 	if err := checkAvailable(c); err != nil {
 		return nil, err
 	}
 	if err := instance.Validate(); err != nil {
 		return nil, err
 	}
-	return c.context.GetEngine().SyncRegisterV2(&instance.InstanceRegisterRequest)
+	return c.context.GetEngine().SyncRegisterV2(ctx, &instance.InstanceRegisterRequest)
 }
 
-// Register 同步注册服务，服务注册成功后会填充instance中的InstanceId字段
-// 用户可保持该instance对象用于反注册和心跳上报
-func (c *providerAPI) Register(instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+// RegisterWithContext 同Register，ctx取消或超时后不再重试
+func (c *providerAPI) RegisterWithContext(ctx context.Context,
+	instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
 	if err := checkAvailable(c); err != nil {
 		return nil, err
 	}
 	if err := instance.Validate(); err != nil {
 		return nil, err
 	}
-	return c.context.GetEngine().SyncRegister(&instance.InstanceRegisterRequest)
+	return c.context.GetEngine().SyncRegister(ctx, &instance.InstanceRegisterRequest)
 }
 
-// Deregister 同步反注册服务
-func (c *providerAPI) Deregister(instance *InstanceDeRegisterRequest) error {
+// DeregisterWithContext 同Deregister，ctx取消或超时后不再重试
+func (c *providerAPI) DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
 	if err := checkAvailable(c); err != nil {
 		return err
 	}
 	if err := instance.Validate(); err != nil {
 		return err
 	}
-	return c.context.GetEngine().SyncDeregister(&instance.InstanceDeRegisterRequest)
+	return c.context.GetEngine().SyncDeregister(ctx, &instance.InstanceDeRegisterRequest)
 }
 
-// Heartbeat 心跳上报
-func (c *providerAPI) Heartbeat(instance *InstanceHeartbeatRequest) error {
+// HeartbeatWithContext 同Heartbeat，ctx取消或超时后不再重试
+func (c *providerAPI) HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
 	if err := checkAvailable(c); err != nil {
 		return err
 	}
 	if err := instance.Validate(); err != nil {
 		return err
 	}
-	return c.context.GetEngine().SyncHeartbeat(&instance.InstanceHeartbeatRequest)
+	return c.context.GetEngine().SyncHeartbeat(ctx, &instance.InstanceHeartbeatRequest)
 }
 
 // SDKContext 获取SDK上下文
@@ -20,6 +20,7 @@ package polaris
 import (
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
 type configAPI struct {
@@ -72,6 +73,42 @@ func (c *configAPI) GetConfigFile(namespace, fileGroup, fileName string) (Config
 	return c.rawAPI.GetConfigFile(namespace, fileGroup, fileName)
 }
 
+// GetConfigKVFile 获取结构化配置文件
+func (c *configAPI) GetConfigKVFile(namespace, fileGroup, fileName string) (ConfigKVFile, error) {
+	return c.rawAPI.GetConfigKVFile(namespace, fileGroup, fileName)
+}
+
+// GetConfigKVFileWithFormat 按照指定的格式获取结构化配置文件
+func (c *configAPI) GetConfigKVFileWithFormat(namespace, fileGroup, fileName string,
+	format model.ConfigFileFormat) (ConfigKVFile, error) {
+	return c.rawAPI.GetConfigKVFileWithFormat(namespace, fileGroup, fileName, format)
+}
+
+// GetConfigGroup 获取配置分组
+func (c *configAPI) GetConfigGroup(namespace, group string) (ConfigFileGroup, error) {
+	return c.rawAPI.GetConfigGroup(namespace, group)
+}
+
+// CreateConfigFile 创建配置文件
+func (c *configAPI) CreateConfigFile(req *ConfigFileRequest) error {
+	return c.rawAPI.CreateConfigFile((*api.ConfigFileRequest)(req))
+}
+
+// UpdateConfigFile 更新配置文件
+func (c *configAPI) UpdateConfigFile(req *ConfigFileRequest) error {
+	return c.rawAPI.UpdateConfigFile((*api.ConfigFileRequest)(req))
+}
+
+// PublishConfigFile 发布配置文件
+func (c *configAPI) PublishConfigFile(namespace, fileGroup, fileName string) error {
+	return c.rawAPI.PublishConfigFile(namespace, fileGroup, fileName)
+}
+
+// DeleteConfigFile 删除配置文件
+func (c *configAPI) DeleteConfigFile(namespace, fileGroup, fileName string) error {
+	return c.rawAPI.DeleteConfigFile(namespace, fileGroup, fileName)
+}
+
 // SDKContext 获取SDK上下文
 func (c *configAPI) SDKContext() api.SDKContext {
 	return c.rawAPI.SDKContext()
@@ -18,6 +18,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -58,6 +60,11 @@ func (c *consumerAPI) UpdateServiceCallResult(req *ServiceCallResult) error {
 	return c.rawAPI.UpdateServiceCallResult((*api.ServiceCallResult)(req))
 }
 
+// ForceCircuitBreaker 手动设置实例的熔断状态
+func (c *consumerAPI) ForceCircuitBreaker(req *ForceCircuitBreakerRequest) error {
+	return c.rawAPI.ForceCircuitBreaker((*api.ForceCircuitBreakerRequest)(req))
+}
+
 // WatchService 订阅服务消息
 func (c *consumerAPI) WatchService(req *WatchServiceRequest) (*model.WatchServiceResponse, error) {
 	return c.rawAPI.WatchService((*api.WatchServiceRequest)(req))
@@ -83,6 +90,36 @@ func (c *consumerAPI) WatchAllServices(req *WatchAllServicesRequest) (*model.Wat
 	return c.rawAPI.WatchAllServices((*api.WatchAllServicesRequest)(req))
 }
 
+// GetOneInstanceWithContext 同步获取单个服务，ctx取消或超时后不再等待及重试
+func (c *consumerAPI) GetOneInstanceWithContext(
+	ctx context.Context, req *GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+	return c.rawAPI.GetOneInstanceWithContext(ctx, (*api.GetOneInstanceRequest)(req))
+}
+
+// GetInstancesWithContext 同步获取可用的服务列表，ctx取消或超时后不再等待及重试
+func (c *consumerAPI) GetInstancesWithContext(
+	ctx context.Context, req *GetInstancesRequest) (*model.InstancesResponse, error) {
+	return c.rawAPI.GetInstancesWithContext(ctx, (*api.GetInstancesRequest)(req))
+}
+
+// GetAllInstancesWithContext 同步获取完整的服务列表，ctx取消或超时后不再等待及重试
+func (c *consumerAPI) GetAllInstancesWithContext(
+	ctx context.Context, req *GetAllInstancesRequest) (*model.InstancesResponse, error) {
+	return c.rawAPI.GetAllInstancesWithContext(ctx, (*api.GetAllInstancesRequest)(req))
+}
+
+// GetRouteRuleWithContext 同步获取服务路由规则，ctx取消或超时后不再等待及重试
+func (c *consumerAPI) GetRouteRuleWithContext(
+	ctx context.Context, req *GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
+	return c.rawAPI.GetRouteRuleWithContext(ctx, (*api.GetServiceRuleRequest)(req))
+}
+
+// GetServicesWithContext 根据业务同步获取批量服务，ctx取消或超时后不再等待及重试
+func (c *consumerAPI) GetServicesWithContext(
+	ctx context.Context, req *GetServicesRequest) (*model.ServicesResponse, error) {
+	return c.rawAPI.GetServicesWithContext(ctx, (*api.GetServicesRequest)(req))
+}
+
 // Destroy 销毁API，销毁后无法再进行调用
 func (c *consumerAPI) Destroy() {
 	c.rawAPI.Destroy()
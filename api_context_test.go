@@ -0,0 +1,149 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/polaristest"
+)
+
+func assertErrCode(t *testing.T, expect model.ErrCode, err error) {
+	if !assert.NotNil(t, err) {
+		return
+	}
+	sdkErr, ok := err.(model.SDKError)
+	if assert.True(t, ok) {
+		assert.Equal(t, expect, sdkErr.ErrorCode())
+	}
+}
+
+func TestGetInstancesWithContext_Canceled(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+	server.AddInstances("default", "echo", polaristest.Instance{Host: "127.0.0.1", Port: 8080})
+	consumer, err := polaris.NewConsumerAPIByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer consumer.Destroy()
+
+	req := &polaris.GetInstancesRequest{}
+	req.Namespace = "default"
+	req.Service = "echo"
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = consumer.GetInstancesWithContext(ctx, req)
+	assertErrCode(t, model.ErrCodeAPICanceled, err)
+
+	// 复用请求对象时不再携带已经取消的ctx
+	resp, err := consumer.GetInstances(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.GetInstances()))
+}
+
+func TestGetOneInstanceWithContext_Deadline(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+	server.AddInstances("default", "echo", polaristest.Instance{Host: "127.0.0.1", Port: 8080})
+	consumer, err := polaris.NewConsumerAPIByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer consumer.Destroy()
+
+	server.InjectLatency(polaristest.OperationDiscover, 3*time.Second)
+	timeout := 10 * time.Second
+	req := &polaris.GetOneInstanceRequest{}
+	req.Namespace = "default"
+	req.Service = "echo"
+	req.Timeout = &timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	_, err = consumer.GetOneInstanceWithContext(ctx, req)
+	assertErrCode(t, model.ErrCodeAPITimeoutError, err)
+	// 调用方ctx到期后立即返回，不等待请求超时以及服务端应答
+	assert.True(t, time.Since(startTime) < 2*time.Second)
+
+	server.ClearFaults()
+	// 后台重试服务发现成功前仍然返回之前的错误
+	var resp *model.OneInstanceResponse
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		resp, err = consumer.GetOneInstance(req)
+		if err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Nil(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, uint32(8080), resp.GetInstance().GetPort())
+	}
+}
+
+func TestRegisterInstanceWithContext_Deadline(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+	provider, err := polaris.NewProviderAPIByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer provider.Destroy()
+
+	server.InjectLatency(polaristest.OperationRegister, 3*time.Second)
+	timeout := 10 * time.Second
+	req := &polaris.InstanceRegisterRequest{}
+	req.Namespace = "default"
+	req.Service = "echo"
+	req.Host = "127.0.0.1"
+	req.Port = 8080
+	req.Timeout = &timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	_, err = provider.RegisterInstanceWithContext(ctx, req)
+	assertErrCode(t, model.ErrCodeAPITimeoutError, err)
+	assert.True(t, time.Since(startTime) < 2*time.Second)
+
+	server.ClearFaults()
+	_, err = provider.RegisterInstance(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(server.GetInstances("default", "echo")))
+}
+
+// customQuotaRequest 不是通过NewQuotaRequest创建的配额请求
+type customQuotaRequest struct {
+	polaris.QuotaRequest
+}
+
+func TestGetQuotaWithContext_InvalidRequest(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+	limitAPI, err := polaris.NewLimitAPIByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer limitAPI.Destroy()
+
+	_, err = limitAPI.GetQuotaWithContext(context.Background(), &customQuotaRequest{})
+	assertErrCode(t, model.ErrCodeAPIInvalidArgument, err)
+}
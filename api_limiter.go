@@ -18,6 +18,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
 )
@@ -36,6 +38,11 @@ func (c *limitAPI) GetQuota(request QuotaRequest) (QuotaFuture, error) {
 	return c.rawAPI.GetQuota(request)
 }
 
+// GetQuotaWithContext 获取限流配额，ctx取消或超时后不再等待限流规则的加载
+func (c *limitAPI) GetQuotaWithContext(ctx context.Context, request QuotaRequest) (QuotaFuture, error) {
+	return c.rawAPI.GetQuotaWithContext(ctx, request)
+}
+
 // Destroy 销毁API，销毁后无法再进行调用
 func (c *limitAPI) Destroy() {
 	c.rawAPI.Destroy()
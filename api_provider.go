@@ -18,6 +18,8 @@
 package polaris
 
 import (
+	"context"
+
 	"github.com/polarismesh/polaris-go/api"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -56,6 +58,28 @@ func (p *providerAPI) Heartbeat(instance *InstanceHeartbeatRequest) error {
 	return p.rawAPI.Heartbeat((*api.InstanceHeartbeatRequest)(instance))
 }
 
+// RegisterInstanceWithContext 同RegisterInstance，ctx取消或超时后不再重试
+func (p *providerAPI) RegisterInstanceWithContext(ctx context.Context,
+	instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return p.rawAPI.RegisterInstanceWithContext(ctx, (*api.InstanceRegisterRequest)(instance))
+}
+
+// RegisterWithContext 同Register，ctx取消或超时后不再重试
+func (p *providerAPI) RegisterWithContext(ctx context.Context,
+	instance *InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return p.rawAPI.RegisterWithContext(ctx, (*api.InstanceRegisterRequest)(instance))
+}
+
+// DeregisterWithContext 同Deregister，ctx取消或超时后不再重试
+func (p *providerAPI) DeregisterWithContext(ctx context.Context, instance *InstanceDeRegisterRequest) error {
+	return p.rawAPI.DeregisterWithContext(ctx, (*api.InstanceDeRegisterRequest)(instance))
+}
+
+// HeartbeatWithContext 同Heartbeat，ctx取消或超时后不再重试
+func (p *providerAPI) HeartbeatWithContext(ctx context.Context, instance *InstanceHeartbeatRequest) error {
+	return p.rawAPI.HeartbeatWithContext(ctx, (*api.InstanceHeartbeatRequest)(instance))
+}
+
 // Destroy the api is destroyed and cannot be called again
 func (p *providerAPI) Destroy() {
 	p.rawAPI.Destroy()
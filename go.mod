@@ -5,6 +5,7 @@ go 1.15
 require (
 	github.com/agiledragon/gomonkey v2.0.2+incompatible
 	github.com/dlclark/regexp2 v1.7.0
+	github.com/envoyproxy/go-control-plane v0.10.3
 	github.com/golang/protobuf v1.5.2
 	github.com/gonum/blas v0.0.0-20181208220705-f22b278b28ac // indirect
 	github.com/gonum/floats v0.0.0-20181209220543-c233463c7e82 // indirect
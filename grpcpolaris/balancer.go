@@ -0,0 +1,323 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpcpolaris
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// BalancerName 北极星负载均衡器名，polaris:// 地址默认使用该负载均衡器
+const BalancerName = "polaris"
+
+// failureCodes 视为被调实例异常的grpc返回码，其余返回码属于业务结果，上报为调用成功
+var failureCodes = map[codes.Code]bool{
+	codes.Unknown:          true,
+	codes.DeadlineExceeded: true,
+	codes.Internal:         true,
+	codes.Unavailable:      true,
+	codes.DataLoss:         true,
+}
+
+func init() {
+	balancer.Register(&balancerBuilder{})
+}
+
+type balancerBuilder struct {
+}
+
+// Build 创建负载均衡器
+func (b *balancerBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	return &polarisBalancer{
+		cc:       cc,
+		subConns: make(map[string]balancer.SubConn),
+		scStates: make(map[balancer.SubConn]connectivity.State),
+		csEvltr:  &balancer.ConnectivityStateEvaluator{},
+		state:    connectivity.Connecting,
+	}
+}
+
+// Name 负载均衡器名
+func (b *balancerBuilder) Name() string {
+	return BalancerName
+}
+
+// polarisBalancer 为每个实例维护一个连接，调用时通过北极星路由和负载均衡选择实例
+type polarisBalancer struct {
+	cc       balancer.ClientConn
+	subConns map[string]balancer.SubConn
+	scStates map[balancer.SubConn]connectivity.State
+	csEvltr  *balancer.ConnectivityStateEvaluator
+	state    connectivity.State
+	picker   balancer.Picker
+	resolved *resolvedState
+
+	resolverErr error
+	connErr     error
+}
+
+// UpdateClientConnState 实例变更后新建或者删除连接
+func (b *polarisBalancer) UpdateClientConnState(state balancer.ClientConnState) error {
+	b.resolverErr = nil
+	if resolved := getResolvedState(state.ResolverState.Attributes); resolved != nil {
+		b.resolved = resolved
+	}
+	addresses := make(map[string]struct{}, len(state.ResolverState.Addresses))
+	for _, address := range state.ResolverState.Addresses {
+		addresses[address.Addr] = struct{}{}
+		if _, ok := b.subConns[address.Addr]; ok {
+			continue
+		}
+		sc, err := b.cc.NewSubConn([]resolver.Address{address}, balancer.NewSubConnOptions{})
+		if err != nil {
+			log.GetBaseLogger().Warnf("grpcpolaris: fail to create connection to %s, err is %v", address.Addr, err)
+			continue
+		}
+		b.subConns[address.Addr] = sc
+		b.scStates[sc] = connectivity.Idle
+		b.csEvltr.RecordTransition(connectivity.Shutdown, connectivity.Idle)
+		sc.Connect()
+	}
+	for addr, sc := range b.subConns {
+		if _, ok := addresses[addr]; !ok {
+			// 连接状态变为Shutdown后再清理连接状态
+			b.cc.RemoveSubConn(sc)
+			delete(b.subConns, addr)
+		}
+	}
+	if len(state.ResolverState.Addresses) == 0 {
+		b.ResolverError(errors.New("produced zero addresses"))
+		return balancer.ErrBadResolverState
+	}
+	b.updateState()
+	return nil
+}
+
+// ResolverError 解析失败时，如果没有可用连接则直接返回错误
+func (b *polarisBalancer) ResolverError(err error) {
+	b.resolverErr = err
+	if len(b.subConns) == 0 {
+		b.state = connectivity.TransientFailure
+	}
+	if b.state != connectivity.TransientFailure {
+		return
+	}
+	b.updateState()
+}
+
+// UpdateSubConnState 连接状态变更后更新选择器
+func (b *polarisBalancer) UpdateSubConnState(sc balancer.SubConn, state balancer.SubConnState) {
+	oldState, ok := b.scStates[sc]
+	if !ok {
+		return
+	}
+	newState := state.ConnectivityState
+	if oldState == connectivity.TransientFailure &&
+		(newState == connectivity.Connecting || newState == connectivity.Idle) {
+		// 连接失败后保持TransientFailure状态，直到重新连接成功，避免聚合状态来回切换
+		if newState == connectivity.Idle {
+			sc.Connect()
+		}
+		return
+	}
+	b.scStates[sc] = newState
+	switch newState {
+	case connectivity.Idle:
+		sc.Connect()
+	case connectivity.Shutdown:
+		delete(b.scStates, sc)
+	case connectivity.TransientFailure:
+		b.connErr = state.ConnectionError
+	}
+	b.state = b.csEvltr.RecordTransition(oldState, newState)
+	b.updateState()
+}
+
+// Close 关闭负载均衡器，连接由grpc负责关闭
+func (b *polarisBalancer) Close() {
+}
+
+// ExitIdle 重新连接空闲的连接
+func (b *polarisBalancer) ExitIdle() {
+	for sc, state := range b.scStates {
+		if state == connectivity.Idle {
+			sc.Connect()
+		}
+	}
+}
+
+// updateState 根据当前的连接状态生成选择器
+func (b *polarisBalancer) updateState() {
+	if b.state == connectivity.TransientFailure {
+		b.picker = base.NewErrPicker(b.mergeErrors())
+	} else {
+		readySubConns := make(map[string]balancer.SubConn, len(b.subConns))
+		for addr, sc := range b.subConns {
+			if b.scStates[sc] == connectivity.Ready {
+				readySubConns[addr] = sc
+			}
+		}
+		b.picker = &polarisPicker{resolved: b.resolved, readySubConns: readySubConns}
+	}
+	b.cc.UpdateState(balancer.State{ConnectivityState: b.state, Picker: b.picker})
+}
+
+func (b *polarisBalancer) mergeErrors() error {
+	if b.connErr == nil {
+		return fmt.Errorf("last resolver error: %v", b.resolverErr)
+	}
+	if b.resolverErr == nil {
+		return fmt.Errorf("last connection error: %v", b.connErr)
+	}
+	return fmt.Errorf("last connection error: %v; last resolver error: %v", b.connErr, b.resolverErr)
+}
+
+// polarisPicker 每次调用时执行北极星路由和负载均衡，并在调用结束后上报调用结果
+type polarisPicker struct {
+	resolved      *resolvedState
+	readySubConns map[string]balancer.SubConn
+}
+
+// Pick 选择本次调用的连接，出流量的metadata作为路由的请求头参数
+func (p *polarisPicker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	if len(p.readySubConns) == 0 || p.resolved == nil {
+		return balancer.PickResult{}, balancer.ErrNoSubConnAvailable
+	}
+	instance, err := p.selectInstance(info)
+	if err != nil {
+		if errors.Is(err, balancer.ErrNoSubConnAvailable) {
+			return balancer.PickResult{}, err
+		}
+		return balancer.PickResult{}, status.Errorf(codes.Unavailable, "polaris: %v", err)
+	}
+	// 负载均衡时已经排除了未建立连接的实例，因此选中的实例一定有可用连接
+	sc := p.readySubConns[instanceAddress(instance)]
+	startTime := time.Now()
+	return balancer.PickResult{
+		SubConn: sc,
+		Done: func(doneInfo balancer.DoneInfo) {
+			p.reportCallResult(instance, info.FullMethodName, doneInfo.Err, time.Since(startTime))
+		},
+	}, nil
+}
+
+// selectInstance 执行路由和负载均衡，负载均衡时排除尚未建立连接的实例；
+// 实例一旦被负载均衡接受就会占用在途请求等状态，因此不能在选中后再替换为其他实例
+func (p *polarisPicker) selectInstance(info balancer.PickInfo) (model.Instance, error) {
+	opts := p.resolved.opts
+	// 监听推送的实例列表没有构建集群索引，路由使用本地缓存中的全量实例
+	allReq := &polaris.GetAllInstancesRequest{}
+	allReq.Namespace = p.resolved.svcKey.Namespace
+	allReq.Service = p.resolved.svcKey.Service
+	instances, err := p.resolved.consumer.GetAllInstances(allReq)
+	if err != nil {
+		return nil, err
+	}
+	routeReq := &polaris.ProcessRoutersRequest{}
+	// 路由时会将请求参数写入主调服务的元数据，需要复制一份避免并发修改
+	routeReq.SourceService = model.ServiceInfo{
+		Namespace: opts.sourceService.Namespace,
+		Service:   opts.sourceService.Service,
+		Metadata:  make(map[string]string, len(opts.sourceService.Metadata)),
+	}
+	for key, value := range opts.sourceService.Metadata {
+		routeReq.SourceService.Metadata[key] = value
+	}
+	routeReq.DstInstances = instances
+	routeReq.Method = info.FullMethodName
+	routeReq.Arguments = headerArguments(outgoingMetadata(info.Ctx))
+	routed, err := p.resolved.router.ProcessRouters(routeReq)
+	if err != nil {
+		return nil, err
+	}
+	var excludes []model.Instance
+	for _, candidate := range routed.GetInstances() {
+		if _, ok := p.readySubConns[instanceAddress(candidate)]; !ok {
+			excludes = append(excludes, candidate)
+		}
+	}
+	if len(excludes) == len(routed.GetInstances()) {
+		// 路由结果中的实例都还没有建立连接，等待连接就绪后重新选择
+		return nil, balancer.ErrNoSubConnAvailable
+	}
+	lbReq := &polaris.ProcessLoadBalanceRequest{}
+	lbReq.DstInstances = routed
+	lbReq.ExcludeInstances = excludes
+	lbReq.LbPolicy = opts.lbPolicy
+	resp, err := p.resolved.router.ProcessLoadBalance(lbReq)
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetInstance(), nil
+}
+
+// reportCallResult 上报调用结果以及时延
+func (p *polarisPicker) reportCallResult(instance model.Instance, method string, err error, delay time.Duration) {
+	code := status.Code(err)
+	result := &polaris.ServiceCallResult{}
+	result.SetCalledInstance(instance)
+	result.Method = method
+	result.SetDelay(delay)
+	result.SetRetCode(int32(code))
+	if failureCodes[code] {
+		result.SetRetStatus(model.RetFail)
+	} else {
+		result.SetRetStatus(model.RetSuccess)
+	}
+	if sourceService := p.resolved.opts.sourceService; len(sourceService.Service) > 0 {
+		result.SourceService = &sourceService
+	}
+	if err = p.resolved.consumer.UpdateServiceCallResult(result); err != nil {
+		log.GetBaseLogger().Warnf("grpcpolaris: fail to report call result of %s, err is %v",
+			instanceAddress(instance), err)
+	}
+}
+
+func outgoingMetadata(ctx context.Context) metadata.MD {
+	if ctx == nil {
+		return nil
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return md
+}
+
+// headerArguments 将grpc的metadata转换为请求头参数，同名的metadata只取第一个值
+func headerArguments(md metadata.MD) []model.Argument {
+	arguments := make([]model.Argument, 0, len(md))
+	for key, values := range md {
+		if len(values) == 0 {
+			continue
+		}
+		arguments = append(arguments, model.BuildHeaderArgument(key, values[0]))
+	}
+	return arguments
+}
@@ -0,0 +1,182 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpcpolaris_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/grpcpolaris"
+	"github.com/polarismesh/polaris-go/polaristest"
+)
+
+// startHealthServer 启动提供健康检查服务的grpc服务端，返回端口以及请求计数
+func startHealthServer(t *testing.T, opts ...grpc.ServerOption) (uint32, *int64) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	var count int64
+	opts = append(opts, grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		atomic.AddInt64(&count, 1)
+		return handler(ctx, req)
+	}))
+	server := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return uint32(listener.Addr().(*net.TCPAddr).Port), &count
+}
+
+func TestParseTarget(t *testing.T) {
+	svcKey, err := grpcpolaris.ParseTarget("polaris://default/echo")
+	assert.Nil(t, err)
+	assert.Equal(t, "default", svcKey.Namespace)
+	assert.Equal(t, "echo", svcKey.Service)
+
+	_, err = grpcpolaris.ParseTarget("polaris://default")
+	assert.NotNil(t, err)
+	_, err = grpcpolaris.ParseTarget("dns:///echo:8080")
+	assert.NotNil(t, err)
+}
+
+func TestResolverAndBalancer(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+
+	port1, count1 := startHealthServer(t)
+	port2, count2 := startHealthServer(t)
+	server.AddInstances("default", "echo",
+		polaristest.Instance{Host: "127.0.0.1", Port: port1},
+		polaristest.Instance{Host: "127.0.0.1", Port: port2},
+	)
+	sdkCtx, err := polaris.NewSDKContextByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer sdkCtx.Destroy()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "polaris://default/echo",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpcpolaris.WithResolver(sdkCtx, grpcpolaris.WithSourceService("default", "caller", nil)))
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+	for i := 0; i < 50; i++ {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+		assert.Nil(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	}
+	assert.Equal(t, int64(50), atomic.LoadInt64(count1)+atomic.LoadInt64(count2))
+	assert.True(t, atomic.LoadInt64(count1) > 0)
+	assert.True(t, atomic.LoadInt64(count2) > 0)
+}
+
+func TestServerRateLimitInterceptor(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+
+	server.SetRateLimitRule("default", "echo", &traffic_manage.RateLimit{
+		Rules: []*traffic_manage.Rule{
+			{
+				Type: traffic_manage.Rule_LOCAL,
+				Method: &apimodel.MatchString{
+					Type:  apimodel.MatchString_EXACT,
+					Value: wrapperspb.String("/grpc.health.v1.Health/Check"),
+				},
+				Amounts: []*traffic_manage.Amount{
+					{MaxAmount: wrapperspb.UInt32(1), ValidDuration: durationpb.New(time.Minute)},
+				},
+			},
+		},
+	})
+	limitAPI, err := polaris.NewLimitAPIByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer limitAPI.Destroy()
+
+	port, _ := startHealthServer(t,
+		grpc.UnaryInterceptor(grpcpolaris.UnaryServerRateLimitInterceptor(limitAPI, "default", "echo")))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, net.JoinHostPort("127.0.0.1", fmt.Sprint(port)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Nil(t, err)
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestBalancerSkipsUnconnectedInstances(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+
+	// 获取一个没有服务监听的端口，该实例的连接始终无法建立
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	deadPort := uint32(listener.Addr().(*net.TCPAddr).Port)
+	_ = listener.Close()
+	port, count := startHealthServer(t)
+	server.AddInstances("default", "echo",
+		polaristest.Instance{Host: "127.0.0.1", Port: deadPort},
+		polaristest.Instance{Host: "127.0.0.1", Port: port},
+	)
+	sdkCtx, err := polaris.NewSDKContextByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer sdkCtx.Destroy()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "polaris://default/echo",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpcpolaris.WithResolver(sdkCtx, grpcpolaris.WithLbPolicy("leastRequest")))
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+	for i := 0; i < 20; i++ {
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, int64(20), atomic.LoadInt64(count))
+}
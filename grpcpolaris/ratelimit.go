@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package grpcpolaris
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// UnaryServerRateLimitInterceptor 服务端一元调用限流拦截器，被限流时返回ResourceExhausted
+// 请求的metadata作为限流规则的请求头参数，调用方法为grpc的完整方法名
+func UnaryServerRateLimitInterceptor(limitAPI polaris.LimitAPI, namespace, service string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		release, err := acquireQuota(limitAPI, namespace, service, info.FullMethod, md)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRateLimitInterceptor 服务端流式调用限流拦截器，在建立流时获取配额
+func StreamServerRateLimitInterceptor(limitAPI polaris.LimitAPI, namespace, service string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		release, err := acquireQuota(limitAPI, namespace, service, info.FullMethod, md)
+		if err != nil {
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
+
+// UnaryClientRateLimitInterceptor 客户端一元调用限流拦截器，被调服务从 polaris:// 拨号地址中解析，
+// 其他协议的地址不做限流
+func UnaryClientRateLimitInterceptor(limitAPI polaris.LimitAPI) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		svcKey, err := ParseTarget(cc.Target())
+		if err != nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		release, err := acquireQuota(limitAPI, svcKey.Namespace, svcKey.Service, method, outgoingMetadata(ctx))
+		if err != nil {
+			return err
+		}
+		defer release()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientRateLimitInterceptor 客户端流式调用限流拦截器，在建立流时获取配额
+func StreamClientRateLimitInterceptor(limitAPI polaris.LimitAPI) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		svcKey, err := ParseTarget(cc.Target())
+		if err != nil {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		release, err := acquireQuota(limitAPI, svcKey.Namespace, svcKey.Service, method, outgoingMetadata(ctx))
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// acquireQuota 获取配额，被限流时返回ResourceExhausted；限流接口异常时放通请求
+func acquireQuota(limitAPI polaris.LimitAPI, namespace, service, method string, md metadata.MD) (func(), error) {
+	quotaReq := polaris.NewQuotaRequest()
+	quotaReq.SetNamespace(namespace)
+	quotaReq.SetService(service)
+	quotaReq.SetMethod(method)
+	for _, argument := range headerArguments(md) {
+		quotaReq.AddArgument(argument)
+	}
+	future, err := limitAPI.GetQuota(quotaReq)
+	if err != nil {
+		log.GetBaseLogger().Warnf("grpcpolaris: fail to get quota of %s/%s %s, err is %v",
+			namespace, service, method, err)
+		return func() {}, nil
+	}
+	resp := future.Get()
+	if resp.Code == model.QuotaResultLimited {
+		return nil, status.Errorf(codes.ResourceExhausted, "request of %s is limited by polaris: %s", method, resp.Info)
+	}
+	return future.Release, nil
+}
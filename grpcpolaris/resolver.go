@@ -0,0 +1,234 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package grpcpolaris 提供google.golang.org/grpc与北极星的集成。
+// 通过 polaris://namespace/service 形式的地址拨号，由resolver监听服务实例变更，
+// balancer在每次调用时执行路由和负载均衡并自动上报调用结果；
+// 另外提供基于北极星限流的客户端以及服务端拦截器。
+package grpcpolaris
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// Scheme 北极星的grpc拨号地址协议，地址格式为 polaris://namespace/service
+	Scheme = "polaris"
+	// 启用北极星负载均衡器的服务配置
+	serviceConfigJSON = `{"loadBalancingConfig":[{"` + BalancerName + `":{}}]}`
+)
+
+// Option 拨号的可选配置
+type Option func(*options)
+
+type options struct {
+	sourceService model.ServiceInfo
+	lbPolicy      string
+}
+
+// WithSourceService 设置主调服务信息，用于匹配路由规则以及上报调用结果
+func WithSourceService(namespace, service string, metadata map[string]string) Option {
+	return func(o *options) {
+		o.sourceService = model.ServiceInfo{Namespace: namespace, Service: service, Metadata: metadata}
+	}
+}
+
+// WithLbPolicy 设置负载均衡插件，为空时使用SDK配置的默认负载均衡插件
+func WithLbPolicy(lbPolicy string) Option {
+	return func(o *options) {
+		o.lbPolicy = lbPolicy
+	}
+}
+
+// WithResolver 返回使用北极星解析 polaris:// 地址的拨号选项
+func WithResolver(sdkCtx api.SDKContext, opts ...Option) grpc.DialOption {
+	return grpc.WithResolvers(NewResolverBuilder(sdkCtx, opts...))
+}
+
+// NewResolverBuilder 创建北极星的地址解析器，解析出的地址默认使用北极星负载均衡器
+func NewResolverBuilder(sdkCtx api.SDKContext, opts ...Option) resolver.Builder {
+	builder := &resolverBuilder{sdkCtx: sdkCtx}
+	for _, opt := range opts {
+		opt(&builder.opts)
+	}
+	return builder
+}
+
+// ParseTarget 从 polaris://namespace/service 形式的地址中解析服务名
+func ParseTarget(target string) (model.ServiceKey, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return model.ServiceKey{}, err
+	}
+	return parseTargetURL(targetURL)
+}
+
+func parseTargetURL(targetURL *url.URL) (model.ServiceKey, error) {
+	if targetURL.Scheme != Scheme {
+		return model.ServiceKey{}, fmt.Errorf("scheme of target %s is not %s", targetURL, Scheme)
+	}
+	svcKey := model.ServiceKey{
+		Namespace: targetURL.Host,
+		Service:   strings.TrimPrefix(targetURL.Path, "/"),
+	}
+	if len(svcKey.Namespace) == 0 || len(svcKey.Service) == 0 {
+		return model.ServiceKey{}, fmt.Errorf("target %s should be %s://namespace/service", targetURL, Scheme)
+	}
+	return svcKey, nil
+}
+
+// resolvedStateKey 解析结果在resolver.State属性中的KEY
+type resolvedStateKey struct{}
+
+// resolvedState 传递给负载均衡器的解析结果
+type resolvedState struct {
+	consumer polaris.ConsumerAPI
+	router   polaris.RouterAPI
+	opts     *options
+	svcKey   model.ServiceKey
+}
+
+func getResolvedState(attrs *attributes.Attributes) *resolvedState {
+	if attrs == nil {
+		return nil
+	}
+	state, _ := attrs.Value(resolvedStateKey{}).(*resolvedState)
+	return state
+}
+
+type resolverBuilder struct {
+	sdkCtx api.SDKContext
+	opts   options
+}
+
+// Scheme 解析器支持的地址协议
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+// Build 创建解析器，并监听服务实例变更
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn,
+	opts resolver.BuildOptions) (resolver.Resolver, error) {
+	svcKey, err := parseTargetURL(&target.URL)
+	if err != nil {
+		return nil, err
+	}
+	serviceConfig := cc.ParseServiceConfig(serviceConfigJSON)
+	if serviceConfig.Err != nil {
+		return nil, serviceConfig.Err
+	}
+	r := &polarisResolver{
+		cc:            cc,
+		svcKey:        svcKey,
+		consumer:      polaris.NewConsumerAPIByContext(b.sdkCtx),
+		router:        polaris.NewRouterAPIByContext(b.sdkCtx),
+		opts:          &b.opts,
+		serviceConfig: serviceConfig,
+	}
+	watchReq := &polaris.WatchAllInstancesRequest{}
+	watchReq.ServiceKey = svcKey
+	watchReq.WatchMode = api.WatchModeNotify
+	watchReq.InstancesListener = r
+	watchResp, err := r.consumer.WatchAllInstances(watchReq)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	r.watchResp = watchResp
+	r.lock.Unlock()
+	r.OnInstancesUpdate(watchResp.InstancesResponse())
+	return r, nil
+}
+
+// polarisResolver 基于北极星实例监听的解析器
+type polarisResolver struct {
+	cc            resolver.ClientConn
+	svcKey        model.ServiceKey
+	consumer      polaris.ConsumerAPI
+	router        polaris.RouterAPI
+	opts          *options
+	serviceConfig *serviceconfig.ParseResult
+	lock          sync.Mutex
+	watchResp     *model.WatchAllInstancesResponse
+	closed        bool
+}
+
+// OnInstancesUpdate 服务实例变更后更新grpc的地址列表，被隔离或者权重为0的实例不建立连接
+func (r *polarisResolver) OnInstancesUpdate(resp *model.InstancesResponse) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.closed || resp == nil {
+		return
+	}
+	if resp.NotExists {
+		r.cc.ReportError(fmt.Errorf("service %s not found", r.svcKey))
+		return
+	}
+	addresses := make([]resolver.Address, 0, len(resp.GetInstances()))
+	for _, instance := range resp.GetInstances() {
+		if instance.IsIsolated() || instance.GetWeight() == 0 {
+			continue
+		}
+		addresses = append(addresses, resolver.Address{Addr: instanceAddress(instance)})
+	}
+	state := resolver.State{
+		Addresses:     addresses,
+		ServiceConfig: r.serviceConfig,
+		Attributes: attributes.New(resolvedStateKey{}, &resolvedState{
+			consumer: r.consumer,
+			router:   r.router,
+			opts:     r.opts,
+			svcKey:   r.svcKey,
+		}),
+	}
+	if err := r.cc.UpdateState(state); err != nil {
+		log.GetBaseLogger().Warnf("grpcpolaris: fail to update addresses of %s, err is %v", r.svcKey, err)
+	}
+}
+
+// ResolveNow 实例变更通过监听推送，无需主动解析
+func (r *polarisResolver) ResolveNow(resolver.ResolveNowOptions) {
+}
+
+// Close 取消实例监听
+func (r *polarisResolver) Close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.closed = true
+	if r.watchResp != nil {
+		r.watchResp.CancelWatch()
+	}
+}
+
+func instanceAddress(instance model.Instance) string {
+	return net.JoinHostPort(instance.GetHost(), strconv.Itoa(int(instance.GetPort())))
+}
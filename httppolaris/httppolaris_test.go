@@ -0,0 +1,272 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httppolaris_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/httppolaris"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/polaristest"
+)
+
+func serverPort(t *testing.T, server *httptest.Server) uint32 {
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	assert.Nil(t, err)
+	value, err := strconv.Atoi(port)
+	assert.Nil(t, err)
+	return uint32(value)
+}
+
+func TestParseHost(t *testing.T) {
+	svcKey, ok := httppolaris.ParseHost("echo.default")
+	assert.True(t, ok)
+	assert.Equal(t, "echo", svcKey.Service)
+	assert.Equal(t, "default", svcKey.Namespace)
+
+	_, ok = httppolaris.ParseHost("127.0.0.1")
+	assert.False(t, ok)
+	_, ok = httppolaris.ParseHost("echo.default:8080")
+	assert.False(t, ok)
+	_, ok = httppolaris.ParseHost("localhost")
+	assert.False(t, ok)
+}
+
+func TestTransport_RetryAnotherInstance(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+
+	var failed, succeeded int64
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&failed, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&succeeded, 1)
+		assert.Equal(t, "echo.default", r.Host)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	server.AddInstances("default", "echo",
+		polaristest.Instance{Host: "127.0.0.1", Port: serverPort(t, failing)},
+		polaristest.Instance{Host: "127.0.0.1", Port: serverPort(t, healthy)},
+	)
+	sdkCtx, err := polaris.NewSDKContextByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer sdkCtx.Destroy()
+
+	client := &http.Client{Transport: httppolaris.NewTransport(sdkCtx), Timeout: 5 * time.Second}
+	for i := 0; i < 10; i++ {
+		resp, err := client.Get("http://echo.default/hello")
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Body.Close()
+	}
+	assert.Equal(t, int64(10), atomic.LoadInt64(&succeeded))
+}
+
+// roundTripperFunc 记录请求地址并返回200应答的RoundTripper
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransport_SendDirectly(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+	server.AddInstances("default", "echo", polaristest.Instance{Host: "127.0.0.1", Port: 8080})
+	sdkCtx, err := polaris.NewSDKContextByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer sdkCtx.Destroy()
+
+	var hosts []string
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		hosts = append(hosts, req.URL.Host)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	// 默认只有default命名空间查询北极星，普通域名按照原始地址发送
+	transport := httppolaris.NewTransport(sdkCtx, httppolaris.WithBase(base))
+	discovered := server.RequestCount(polaristest.OperationDiscover)
+	resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://api.example.com/hello", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, discovered, server.RequestCount(polaristest.OperationDiscover))
+	resp, err = transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://echo.default/hello", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"api.example.com", "127.0.0.1:8080"}, hosts)
+
+	// 命名空间不在列表中时不查询北极星
+	hosts = nil
+	transport = httppolaris.NewTransport(sdkCtx, httppolaris.WithBase(base), httppolaris.WithNamespaces("test"))
+	resp, err = transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://echo.default/hello", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"echo.default"}, hosts)
+}
+
+func TestTransport_ServiceNotFound(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+	sdkCtx, err := polaris.NewSDKContextByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer sdkCtx.Destroy()
+
+	var hosts []string
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		hosts = append(hosts, req.URL.Host)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	// 不限制命名空间时，北极星中不存在的服务按照原始地址发送，并且不再重复查询
+	transport := httppolaris.NewTransport(sdkCtx, httppolaris.WithBase(base), httppolaris.WithAllNamespaces())
+	resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://api.example.com/hello", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	discovered := server.RequestCount(polaristest.OperationDiscover)
+	resp, err = transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://api.example.com/hello", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, discovered, server.RequestCount(polaristest.OperationDiscover))
+	assert.Equal(t, []string{"api.example.com", "api.example.com"}, hosts)
+}
+
+func TestTransport_RequestContext(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+	server.AddInstances("default", "echo", polaristest.Instance{Host: "127.0.0.1", Port: 8080})
+	sdkCtx, err := polaris.NewSDKContextByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer sdkCtx.Destroy()
+
+	var requested int64
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt64(&requested, 1)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})
+	transport := httppolaris.NewTransport(sdkCtx, httppolaris.WithBase(base))
+	// 请求的ctx已经取消时不再选择实例和发送请求
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "http://echo.default/hello", nil).WithContext(ctx)
+	_, err = transport.RoundTrip(req)
+	sdkErr, ok := err.(model.SDKError)
+	assert.True(t, ok)
+	assert.Equal(t, model.ErrCodeAPICanceled, sdkErr.ErrorCode())
+	assert.Equal(t, int64(0), atomic.LoadInt64(&requested))
+}
+
+func TestTransport_BodyNotReplayable(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+
+	var requested int64
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requested, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("unavailable"))
+	}))
+	defer failing.Close()
+	server.AddInstances("default", "echo",
+		polaristest.Instance{Host: "127.0.0.1", Port: serverPort(t, failing)},
+		polaristest.Instance{Host: "localhost", Port: serverPort(t, failing)},
+	)
+	sdkCtx, err := polaris.NewSDKContextByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer sdkCtx.Destroy()
+
+	transport := httppolaris.NewTransport(sdkCtx)
+	req, err := http.NewRequest(http.MethodPost, "http://echo.default/hello", strings.NewReader("body"))
+	assert.Nil(t, err)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return nil, errors.New("body is consumed")
+	}
+	// 重试时无法重新获取请求体，返回上一次的应答
+	resp, err := transport.RoundTrip(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "unavailable", string(body))
+	resp.Body.Close()
+	assert.Equal(t, int64(1), atomic.LoadInt64(&requested))
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+
+	server.SetRateLimitRule("default", "echo", &traffic_manage.RateLimit{
+		Rules: []*traffic_manage.Rule{
+			{
+				Type: traffic_manage.Rule_LOCAL,
+				Method: &apimodel.MatchString{
+					Type:  apimodel.MatchString_EXACT,
+					Value: wrapperspb.String("/hello"),
+				},
+				Amounts: []*traffic_manage.Amount{
+					{MaxAmount: wrapperspb.UInt32(1), ValidDuration: durationpb.New(time.Minute)},
+				},
+			},
+		},
+	})
+	limitAPI, err := polaris.NewLimitAPIByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer limitAPI.Destroy()
+
+	handler := httppolaris.RateLimitHandler(limitAPI, "default", "echo",
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/hello", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/hello", nil))
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	// 其他路径不受限流规则影响
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/other", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
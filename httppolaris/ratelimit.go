@@ -0,0 +1,60 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package httppolaris
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// RateLimitHandler 基于北极星限流的服务端中间件，被限流时返回429
+// 请求路径作为限流规则的调用方法，路径、请求头、查询参数、Cookie以及主调IP作为限流参数；
+// 限流接口异常时放通请求
+func RateLimitHandler(limitAPI polaris.LimitAPI, namespace, service string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		quotaReq := polaris.NewQuotaRequest()
+		quotaReq.SetNamespace(namespace)
+		quotaReq.SetService(service)
+		quotaReq.SetMethod(r.URL.Path)
+		for _, argument := range RequestArguments(r) {
+			quotaReq.AddArgument(argument)
+		}
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			quotaReq.AddArgument(model.BuildCallerIPArgument(host))
+		}
+		future, err := limitAPI.GetQuota(quotaReq)
+		if err != nil {
+			log.GetBaseLogger().Warnf("httppolaris: fail to get quota of %s/%s %s, err is %v",
+				namespace, service, r.URL.Path, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		resp := future.Get()
+		if resp.Code == model.QuotaResultLimited {
+			http.Error(w, fmt.Sprintf("request is limited by polaris: %s", resp.Info), http.StatusTooManyRequests)
+			return
+		}
+		defer future.Release()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,330 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package httppolaris 提供net/http与北极星的集成。
+// Transport 将 http://service.namespace/path 形式的请求通过北极星路由和负载均衡发送到服务实例，
+// 默认只有default命名空间的主机名查询北极星，北极星中不存在对应服务时按照原始地址发送，
+// 失败时换一个实例重试，并自动上报状态码和时延；RateLimitHandler 为服务端提供基于北极星的限流。
+package httppolaris
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/api"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// 默认的重试次数
+	defaultRetryCount = 1
+	// 请求未得到应答时上报的返回码
+	retCodeNoResponse = -1
+	// 北极星中不存在的服务的缓存时间，缓存期间直接按照原始地址发送
+	notFoundExpireDuration = 30 * time.Second
+)
+
+// DefaultNamespaces 默认经过北极星发现的命名空间
+var DefaultNamespaces = []string{"default"}
+
+// Option Transport的可选配置
+type Option func(*Transport)
+
+// WithBase 设置实际发送请求的RoundTripper，默认为http.DefaultTransport
+func WithBase(base http.RoundTripper) Option {
+	return func(t *Transport) {
+		t.base = base
+	}
+}
+
+// WithSourceService 设置主调服务信息，用于匹配路由规则以及上报调用结果
+func WithSourceService(namespace, service string, metadata map[string]string) Option {
+	return func(t *Transport) {
+		t.sourceService = model.ServiceInfo{Namespace: namespace, Service: service, Metadata: metadata}
+	}
+}
+
+// WithLbPolicy 设置负载均衡插件，为空时使用SDK配置的默认负载均衡插件
+func WithLbPolicy(lbPolicy string) Option {
+	return func(t *Transport) {
+		t.lbPolicy = lbPolicy
+	}
+}
+
+// WithRetryCount 设置失败后换实例重试的次数，默认为1，请求体无法重放时不重试
+func WithRetryCount(retryCount int) Option {
+	return func(t *Transport) {
+		t.retryCount = retryCount
+	}
+}
+
+// WithNamespaces 设置经过北极星发现的命名空间，只有命名空间在列表中的主机名才查询北极星，
+// 其他主机名直接发送，避免普通域名的请求查询北极星，默认为DefaultNamespaces
+func WithNamespaces(namespaces ...string) Option {
+	return func(t *Transport) {
+		t.namespaces = make(map[string]struct{}, len(namespaces))
+		for _, namespace := range namespaces {
+			t.namespaces[namespace] = struct{}{}
+		}
+	}
+}
+
+// WithAllNamespaces 不限制命名空间，所有 service.namespace 形式的主机名都先查询北极星，
+// 北极星中不存在的服务在一段时间内不再重复查询
+func WithAllNamespaces() Option {
+	return func(t *Transport) {
+		t.namespaces = nil
+	}
+}
+
+// Transport 通过北极星发现服务实例的http.RoundTripper
+// 请求地址的主机名为 service.namespace 时，经过路由和负载均衡选择实例，主机名带端口或者为IP时直接发送，
+// 北极星中不存在对应服务时同样直接发送
+type Transport struct {
+	base          http.RoundTripper
+	consumer      polaris.ConsumerAPI
+	sourceService model.ServiceInfo
+	lbPolicy      string
+	retryCount    int
+	// 经过北极星发现的命名空间，为空时不限制
+	namespaces map[string]struct{}
+	// 北极星中不存在的服务，值为缓存的过期时间
+	notFoundServices sync.Map
+}
+
+// NewTransport 创建基于北极星的http.RoundTripper
+func NewTransport(sdkCtx api.SDKContext, opts ...Option) *Transport {
+	t := &Transport{
+		base:       http.DefaultTransport,
+		consumer:   polaris.NewConsumerAPIByContext(sdkCtx),
+		retryCount: defaultRetryCount,
+	}
+	WithNamespaces(DefaultNamespaces...)(t)
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// ParseHost 从 service.namespace 形式的主机名中解析服务名，最后一个点号之后为命名空间
+func ParseHost(host string) (model.ServiceKey, bool) {
+	if strings.Contains(host, ":") || net.ParseIP(host) != nil {
+		return model.ServiceKey{}, false
+	}
+	idx := strings.LastIndex(host, ".")
+	if idx <= 0 || idx == len(host)-1 {
+		return model.ServiceKey{}, false
+	}
+	return model.ServiceKey{Namespace: host[idx+1:], Service: host[:idx]}, true
+}
+
+// statusError 应答状态码为5xx，用于在重试时上报调用失败
+type statusError struct {
+	statusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("response status %d", e.statusCode)
+}
+
+// RoundTrip 选择服务实例并发送请求，网络异常或者应答为502、503、504时换一个实例重试
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	svcKey, ok := ParseHost(req.URL.Host)
+	if !ok || !t.isDiscoverNamespace(svcKey.Namespace) || t.isServiceNotFound(svcKey) {
+		return t.base.RoundTrip(req)
+	}
+	allReq := &polaris.GetAllInstancesRequest{}
+	allReq.Namespace = svcKey.Namespace
+	allReq.Service = svcKey.Service
+	allResp, err := t.consumer.GetAllInstancesWithContext(req.Context(), allReq)
+	if isNotFound(allResp, err) {
+		// 主机名不是北极星中的服务，按照普通域名发送
+		log.GetBaseLogger().Debugf("httppolaris: service %s not found, send request to %s directly",
+			svcKey, req.URL.Host)
+		t.notFoundServices.Store(svcKey, time.Now().Add(notFoundExpireDuration))
+		return t.base.RoundTrip(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+	retryReq := &polaris.RetryRequest{}
+	retryReq.Namespace = svcKey.Namespace
+	retryReq.Service = svcKey.Service
+	retryReq.SourceService = t.copySourceService()
+	retryReq.Method = req.URL.Path
+	retryReq.Arguments = RequestArguments(req)
+	retryReq.LbPolicy = t.lbPolicy
+	retryReq.Attempts = t.retryCount + 1
+	if !canReplay(req) {
+		retryReq.Attempts = 1
+	}
+	var (
+		resp *http.Response
+		body io.ReadCloser
+	)
+	retryReq.Retryable = func(err error) bool {
+		if statusErr, ok := err.(*statusError); ok && !needRetry(statusErr.statusCode) {
+			return false
+		}
+		if req.Body == nil || req.Body == http.NoBody {
+			return true
+		}
+		// 重试前重新获取请求体，无法获取时返回上一次的应答
+		if body != nil {
+			body.Close()
+		}
+		var bodyErr error
+		body, bodyErr = req.GetBody()
+		return bodyErr == nil
+	}
+	_, err = polaris.CallWithRetry(req.Context(), t.consumer, retryReq,
+		func(ctx context.Context, instance model.Instance) (int32, error) {
+			outReq := t.rewriteRequest(ctx, req, instance, body)
+			body = nil
+			if resp != nil {
+				resp.Body.Close()
+				resp = nil
+			}
+			var callErr error
+			resp, callErr = t.base.RoundTrip(outReq)
+			if callErr != nil {
+				log.GetBaseLogger().Warnf("httppolaris: request to %s of %s failed, err is %v",
+					instanceAddress(instance), svcKey, callErr)
+				return retCodeNoResponse, callErr
+			}
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return int32(resp.StatusCode), &statusError{statusCode: resp.StatusCode}
+			}
+			return int32(resp.StatusCode), nil
+		})
+	if resp != nil {
+		return resp, nil
+	}
+	if body != nil {
+		body.Close()
+	}
+	return nil, err
+}
+
+// isDiscoverNamespace 命名空间是否经过北极星发现
+func (t *Transport) isDiscoverNamespace(namespace string) bool {
+	if len(t.namespaces) == 0 {
+		return true
+	}
+	_, ok := t.namespaces[namespace]
+	return ok
+}
+
+// isServiceNotFound 服务是否在最近的查询中不存在
+func (t *Transport) isServiceNotFound(svcKey model.ServiceKey) bool {
+	value, ok := t.notFoundServices.Load(svcKey)
+	if !ok {
+		return false
+	}
+	if time.Now().Before(value.(time.Time)) {
+		return true
+	}
+	t.notFoundServices.Delete(svcKey)
+	return false
+}
+
+// isNotFound 查询实例的结果是否表示北极星中不存在对应的服务
+func isNotFound(resp *model.InstancesResponse, err error) bool {
+	if err != nil {
+		sdkErr, ok := err.(model.SDKError)
+		return ok && sdkErr.ErrorCode() == model.ErrCodeServiceNotFound
+	}
+	return resp.NotExists
+}
+
+// rewriteRequest 将请求地址替换为实例地址，Host头保持为原始的服务名，body不为空时替换为重新获取的请求体
+func (t *Transport) rewriteRequest(ctx context.Context, req *http.Request, instance model.Instance,
+	body io.ReadCloser) *http.Request {
+	outReq := req.Clone(ctx)
+	if body != nil {
+		outReq.Body = body
+	}
+	if len(outReq.Host) == 0 {
+		outReq.Host = req.URL.Host
+	}
+	outReq.URL.Host = instanceAddress(instance)
+	return outReq
+}
+
+// copySourceService 路由时会将请求参数写入主调服务的元数据，需要复制一份避免并发修改
+func (t *Transport) copySourceService() *model.ServiceInfo {
+	sourceService := &model.ServiceInfo{
+		Namespace: t.sourceService.Namespace,
+		Service:   t.sourceService.Service,
+		Metadata:  make(map[string]string, len(t.sourceService.Metadata)),
+	}
+	for key, value := range t.sourceService.Metadata {
+		sourceService.Metadata[key] = value
+	}
+	return sourceService
+}
+
+// needRetry 应答状态码是否需要换一个实例重试
+func needRetry(statusCode int) bool {
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// canReplay 请求体为空或者可以重新获取时才能重试
+func canReplay(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func instanceAddress(instance model.Instance) string {
+	return net.JoinHostPort(instance.GetHost(), strconv.Itoa(int(instance.GetPort())))
+}
+
+// RequestArguments 将请求的路径、请求头、查询参数以及Cookie转换为路由和限流的参数
+// 请求头名称转换为小写，同名的请求头和查询参数只取第一个值
+func RequestArguments(req *http.Request) []model.Argument {
+	arguments := make([]model.Argument, 0, len(req.Header)+len(req.URL.Query())+1)
+	arguments = append(arguments, model.BuildPathArgument(req.URL.Path))
+	for key, values := range req.Header {
+		if len(values) == 0 || key == "Cookie" {
+			continue
+		}
+		arguments = append(arguments, model.BuildHeaderArgument(strings.ToLower(key), values[0]))
+	}
+	for key, values := range req.URL.Query() {
+		if len(values) == 0 {
+			continue
+		}
+		arguments = append(arguments, model.BuildQueryArgument(key, values[0]))
+	}
+	for _, cookie := range req.Cookies() {
+		arguments = append(arguments, model.BuildCookieArgument(cookie.Name, cookie.Value))
+	}
+	return arguments
+}
@@ -59,6 +59,14 @@ type ConsumerConfig interface {
 	GetCircuitBreaker() CircuitBreakerConfig
 	// GetHealthCheck get health check config
 	GetHealthCheck() HealthCheckConfig
+	// GetWeightAdjuster get weight adjuster config
+	GetWeightAdjuster() WeightAdjusterConfig
+	// GetWarmup get instance warmup config
+	GetWarmup() WarmupConfig
+	// GetEffectiveWarmup 获取服务生效的实例预热配置，服务级配置优先
+	GetEffectiveWarmup(namespace string, service string) WarmupConfig
+	// IsAnyWarmupEnabled 全局或者任意服务级的实例预热配置是否开启
+	IsAnyWarmupEnabled() bool
 	// GetServiceSpecific 服务独立配置
 	GetServiceSpecific(namespace string, service string) ServiceSpecificConfig
 }
@@ -85,6 +93,8 @@ type ConfigFileConfig interface {
 	GetPropertiesValueCacheSize() int32
 	// GetPropertiesValueExpireTime 缓存的过期时间，默认为 60s
 	GetPropertiesValueExpireTime() int64
+	// GetGroupRefreshInterval 配置分组文件列表的刷新间隔
+	GetGroupRefreshInterval() time.Duration
 }
 
 // RateLimitConfig 限流相关配置.
@@ -258,6 +268,52 @@ type ServerConnectorConfig interface {
 	GetConnectionIdleTimeout() time.Duration
 	// SetConnectionIdleTimeout 设置连接会被释放的空闲的时长
 	SetConnectionIdleTimeout(time.Duration)
+	// GetTLS global.serverConnector.tls
+	// 与server通信的传输层安全配置
+	GetTLS() TLSConfig
+	// GetToken global.serverConnector.token
+	// 访问server的鉴权凭证，为空时不进行鉴权
+	GetToken() string
+	// SetToken 设置访问server的鉴权凭证
+	SetToken(string)
+	// GetTokenProvider global.serverConnector.tokenProvider
+	// 鉴权凭证提供者的名称，为空时使用token配置项
+	GetTokenProvider() string
+	// SetTokenProvider 设置鉴权凭证提供者的名称
+	SetTokenProvider(string)
+}
+
+// TLSConfig 与server通信的传输层安全配置.
+type TLSConfig interface {
+	BaseConfig
+	// IsEnable 是否启用TLS
+	IsEnable() bool
+	// SetEnable 设置是否启用TLS
+	SetEnable(bool)
+	// GetCaFile CA证书文件路径，用于校验server证书，为空时使用系统根证书
+	GetCaFile() string
+	// SetCaFile 设置CA证书文件路径
+	SetCaFile(string)
+	// GetCertFile 客户端证书文件路径，配置后启用双向认证
+	GetCertFile() string
+	// SetCertFile 设置客户端证书文件路径
+	SetCertFile(string)
+	// GetKeyFile 客户端私钥文件路径
+	GetKeyFile() string
+	// SetKeyFile 设置客户端私钥文件路径
+	SetKeyFile(string)
+	// GetServerName 校验server证书时使用的域名，为空时使用连接地址
+	GetServerName() string
+	// SetServerName 设置校验server证书时使用的域名
+	SetServerName(string)
+	// IsInsecureSkipVerify 是否跳过server证书校验，仅用于测试环境
+	IsInsecureSkipVerify() bool
+	// SetInsecureSkipVerify 设置是否跳过server证书校验
+	SetInsecureSkipVerify(bool)
+	// GetReloadInterval 证书文件变更的检查周期，证书轮转后新建的连接使用新证书
+	GetReloadInterval() time.Duration
+	// SetReloadInterval 设置证书文件变更的检查周期
+	SetReloadInterval(time.Duration)
 }
 
 // LocalCacheConfig 本地缓存相关配置项.
@@ -350,6 +406,16 @@ type NearbyConfig interface {
 	GetUnhealthyPercentToDegrade() int
 	// SetUnhealthyPercentToDegrade 设置触发降级匹配的不健康实例比例,consumer.serviceRouter.plugin.nearbyBasedRouter.unhealthyPercentToDegrade
 	SetUnhealthyPercentToDegrade(u int)
+	// GetStrategy 获取就近匹配策略，支持filter和localityWeighted,
+	// consumer.serviceRouter.plugin.nearbyBasedRouter.strategy
+	GetStrategy() string
+	// SetStrategy 设置就近匹配策略,consumer.serviceRouter.plugin.nearbyBasedRouter.strategy
+	SetStrategy(strategy string)
+	// GetOverprovisioningFactor 获取localityWeighted策略下的容量超配系数,
+	// consumer.serviceRouter.plugin.nearbyBasedRouter.overprovisioningFactor
+	GetOverprovisioningFactor() float64
+	// SetOverprovisioningFactor 设置localityWeighted策略下的容量超配系数
+	SetOverprovisioningFactor(factor float64)
 }
 
 // ServiceRouterConfig 服务路由相关配置项.
@@ -428,6 +494,60 @@ type CircuitBreakerConfig interface {
 	GetErrorRateConfig() ErrorRateConfig
 }
 
+// WeightAdjusterConfig 动态权重调整配置.
+type WeightAdjusterConfig interface {
+	BaseConfig
+	// IsEnable 是否启用动态权重调整
+	IsEnable() bool
+	// SetEnable 设置是否启用动态权重调整
+	SetEnable(bool)
+	// GetType 动态权重调整插件名
+	GetType() string
+	// SetType 设置动态权重调整插件名
+	SetType(string)
+	// GetAdjustPeriod 定时调整动态权重的周期
+	GetAdjustPeriod() time.Duration
+	// SetAdjustPeriod 设置定时调整动态权重的周期
+	SetAdjustPeriod(time.Duration)
+	// GetDelayFactor 时延对权重的影响倍率
+	GetDelayFactor() float64
+	// SetDelayFactor 设置时延对权重的影响倍率
+	SetDelayFactor(float64)
+	// GetRateFactor 错误率对权重的影响倍率
+	GetRateFactor() float64
+	// SetRateFactor 设置错误率对权重的影响倍率
+	SetRateFactor(float64)
+	// GetMinWeightPercent 动态权重相对静态权重的最低百分比，避免实例被完全摘除
+	GetMinWeightPercent() int
+	// SetMinWeightPercent 设置动态权重相对静态权重的最低百分比
+	SetMinWeightPercent(int)
+}
+
+// WarmupConfig 新增实例预热配置.
+type WarmupConfig interface {
+	BaseConfig
+	// IsEnable 是否启用实例预热
+	IsEnable() bool
+	// SetEnable 设置是否启用实例预热
+	SetEnable(bool)
+	// GetWindow 预热时长，实例在该时间窗口内权重从下限逐步提升到完整权重
+	GetWindow() time.Duration
+	// SetWindow 设置预热时长
+	SetWindow(time.Duration)
+	// GetCurve 预热曲线，支持linear和aggressive
+	GetCurve() string
+	// SetCurve 设置预热曲线
+	SetCurve(string)
+	// GetMinWeightPercent 预热开始时权重相对完整权重的百分比
+	GetMinWeightPercent() int
+	// SetMinWeightPercent 设置预热开始时权重相对完整权重的百分比
+	SetMinWeightPercent(int)
+	// GetStartTimeMetadataKey 实例元数据中记录启动时间的key，为空时以SDK首次发现实例的时间作为预热开始时间
+	GetStartTimeMetadataKey() string
+	// SetStartTimeMetadataKey 设置实例元数据中记录启动时间的key
+	SetStartTimeMetadataKey(string)
+}
+
 // Configuration 全量配置对象.
 type Configuration interface {
 	BaseConfig
@@ -486,6 +606,8 @@ type ServiceSpecificConfig interface {
 	GetServiceCircuitBreaker() CircuitBreakerConfig
 
 	GetServiceRouter() ServiceRouterConfig
+
+	GetServiceWarmup() WarmupConfig
 }
 
 // ConfigConnectorConfig 配置中心连接相关的配置.
@@ -52,6 +52,15 @@ type ConfigConnectorConfigImpl struct {
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
 
 	ConnectorType string `yaml:"connectorType" json:"connectorType"`
+
+	// 传输层安全配置，未启用时使用global.serverConnector.tls
+	TLS *TLSConfigImpl `yaml:"tls" json:"tls"`
+
+	// 访问配置中心的鉴权凭证，与tokenProvider均为空时使用global.serverConnector的鉴权配置
+	Token string `yaml:"token" json:"token"`
+
+	// 鉴权凭证提供者的名称
+	TokenProvider string `yaml:"tokenProvider" json:"tokenProvider"`
 }
 
 // GetAddresses config.configConnector.addresses.
@@ -158,6 +167,34 @@ func (c *ConfigConnectorConfigImpl) SetConnectorType(connectorType string) {
 	c.ConnectorType = connectorType
 }
 
+// GetTLS config.configConnector.tls
+// 传输层安全配置.
+func (c *ConfigConnectorConfigImpl) GetTLS() TLSConfig {
+	return c.TLS
+}
+
+// GetToken config.configConnector.token
+// 访问配置中心的鉴权凭证.
+func (c *ConfigConnectorConfigImpl) GetToken() string {
+	return c.Token
+}
+
+// SetToken 设置访问配置中心的鉴权凭证.
+func (c *ConfigConnectorConfigImpl) SetToken(token string) {
+	c.Token = token
+}
+
+// GetTokenProvider config.configConnector.tokenProvider
+// 鉴权凭证提供者的名称.
+func (c *ConfigConnectorConfigImpl) GetTokenProvider() string {
+	return c.TokenProvider
+}
+
+// SetTokenProvider 设置鉴权凭证提供者的名称.
+func (c *ConfigConnectorConfigImpl) SetTokenProvider(name string) {
+	c.TokenProvider = name
+}
+
 // Verify 检验ConfigConnector配置.
 func (c *ConfigConnectorConfigImpl) Verify() error {
 	if nil == c {
@@ -186,6 +223,9 @@ func (c *ConfigConnectorConfigImpl) Verify() error {
 	if len(c.ConnectorType) == 0 {
 		errs = multierror.Append(errs, fmt.Errorf("config.configConnector.connectorType is empty"))
 	}
+	if err := c.TLS.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
@@ -218,11 +258,16 @@ func (c *ConfigConnectorConfigImpl) SetDefault() {
 	if len(c.ConnectorType) == 0 {
 		c.ConnectorType = DefaultConnectorType
 	}
+	if nil == c.TLS {
+		c.TLS = &TLSConfigImpl{}
+	}
+	c.TLS.SetDefault()
 	c.Plugin.SetDefault(common.TypeConfigConnector)
 }
 
 // Init 配置初始化.
 func (c *ConfigConnectorConfigImpl) Init() {
+	c.TLS = &TLSConfigImpl{}
 	c.Plugin = PluginConfigs{}
 	c.Plugin.Init(common.TypeConfigConnector)
 }
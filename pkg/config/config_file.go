@@ -21,9 +21,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
 )
 
 // DefaultConfigFileEnable 默认打开配置中心能力
@@ -37,6 +40,8 @@ type ConfigFileConfigImpl struct {
 	Enable                    *bool  `yaml:"enable" json:"enable"`
 	PropertiesValueCacheSize  *int32 `yaml:"propertiesValueCacheSize" json:"propertiesValueCacheSize"`
 	PropertiesValueExpireTime *int64 `yaml:"propertiesValueExpireTime" json:"propertiesValueExpireTime"`
+	// 配置分组文件列表的刷新间隔
+	GroupRefreshInterval *time.Duration `yaml:"groupRefreshInterval" json:"groupRefreshInterval"`
 }
 
 // GetConfigConnectorConfig config.configConnector前缀开头的所有配置项.
@@ -79,6 +84,16 @@ func (c *ConfigFileConfigImpl) SetPropertiesValueExpireTime(propertiesValueExpir
 	c.PropertiesValueExpireTime = &propertiesValueExpireTime
 }
 
+// GetGroupRefreshInterval config.groupRefreshInterval.
+func (c *ConfigFileConfigImpl) GetGroupRefreshInterval() time.Duration {
+	return *c.GroupRefreshInterval
+}
+
+// SetGroupRefreshInterval 设置配置分组文件列表的刷新间隔.
+func (c *ConfigFileConfigImpl) SetGroupRefreshInterval(interval time.Duration) {
+	c.GroupRefreshInterval = &interval
+}
+
 // Verify 检验ConfigConnector配置.
 func (c *ConfigFileConfigImpl) Verify() error {
 	if c == nil {
@@ -100,6 +115,10 @@ func (c *ConfigFileConfigImpl) Verify() error {
 	if c.PropertiesValueExpireTime != nil && *c.PropertiesValueExpireTime < 0 {
 		errs = multierror.Append(errs, fmt.Errorf("config.propertiesValueExpireTime %v is invalid", c.PropertiesValueExpireTime))
 	}
+	if c.GroupRefreshInterval != nil && *c.GroupRefreshInterval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs, fmt.Errorf("config.groupRefreshInterval %v is less than minimal timing interval %v",
+			*c.GroupRefreshInterval, DefaultMinTimingInterval))
+	}
 	return errs
 }
 
@@ -113,8 +132,11 @@ func (c *ConfigFileConfigImpl) SetDefault() {
 	if c.PropertiesValueCacheSize == nil {
 		c.PropertiesValueCacheSize = proto.Int32(int32(DefaultPropertiesValueCacheSize))
 	}
-	if c.PropertiesValueCacheSize == nil {
-		c.PropertiesValueExpireTime = proto.Int64(int64(DefaultPropertiesValueCacheSize))
+	if c.PropertiesValueExpireTime == nil {
+		c.PropertiesValueExpireTime = proto.Int64(int64(DefaultPropertiesValueExpireTime))
+	}
+	if c.GroupRefreshInterval == nil {
+		c.GroupRefreshInterval = model.ToDurationPtr(DefaultConfigGroupRefreshInterval)
 	}
 }
 
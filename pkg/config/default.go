@@ -55,6 +55,8 @@ const (
 	DefaultRequestQueueSize int = 1000
 	// DefaultServerSwitchInterval 默认server的切换时间时间.
 	DefaultServerSwitchInterval = 10 * time.Minute
+	// DefaultTLSReloadInterval 默认TLS证书文件变更的检查周期.
+	DefaultTLSReloadInterval = 1 * time.Minute
 	// DefaultCachePersistDir 默认缓存持久化存储目录.
 	DefaultCachePersistDir string = "./polaris/backup"
 	// DefaultPersistMaxWriteRetry 持久化缓存写文件的默认重试次数.
@@ -71,6 +73,30 @@ const (
 	MinCircuitBreakerCheckPeriod = 1 * time.Second
 	// DefaultCircuitBreakerEnabled 熔断器默认开启与否.
 	DefaultCircuitBreakerEnabled bool = true
+	// DefaultWeightAdjusterEnabled 动态权重调整默认开启与否.
+	DefaultWeightAdjusterEnabled bool = false
+	// DefaultWeightAdjustPeriod 默认动态权重调整周期.
+	DefaultWeightAdjustPeriod = 5 * time.Second
+	// MinWeightAdjustPeriod 最低动态权重调整周期.
+	MinWeightAdjustPeriod = 1 * time.Second
+	// DefaultWeightAdjustDelayFactor 默认时延对权重的影响倍率.
+	DefaultWeightAdjustDelayFactor float64 = 1
+	// DefaultWeightAdjustRateFactor 默认错误率对权重的影响倍率.
+	DefaultWeightAdjustRateFactor float64 = 7
+	// DefaultMinWeightPercent 默认动态权重相对静态权重的最低百分比.
+	DefaultMinWeightPercent int = 10
+	// DefaultWarmupEnabled 实例预热默认开启与否.
+	DefaultWarmupEnabled bool = false
+	// DefaultWarmupWindow 默认实例预热时长.
+	DefaultWarmupWindow = 60 * time.Second
+	// MinWarmupWindow 最低实例预热时长.
+	MinWarmupWindow = 1 * time.Second
+	// DefaultWarmupMinWeightPercent 默认预热开始时权重相对完整权重的百分比.
+	DefaultWarmupMinWeightPercent int = 10
+	// WarmupCurveLinear 线性预热曲线，权重随时间线性增长.
+	WarmupCurveLinear = "linear"
+	// WarmupCurveAggressive 激进预热曲线，权重随时间的平方根增长，前期增长较快.
+	WarmupCurveAggressive = "aggressive"
 	// DefaultRecoverAllEnabled 服务路由的全死全活默认开启与否.
 	DefaultRecoverAllEnabled bool = true
 	// DefaultPercentOfMinInstances 路由至少返回节点数百分比.
@@ -133,6 +159,8 @@ const (
 	DefaultPropertiesValueCacheSize = 100
 	// DefaultPropertiesValueExpireTime 默认类型转化缓存的过期时间，1分钟.
 	DefaultPropertiesValueExpireTime = 60000
+	// DefaultConfigGroupRefreshInterval 默认配置分组的刷新间隔.
+	DefaultConfigGroupRefreshInterval = 10 * time.Second
 	// DefaultConnectorType 默认连接器类型.
 	DefaultConnectorType = "polaris"
 	// DefaultConfigConnectorAddresses 默认连接器类型.
@@ -177,6 +205,8 @@ const (
 	DefaultServiceRouterCanary string = "canaryRouter"
 	// DefaultServiceRouterZeroProtect 零实例保护
 	DefaultServiceRouterZeroProtect string = "zeroProtectRouter"
+	// DefaultServiceRouterPriority 基于实例优先级的路由
+	DefaultServiceRouterPriority string = "priorityRouter"
 
 	// DefaultLoadBalancerWR 默认负载均衡器,权重随机.
 	DefaultLoadBalancerWR string = "weightedRandom"
@@ -188,12 +218,24 @@ const (
 	DefaultLoadBalancerL5CST string = "l5cst"
 	// DefaultLoadBalancerHash 负载均衡器,普通hash.
 	DefaultLoadBalancerHash string = "hash"
+	// DefaultLoadBalancerLeastRequest 负载均衡器,最少在途请求.
+	DefaultLoadBalancerLeastRequest string = "leastRequest"
+	// DefaultLoadBalancerWRR 负载均衡器,平滑加权轮询.
+	DefaultLoadBalancerWRR string = "weightedRoundRobin"
 	// DefaultCircuitBreakerErrRate 默认错误率熔断器.
 	DefaultCircuitBreakerErrRate string = "errorRate"
 	// DefaultCircuitBreakerErrCount 默认持续错误熔断器.
 	DefaultCircuitBreakerErrCount string = "errorCount"
 	// DefaultCircuitBreakerErrCheck 默认错误探测熔断器.
 	DefaultCircuitBreakerErrCheck string = "errorCheck"
+	// DefaultCircuitBreakerRuleBased 基于服务端熔断规则的熔断器.
+	DefaultCircuitBreakerRuleBased string = "ruleBased"
+	// DefaultCircuitBreakerOutlierDetection 基于成功率离群检测的熔断器.
+	DefaultCircuitBreakerOutlierDetection string = "outlierDetection"
+	// DefaultCircuitBreakerSlowCallRate 基于慢调用比例的熔断器.
+	DefaultCircuitBreakerSlowCallRate string = "slowCallRate"
+	// DefaultWeightAdjuster 默认动态权重调整器.
+	DefaultWeightAdjuster string = "rateDelayAdjuster"
 	// DefaultTCPHealthCheck 默认TCP探测器.
 	DefaultTCPHealthCheck string = "tcp"
 	// DefaultUDPHealthCheck 默认UDP探测器.
@@ -231,6 +273,16 @@ const (
 	AllLevel          = ""
 )
 
+// 就近路由的匹配策略.
+const (
+	// NearbyStrategyFilter 按照就近级别严格过滤，健康实例比例过低时整体降级
+	NearbyStrategyFilter = "filter"
+	// NearbyStrategyLocalityWeighted 按照各地域的健康容量分配流量，本地容量不足时按比例溢出到邻近地域
+	NearbyStrategyLocalityWeighted = "localityWeighted"
+	// DefaultOverprovisioningFactor 默认的容量超配系数，本地健康容量乘以该系数不低于100%时流量全部留在本地
+	DefaultOverprovisioningFactor float64 = 1.4
+)
+
 const (
 	// DefaultStatReporter .
 	DefaultStatReporter = "stat2Monitor"
@@ -471,6 +523,8 @@ func (c *ConsumerConfigImpl) Init() {
 	c.Loadbalancer.Init()
 	c.HealthCheck = &HealthCheckConfigImpl{}
 	c.HealthCheck.Init()
+	c.WeightAdjuster = &WeightAdjusterConfigImpl{}
+	c.Warmup = &WarmupConfigImpl{}
 }
 
 // Verify 检验consumerConfig配置.
@@ -495,6 +549,28 @@ func (c *ConsumerConfigImpl) Verify() error {
 	if err = c.HealthCheck.Verify(); err != nil {
 		errs = multierror.Append(errs, err)
 	}
+	if err = c.WeightAdjuster.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err = c.Warmup.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	for _, specific := range c.ServicesSpecific {
+		if nil != specific.CircuitBreaker {
+			if err = specific.CircuitBreaker.Verify(); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+			if err = specific.CircuitBreaker.Plugin.verifyWithGlobal(c.CircuitBreaker.Plugin); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+		if nil == specific.Warmup {
+			continue
+		}
+		if err = specific.Warmup.Verify(); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
 	return errs
 }
 
@@ -505,6 +581,14 @@ func (c *ConsumerConfigImpl) SetDefault() {
 	c.ServiceRouter.SetDefault()
 	c.CircuitBreaker.SetDefault()
 	c.HealthCheck.SetDefault()
+	c.WeightAdjuster.SetDefault()
+	c.Warmup.SetDefault()
+	for _, specific := range c.ServicesSpecific {
+		specific.setCircuitBreakerDefault(c.CircuitBreaker)
+		if nil != specific.Warmup {
+			specific.Warmup.inherit(c.Warmup)
+		}
+	}
 }
 
 // Init 初始化整体配置对象.
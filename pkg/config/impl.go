@@ -98,6 +98,8 @@ type ConsumerConfigImpl struct {
 	Loadbalancer     *LoadBalancerConfigImpl   `yaml:"loadbalancer" json:"loadbalancer"`
 	CircuitBreaker   *CircuitBreakerConfigImpl `yaml:"circuitBreaker" json:"circuitBreaker"`
 	HealthCheck      *HealthCheckConfigImpl    `yaml:"healthCheck" json:"healthCheck"`
+	WeightAdjuster   *WeightAdjusterConfigImpl `yaml:"weightAdjuster" json:"weightAdjuster"`
+	Warmup           *WarmupConfigImpl         `yaml:"warmup" json:"warmup"`
 	ServicesSpecific []*ServiceSpecific        `yaml:"servicesSpecific" json:"servicesSpecific"`
 }
 
@@ -126,6 +128,39 @@ func (c *ConsumerConfigImpl) GetHealthCheck() HealthCheckConfig {
 	return c.HealthCheck
 }
 
+// GetWeightAdjuster consumer.weightAdjuster前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetWeightAdjuster() WeightAdjusterConfig {
+	return c.WeightAdjuster
+}
+
+// GetWarmup consumer.warmup前缀开头的所有配置.
+func (c *ConsumerConfigImpl) GetWarmup() WarmupConfig {
+	return c.Warmup
+}
+
+// GetEffectiveWarmup 获取服务生效的实例预热配置，服务级配置优先.
+func (c *ConsumerConfigImpl) GetEffectiveWarmup(namespace string, service string) WarmupConfig {
+	if specific := c.GetServiceSpecific(namespace, service); nil != specific {
+		if warmupConfig := specific.GetServiceWarmup(); nil != warmupConfig {
+			return warmupConfig
+		}
+	}
+	return c.Warmup
+}
+
+// IsAnyWarmupEnabled 全局或者任意服务级的实例预热配置是否开启.
+func (c *ConsumerConfigImpl) IsAnyWarmupEnabled() bool {
+	if c.Warmup.IsEnable() {
+		return true
+	}
+	for _, specific := range c.ServicesSpecific {
+		if nil != specific.Warmup && specific.Warmup.IsEnable() {
+			return true
+		}
+	}
+	return false
+}
+
 // GetServiceSpecific 服务独立配置.
 func (c *ConsumerConfigImpl) GetServiceSpecific(namespace string, service string) ServiceSpecificConfig {
 	for _, v := range c.ServicesSpecific {
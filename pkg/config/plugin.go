@@ -155,6 +155,47 @@ func (p PluginConfigs) SetDefault(typ common.Type) {
 	}
 }
 
+// PluginConfigInheritor 服务级的插件配置可选实现该接口，继承全局插件配置中未设置的配置项
+type PluginConfigInheritor interface {
+	// Inherit 继承全局插件配置中未设置的配置项，在设置默认值之前调用
+	Inherit(global BaseConfig)
+	// VerifyWithGlobal 校验服务级插件配置与全局插件配置之间的约束
+	VerifyWithGlobal(global BaseConfig) error
+}
+
+// inherit 服务级插件配置中未设置的配置项，继承全局的插件配置.
+func (p PluginConfigs) inherit(typ common.Type, global PluginConfigs) {
+	for pluginName, configType := range getPluginConfigTypes(typ) {
+		globalCfg, ok := global[pluginName].(BaseConfig)
+		if !ok {
+			continue
+		}
+		cfg := convertFromTextValues(configType, p[pluginName])
+		if inheritor, ok := cfg.(PluginConfigInheritor); ok {
+			inheritor.Inherit(globalCfg)
+		}
+		p[pluginName] = cfg
+	}
+}
+
+// verifyWithGlobal 校验服务级插件配置与全局插件配置之间的约束.
+func (p PluginConfigs) verifyWithGlobal(global PluginConfigs) error {
+	for name, cfgValue := range p {
+		inheritor, ok := cfgValue.(PluginConfigInheritor)
+		if !ok {
+			continue
+		}
+		globalCfg, ok := global[name].(BaseConfig)
+		if !ok {
+			continue
+		}
+		if err := inheritor.VerifyWithGlobal(globalCfg); err != nil {
+			return fmt.Errorf("fail to verify plugin %s config, err is %v", name, err)
+		}
+	}
+	return nil
+}
+
 // Verify 校验插件配置.
 func (p PluginConfigs) Verify() error {
 	for name, cfgValue := range p {
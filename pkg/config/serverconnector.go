@@ -48,6 +48,15 @@ type ServerConnectorConfigImpl struct {
 
 	ReconnectInterval *time.Duration `yaml:"reconnectInterval" json:"reconnectInterval"`
 
+	// 传输层安全配置，同时作用于服务发现、配置中心以及限流集群的连接，配置中心可通过config.configConnector.tls单独配置
+	TLS *TLSConfigImpl `yaml:"tls" json:"tls"`
+
+	// 访问server的鉴权凭证，同时作用于服务发现、配置中心以及限流集群的请求
+	Token string `yaml:"token" json:"token"`
+
+	// 鉴权凭证提供者的名称，为空时使用token配置项，可通过network.RegisterTokenProvider注册自定义的提供者
+	TokenProvider string `yaml:"tokenProvider" json:"tokenProvider"`
+
 	Plugin PluginConfigs `yaml:"plugin" json:"plugin"`
 }
 
@@ -138,6 +147,34 @@ func (s *ServerConnectorConfigImpl) SetReconnectInterval(interval time.Duration)
 	s.ReconnectInterval = &interval
 }
 
+// GetTLS global.serverConnector.tls
+// 传输层安全配置.
+func (s *ServerConnectorConfigImpl) GetTLS() TLSConfig {
+	return s.TLS
+}
+
+// GetToken global.serverConnector.token
+// 访问server的鉴权凭证.
+func (s *ServerConnectorConfigImpl) GetToken() string {
+	return s.Token
+}
+
+// SetToken 设置访问server的鉴权凭证.
+func (s *ServerConnectorConfigImpl) SetToken(token string) {
+	s.Token = token
+}
+
+// GetTokenProvider global.serverConnector.tokenProvider
+// 鉴权凭证提供者的名称.
+func (s *ServerConnectorConfigImpl) GetTokenProvider() string {
+	return s.TokenProvider
+}
+
+// SetTokenProvider 设置鉴权凭证提供者的名称.
+func (s *ServerConnectorConfigImpl) SetTokenProvider(name string) {
+	s.TokenProvider = name
+}
+
 // GetPluginConfig global.serverConnector.plugin.
 func (s *ServerConnectorConfigImpl) GetPluginConfig(pluginName string) BaseConfig {
 	cfgValue, ok := s.Plugin[pluginName]
@@ -177,6 +214,9 @@ func (s *ServerConnectorConfigImpl) Verify() error {
 				" is less than or equal to global.serverConnector.connectionIdleTimeout %v",
 				*s.ServerSwitchInterval, *s.ConnectionIdleTimeout))
 	}
+	if err := s.TLS.Verify(); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
@@ -203,11 +243,16 @@ func (s *ServerConnectorConfigImpl) SetDefault() {
 	if len(s.Protocol) == 0 {
 		s.Protocol = DefaultServerConnector
 	}
+	if nil == s.TLS {
+		s.TLS = &TLSConfigImpl{}
+	}
+	s.TLS.SetDefault()
 	s.Plugin.SetDefault(common.TypeServerConnector)
 }
 
 // Init 配置初始化.
 func (s *ServerConnectorConfigImpl) Init() {
+	s.TLS = &TLSConfigImpl{}
 	s.Plugin = PluginConfigs{}
 	s.Plugin.Init(common.TypeServerConnector)
 }
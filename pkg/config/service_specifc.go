@@ -19,6 +19,8 @@ package config
 
 import (
 	"github.com/modern-go/reflect2"
+
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 )
 
 // ServiceSpecific .
@@ -27,6 +29,8 @@ type ServiceSpecific struct {
 	Service        string                    `yaml:"service" json:"service"`
 	ServiceRouter  *ServiceRouterConfigImpl  `yaml:"serviceRouter" json:"serviceRouter"`
 	CircuitBreaker *CircuitBreakerConfigImpl `yaml:"circuitBreaker" json:"circuitBreaker"`
+	// Warmup 服务级的实例预热配置，未设置的配置项继承consumer.warmup
+	Warmup *WarmupConfigImpl `yaml:"warmup" json:"warmup"`
 }
 
 // ServicesSpecificImpl .
@@ -53,9 +57,9 @@ func (s *ServiceSpecific) SetDefault() {
 	s.ServiceRouter.SetDefault()
 }
 
-// GetServiceCircuitBreaker 获取熔断器
+// GetServiceCircuitBreaker 获取熔断器，未配置时返回nil
 func (s *ServiceSpecific) GetServiceCircuitBreaker() CircuitBreakerConfig {
-	if s == nil || reflect2.IsNil(s) {
+	if s == nil || reflect2.IsNil(s) || nil == s.CircuitBreaker {
 		return nil
 	}
 
@@ -66,3 +70,24 @@ func (s *ServiceSpecific) GetServiceCircuitBreaker() CircuitBreakerConfig {
 func (s *ServiceSpecific) GetServiceRouter() ServiceRouterConfig {
 	return s.ServiceRouter
 }
+
+// GetServiceWarmup 获取实例预热配置，未配置时返回nil
+func (s *ServiceSpecific) GetServiceWarmup() WarmupConfig {
+	if s == nil || reflect2.IsNil(s) || nil == s.Warmup {
+		return nil
+	}
+	return s.Warmup
+}
+
+// setCircuitBreakerDefault 设置服务级熔断配置的默认值，插件配置从配置文件的原始值转换为插件配置对象，
+// 未设置的插件配置项继承全局的熔断插件配置
+func (s *ServiceSpecific) setCircuitBreakerDefault(global *CircuitBreakerConfigImpl) {
+	if nil == s.CircuitBreaker {
+		return
+	}
+	if nil == s.CircuitBreaker.Plugin {
+		s.CircuitBreaker.Plugin = PluginConfigs{}
+	}
+	s.CircuitBreaker.Plugin.inherit(common.TypeCircuitBreaker, global.Plugin)
+	s.CircuitBreaker.SetDefault()
+}
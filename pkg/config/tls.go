@@ -0,0 +1,166 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// DefaultTLSEnable 默认不启用TLS.
+var DefaultTLSEnable = false
+
+// TLSConfigImpl 与server通信的传输层安全配置.
+type TLSConfigImpl struct {
+	// 是否启用TLS
+	Enable *bool `yaml:"enable" json:"enable"`
+	// CA证书文件路径，用于校验server证书，为空时使用系统根证书
+	CaFile string `yaml:"caFile" json:"caFile"`
+	// 客户端证书文件路径，配置后启用双向认证
+	CertFile string `yaml:"certFile" json:"certFile"`
+	// 客户端私钥文件路径
+	KeyFile string `yaml:"keyFile" json:"keyFile"`
+	// 校验server证书时使用的域名，为空时使用连接地址
+	ServerName string `yaml:"serverName" json:"serverName"`
+	// 是否跳过server证书校验，仅用于测试环境
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify" json:"insecureSkipVerify"`
+	// 证书文件变更的检查周期
+	ReloadInterval *time.Duration `yaml:"reloadInterval" json:"reloadInterval"`
+}
+
+// IsEnable global.serverConnector.tls.enable
+// 是否启用TLS.
+func (t *TLSConfigImpl) IsEnable() bool {
+	return *t.Enable
+}
+
+// SetEnable 设置是否启用TLS.
+func (t *TLSConfigImpl) SetEnable(enable bool) {
+	t.Enable = &enable
+}
+
+// GetCaFile global.serverConnector.tls.caFile
+// CA证书文件路径.
+func (t *TLSConfigImpl) GetCaFile() string {
+	return t.CaFile
+}
+
+// SetCaFile 设置CA证书文件路径.
+func (t *TLSConfigImpl) SetCaFile(caFile string) {
+	t.CaFile = caFile
+}
+
+// GetCertFile global.serverConnector.tls.certFile
+// 客户端证书文件路径.
+func (t *TLSConfigImpl) GetCertFile() string {
+	return t.CertFile
+}
+
+// SetCertFile 设置客户端证书文件路径.
+func (t *TLSConfigImpl) SetCertFile(certFile string) {
+	t.CertFile = certFile
+}
+
+// GetKeyFile global.serverConnector.tls.keyFile
+// 客户端私钥文件路径.
+func (t *TLSConfigImpl) GetKeyFile() string {
+	return t.KeyFile
+}
+
+// SetKeyFile 设置客户端私钥文件路径.
+func (t *TLSConfigImpl) SetKeyFile(keyFile string) {
+	t.KeyFile = keyFile
+}
+
+// GetServerName global.serverConnector.tls.serverName
+// 校验server证书时使用的域名.
+func (t *TLSConfigImpl) GetServerName() string {
+	return t.ServerName
+}
+
+// SetServerName 设置校验server证书时使用的域名.
+func (t *TLSConfigImpl) SetServerName(serverName string) {
+	t.ServerName = serverName
+}
+
+// IsInsecureSkipVerify global.serverConnector.tls.insecureSkipVerify
+// 是否跳过server证书校验.
+func (t *TLSConfigImpl) IsInsecureSkipVerify() bool {
+	return t.InsecureSkipVerify
+}
+
+// SetInsecureSkipVerify 设置是否跳过server证书校验.
+func (t *TLSConfigImpl) SetInsecureSkipVerify(skip bool) {
+	t.InsecureSkipVerify = skip
+}
+
+// GetReloadInterval global.serverConnector.tls.reloadInterval
+// 证书文件变更的检查周期.
+func (t *TLSConfigImpl) GetReloadInterval() time.Duration {
+	return *t.ReloadInterval
+}
+
+// SetReloadInterval 设置证书文件变更的检查周期.
+func (t *TLSConfigImpl) SetReloadInterval(interval time.Duration) {
+	t.ReloadInterval = &interval
+}
+
+// Verify 检验TLS配置.
+func (t *TLSConfigImpl) Verify() error {
+	if nil == t {
+		return errors.New("TLSConfig is nil")
+	}
+	if !t.IsEnable() {
+		return nil
+	}
+	var errs error
+	if (len(t.CertFile) == 0) != (len(t.KeyFile) == 0) {
+		errs = multierror.Append(errs,
+			fmt.Errorf("global.serverConnector.tls.certFile and keyFile must be configured together"))
+	}
+	for _, file := range []string{t.CaFile, t.CertFile, t.KeyFile} {
+		if len(file) == 0 {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("global.serverConnector.tls file %s is invalid: %v", file, err))
+		}
+	}
+	if *t.ReloadInterval < DefaultMinTimingInterval {
+		errs = multierror.Append(errs,
+			fmt.Errorf("global.serverConnector.tls.reloadInterval %v is less than minimal timing interval %v",
+				*t.ReloadInterval, DefaultMinTimingInterval))
+	}
+	return errs
+}
+
+// SetDefault 设置TLS配置的默认值.
+func (t *TLSConfigImpl) SetDefault() {
+	if nil == t.Enable {
+		t.Enable = &DefaultTLSEnable
+	}
+	if nil == t.ReloadInterval {
+		t.ReloadInterval = model.ToDurationPtr(DefaultTLSReloadInterval)
+	}
+}
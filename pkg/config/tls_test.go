@@ -0,0 +1,62 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTLSConfigVerify 测试客户端证书以及私钥必须同时配置
+func TestTLSConfigVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "polaris-tls-config")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	assert.Nil(t, ioutil.WriteFile(certFile, []byte("cert"), 0600))
+	assert.Nil(t, ioutil.WriteFile(keyFile, []byte("key"), 0600))
+
+	cfg := &TLSConfigImpl{}
+	cfg.SetDefault()
+	// 未启用时不校验
+	cfg.SetCertFile(certFile)
+	assert.Nil(t, cfg.Verify())
+
+	cfg.SetEnable(true)
+	assert.NotNil(t, cfg.Verify())
+
+	cfg.SetCertFile("")
+	cfg.SetKeyFile(keyFile)
+	assert.NotNil(t, cfg.Verify())
+
+	cfg.SetCertFile(certFile)
+	assert.Nil(t, cfg.Verify())
+
+	// 证书文件不存在
+	cfg.SetCaFile(filepath.Join(dir, "ca.pem"))
+	assert.NotNil(t, cfg.Verify())
+	cfg.SetCaFile("")
+
+	cfg.SetReloadInterval(0)
+	assert.NotNil(t, cfg.Verify())
+}
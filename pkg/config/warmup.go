@@ -0,0 +1,148 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// WarmupConfigImpl 新增实例预热相关配置
+type WarmupConfigImpl struct {
+	// Enable 是否启用实例预热
+	Enable *bool `yaml:"enable" json:"enable"`
+	// Window 预热时长，实例在该时间窗口内权重从下限逐步提升到完整权重
+	Window *time.Duration `yaml:"window" json:"window"`
+	// Curve 预热曲线，linear为线性增长，aggressive为前期快速增长
+	Curve string `yaml:"curve" json:"curve"`
+	// MinWeightPercent 预热开始时权重相对完整权重的百分比
+	MinWeightPercent int `yaml:"minWeightPercent" json:"minWeightPercent"`
+	// StartTimeMetadataKey 实例元数据中记录启动时间的key，值为毫秒时间戳或者RFC3339格式的时间，
+	// 为空或者实例没有该元数据时，以SDK首次发现该实例的时间作为预热开始时间
+	StartTimeMetadataKey string `yaml:"startTimeMetadataKey" json:"startTimeMetadataKey"`
+}
+
+// IsEnable 是否启用实例预热
+func (w *WarmupConfigImpl) IsEnable() bool {
+	return *w.Enable
+}
+
+// SetEnable 设置是否启用实例预热
+func (w *WarmupConfigImpl) SetEnable(enable bool) {
+	w.Enable = &enable
+}
+
+// GetWindow 预热时长
+func (w *WarmupConfigImpl) GetWindow() time.Duration {
+	return *w.Window
+}
+
+// SetWindow 设置预热时长
+func (w *WarmupConfigImpl) SetWindow(window time.Duration) {
+	w.Window = &window
+}
+
+// GetCurve 预热曲线
+func (w *WarmupConfigImpl) GetCurve() string {
+	return w.Curve
+}
+
+// SetCurve 设置预热曲线
+func (w *WarmupConfigImpl) SetCurve(curve string) {
+	w.Curve = curve
+}
+
+// GetMinWeightPercent 预热开始时权重相对完整权重的百分比
+func (w *WarmupConfigImpl) GetMinWeightPercent() int {
+	return w.MinWeightPercent
+}
+
+// SetMinWeightPercent 设置预热开始时权重相对完整权重的百分比
+func (w *WarmupConfigImpl) SetMinWeightPercent(percent int) {
+	w.MinWeightPercent = percent
+}
+
+// GetStartTimeMetadataKey 实例元数据中记录启动时间的key
+func (w *WarmupConfigImpl) GetStartTimeMetadataKey() string {
+	return w.StartTimeMetadataKey
+}
+
+// SetStartTimeMetadataKey 设置实例元数据中记录启动时间的key
+func (w *WarmupConfigImpl) SetStartTimeMetadataKey(key string) {
+	w.StartTimeMetadataKey = key
+}
+
+// Verify 检验WarmupConfig配置
+func (w *WarmupConfigImpl) Verify() error {
+	if nil == w {
+		return errors.New("WarmupConfig is nil")
+	}
+	var errs error
+	if nil != w.Window && *w.Window < MinWarmupWindow {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.warmup.window should greater than %v", MinWarmupWindow))
+	}
+	if len(w.Curve) > 0 && w.Curve != WarmupCurveLinear && w.Curve != WarmupCurveAggressive {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.warmup.curve must be %s or %s",
+			WarmupCurveLinear, WarmupCurveAggressive))
+	}
+	if w.MinWeightPercent < 1 || w.MinWeightPercent > 100 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.warmup.minWeightPercent must be in [1, 100]"))
+	}
+	return errs
+}
+
+// SetDefault 设置WarmupConfig配置的默认值
+func (w *WarmupConfigImpl) SetDefault() {
+	if nil == w.Enable {
+		enable := DefaultWarmupEnabled
+		w.Enable = &enable
+	}
+	if nil == w.Window {
+		w.Window = model.ToDurationPtr(DefaultWarmupWindow)
+	}
+	if len(w.Curve) == 0 {
+		w.Curve = WarmupCurveLinear
+	}
+	if w.MinWeightPercent == 0 {
+		w.MinWeightPercent = DefaultWarmupMinWeightPercent
+	}
+}
+
+// inherit 服务级的预热配置中未设置的配置项，继承全局的预热配置
+func (w *WarmupConfigImpl) inherit(global *WarmupConfigImpl) {
+	if nil == w.Enable {
+		w.Enable = global.Enable
+	}
+	if nil == w.Window {
+		w.Window = global.Window
+	}
+	if len(w.Curve) == 0 {
+		w.Curve = global.Curve
+	}
+	if w.MinWeightPercent == 0 {
+		w.MinWeightPercent = global.MinWeightPercent
+	}
+	if len(w.StartTimeMetadataKey) == 0 {
+		w.StartTimeMetadataKey = global.StartTimeMetadataKey
+	}
+}
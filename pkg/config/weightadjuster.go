@@ -0,0 +1,151 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// WeightAdjusterConfigImpl 动态权重调整相关配置
+type WeightAdjusterConfigImpl struct {
+	// Enable 是否启用动态权重调整
+	Enable *bool `yaml:"enable" json:"enable"`
+	// Type 动态权重调整插件名
+	Type string `yaml:"type" json:"type"`
+	// AdjustPeriod 定时调整动态权重的周期
+	AdjustPeriod *time.Duration `yaml:"adjustPeriod" json:"adjustPeriod"`
+	// DelayFactor 时延对权重的影响倍率
+	DelayFactor *float64 `yaml:"delayFactor" json:"delayFactor"`
+	// RateFactor 错误率对权重的影响倍率
+	RateFactor *float64 `yaml:"rateFactor" json:"rateFactor"`
+	// MinWeightPercent 动态权重相对静态权重的最低百分比
+	MinWeightPercent int `yaml:"minWeightPercent" json:"minWeightPercent"`
+}
+
+// IsEnable 是否启用动态权重调整
+func (w *WeightAdjusterConfigImpl) IsEnable() bool {
+	return *w.Enable
+}
+
+// SetEnable 设置是否启用动态权重调整
+func (w *WeightAdjusterConfigImpl) SetEnable(enable bool) {
+	w.Enable = &enable
+}
+
+// GetType 动态权重调整插件名
+func (w *WeightAdjusterConfigImpl) GetType() string {
+	return w.Type
+}
+
+// SetType 设置动态权重调整插件名
+func (w *WeightAdjusterConfigImpl) SetType(typ string) {
+	w.Type = typ
+}
+
+// GetAdjustPeriod 定时调整动态权重的周期
+func (w *WeightAdjusterConfigImpl) GetAdjustPeriod() time.Duration {
+	return *w.AdjustPeriod
+}
+
+// SetAdjustPeriod 设置定时调整动态权重的周期
+func (w *WeightAdjusterConfigImpl) SetAdjustPeriod(period time.Duration) {
+	w.AdjustPeriod = &period
+}
+
+// GetDelayFactor 时延对权重的影响倍率
+func (w *WeightAdjusterConfigImpl) GetDelayFactor() float64 {
+	return *w.DelayFactor
+}
+
+// SetDelayFactor 设置时延对权重的影响倍率
+func (w *WeightAdjusterConfigImpl) SetDelayFactor(factor float64) {
+	w.DelayFactor = &factor
+}
+
+// GetRateFactor 错误率对权重的影响倍率
+func (w *WeightAdjusterConfigImpl) GetRateFactor() float64 {
+	return *w.RateFactor
+}
+
+// SetRateFactor 设置错误率对权重的影响倍率
+func (w *WeightAdjusterConfigImpl) SetRateFactor(factor float64) {
+	w.RateFactor = &factor
+}
+
+// GetMinWeightPercent 动态权重相对静态权重的最低百分比
+func (w *WeightAdjusterConfigImpl) GetMinWeightPercent() int {
+	return w.MinWeightPercent
+}
+
+// SetMinWeightPercent 设置动态权重相对静态权重的最低百分比
+func (w *WeightAdjusterConfigImpl) SetMinWeightPercent(percent int) {
+	w.MinWeightPercent = percent
+}
+
+// Verify 检验WeightAdjusterConfig配置
+func (w *WeightAdjusterConfigImpl) Verify() error {
+	if nil == w {
+		return errors.New("WeightAdjusterConfig is nil")
+	}
+	var errs error
+	if nil != w.AdjustPeriod && *w.AdjustPeriod < MinWeightAdjustPeriod {
+		errs = multierror.Append(errs,
+			fmt.Errorf("consumer.weightAdjuster.adjustPeriod should greater than %v", MinWeightAdjustPeriod))
+	}
+	if nil != w.DelayFactor && *w.DelayFactor < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.weightAdjuster.delayFactor must not be negative"))
+	}
+	if nil != w.RateFactor && *w.RateFactor < 0 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.weightAdjuster.rateFactor must not be negative"))
+	}
+	if w.MinWeightPercent < 1 || w.MinWeightPercent > 100 {
+		errs = multierror.Append(errs, fmt.Errorf("consumer.weightAdjuster.minWeightPercent must be in [1, 100]"))
+	}
+	return errs
+}
+
+// SetDefault 设置WeightAdjusterConfig配置的默认值
+func (w *WeightAdjusterConfigImpl) SetDefault() {
+	if nil == w.Enable {
+		enable := DefaultWeightAdjusterEnabled
+		w.Enable = &enable
+	}
+	if len(w.Type) == 0 {
+		w.Type = DefaultWeightAdjuster
+	}
+	if nil == w.AdjustPeriod {
+		w.AdjustPeriod = model.ToDurationPtr(DefaultWeightAdjustPeriod)
+	}
+	if nil == w.DelayFactor {
+		factor := DefaultWeightAdjustDelayFactor
+		w.DelayFactor = &factor
+	}
+	if nil == w.RateFactor {
+		factor := DefaultWeightAdjustRateFactor
+		w.RateFactor = &factor
+	}
+	if w.MinWeightPercent == 0 {
+		w.MinWeightPercent = DefaultMinWeightPercent
+	}
+}
@@ -18,6 +18,7 @@
 package flow
 
 import (
+	"context"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
@@ -25,9 +26,10 @@ import (
 )
 
 // AsyncGetQuota 异步获取配额信息
-func (e *Engine) AsyncGetQuota(request *model.QuotaRequestImpl) (*model.QuotaFutureImpl, error) {
+func (e *Engine) AsyncGetQuota(
+	ctx context.Context, request *model.QuotaRequestImpl) (*model.QuotaFutureImpl, error) {
 	commonRequest := data.PoolGetCommonRateLimitRequest()
-	commonRequest.InitByGetQuotaRequest(request, e.configuration)
+	commonRequest.InitByGetQuotaRequest(ctx, request, e.configuration)
 	startTime := model.CurrentMillisecond()
 	future, err := e.flowQuotaAssistant.GetQuota(commonRequest)
 	consumeTime := model.CurrentMillisecond() - startTime
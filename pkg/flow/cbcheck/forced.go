@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cbcheck
+
+import (
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+// forcedKey 被手动设置熔断状态的实例标识
+type forcedKey struct {
+	svcKey model.ServiceKey
+	instID string
+}
+
+// ForcedCircuitBreaker 运维人员手动设置实例的熔断状态，在有效期内固定实例的熔断状态，熔断器不再对该实例进行状态转换；
+// 手动设置为关闭状态同样会被固定，有效期内实例即使调用持续失败也不会被熔断。
+// 有效期到期后，在下一个熔断检查周期将实例恢复为关闭状态
+type ForcedCircuitBreaker struct {
+	registry localregistry.LocalRegistry
+	mutex    sync.Mutex
+	// 固定状态的过期时间
+	pins map[forcedKey]time.Time
+}
+
+// NewForcedCircuitBreaker 创建手动熔断处理器
+func NewForcedCircuitBreaker(registry localregistry.LocalRegistry) *ForcedCircuitBreaker {
+	return &ForcedCircuitBreaker{
+		registry: registry,
+		pins:     make(map[forcedKey]time.Time),
+	}
+}
+
+// Force 固定实例的熔断状态，重复设置时以最后一次为准
+func (f *ForcedCircuitBreaker) Force(instance model.Instance, status model.Status, ttl time.Duration) error {
+	now := clock.GetClock().Now()
+	key := forcedKey{
+		svcKey: model.ServiceKey{Namespace: instance.GetNamespace(), Service: instance.GetService()},
+		instID: instance.GetId(),
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	log.GetDetectLogger().Infof("forced circuitbreaker: instance(id=%s, address=%s:%d) of %s change to %v, ttl %v",
+		key.instID, instance.GetHost(), instance.GetPort(), key.svcKey, status, ttl)
+	if err := f.updateStatus(key, status, now); err != nil {
+		return err
+	}
+	f.pins[key] = now.Add(ttl)
+	return nil
+}
+
+// IsForced 判断实例的熔断状态是否处于固定的有效期内，固定的状态包括手动设置的关闭状态
+func (f *ForcedCircuitBreaker) IsForced(svcKey model.ServiceKey, instID string, now time.Time) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	expireTime, ok := f.pins[forcedKey{svcKey: svcKey, instID: instID}]
+	return ok && now.Before(expireTime)
+}
+
+// Check 定时清理服务下已经过期的固定状态，过期的实例恢复为关闭状态，重新由熔断器进行判断
+func (f *ForcedCircuitBreaker) Check(svcKey model.ServiceKey, now time.Time) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for key, expireTime := range f.pins {
+		if key.svcKey != svcKey || now.Before(expireTime) {
+			continue
+		}
+		delete(f.pins, key)
+		log.GetDetectLogger().Infof("forced circuitbreaker: instance %s of %s expired, change to %v",
+			key.instID, key.svcKey, model.Close)
+		if err := f.updateStatus(key, model.Close, now); err != nil {
+			log.GetDetectLogger().Errorf("fail to recover forced circuitbreaker status for %s, error: %v",
+				key.svcKey, err)
+		}
+	}
+}
+
+// updateStatus 将手动设置的熔断状态写入本地缓存，调用方需持有锁
+func (f *ForcedCircuitBreaker) updateStatus(key forcedKey, status model.Status, now time.Time) error {
+	svcKey := key.svcKey
+	request := &localregistry.ServiceUpdateRequest{
+		ServiceKey: svcKey,
+		Properties: []localregistry.InstanceProperties{{
+			ID:      key.instID,
+			Service: &svcKey,
+			Properties: map[string]interface{}{
+				localregistry.PropertyCircuitBreakerStatus: &circuitBreakerStatus{
+					circuitBreaker: model.CircuitBreakerOperator,
+					status:         status,
+					startTime:      now,
+				},
+			},
+		}},
+	}
+	return f.registry.UpdateInstances(request)
+}
@@ -0,0 +1,185 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cbcheck
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/log/logtest"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+func init() {
+	logtest.DiscardLoggers()
+}
+
+// recordRegistry 记录实例状态更新请求的本地缓存
+type recordRegistry struct {
+	localregistry.LocalRegistry
+	requests []*localregistry.ServiceUpdateRequest
+	err      error
+}
+
+func (r *recordRegistry) UpdateInstances(request *localregistry.ServiceUpdateRequest) error {
+	if nil != r.err {
+		return r.err
+	}
+	r.requests = append(r.requests, request)
+	return nil
+}
+
+// lastStatus 获取最后一次更新的实例熔断状态
+func (r *recordRegistry) lastStatus(t *testing.T) (string, *circuitBreakerStatus) {
+	assert.NotEmpty(t, r.requests)
+	request := r.requests[len(r.requests)-1]
+	assert.Equal(t, 1, len(request.Properties))
+	property := request.Properties[0]
+	return property.ID, property.Properties[localregistry.PropertyCircuitBreakerStatus].(*circuitBreakerStatus)
+}
+
+// recordCircuitBreaker 记录参与熔断计算实例的熔断器
+type recordCircuitBreaker struct {
+	circuitbreaker.InstanceCircuitBreaker
+	instances []string
+}
+
+func (r *recordCircuitBreaker) Name() string {
+	return "record"
+}
+
+func (r *recordCircuitBreaker) CircuitBreak(instances []model.Instance) (*circuitbreaker.Result, error) {
+	for _, instance := range instances {
+		r.instances = append(r.instances, instance.GetId())
+	}
+	return nil, nil
+}
+
+func newTestInstances(ids ...string) model.ServiceInstances {
+	resp := &apiservice.DiscoverResponse{
+		Service: &apiservice.Service{Namespace: wrapperspb.String("default"), Name: wrapperspb.String("echo")},
+	}
+	for i, id := range ids {
+		resp.Instances = append(resp.Instances, &apiservice.Instance{
+			Id:        wrapperspb.String(id),
+			Namespace: wrapperspb.String("default"),
+			Service:   wrapperspb.String("echo"),
+			Host:      wrapperspb.String("127.0.0.1"),
+			Port:      wrapperspb.UInt32(uint32(8001 + i)),
+			Weight:    wrapperspb.UInt32(100),
+			Healthy:   wrapperspb.Bool(true),
+		})
+	}
+	return pb.NewServiceInstancesInProto(resp, func(string) local.InstanceLocalValue {
+		return local.NewInstanceLocalValue()
+	}, nil, nil)
+}
+
+// TestForce 测试手动设置熔断状态并在有效期内固定，关闭状态同样被固定
+func TestForce(t *testing.T) {
+	registry := &recordRegistry{}
+	forced := NewForcedCircuitBreaker(registry)
+	svcInstances := newTestInstances("a", "b")
+	svcKey := model.ServiceKey{Namespace: "default", Service: "echo"}
+	now := time.Now()
+
+	assert.Nil(t, forced.Force(svcInstances.GetInstance("a"), model.Open, time.Minute))
+	instID, status := registry.lastStatus(t)
+	assert.Equal(t, "a", instID)
+	assert.Equal(t, model.Open, status.GetStatus())
+	assert.Equal(t, model.CircuitBreakerOperator, status.GetCircuitBreaker())
+	assert.True(t, forced.IsForced(svcKey, "a", now))
+	assert.False(t, forced.IsForced(svcKey, "a", now.Add(2*time.Minute)))
+	assert.False(t, forced.IsForced(svcKey, "b", now))
+
+	// 重复设置时以最后一次为准
+	assert.Nil(t, forced.Force(svcInstances.GetInstance("a"), model.Close, 5*time.Minute))
+	_, status = registry.lastStatus(t)
+	assert.Equal(t, model.Close, status.GetStatus())
+	assert.True(t, forced.IsForced(svcKey, "a", now.Add(2*time.Minute)))
+
+	// 更新本地缓存失败时不固定状态
+	registry.err = errors.New("update failed")
+	assert.NotNil(t, forced.Force(svcInstances.GetInstance("b"), model.Open, time.Minute))
+	assert.False(t, forced.IsForced(svcKey, "b", now))
+}
+
+// TestForceCheck 测试有效期到期后恢复为关闭状态，不影响其他服务以及未到期的实例
+func TestForceCheck(t *testing.T) {
+	registry := &recordRegistry{}
+	forced := NewForcedCircuitBreaker(registry)
+	svcInstances := newTestInstances("a", "b")
+	svcKey := model.ServiceKey{Namespace: "default", Service: "echo"}
+	otherKey := model.ServiceKey{Namespace: "default", Service: "other"}
+	assert.Nil(t, forced.Force(svcInstances.GetInstance("a"), model.Open, time.Minute))
+	assert.Nil(t, forced.Force(svcInstances.GetInstance("b"), model.Open, time.Hour))
+	requestCount := len(registry.requests)
+
+	// 未到期以及其他服务的检查不更新状态
+	now := time.Now()
+	forced.Check(svcKey, now)
+	forced.Check(otherKey, now.Add(2*time.Minute))
+	assert.Equal(t, requestCount, len(registry.requests))
+
+	now = now.Add(2 * time.Minute)
+	forced.Check(svcKey, now)
+	assert.Equal(t, requestCount+1, len(registry.requests))
+	instID, status := registry.lastStatus(t)
+	assert.Equal(t, "a", instID)
+	assert.Equal(t, model.Close, status.GetStatus())
+	assert.False(t, forced.IsForced(svcKey, "a", now))
+	assert.True(t, forced.IsForced(svcKey, "b", now))
+
+	// 已经恢复的实例不再重复更新
+	forced.Check(svcKey, now)
+	assert.Equal(t, requestCount+1, len(registry.requests))
+}
+
+// TestForcedSkipCircuitBreak 测试固定状态的实例不参与熔断器的计算
+func TestForcedSkipCircuitBreak(t *testing.T) {
+	registry := &recordRegistry{}
+	cb := &recordCircuitBreaker{}
+	callBack := &CircuitBreakCallBack{
+		circuitBreakerChain:  []circuitbreaker.InstanceCircuitBreaker{cb},
+		registry:             registry,
+		forcedCircuitBreaker: NewForcedCircuitBreaker(registry),
+	}
+	svcInstances := newTestInstances("a", "b", "c")
+	svcKey := model.ServiceKey{Namespace: "default", Service: "echo"}
+	assert.Nil(t, callBack.forcedCircuitBreaker.Force(svcInstances.GetInstance("a"), model.Open, time.Minute))
+	assert.Nil(t, callBack.forcedCircuitBreaker.Force(svcInstances.GetInstance("b"), model.Close, time.Minute))
+
+	request, err := callBack.doCircuitBreakForService(svcKey, svcInstances, nil, "")
+	assert.Nil(t, err)
+	assert.Nil(t, request)
+	assert.Equal(t, []string{"c"}, cb.instances)
+
+	cb.instances = nil
+	_, err = callBack.doCircuitBreakForService(svcKey, svcInstances, svcInstances.GetInstance("b"), "")
+	assert.Nil(t, err)
+	assert.Empty(t, cb.instances)
+}
@@ -0,0 +1,272 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cbcheck
+
+import (
+	"sync"
+	"time"
+
+	"github.com/modern-go/reflect2"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+// methodCircuitBreakerName 接口级熔断状态中记录的熔断器名字
+const methodCircuitBreakerName = "method"
+
+// methodKey 接口级熔断的统计维度，实例ID为空时代表(服务, 接口)维度
+type methodKey struct {
+	svcKey model.ServiceKey
+	instID string
+	method string
+}
+
+// methodCounter 接口级熔断计数器
+type methodCounter struct {
+	mutex sync.Mutex
+	// 连续错误数
+	consecutiveErrors int
+	// 当前熔断状态，为nil时代表关闭
+	status *circuitBreakerStatus
+	// 最近一次被调用的实例，服务维度熔断状态变更时作为上报的实例
+	lastInstance model.Instance
+}
+
+// newStatus 构建新的熔断状态
+func newStatus(status model.Status, now time.Time, allowedRequests int) *circuitBreakerStatus {
+	return &circuitBreakerStatus{
+		circuitBreaker:           methodCircuitBreakerName,
+		status:                   status,
+		startTime:                now,
+		maxHalfOpenAllowReqTimes: allowedRequests,
+		halfOpenQuota:            int32(allowedRequests),
+	}
+}
+
+// toStatus 切换熔断状态，调用方需持有锁
+func (c *methodCounter) toStatus(status model.Status, now time.Time, allowedRequests int) *circuitBreakerStatus {
+	c.consecutiveErrors = 0
+	c.status = newStatus(status, now, allowedRequests)
+	return c.status
+}
+
+// stat 统计调用结果，状态发生变更时返回新的熔断状态
+func (c *methodCounter) stat(instance model.Instance, success bool, threshold int, now time.Time,
+	allowedRequests int) *circuitBreakerStatus {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.lastInstance = instance
+	if nil == c.status || c.status.GetStatus() == model.Close {
+		if success {
+			c.consecutiveErrors = 0
+			return nil
+		}
+		c.consecutiveErrors++
+		if c.consecutiveErrors < threshold {
+			return nil
+		}
+		return c.toStatus(model.Open, now, allowedRequests)
+	}
+	if c.status.GetStatus() != model.HalfOpen {
+		// 熔断状态不进行统计
+		return nil
+	}
+	reqCount := c.status.AddRequestCountAfterHalfOpen(1, success)
+	if !success {
+		return c.toStatus(model.Open, now, allowedRequests)
+	}
+	if int(reqCount) >= c.status.maxHalfOpenAllowReqTimes {
+		return c.toStatus(model.Close, now, allowedRequests)
+	}
+	return nil
+}
+
+// check 定时进行熔断状态转换，状态发生变更时返回新的熔断状态
+func (c *methodCounter) check(now time.Time, sleepWindow time.Duration, allowedRequests int) *circuitBreakerStatus {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if nil == c.status {
+		return nil
+	}
+	switch c.status.GetStatus() {
+	case model.Open:
+		if now.Sub(c.status.GetStartTime()) >= sleepWindow {
+			return c.toStatus(model.HalfOpen, now, allowedRequests)
+		}
+	case model.HalfOpen:
+		// 探测配额已经分配完，但是过了一个熔断周期还没有上报完调用结果，则认为探测失败
+		if c.status.IsAvailable() || c.status.AllocatedRequestsAfterHalfOpen() <= c.status.GetRequestsAfterHalfOpen() {
+			return nil
+		}
+		finalAllocTime := c.status.GetFinalAllocateTimeInt64()
+		if finalAllocTime != 0 && now.Sub(time.Unix(0, finalAllocTime)) >= sleepWindow {
+			return c.toStatus(model.Open, now, allowedRequests)
+		}
+	}
+	return nil
+}
+
+// getStatus 获取当前的熔断状态
+func (c *methodCounter) getStatus() *circuitBreakerStatus {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.status
+}
+
+// getLastInstance 获取最近一次被调用的实例
+func (c *methodCounter) getLastInstance() model.Instance {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lastInstance
+}
+
+// MethodCircuitBreaker 接口级熔断，分别按照(服务, 接口)以及(实例, 接口)两个维度统计连续错误数进行熔断
+// (实例, 接口)的熔断状态写入本地缓存，负载均衡时跳过该接口熔断的实例；
+// (服务, 接口)的熔断状态保存在内存中，只用于上报接口整体的熔断状态变更，不拒绝实例获取请求
+type MethodCircuitBreaker struct {
+	wholeCfg config.Configuration
+	cbCfg    config.CircuitBreakerConfig
+	registry localregistry.LocalRegistry
+	engine   model.Engine
+	// methodKey -> *methodCounter
+	counters *sync.Map
+}
+
+// NewMethodCircuitBreaker 创建接口级熔断器
+func NewMethodCircuitBreaker(
+	cfg config.Configuration, registry localregistry.LocalRegistry, engine model.Engine) *MethodCircuitBreaker {
+	return &MethodCircuitBreaker{
+		wholeCfg: cfg,
+		cbCfg:    cfg.GetConsumer().GetCircuitBreaker(),
+		registry: registry,
+		engine:   engine,
+		counters: &sync.Map{},
+	}
+}
+
+// getThreshold 获取连续错误数阈值，复用连续错误数熔断的配置
+func (m *MethodCircuitBreaker) getThreshold(svcKey *model.ServiceKey) int {
+	cfg := m.cbCfg.GetErrorCountConfig()
+	serviceSp := m.wholeCfg.GetConsumer().GetServiceSpecific(svcKey.Namespace, svcKey.Service)
+	if serviceSp != nil && serviceSp.GetServiceCircuitBreaker() != nil {
+		cfg = serviceSp.GetServiceCircuitBreaker().GetErrorCountConfig()
+	}
+	return cfg.GetContinuousErrorThreshold()
+}
+
+// getCounter 获取或者创建计数器
+func (m *MethodCircuitBreaker) getCounter(key methodKey) *methodCounter {
+	value, ok := m.counters.Load(key)
+	if !ok {
+		value, _ = m.counters.LoadOrStore(key, &methodCounter{})
+	}
+	return value.(*methodCounter)
+}
+
+// Stat 统计接口调用结果，并进行接口级的熔断判断
+func (m *MethodCircuitBreaker) Stat(gauge model.InstanceGauge, method string) {
+	instance := gauge.GetCalledInstance()
+	if len(method) == 0 || reflect2.IsNil(instance) {
+		return
+	}
+	svcKey := model.ServiceKey{Namespace: gauge.GetNamespace(), Service: gauge.GetService()}
+	success := gauge.GetRetStatus() == model.RetSuccess
+	threshold := m.getThreshold(&svcKey)
+	allowedRequests := m.cbCfg.GetRequestCountAfterHalfOpen()
+	now := clock.GetClock().Now()
+
+	instKey := methodKey{svcKey: svcKey, instID: instance.GetId(), method: method}
+	if status := m.getCounter(instKey).stat(instance, success, threshold, now, allowedRequests); nil != status {
+		m.updateInstanceStatus(instKey, status)
+	}
+	svcMethodKey := methodKey{svcKey: svcKey, method: method}
+	if status := m.getCounter(svcMethodKey).stat(instance, success, threshold, now, allowedRequests); nil != status {
+		m.reportServiceStatus(svcMethodKey, instance, status)
+	}
+}
+
+// Check 定时对服务下的接口熔断状态进行转换，并清理已经下线实例的计数器
+func (m *MethodCircuitBreaker) Check(svcKey model.ServiceKey, svcInstances model.ServiceInstances) {
+	now := clock.GetClock().Now()
+	sleepWindow := m.cbCfg.GetSleepWindow()
+	allowedRequests := m.cbCfg.GetRequestCountAfterHalfOpen()
+	svcInstancesInProto, _ := svcInstances.(*pb.ServiceInstancesInProto)
+	m.counters.Range(func(k, v interface{}) bool {
+		key := k.(methodKey)
+		if key.svcKey != svcKey {
+			return true
+		}
+		counter := v.(*methodCounter)
+		if len(key.instID) == 0 {
+			if status := counter.check(now, sleepWindow, allowedRequests); nil != status {
+				m.reportServiceStatus(key, counter.getLastInstance(), status)
+			}
+			return true
+		}
+		if nil != svcInstancesInProto && reflect2.IsNil(svcInstancesInProto.GetInstance(key.instID)) {
+			m.counters.Delete(key)
+			return true
+		}
+		if status := counter.check(now, sleepWindow, allowedRequests); nil != status {
+			m.updateInstanceStatus(key, status)
+		}
+		return true
+	})
+}
+
+// updateInstanceStatus 将(实例, 接口)的熔断状态写入本地缓存
+func (m *MethodCircuitBreaker) updateInstanceStatus(key methodKey, status *circuitBreakerStatus) {
+	log.GetDetectLogger().Infof("method circuitbreaker: instance %s of %s, method %s change to %v",
+		key.instID, key.svcKey, key.method, status.GetStatus())
+	request := &localregistry.ServiceUpdateRequest{
+		ServiceKey: key.svcKey,
+		Properties: []localregistry.InstanceProperties{{
+			ID:      key.instID,
+			Service: &key.svcKey,
+			Properties: map[string]interface{}{
+				localregistry.PropertyMethodCircuitBreakerStatus: &localregistry.MethodCircuitBreakerStatus{
+					Method: key.method,
+					Status: status,
+				},
+			},
+		}},
+	}
+	if err := m.registry.UpdateInstances(request); err != nil {
+		log.GetDetectLogger().Errorf("fail to update method circuitbreaker status for %s, error: %v",
+			key.svcKey, err)
+	}
+}
+
+// reportServiceStatus 上报(服务, 接口)的熔断状态变更
+func (m *MethodCircuitBreaker) reportServiceStatus(key methodKey, instance model.Instance, status *circuitBreakerStatus) {
+	log.GetDetectLogger().Infof("method circuitbreaker: service %s, method %s change to %v",
+		key.svcKey, key.method, status.GetStatus())
+	if reflect2.IsNil(instance) || nil == m.engine {
+		return
+	}
+	err := m.engine.SyncReportStat(model.CircuitBreakStat,
+		&model.CircuitBreakGauge{ChangeInstance: instance, Method: key.method, CBStatus: status})
+	if err != nil {
+		log.GetBaseLogger().Errorf("fail to report method circuitbreak change, error %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cbcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestMethodCounterConversion 测试接口级熔断的打开、半开以及恢复
+func TestMethodCounterConversion(t *testing.T) {
+	counter := &methodCounter{}
+	now := time.Now()
+	sleepWindow := 10 * time.Second
+	assert.Nil(t, counter.stat(nil, false, 3, now, 2))
+	assert.Nil(t, counter.stat(nil, false, 3, now, 2))
+	// 成功调用重置连续错误数
+	assert.Nil(t, counter.stat(nil, true, 3, now, 2))
+	assert.Nil(t, counter.stat(nil, false, 3, now, 2))
+	assert.Nil(t, counter.stat(nil, false, 3, now, 2))
+	status := counter.stat(nil, false, 3, now, 2)
+	assert.NotNil(t, status)
+	assert.Equal(t, model.Open, status.GetStatus())
+	assert.False(t, status.IsAvailable())
+	// 熔断期间不统计
+	assert.Nil(t, counter.stat(nil, true, 3, now, 2))
+
+	assert.Nil(t, counter.check(now.Add(sleepWindow/2), sleepWindow, 2))
+	now = now.Add(sleepWindow)
+	status = counter.check(now, sleepWindow, 2)
+	assert.Equal(t, model.HalfOpen, status.GetStatus())
+	assert.True(t, status.Allocate())
+	assert.True(t, status.Allocate())
+	assert.False(t, status.Allocate())
+
+	assert.Nil(t, counter.stat(nil, true, 3, now, 2))
+	status = counter.stat(nil, true, 3, now, 2)
+	assert.Equal(t, model.Close, status.GetStatus())
+}
+
+// TestMethodCounterHalfOpenFail 测试半开期间调用失败，以及探测结果未上报时重新熔断
+func TestMethodCounterHalfOpenFail(t *testing.T) {
+	counter := &methodCounter{}
+	sleepWindow := 10 * time.Second
+	// 半开配额分配时间使用当前时间记录，因此从两个熔断周期之前开始
+	now := time.Now().Add(-2 * sleepWindow)
+	counter.stat(nil, false, 1, now, 2)
+	now = now.Add(sleepWindow)
+	counter.check(now, sleepWindow, 2)
+	status := counter.stat(nil, false, 1, now, 2)
+	assert.Equal(t, model.Open, status.GetStatus())
+
+	now = now.Add(sleepWindow)
+	status = counter.check(now, sleepWindow, 2)
+	assert.Equal(t, model.HalfOpen, status.GetStatus())
+	status.Allocate()
+	status.Allocate()
+	// 探测配额已经分配完，但是一个熔断周期内没有上报调用结果
+	assert.Nil(t, counter.check(now, sleepWindow, 2))
+	status = counter.check(now.Add(sleepWindow), sleepWindow, 2)
+	assert.Equal(t, model.Open, status.GetStatus())
+}
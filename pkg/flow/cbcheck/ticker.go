@@ -22,6 +22,7 @@ import (
 
 	"github.com/modern-go/reflect2"
 
+	"github.com/polarismesh/polaris-go/pkg/clock"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -32,7 +33,8 @@ import (
 )
 
 // NewCircuitBreakCallBack 创建定时熔断任务回调
-func NewCircuitBreakCallBack(cfg config.Configuration, supplier plugin.Supplier) (*CircuitBreakCallBack, error) {
+func NewCircuitBreakCallBack(
+	cfg config.Configuration, supplier plugin.Supplier, engine model.Engine) (*CircuitBreakCallBack, error) {
 	var err error
 	callBack := &CircuitBreakCallBack{}
 	if callBack.registry, err = data.GetRegistry(cfg, supplier); err != nil {
@@ -42,6 +44,8 @@ func NewCircuitBreakCallBack(cfg config.Configuration, supplier plugin.Supplier)
 		return nil, err
 	}
 	callBack.interval = cfg.GetConsumer().GetCircuitBreaker().GetCheckPeriod()
+	callBack.methodCircuitBreaker = NewMethodCircuitBreaker(cfg, callBack.registry, engine)
+	callBack.forcedCircuitBreaker = NewForcedCircuitBreaker(callBack.registry)
 	return callBack, nil
 }
 
@@ -53,6 +57,20 @@ type CircuitBreakCallBack struct {
 	registry localregistry.LocalRegistry
 	// 轮询间隔
 	interval time.Duration
+	// 接口级熔断器
+	methodCircuitBreaker *MethodCircuitBreaker
+	// 手动熔断处理器
+	forcedCircuitBreaker *ForcedCircuitBreaker
+}
+
+// GetMethodCircuitBreaker 获取接口级熔断器
+func (c *CircuitBreakCallBack) GetMethodCircuitBreaker() *MethodCircuitBreaker {
+	return c.methodCircuitBreaker
+}
+
+// GetForcedCircuitBreaker 获取手动熔断处理器
+func (c *CircuitBreakCallBack) GetForcedCircuitBreaker() *ForcedCircuitBreaker {
+	return c.forcedCircuitBreaker
 }
 
 // Process 执行任务
@@ -69,6 +87,8 @@ func (c *CircuitBreakCallBack) Process(
 		log.GetDetectLogger().Infof("instances not initialized for %s", svc)
 		return model.CONTINUE
 	}
+	c.methodCircuitBreaker.Check(svc, svcInstances)
+	c.forcedCircuitBreaker.Check(svc, clock.GetClock().Now())
 	request, err := c.
 		doCircuitBreakForService(svc, svcInstances, nil, "")
 	var resultStr = "nil"
@@ -102,10 +122,15 @@ func (c *CircuitBreakCallBack) doCircuitBreakForService(svc model.ServiceKey, sv
 	if len(instances) == 0 {
 		return nil, nil
 	}
+	now := clock.GetClock().Now()
 	for _, instance := range instances {
 		if len(c.circuitBreakerChain) == 0 {
 			continue
 		}
+		if c.forcedCircuitBreaker.IsForced(svc, instance.GetId(), now) {
+			// 手动设置的熔断状态在有效期内不进行转换
+			continue
+		}
 		for _, circuitBreaker := range c.circuitBreakerChain {
 			if len(cbName) > 0 && circuitBreaker.Name() != cbName {
 				continue
@@ -147,19 +172,20 @@ func cleanInstanceSet(instanceSet model.HashSet, allInstances model.HashSet) {
 }
 
 // buildInstanceProperty 构建实例更新数据
-func buildInstanceProperty(now time.Time, allowedRequests int, instances model.HashSet,
+func buildInstanceProperty(result *circuitbreaker.Result, instances model.HashSet,
 	request *localregistry.ServiceUpdateRequest, cbName string, status model.Status) {
 	if len(instances) == 0 {
 		return
 	}
 	for instID := range instances {
+		allowedRequests := result.GetRequestCountAfterHalfOpen(instID.(string))
 		request.Properties = append(request.Properties, localregistry.InstanceProperties{
 			ID:      instID.(string),
 			Service: &request.ServiceKey,
 			Properties: map[string]interface{}{localregistry.PropertyCircuitBreakerStatus: &circuitBreakerStatus{
 				circuitBreaker:           cbName,
 				status:                   status,
-				startTime:                now,
+				startTime:                result.Now,
 				maxHalfOpenAllowReqTimes: allowedRequests,
 				halfOpenQuota:            int32(allowedRequests),
 			}},
@@ -174,12 +200,9 @@ func buildServiceUpdateRequest(
 		ServiceKey: svc,
 	}
 	for cbName, result := range results {
-		buildInstanceProperty(result.Now, result.RequestCountAfterHalfOpen, result.InstancesToHalfOpen,
-			request, cbName, model.HalfOpen)
-		buildInstanceProperty(result.Now, result.RequestCountAfterHalfOpen, result.InstancesToOpen,
-			request, cbName, model.Open)
-		buildInstanceProperty(result.Now, result.RequestCountAfterHalfOpen, result.InstancesToClose,
-			request, cbName, model.Close)
+		buildInstanceProperty(result, result.InstancesToHalfOpen, request, cbName, model.HalfOpen)
+		buildInstanceProperty(result, result.InstancesToOpen, request, cbName, model.Open)
+		buildInstanceProperty(result, result.InstancesToClose, request, cbName, model.Close)
 	}
 	return request
 }
@@ -39,15 +39,23 @@ type ConfigFileFlow struct {
 
 	fclock          sync.RWMutex
 	configFileCache map[string]model.ConfigFile
-	repos           []*ConfigFileRepo
-	configFilePool  map[string]*ConfigFileRepo
-	notifiedVersion map[string]uint64
+	// 结构化配置文件缓存，key为配置文件缓存key加上配置文件格式
+	configKVFileCache map[string]model.ConfigKVFile
+	repos             []*ConfigFileRepo
+	configFilePool    map[string]*ConfigFileRepo
+	notifiedVersion   map[string]uint64
 
-	connector     configconnector.ConfigConnector
-	chain         configfilter.Chain
-	configuration config.Configuration
+	connector      configconnector.ConfigConnector
+	chain          configfilter.Chain
+	configuration  config.Configuration
+	persistHandler *configFilePersistHandler
 
 	startLongPollingTaskOnce sync.Once
+
+	// 配置分组，key为命名空间加分组名
+	configGroups          map[string]*configFileGroup
+	groupCancel           context.CancelFunc
+	startGroupPollingOnce sync.Once
 }
 
 // NewConfigFileFlow 创建配置中心服务
@@ -55,13 +63,16 @@ func NewConfigFileFlow(connector configconnector.ConfigConnector,
 	chain configfilter.Chain,
 	configuration config.Configuration) *ConfigFileFlow {
 	configFileService := &ConfigFileFlow{
-		connector:       connector,
-		chain:           chain,
-		configuration:   configuration,
-		repos:           make([]*ConfigFileRepo, 0, 8),
-		configFileCache: map[string]model.ConfigFile{},
-		configFilePool:  map[string]*ConfigFileRepo{},
-		notifiedVersion: map[string]uint64{},
+		connector:         connector,
+		chain:             chain,
+		configuration:     configuration,
+		persistHandler:    newConfigFilePersistHandler(configuration),
+		repos:             make([]*ConfigFileRepo, 0, 8),
+		configFileCache:   map[string]model.ConfigFile{},
+		configKVFileCache: map[string]model.ConfigKVFile{},
+		configGroups:      map[string]*configFileGroup{},
+		configFilePool:    map[string]*ConfigFileRepo{},
+		notifiedVersion:   map[string]uint64{},
 	}
 
 	return configFileService
@@ -72,6 +83,9 @@ func (c *ConfigFileFlow) Destroy() {
 	if c.cancel != nil {
 		c.cancel()
 	}
+	if c.groupCancel != nil {
+		c.groupCancel()
+	}
 }
 
 // GetConfigFile 获取配置文件
@@ -100,18 +114,140 @@ func (c *ConfigFileFlow) GetConfigFile(namespace, fileGroup, fileName string) (m
 		return configFile, nil
 	}
 
-	fileRepo, err := newConfigFileRepo(configFileMetadata, c.connector, c.chain, c.configuration)
+	fileRepo, err := c.getOrCreateRepo(configFileMetadata)
 	if err != nil {
 		return nil, err
 	}
-	c.addConfigFileToLongPollingPool(fileRepo)
-	c.repos = append(c.repos, fileRepo)
 
-	configFile = newDefaultConfigFile(configFileMetadata, fileRepo)
+	configFile = newDefaultConfigFile(configFileMetadata, fileRepo, nil)
 	c.configFileCache[cacheKey] = configFile
 	return configFile, nil
 }
 
+// GetConfigKVFile 获取结构化配置文件，format为空时根据文件名后缀判断配置文件格式
+func (c *ConfigFileFlow) GetConfigKVFile(namespace, fileGroup, fileName string,
+	format model.ConfigFileFormat) (model.ConfigKVFile, error) {
+	if format == model.ConfigFileFormatUnknown {
+		format = model.ParseConfigFileFormat(fileName)
+	}
+	switch format {
+	case model.ConfigFileFormatYaml, model.ConfigFileFormatJSON, model.ConfigFileFormatProperties:
+	default:
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"unsupported format %q of config file %s, only yaml, json and properties are supported", format, fileName)
+	}
+	configFileMetadata := &model.DefaultConfigFileMetadata{
+		Namespace: namespace,
+		FileGroup: fileGroup,
+		FileName:  fileName,
+	}
+
+	cacheKey := genCacheKeyByMetadata(configFileMetadata) + separator + string(format)
+
+	c.fclock.RLock()
+	kvFile, ok := c.configKVFileCache[cacheKey]
+	c.fclock.RUnlock()
+	if ok {
+		return kvFile, nil
+	}
+
+	c.fclock.Lock()
+	defer c.fclock.Unlock()
+
+	// double check
+	kvFile, ok = c.configKVFileCache[cacheKey]
+	if ok {
+		return kvFile, nil
+	}
+
+	fileRepo, err := c.getOrCreateRepo(configFileMetadata)
+	if err != nil {
+		return nil, err
+	}
+	kvFile, err = newConfigKVFile(configFileMetadata, fileRepo, format, c.configuration.GetConfigFile())
+	if err != nil {
+		return nil, err
+	}
+	c.configKVFileCache[cacheKey] = kvFile
+	return kvFile, nil
+}
+
+// GetConfigGroup 获取配置分组，并定时刷新分组下已发布的配置文件列表
+func (c *ConfigFileFlow) GetConfigGroup(namespace, group string) (model.ConfigFileGroup, error) {
+	cacheKey := namespace + separator + group
+
+	c.fclock.RLock()
+	fileGroup, ok := c.configGroups[cacheKey]
+	c.fclock.RUnlock()
+	if ok {
+		return fileGroup, nil
+	}
+
+	// 在锁外拉取配置文件列表，避免远程请求阻塞其他配置文件的获取
+	newGroup := newConfigFileGroup(namespace, group, c.connector)
+	if err := newGroup.pull(); err != nil {
+		return nil, err
+	}
+
+	c.fclock.Lock()
+	defer c.fclock.Unlock()
+
+	// double check，并发创建时使用先加入缓存的配置分组
+	fileGroup, ok = c.configGroups[cacheKey]
+	if ok {
+		return fileGroup, nil
+	}
+	fileGroup = newGroup
+	c.configGroups[cacheKey] = fileGroup
+
+	// 开启配置分组刷新任务
+	c.startGroupPollingOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		c.groupCancel = cancel
+		go c.groupPollingLoop(ctx)
+	})
+	return fileGroup, nil
+}
+
+// groupPollingLoop 定时刷新配置分组下已发布的配置文件列表
+func (c *ConfigFileFlow) groupPollingLoop(ctx context.Context) {
+	ticker := time.NewTicker(c.configuration.GetConfigFile().GetGroupRefreshInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.fclock.RLock()
+			fileGroups := make([]*configFileGroup, 0, len(c.configGroups))
+			for _, fileGroup := range c.configGroups {
+				fileGroups = append(fileGroups, fileGroup)
+			}
+			c.fclock.RUnlock()
+			for _, fileGroup := range fileGroups {
+				if err := fileGroup.pull(); err != nil {
+					log.GetBaseLogger().Errorf("[Config] fail to refresh config group %s/%s, err: %v",
+						fileGroup.GetNamespace(), fileGroup.GetGroup(), err)
+				}
+			}
+		}
+	}
+}
+
+// getOrCreateRepo 获取配置文件对应的远程配置代理，不存在时创建并加入长轮询，调用方需持有写锁
+func (c *ConfigFileFlow) getOrCreateRepo(configFileMetadata model.ConfigFileMetadata) (*ConfigFileRepo, error) {
+	if fileRepo, ok := c.configFilePool[genCacheKeyByMetadata(configFileMetadata)]; ok {
+		return fileRepo, nil
+	}
+	fileRepo, err := newConfigFileRepo(configFileMetadata, c.connector, c.chain, c.configuration, c.persistHandler)
+	if err != nil {
+		return nil, err
+	}
+	c.addConfigFileToLongPollingPool(fileRepo)
+	c.repos = append(c.repos, fileRepo)
+	return fileRepo, nil
+}
+
 func (c *ConfigFileFlow) addConfigFileToLongPollingPool(fileRepo *ConfigFileRepo) {
 	configFileMetadata := fileRepo.configFileMetadata
 	version := fileRepo.getVersion()
@@ -234,7 +370,7 @@ func (c *ConfigFileFlow) mainLoop(ctx context.Context) {
 			// 通知 remoteConfigFileRepo 拉取最新配置
 			remoteConfigFileRepo := c.getRemoteConfigFileRepo(cacheKey)
 			remoteConfigFileRepo.onLongPollingNotified(maxVersion)
-
+			c.reconcileStaleRepos()
 			continue
 		}
 
@@ -242,6 +378,7 @@ func (c *ConfigFileFlow) mainLoop(ctx context.Context) {
 		if responseCode == uint32(apimodel.Code_DataNoChange) {
 			pollingRetryPolicy.success()
 			log.GetBaseLogger().Infof("[Config] long polling result: data no change")
+			c.reconcileStaleRepos()
 			continue
 		}
 
@@ -252,6 +389,21 @@ func (c *ConfigFileFlow) mainLoop(ctx context.Context) {
 	}
 }
 
+// reconcileStaleRepos 长轮询恢复后，将启动时从本地缓存加载的配置与服务端重新同步
+func (c *ConfigFileFlow) reconcileStaleRepos() {
+	c.fclock.RLock()
+	staleRepos := make([]*ConfigFileRepo, 0)
+	for _, repo := range c.repos {
+		if repo.isStale() {
+			staleRepos = append(staleRepos, repo)
+		}
+	}
+	c.fclock.RUnlock()
+	for _, repo := range staleRepos {
+		repo.reconcile()
+	}
+}
+
 func (c *ConfigFileFlow) assembleWatchConfigFiles() []*configconnector.ConfigFile {
 	c.fclock.RLock()
 	defer c.fclock.RUnlock()
@@ -0,0 +1,124 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"sort"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+// CreateConfigFile 创建配置文件
+func (c *ConfigFileFlow) CreateConfigFile(req *model.ConfigFileRequest) error {
+	configFile, err := c.toUploadConfigFile(req)
+	if err != nil {
+		return err
+	}
+	resp, err := c.connector.CreateConfigFile(configFile)
+	return c.handleWriteResponse("create", configFile, resp, err)
+}
+
+// UpdateConfigFile 更新配置文件，更新后需要发布才会推送给客户端
+func (c *ConfigFileFlow) UpdateConfigFile(req *model.ConfigFileRequest) error {
+	configFile, err := c.toUploadConfigFile(req)
+	if err != nil {
+		return err
+	}
+	resp, err := c.connector.UpdateConfigFile(configFile)
+	return c.handleWriteResponse("update", configFile, resp, err)
+}
+
+// PublishConfigFile 发布配置文件
+func (c *ConfigFileFlow) PublishConfigFile(metadata model.ConfigFileMetadata) error {
+	configFile := &configconnector.ConfigFile{
+		Namespace: metadata.GetNamespace(),
+		FileGroup: metadata.GetFileGroup(),
+		FileName:  metadata.GetFileName(),
+	}
+	resp, err := c.connector.PublishConfigFile(configFile)
+	return c.handleWriteResponse("publish", configFile, resp, err)
+}
+
+// DeleteConfigFile 删除配置文件
+func (c *ConfigFileFlow) DeleteConfigFile(metadata model.ConfigFileMetadata) error {
+	configFile := &configconnector.ConfigFile{
+		Namespace: metadata.GetNamespace(),
+		FileGroup: metadata.GetFileGroup(),
+		FileName:  metadata.GetFileName(),
+	}
+	resp, err := c.connector.DeleteConfigFile(configFile)
+	return c.handleWriteResponse("delete", configFile, resp, err)
+}
+
+// toUploadConfigFile 将请求转换为上传的配置文件，并经过过滤链的上传处理
+func (c *ConfigFileFlow) toUploadConfigFile(req *model.ConfigFileRequest) (*configconnector.ConfigFile, error) {
+	configFile := &configconnector.ConfigFile{
+		Namespace: req.GetNamespace(),
+		FileGroup: req.GetFileGroup(),
+		FileName:  req.GetFileName(),
+		Content:   req.Content,
+		Encrypted: req.Encrypted,
+	}
+	keys := make([]string, 0, len(req.Tags))
+	for key := range req.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		configFile.SetTag(key, req.Tags[key])
+	}
+	if len(req.EncryptAlgo) > 0 {
+		configFile.SetTag(configconnector.ConfigFileTagKeyEncryptAlgo, req.EncryptAlgo)
+	}
+	if err := c.chain.BeforeUpload(configFile); err != nil {
+		return nil, err
+	}
+	return configFile, nil
+}
+
+// handleWriteResponse 将写操作的应答码转换为错误
+func (c *ConfigFileFlow) handleWriteResponse(op string, configFile *configconnector.ConfigFile,
+	resp *configconnector.ConfigFileResponse, err error) error {
+	if err != nil {
+		log.GetBaseLogger().Errorf("[Config] fail to %s config file %s/%s/%s, err: %v", op,
+			configFile.GetNamespace(), configFile.GetFileGroup(), configFile.GetFileName(), err)
+		return err
+	}
+	code := resp.GetCode()
+	if pb.ConvertServerErrorToRpcError(code) == model.ErrCodeSuccess {
+		log.GetBaseLogger().Infof("[Config] success to %s config file %s/%s/%s", op,
+			configFile.GetNamespace(), configFile.GetFileGroup(), configFile.GetFileName())
+		return nil
+	}
+	var errCode model.ErrCode
+	switch apimodel.Code(code) {
+	case apimodel.Code_ExistedResource, apimodel.Code_DataConflict:
+		errCode = model.ErrCodeConfigFileConflict
+	case apimodel.Code_NotFoundResource:
+		errCode = model.ErrCodeConfigFileNotFound
+	default:
+		errCode = model.ErrCodeServerUserError
+	}
+	return model.NewSDKError(errCode, nil, "fail to %s config file %s/%s/%s, server code %d, message %s", op,
+		configFile.GetNamespace(), configFile.GetFileGroup(), configFile.GetFileName(), code, resp.GetMessage())
+}
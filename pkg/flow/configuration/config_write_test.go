@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
+)
+
+// writeConfigConnector 记录写操作请求并返回指定应答码的配置中心连接器
+type writeConfigConnector struct {
+	configconnector.ConfigConnector
+	code      apimodel.Code
+	err       error
+	requests  []*configconnector.ConfigFile
+	operation string
+}
+
+func (c *writeConfigConnector) response(op string,
+	configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	c.operation = op
+	c.requests = append(c.requests, configFile)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &configconnector.ConfigFileResponse{Code: uint32(c.code), Message: c.code.String()}, nil
+}
+
+func (c *writeConfigConnector) CreateConfigFile(
+	configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	return c.response("create", configFile)
+}
+
+func (c *writeConfigConnector) UpdateConfigFile(
+	configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	return c.response("update", configFile)
+}
+
+func (c *writeConfigConnector) PublishConfigFile(
+	configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	return c.response("publish", configFile)
+}
+
+func (c *writeConfigConnector) DeleteConfigFile(
+	configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	return c.response("delete", configFile)
+}
+
+// uploadFilter 上传前为配置文件增加标签的过滤器
+type uploadFilter struct {
+	configfilter.ConfigFilter
+}
+
+func (f *uploadFilter) BeforeUpload(configFile *configconnector.ConfigFile) error {
+	configFile.SetTag("filtered", "true")
+	return nil
+}
+
+func assertSDKErrCode(t *testing.T, expect model.ErrCode, err error) {
+	if !assert.NotNil(t, err) {
+		return
+	}
+	sdkErr, ok := err.(model.SDKError)
+	if assert.True(t, ok) {
+		assert.Equal(t, expect, sdkErr.ErrorCode())
+	}
+}
+
+// TestConfigFileWriteResponse 测试配置文件写操作的应答码转换
+func TestConfigFileWriteResponse(t *testing.T) {
+	connector := &writeConfigConnector{code: apimodel.Code_ExecuteSuccess}
+	flow := &ConfigFileFlow{connector: connector, chain: configfilter.Chain{&uploadFilter{}}}
+	req := &model.ConfigFileRequest{
+		Content: "key: value",
+		Tags:    map[string]string{"b": "2", "a": "1"},
+	}
+	req.Namespace = "default"
+	req.FileGroup = "group"
+	req.FileName = "app.yaml"
+
+	assert.Nil(t, flow.CreateConfigFile(req))
+	assert.Equal(t, "create", connector.operation)
+	created := connector.requests[0]
+	assert.Equal(t, "key: value", created.GetContent())
+	assert.Equal(t, []*configconnector.ConfigFileTag{
+		{Key: "a", Value: "1"}, {Key: "b", Value: "2"}, {Key: "filtered", Value: "true"},
+	}, created.Tags)
+
+	connector.code = apimodel.Code_ExistedResource
+	assertSDKErrCode(t, model.ErrCodeConfigFileConflict, flow.CreateConfigFile(req))
+	connector.code = apimodel.Code_DataConflict
+	assertSDKErrCode(t, model.ErrCodeConfigFileConflict, flow.UpdateConfigFile(req))
+	connector.code = apimodel.Code_NotFoundResource
+	assertSDKErrCode(t, model.ErrCodeConfigFileNotFound, flow.PublishConfigFile(req))
+	connector.code = apimodel.Code_InvalidParameter
+	assertSDKErrCode(t, model.ErrCodeServerUserError, flow.UpdateConfigFile(req))
+
+	connector.code = apimodel.Code_ExecuteSuccess
+	assert.Nil(t, flow.DeleteConfigFile(req))
+	assert.Equal(t, "delete", connector.operation)
+
+	// 连接器返回的错误直接返回给调用方
+	netErr := model.NewSDKError(model.ErrCodeNetworkError, errors.New("connection refused"), "network error")
+	connector.err = netErr
+	assert.Equal(t, netErr, flow.DeleteConfigFile(req))
+}
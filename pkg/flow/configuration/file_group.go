@@ -0,0 +1,215 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"sort"
+	"sync"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+// configFileGroup 配置分组，定时从服务端拉取分组下已发布的配置文件列表，列表变更时触发变更事件
+type configFileGroup struct {
+	namespace string
+	group     string
+	connector configconnector.ConfigConnector
+
+	lock     sync.RWMutex
+	revision string
+	files    []*model.SimpleConfigFile
+
+	listenerLock        sync.RWMutex
+	changeListeners     []model.OnConfigGroupChange
+	changeListenerChans []chan model.ConfigGroupChangeEvent
+}
+
+func newConfigFileGroup(namespace, group string, connector configconnector.ConfigConnector) *configFileGroup {
+	return &configFileGroup{
+		namespace: namespace,
+		group:     group,
+		connector: connector,
+	}
+}
+
+// GetNamespace 获取命名空间
+func (g *configFileGroup) GetNamespace() string {
+	return g.namespace
+}
+
+// GetGroup 获取配置分组名
+func (g *configFileGroup) GetGroup() string {
+	return g.group
+}
+
+// GetRevision 获取配置分组的版本
+func (g *configFileGroup) GetRevision() string {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.revision
+}
+
+// GetFiles 获取分组下已发布的配置文件
+func (g *configFileGroup) GetFiles() []*model.SimpleConfigFile {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	files := make([]*model.SimpleConfigFile, 0, len(g.files))
+	for _, file := range g.files {
+		copied := *file
+		files = append(files, &copied)
+	}
+	return files
+}
+
+// AddChangeListenerWithChannel 增加配置分组变更监听器
+func (g *configFileGroup) AddChangeListenerWithChannel() <-chan model.ConfigGroupChangeEvent {
+	g.listenerLock.Lock()
+	defer g.listenerLock.Unlock()
+	changeChan := make(chan model.ConfigGroupChangeEvent, 64)
+	g.changeListenerChans = append(g.changeListenerChans, changeChan)
+	return changeChan
+}
+
+// AddChangeListener 增加配置分组变更监听器
+func (g *configFileGroup) AddChangeListener(cb model.OnConfigGroupChange) {
+	g.listenerLock.Lock()
+	defer g.listenerLock.Unlock()
+	g.changeListeners = append(g.changeListeners, cb)
+}
+
+// pull 从服务端拉取分组下已发布的配置文件列表
+func (g *configFileGroup) pull() error {
+	resp, err := g.connector.GetConfigGroup(&configconnector.ConfigGroup{
+		Namespace: g.namespace,
+		Group:     g.group,
+		Revision:  g.GetRevision(),
+	})
+	if err != nil {
+		return err
+	}
+	switch apimodel.Code(resp.GetCode()) {
+	case apimodel.Code_DataNoChange:
+		return nil
+	case apimodel.Code_NotFoundResource:
+		// 分组不存在或者分组下没有已发布的配置文件
+		g.update("", nil)
+		return nil
+	case apimodel.Code_ExecuteSuccess:
+		files := make([]*model.SimpleConfigFile, 0, len(resp.GetConfigFiles()))
+		for _, configFile := range resp.GetConfigFiles() {
+			files = append(files, &model.SimpleConfigFile{
+				Namespace: g.namespace,
+				FileGroup: g.group,
+				FileName:  configFile.GetFileName(),
+				Version:   configFile.GetVersion(),
+				Md5:       configFile.GetMd5(),
+			})
+		}
+		g.update(resp.GetRevision(), files)
+		return nil
+	default:
+		return model.NewSDKError(model.ErrCodeServerUserError, nil,
+			"fail to get config group %s/%s, server code %d, message %s",
+			g.namespace, g.group, resp.GetCode(), resp.GetMessage())
+	}
+}
+
+// update 更新配置文件列表，发生变更时触发变更事件
+func (g *configFileGroup) update(revision string, files []*model.SimpleConfigFile) {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].FileName < files[j].FileName
+	})
+	g.lock.Lock()
+	oldFiles := g.files
+	g.revision = revision
+	g.files = files
+	g.lock.Unlock()
+
+	changes := diffGroupFiles(oldFiles, files)
+	if len(changes) == 0 {
+		return
+	}
+	log.GetBaseLogger().Infof("[Config] config group %s/%s changed, revision %s, changed files %d",
+		g.namespace, g.group, revision, len(changes))
+	event := model.ConfigGroupChangeEvent{
+		Namespace: g.namespace,
+		Group:     g.group,
+		Changes:   changes,
+		Files:     g.GetFiles(),
+	}
+	g.listenerLock.RLock()
+	listenerChans := g.changeListenerChans
+	changeListeners := g.changeListeners
+	g.listenerLock.RUnlock()
+	for _, listenerChan := range listenerChans {
+		// 监听方没有及时消费时丢弃事件，避免阻塞配置分组的刷新任务
+		select {
+		case listenerChan <- event:
+		default:
+			log.GetBaseLogger().Warnf("[Config] change listener channel of config group %s/%s is full, "+
+				"drop the change event", g.namespace, g.group)
+		}
+	}
+	for _, changeListener := range changeListeners {
+		changeListener(event)
+	}
+}
+
+// diffGroupFiles 对比配置分组下配置文件的变更，新版本号不同即认为重新发布
+func diffGroupFiles(oldFiles, newFiles []*model.SimpleConfigFile) []*model.ConfigGroupFileChange {
+	oldVersions := make(map[string]uint64, len(oldFiles))
+	for _, file := range oldFiles {
+		oldVersions[file.FileName] = file.Version
+	}
+	changes := make([]*model.ConfigGroupFileChange, 0)
+	for _, file := range newFiles {
+		oldVersion, ok := oldVersions[file.FileName]
+		delete(oldVersions, file.FileName)
+		if !ok {
+			changes = append(changes, &model.ConfigGroupFileChange{
+				FileName:   file.FileName,
+				NewVersion: file.Version,
+				ChangeType: model.Added,
+			})
+			continue
+		}
+		if oldVersion != file.Version {
+			changes = append(changes, &model.ConfigGroupFileChange{
+				FileName:   file.FileName,
+				OldVersion: oldVersion,
+				NewVersion: file.Version,
+				ChangeType: model.Modified,
+			})
+		}
+	}
+	for fileName, oldVersion := range oldVersions {
+		changes = append(changes, &model.ConfigGroupFileChange{
+			FileName:   fileName,
+			OldVersion: oldVersion,
+			ChangeType: model.Deleted,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].FileName < changes[j].FileName
+	})
+	return changes
+}
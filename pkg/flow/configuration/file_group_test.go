@@ -0,0 +1,214 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+// groupConfigConnector 返回指定配置分组应答的配置中心连接器，blocked不为nil时阻塞对应分组的请求
+type groupConfigConnector struct {
+	configconnector.ConfigConnector
+	lock      sync.Mutex
+	responses map[string]*configconnector.ConfigGroupResponse
+	revisions []string
+	blocked   map[string]chan struct{}
+}
+
+func (c *groupConfigConnector) setResponse(group string, code apimodel.Code, revision string,
+	files ...*configconnector.ConfigFile) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.responses[group] = &configconnector.ConfigGroupResponse{
+		Code:        uint32(code),
+		Revision:    revision,
+		ConfigFiles: files,
+	}
+}
+
+func (c *groupConfigConnector) GetConfigGroup(
+	group *configconnector.ConfigGroup) (*configconnector.ConfigGroupResponse, error) {
+	c.lock.Lock()
+	blocked := c.blocked[group.GetGroup()]
+	c.lock.Unlock()
+	if blocked != nil {
+		<-blocked
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.revisions = append(c.revisions, group.GetRevision())
+	return c.responses[group.GetGroup()], nil
+}
+
+func newGroupConfigConnector() *groupConfigConnector {
+	return &groupConfigConnector{
+		responses: map[string]*configconnector.ConfigGroupResponse{},
+		blocked:   map[string]chan struct{}{},
+	}
+}
+
+// TestDiffGroupFiles 测试配置分组下配置文件的新增、重新发布以及删除
+func TestDiffGroupFiles(t *testing.T) {
+	changes := diffGroupFiles([]*model.SimpleConfigFile{
+		{FileName: "a.yaml", Version: 1},
+		{FileName: "b.yaml", Version: 2},
+		{FileName: "c.yaml", Version: 3},
+	}, []*model.SimpleConfigFile{
+		{FileName: "a.yaml", Version: 1},
+		{FileName: "b.yaml", Version: 5},
+		{FileName: "d.yaml", Version: 4},
+	})
+	assert.Equal(t, []*model.ConfigGroupFileChange{
+		{FileName: "b.yaml", OldVersion: 2, NewVersion: 5, ChangeType: model.Modified},
+		{FileName: "c.yaml", OldVersion: 3, ChangeType: model.Deleted},
+		{FileName: "d.yaml", NewVersion: 4, ChangeType: model.Added},
+	}, changes)
+	assert.Empty(t, diffGroupFiles(nil, nil))
+	assert.Empty(t, diffGroupFiles([]*model.SimpleConfigFile{{FileName: "a.yaml", Version: 1}},
+		[]*model.SimpleConfigFile{{FileName: "a.yaml", Version: 1}}))
+}
+
+// TestConfigGroupPull 测试刷新配置分组时的应答码处理以及变更事件
+func TestConfigGroupPull(t *testing.T) {
+	connector := newGroupConfigConnector()
+	fileGroup := newConfigFileGroup("default", "group", connector)
+	var events []model.ConfigGroupChangeEvent
+	fileGroup.AddChangeListener(func(event model.ConfigGroupChangeEvent) {
+		events = append(events, event)
+	})
+	changeChan := fileGroup.AddChangeListenerWithChannel()
+
+	connector.setResponse("group", apimodel.Code_ExecuteSuccess, "r1",
+		&configconnector.ConfigFile{FileName: "b.yaml", Version: 2, Md5: "md5-b"},
+		&configconnector.ConfigFile{FileName: "a.yaml", Version: 1, Md5: "md5-a"})
+	assert.Nil(t, fileGroup.pull())
+	assert.Equal(t, "r1", fileGroup.GetRevision())
+	assert.Equal(t, []*model.SimpleConfigFile{
+		{Namespace: "default", FileGroup: "group", FileName: "a.yaml", Version: 1, Md5: "md5-a"},
+		{Namespace: "default", FileGroup: "group", FileName: "b.yaml", Version: 2, Md5: "md5-b"},
+	}, fileGroup.GetFiles())
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, 2, len(events[0].Changes))
+	event := <-changeChan
+	assert.Equal(t, "a.yaml", event.Changes[0].FileName)
+
+	// 版本未变化时携带当前版本请求，不触发事件
+	connector.setResponse("group", apimodel.Code_DataNoChange, "")
+	assert.Nil(t, fileGroup.pull())
+	assert.Equal(t, "r1", connector.revisions[len(connector.revisions)-1])
+	assert.Equal(t, "r1", fileGroup.GetRevision())
+	assert.Equal(t, 1, len(events))
+
+	// 分组下已发布的配置文件全部删除
+	connector.setResponse("group", apimodel.Code_NotFoundResource, "")
+	assert.Nil(t, fileGroup.pull())
+	assert.Empty(t, fileGroup.GetFiles())
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, model.Deleted, events[1].Changes[0].ChangeType)
+
+	connector.setResponse("group", apimodel.Code_InvalidParameter, "")
+	err := fileGroup.pull()
+	assert.NotNil(t, err)
+	assert.Equal(t, model.ErrCodeServerUserError, err.(model.SDKError).ErrorCode())
+}
+
+// TestConfigGroupFullChannel 测试监听通道已满时丢弃事件，不阻塞刷新
+func TestConfigGroupFullChannel(t *testing.T) {
+	connector := newGroupConfigConnector()
+	fileGroup := newConfigFileGroup("default", "group", connector)
+	changeChan := fileGroup.AddChangeListenerWithChannel()
+	var lastEvent model.ConfigGroupChangeEvent
+	fileGroup.AddChangeListener(func(event model.ConfigGroupChangeEvent) {
+		lastEvent = event
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= cap(changeChan)+10; i++ {
+			fileGroup.update("", []*model.SimpleConfigFile{{FileName: "a.yaml", Version: uint64(i)}})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("config group change event is blocked by full channel")
+	}
+	assert.Equal(t, cap(changeChan), len(changeChan))
+	assert.Equal(t, uint64(cap(changeChan)+10), lastEvent.Changes[0].NewVersion)
+}
+
+// TestGetConfigGroup 测试拉取配置分组时不阻塞其他分组的获取，并由刷新任务更新配置文件列表
+func TestGetConfigGroup(t *testing.T) {
+	connector := newGroupConfigConnector()
+	configuration := config.NewDefaultConfiguration(nil)
+	configuration.Config.SetGroupRefreshInterval(config.DefaultMinTimingInterval)
+	flow := NewConfigFileFlow(connector, nil, configuration)
+	defer flow.Destroy()
+
+	connector.setResponse("slow", apimodel.Code_NotFoundResource, "")
+	connector.setResponse("fast", apimodel.Code_ExecuteSuccess, "r1",
+		&configconnector.ConfigFile{FileName: "a.yaml", Version: 1})
+	release := make(chan struct{})
+	connector.blocked["slow"] = release
+
+	slowDone := make(chan struct{})
+	go func() {
+		defer close(slowDone)
+		_, err := flow.GetConfigGroup("default", "slow")
+		assert.Nil(t, err)
+	}()
+	fastDone := make(chan model.ConfigFileGroup, 1)
+	go func() {
+		fileGroup, err := flow.GetConfigGroup("default", "fast")
+		assert.Nil(t, err)
+		fastDone <- fileGroup
+	}()
+	var fileGroup model.ConfigFileGroup
+	select {
+	case fileGroup = <-fastDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("get config group is blocked by pulling another group")
+	}
+	close(release)
+	<-slowDone
+
+	cached, err := flow.GetConfigGroup("default", "fast")
+	assert.Nil(t, err)
+	assert.True(t, fileGroup == cached)
+
+	changeChan := fileGroup.AddChangeListenerWithChannel()
+	connector.setResponse("fast", apimodel.Code_ExecuteSuccess, "r2",
+		&configconnector.ConfigFile{FileName: "a.yaml", Version: 2})
+	select {
+	case event := <-changeChan:
+		assert.Equal(t, model.Modified, event.Changes[0].ChangeType)
+		assert.Equal(t, uint64(2), event.Files[0].Version)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config group is not refreshed")
+	}
+}
@@ -19,6 +19,7 @@ package configuration
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
@@ -47,6 +48,9 @@ type ConfigFileRepo struct {
 	remoteConfigFile   *configconnector.ConfigFile // 从服务端获取的原始配置对象
 	retryPolicy        retryPolicy
 	listeners          []ConfigFileRepoChangeListener
+	persistHandler     *configFilePersistHandler
+	// 配置是否来自本地缓存且尚未与服务端同步，1代表是
+	stale int32
 }
 
 // ConfigFileRepoChangeListener 远程配置文件发布监听器
@@ -56,11 +60,13 @@ type ConfigFileRepoChangeListener func(configFileMetadata model.ConfigFileMetada
 func newConfigFileRepo(metadata model.ConfigFileMetadata,
 	connector configconnector.ConfigConnector,
 	chain configfilter.Chain,
-	configuration config.Configuration) (*ConfigFileRepo, error) {
+	configuration config.Configuration,
+	persistHandler *configFilePersistHandler) (*ConfigFileRepo, error) {
 	repo := &ConfigFileRepo{
 		connector:          connector,
 		chain:              chain,
 		configuration:      configuration,
+		persistHandler:     persistHandler,
 		configFileMetadata: metadata,
 		notifiedVersion:    initVersion,
 		retryPolicy: retryPolicy{
@@ -76,11 +82,65 @@ func newConfigFileRepo(metadata model.ConfigFileMetadata,
 	}
 	// 1. 同步从服务端拉取配置
 	if err := repo.pull(); err != nil {
-		return nil, err
+		// 2. 配置中心不可用时，使用本地缓存的配置启动，待长轮询恢复后再与服务端同步
+		if !repo.loadFromPersist() {
+			return nil, err
+		}
 	}
 	return repo, nil
 }
 
+// loadFromPersist 从本地缓存加载服务端返回的原始配置文件，经过配置过滤链处理后使用，加载成功后标记为过期配置
+func (r *ConfigFileRepo) loadFromPersist() bool {
+	if r.persistHandler == nil {
+		return false
+	}
+	cachedConfigFile, err := r.persistHandler.load(r.configFileMetadata)
+	if err != nil {
+		log.GetBaseLogger().Errorf("[Config] fail to load config file from local cache. file = %+v, err = %v",
+			r.configFileMetadata, err)
+		return false
+	}
+	loadConfigFileReq := &configconnector.ConfigFile{
+		Namespace: r.configFileMetadata.GetNamespace(),
+		FileGroup: r.configFileMetadata.GetFileGroup(),
+		FileName:  r.configFileMetadata.GetFileName(),
+	}
+	response, err := r.chain.Execute(loadConfigFileReq,
+		func(*configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+			return &configconnector.ConfigFileResponse{
+				Code:       uint32(apimodel.Code_ExecuteSuccess),
+				ConfigFile: deepCloneConfigFile(cachedConfigFile),
+			}, nil
+		})
+	if err != nil {
+		log.GetBaseLogger().Errorf("[Config] fail to filter config file from local cache. file = %+v, err = %v",
+			r.configFileMetadata, err)
+		return false
+	}
+	log.GetBaseLogger().Warnf("[Config] config server is unavailable, use stale config file from local cache. "+
+		"file = %+v, version = %d", r.configFileMetadata, cachedConfigFile.GetVersion())
+	r.remoteConfigFile = response.GetConfigFile()
+	atomic.StoreInt32(&r.stale, 1)
+	return true
+}
+
+// isStale 配置是否来自本地缓存且尚未与服务端同步
+func (r *ConfigFileRepo) isStale() bool {
+	return atomic.LoadInt32(&r.stale) == 1
+}
+
+// reconcile 与服务端重新同步来自本地缓存的配置
+func (r *ConfigFileRepo) reconcile() {
+	if !r.isStale() {
+		return
+	}
+	if err := r.pull(); err != nil {
+		log.GetBaseLogger().Errorf("[Config] fail to reconcile stale config file. file = %+v, err = %v",
+			r.configFileMetadata, err)
+	}
+}
+
 func (r *ConfigFileRepo) GetNotifiedVersion() uint64 {
 	return r.notifiedVersion
 }
@@ -125,7 +185,19 @@ func (r *ConfigFileRepo) pull() error {
 	for retryTimes < 3 {
 		startTime := time.Now()
 
-		response, err := r.chain.Execute(pullConfigFileReq, r.connector.GetConfigFile)
+		var (
+			response *configconnector.ConfigFileResponse
+			// 服务端返回的原始配置文件，加密配置文件为密文，用于本地持久化
+			rawConfigFile *configconnector.ConfigFile
+		)
+		response, err = r.chain.Execute(pullConfigFileReq,
+			func(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+				resp, err := r.connector.GetConfigFile(configFile)
+				if err == nil && resp.GetConfigFile() != nil {
+					rawConfigFile = deepCloneConfigFile(resp.GetConfigFile())
+				}
+				return resp, err
+			})
 
 		if err != nil {
 			log.GetBaseLogger().Errorf("[Config] failed to pull config file. retry times = %d, err = %v", retryTimes, err)
@@ -149,11 +221,15 @@ func (r *ConfigFileRepo) pull() error {
 
 		// 拉取成功
 		if responseCode == uint32(apimodel.Code_ExecuteSuccess) {
-			// 本地配置文件落后，更新内存缓存
-			if r.remoteConfigFile == nil || pulledConfigFile.Version >= r.remoteConfigFile.Version {
+			// 本地配置文件落后或者来自本地缓存，以服务端为准更新内存缓存
+			if r.remoteConfigFile == nil || r.isStale() || pulledConfigFile.Version >= r.remoteConfigFile.Version {
 				r.remoteConfigFile = deepCloneConfigFile(pulledConfigFile)
+				if r.persistHandler != nil && rawConfigFile != nil {
+					r.persistHandler.save(rawConfigFile)
+				}
 				r.fireChangeEvent(pulledConfigFile.GetContent())
 			}
+			atomic.StoreInt32(&r.stale, 0)
 			return nil
 		}
 
@@ -163,8 +239,12 @@ func (r *ConfigFileRepo) pull() error {
 			// 删除配置文件
 			if r.remoteConfigFile != nil {
 				r.remoteConfigFile = nil
+				if r.persistHandler != nil {
+					r.persistHandler.delete(r.configFileMetadata)
+				}
 				r.fireChangeEvent(NotExistedFileContent)
 			}
+			atomic.StoreInt32(&r.stale, 0)
 			return nil
 		}
 
@@ -0,0 +1,329 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// cachedPropertyValue 类型转换后的配置值缓存
+type cachedPropertyValue struct {
+	value    interface{}
+	expireAt time.Time
+}
+
+// configKVFile 结构化配置文件，配置内容变更时重新解析配置项并触发配置项变更事件
+type configKVFile struct {
+	*defaultConfigFile
+
+	format     model.ConfigFileFormat
+	cacheSize  int
+	expireTime time.Duration
+
+	kvLock     sync.RWMutex
+	properties map[string]string
+	// 类型转换结果的缓存，配置内容变更时清空
+	valueCache map[string]*cachedPropertyValue
+	// 配置项的版本，配置内容变更时递增，避免将旧配置项的转换结果写入清空后的缓存
+	generation uint64
+
+	kvChangeListeners     []model.OnConfigKVFileChange
+	kvChangeListenerChans []chan model.ConfigKVFileChangeEvent
+}
+
+// newConfigKVFile 创建结构化配置文件，初始配置内容无法解析时返回错误
+func newConfigKVFile(metadata model.ConfigFileMetadata, repo *ConfigFileRepo, format model.ConfigFileFormat,
+	cfg config.ConfigFileConfig) (*configKVFile, error) {
+	content := repo.GetContent()
+	if content == NotExistedFileContent {
+		content = ""
+	}
+	properties, err := parseProperties(format, content)
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidArgument, err,
+			"fail to parse %s config file %s/%s/%s", format,
+			metadata.GetNamespace(), metadata.GetFileGroup(), metadata.GetFileName())
+	}
+	kvFile := &configKVFile{
+		format:     format,
+		cacheSize:  int(cfg.GetPropertiesValueCacheSize()),
+		expireTime: time.Duration(cfg.GetPropertiesValueExpireTime()) * time.Millisecond,
+		properties: properties,
+		valueCache: make(map[string]*cachedPropertyValue),
+	}
+	kvFile.defaultConfigFile = newDefaultConfigFile(metadata, repo, kvFile.onContentChange)
+	return kvFile, nil
+}
+
+// GetFormat 获取配置文件格式
+func (c *configKVFile) GetFormat() model.ConfigFileFormat {
+	return c.format
+}
+
+// GetPropertyNames 获取所有的配置项名称
+func (c *configKVFile) GetPropertyNames() []string {
+	c.kvLock.RLock()
+	defer c.kvLock.RUnlock()
+	names := make([]string, 0, len(c.properties))
+	for key := range c.properties {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetProperty 获取配置项的值
+func (c *configKVFile) GetProperty(key string, defaultValue string) string {
+	c.kvLock.RLock()
+	defer c.kvLock.RUnlock()
+	if value, ok := c.properties[key]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+// GetIntProperty 获取int类型的配置项
+func (c *configKVFile) GetIntProperty(key string, defaultValue int) int {
+	value, ok := c.getTypedProperty(key, "int", func(s string) (interface{}, error) {
+		return strconv.Atoi(s)
+	})
+	if !ok {
+		return defaultValue
+	}
+	return value.(int)
+}
+
+// GetInt64Property 获取int64类型的配置项
+func (c *configKVFile) GetInt64Property(key string, defaultValue int64) int64 {
+	value, ok := c.getTypedProperty(key, "int64", func(s string) (interface{}, error) {
+		return strconv.ParseInt(s, 10, 64)
+	})
+	if !ok {
+		return defaultValue
+	}
+	return value.(int64)
+}
+
+// GetFloat64Property 获取float64类型的配置项
+func (c *configKVFile) GetFloat64Property(key string, defaultValue float64) float64 {
+	value, ok := c.getTypedProperty(key, "float64", func(s string) (interface{}, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+	if !ok {
+		return defaultValue
+	}
+	return value.(float64)
+}
+
+// GetBoolProperty 获取bool类型的配置项
+func (c *configKVFile) GetBoolProperty(key string, defaultValue bool) bool {
+	value, ok := c.getTypedProperty(key, "bool", func(s string) (interface{}, error) {
+		return strconv.ParseBool(s)
+	})
+	if !ok {
+		return defaultValue
+	}
+	return value.(bool)
+}
+
+// GetDurationProperty 获取时间类型的配置项
+func (c *configKVFile) GetDurationProperty(key string, defaultValue time.Duration) time.Duration {
+	value, ok := c.getTypedProperty(key, "duration", func(s string) (interface{}, error) {
+		return time.ParseDuration(s)
+	})
+	if !ok {
+		return defaultValue
+	}
+	return value.(time.Duration)
+}
+
+// GetStringSliceProperty 获取字符串数组配置项，支持以,分隔的配置值以及yaml/json中的数组
+func (c *configKVFile) GetStringSliceProperty(key string, defaultValue []string) []string {
+	c.kvLock.RLock()
+	defer c.kvLock.RUnlock()
+	if value, ok := c.properties[key]; ok {
+		if len(value) == 0 {
+			return []string{}
+		}
+		values := strings.Split(value, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		return values
+	}
+	var values []string
+	for i := 0; ; i++ {
+		value, ok := c.properties[fmt.Sprintf("%s[%d]", key, i)]
+		if !ok {
+			break
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return defaultValue
+	}
+	return values
+}
+
+// Unmarshal 将配置文件内容解析到结构体中，yaml和properties格式使用yaml标签，json格式使用json标签
+func (c *configKVFile) Unmarshal(v interface{}) error {
+	switch c.format {
+	case model.ConfigFileFormatYaml:
+		return yaml.Unmarshal([]byte(c.GetContent()), v)
+	case model.ConfigFileFormatJSON:
+		content := c.GetContent()
+		if strings.TrimSpace(content) == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(content), v)
+	case model.ConfigFileFormatProperties:
+		c.kvLock.RLock()
+		tree := propertiesToTree(c.properties)
+		c.kvLock.RUnlock()
+		data, err := yaml.Marshal(tree)
+		if err != nil {
+			return err
+		}
+		return yaml.Unmarshal(data, v)
+	default:
+		return fmt.Errorf("unsupported config file format %q", c.format)
+	}
+}
+
+// AddKVChangeListenerWithChannel 增加配置项变更监听器
+func (c *configKVFile) AddKVChangeListenerWithChannel() <-chan model.ConfigKVFileChangeEvent {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	changeChan := make(chan model.ConfigKVFileChangeEvent, 64)
+	c.kvChangeListenerChans = append(c.kvChangeListenerChans, changeChan)
+	return changeChan
+}
+
+// AddKVChangeListener 增加配置项变更监听器
+func (c *configKVFile) AddKVChangeListener(cb model.OnConfigKVFileChange) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.kvChangeListeners = append(c.kvChangeListeners, cb)
+}
+
+// getTypedProperty 获取类型转换后的配置值，转换结果按照 config.propertiesValueCacheSize 以及
+// config.propertiesValueExpireTime 进行缓存
+func (c *configKVFile) getTypedProperty(key string, typ string,
+	convert func(string) (interface{}, error)) (interface{}, bool) {
+	cacheKey := typ + separator + key
+	now := time.Now()
+	c.kvLock.RLock()
+	cached, ok := c.valueCache[cacheKey]
+	value, exist := c.properties[key]
+	generation := c.generation
+	c.kvLock.RUnlock()
+	if ok && now.Before(cached.expireAt) {
+		return cached.value, true
+	}
+	if !exist {
+		return nil, false
+	}
+	typedValue, err := convert(value)
+	if err != nil {
+		log.GetBaseLogger().Warnf("[Config] fail to convert property %s value %s to %s, err: %v",
+			key, value, typ, err)
+		return nil, false
+	}
+	c.cacheTypedValue(cacheKey, typedValue, generation, now)
+	return typedValue, true
+}
+
+// cacheTypedValue 缓存类型转换结果，缓存已满时先淘汰过期的缓存，仍然没有空间则不缓存
+// 转换期间配置内容发生了变更时，转换结果已经过期，不写入缓存
+func (c *configKVFile) cacheTypedValue(cacheKey string, value interface{}, generation uint64, now time.Time) {
+	if c.cacheSize <= 0 || c.expireTime <= 0 {
+		return
+	}
+	c.kvLock.Lock()
+	defer c.kvLock.Unlock()
+	if generation != c.generation {
+		return
+	}
+	if len(c.valueCache) >= c.cacheSize {
+		for k, v := range c.valueCache {
+			if !now.Before(v.expireAt) {
+				delete(c.valueCache, k)
+			}
+		}
+		if len(c.valueCache) >= c.cacheSize {
+			return
+		}
+	}
+	c.valueCache[cacheKey] = &cachedPropertyValue{
+		value:    value,
+		expireAt: now.Add(c.expireTime),
+	}
+}
+
+// onContentChange 配置内容变更时重新解析配置项，在配置文件变更事件之前执行
+func (c *configKVFile) onContentChange(newContent string) {
+	properties, err := parseProperties(c.format, newContent)
+	if err != nil {
+		log.GetBaseLogger().Errorf("[Config] fail to parse %s config file %+v, keep the previous properties, err: %v",
+			c.format, c.DefaultConfigFileMetadata, err)
+		return
+	}
+	c.kvLock.Lock()
+	oldProperties := c.properties
+	c.properties = properties
+	c.valueCache = make(map[string]*cachedPropertyValue)
+	c.generation++
+	c.kvLock.Unlock()
+
+	changes := diffProperties(oldProperties, properties)
+	if len(changes) == 0 {
+		return
+	}
+	event := model.ConfigKVFileChangeEvent{
+		ConfigFileMetadata: &c.DefaultConfigFileMetadata,
+		Changes:            changes,
+	}
+	c.lock.RLock()
+	listenerChans := c.kvChangeListenerChans
+	changeListeners := c.kvChangeListeners
+	c.lock.RUnlock()
+	for _, listenerChan := range listenerChans {
+		// 监听方没有及时消费时丢弃事件，避免阻塞配置文件的长轮询流程
+		select {
+		case listenerChan <- event:
+		default:
+			log.GetBaseLogger().Warnf("[Config] kv change listener channel of config file %+v is full, "+
+				"drop the change event", c.DefaultConfigFileMetadata)
+		}
+	}
+	for _, changeListener := range changeListeners {
+		changeListener(event)
+	}
+}
@@ -0,0 +1,105 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// newTestKVFile 创建不关联配置文件仓库的结构化配置文件
+func newTestKVFile(t *testing.T, content string) *configKVFile {
+	properties, err := parseProperties(model.ConfigFileFormatProperties, content)
+	assert.Nil(t, err)
+	kvFile := &configKVFile{
+		defaultConfigFile: &defaultConfigFile{},
+		format:            model.ConfigFileFormatProperties,
+		cacheSize:         10,
+		expireTime:        time.Minute,
+		properties:        properties,
+		valueCache:        make(map[string]*cachedPropertyValue),
+	}
+	kvFile.Namespace = "default"
+	kvFile.FileGroup = "group"
+	kvFile.FileName = "app.properties"
+	return kvFile
+}
+
+// TestKVFileChangeEvent 测试配置项变更事件，监听通道已满时不阻塞
+func TestKVFileChangeEvent(t *testing.T) {
+	kvFile := newTestKVFile(t, "a=1\nb=2")
+	var events []model.ConfigKVFileChangeEvent
+	kvFile.AddKVChangeListener(func(event model.ConfigKVFileChangeEvent) {
+		events = append(events, event)
+	})
+	changeChan := kvFile.AddKVChangeListenerWithChannel()
+
+	kvFile.onContentChange("a=10\nc=3")
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, model.Modified, events[0].Changes["a"].ChangeType)
+	assert.Equal(t, model.Deleted, events[0].Changes["b"].ChangeType)
+	assert.Equal(t, model.Added, events[0].Changes["c"].ChangeType)
+	event := <-changeChan
+	assert.Equal(t, "10", event.Changes["a"].NewValue)
+
+	// 配置项没有变化时不触发事件
+	kvFile.onContentChange("c=3\na=10")
+	assert.Equal(t, 1, len(events))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < cap(changeChan)+10; i++ {
+			kvFile.onContentChange(fmt.Sprintf("a=%d", i))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("kv change event is blocked by full channel")
+	}
+	assert.Equal(t, cap(changeChan), len(changeChan))
+	assert.Equal(t, fmt.Sprint(cap(changeChan)+9), kvFile.GetProperty("a", ""))
+}
+
+// TestKVFileTypedValueCache 测试类型转换结果的缓存，配置内容变更后旧的转换结果不写入缓存
+func TestKVFileTypedValueCache(t *testing.T) {
+	kvFile := newTestKVFile(t, "port=8080\nenable=true\ntimeout=1s")
+	assert.Equal(t, 8080, kvFile.GetIntProperty("port", 0))
+	assert.True(t, kvFile.GetBoolProperty("enable", false))
+	assert.Equal(t, time.Second, kvFile.GetDurationProperty("timeout", 0))
+	assert.Equal(t, 1, kvFile.GetIntProperty("enable", 1))
+	assert.Equal(t, 3, len(kvFile.valueCache))
+
+	kvFile.onContentChange("port=9090")
+	assert.Equal(t, 9090, kvFile.GetIntProperty("port", 0))
+	assert.False(t, kvFile.GetBoolProperty("enable", false))
+
+	// 模拟配置内容变更前开始的类型转换
+	kvFile.kvLock.RLock()
+	generation := kvFile.generation
+	kvFile.kvLock.RUnlock()
+	kvFile.onContentChange("port=7070")
+	kvFile.cacheTypedValue("int"+separator+"port", 9090, generation, time.Now())
+	assert.Equal(t, 7070, kvFile.GetIntProperty("port", 0))
+}
@@ -0,0 +1,331 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// parseProperties 按照配置文件格式将配置内容解析为扁平化的配置项
+func parseProperties(format model.ConfigFileFormat, content string) (map[string]string, error) {
+	properties := make(map[string]string)
+	if strings.TrimSpace(content) == "" {
+		return properties, nil
+	}
+	var (
+		root interface{}
+		err  error
+	)
+	switch format {
+	case model.ConfigFileFormatProperties:
+		return parsePropertiesContent(content)
+	case model.ConfigFileFormatYaml:
+		err = yaml.Unmarshal([]byte(content), &root)
+	case model.ConfigFileFormatJSON:
+		decoder := json.NewDecoder(strings.NewReader(content))
+		// 保留数字的原始格式，避免大整数被转换为科学计数法
+		decoder.UseNumber()
+		err = decoder.Decode(&root)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch root.(type) {
+	case nil:
+		return properties, nil
+	case map[interface{}]interface{}, map[string]interface{}:
+		flattenValue("", root, properties)
+		return properties, nil
+	default:
+		return nil, fmt.Errorf("content of %s config file is not key-value structure", format)
+	}
+}
+
+// flattenValue 将多层级的配置展开，层级之间以.连接，数组元素以[下标]表示
+func flattenValue(prefix string, value interface{}, properties map[string]string) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		for key, subValue := range v {
+			flattenValue(joinKey(prefix, fmt.Sprint(key)), subValue, properties)
+		}
+	case map[string]interface{}:
+		for key, subValue := range v {
+			flattenValue(joinKey(prefix, key), subValue, properties)
+		}
+	case []interface{}:
+		for i, subValue := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", prefix, i), subValue, properties)
+		}
+	case nil:
+		properties[prefix] = ""
+	default:
+		properties[prefix] = fmt.Sprint(v)
+	}
+}
+
+func joinKey(prefix string, key string) string {
+	if len(prefix) == 0 {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// parsePropertiesContent 解析properties格式的配置内容，与java.util.Properties的格式保持一致
+// 支持#和!开头的注释、=、:以及空白分隔符、以奇数个\结尾的续行以及\转义字符
+func parsePropertiesContent(content string) (map[string]string, error) {
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	var (
+		logicalLine strings.Builder
+		continued   bool
+	)
+	for scanner.Scan() {
+		// 行首空白不属于配置项，续行的行首空白同样忽略
+		line := strings.TrimLeft(scanner.Text(), propertiesWhitespace)
+		if !continued && (len(line) == 0 || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+		if continued = endsWithEscape(line); continued {
+			logicalLine.WriteString(line[:len(line)-1])
+			continue
+		}
+		logicalLine.WriteString(line)
+		if err := putPropertyLine(logicalLine.String(), properties); err != nil {
+			return nil, err
+		}
+		logicalLine.Reset()
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if logicalLine.Len() > 0 {
+		if err := putPropertyLine(logicalLine.String(), properties); err != nil {
+			return nil, err
+		}
+	}
+	return properties, nil
+}
+
+// propertiesWhitespace properties格式中的空白字符
+const propertiesWhitespace = " \t\f"
+
+// endsWithEscape 行尾是否为未被转义的\，即行尾连续的\个数为奇数
+func endsWithEscape(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// putPropertyLine 解析一个逻辑行，配置项名称为空时忽略
+func putPropertyLine(line string, properties map[string]string) error {
+	key, value := splitPropertyLine(line)
+	unescapedKey, err := unescapeProperty(key)
+	if err != nil {
+		return err
+	}
+	if len(unescapedKey) == 0 {
+		return nil
+	}
+	unescapedValue, err := unescapeProperty(value)
+	if err != nil {
+		return err
+	}
+	properties[unescapedKey] = unescapedValue
+	return nil
+}
+
+// splitPropertyLine 以第一个未被转义的=、:或者空白拆分配置项名称和值
+// 名称之后的空白以及紧随其后的一个=或者:均视为分隔符
+func splitPropertyLine(line string) (string, string) {
+	idx := 0
+	for idx < len(line) {
+		c := line[idx]
+		if c == '\\' {
+			idx += 2
+			continue
+		}
+		if c == '=' || c == ':' || strings.IndexByte(propertiesWhitespace, c) >= 0 {
+			break
+		}
+		idx++
+	}
+	if idx > len(line) {
+		idx = len(line)
+	}
+	key := line[:idx]
+	value := strings.TrimLeft(line[idx:], propertiesWhitespace)
+	if len(value) > 0 && (value[0] == '=' || value[0] == ':') {
+		value = strings.TrimLeft(value[1:], propertiesWhitespace)
+	}
+	return key, value
+}
+
+// unescapeProperty 处理配置项中的转义字符，支持\t、\n、\r、\f以及\uXXXX，其他字符转义后为字符本身
+func unescapeProperty(value string) (string, error) {
+	if strings.IndexByte(value, '\\') < 0 {
+		return value, nil
+	}
+	var builder strings.Builder
+	builder.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c != '\\' {
+			builder.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(value) {
+			break
+		}
+		switch value[i] {
+		case 't':
+			builder.WriteByte('\t')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			r, err := parseUnicodeEscape(value, i+1)
+			if err != nil {
+				return "", err
+			}
+			i += 4
+			// 代理对由两个连续的\uXXXX表示
+			if utf16.IsSurrogate(r) && i+6 < len(value) && value[i+1] == '\\' && value[i+2] == 'u' {
+				if low, err := parseUnicodeEscape(value, i+3); err == nil {
+					if combined := utf16.DecodeRune(r, low); combined != unicode.ReplacementChar {
+						r = combined
+						i += 6
+					}
+				}
+			}
+			builder.WriteRune(r)
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+	return builder.String(), nil
+}
+
+// parseUnicodeEscape 解析\u之后的4位十六进制字符
+func parseUnicodeEscape(value string, start int) (rune, error) {
+	if start+4 > len(value) {
+		return 0, fmt.Errorf("malformed \\uxxxx encoding in %q", value)
+	}
+	code, err := strconv.ParseUint(value[start:start+4], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("malformed \\uxxxx encoding in %q", value)
+	}
+	return rune(code), nil
+}
+
+// propertiesToTree 将扁平化的properties配置项还原为多层级结构，用于反序列化到结构体
+// 同时存在a以及a.b时以多层级的a.b为准，保证结果与遍历顺序无关
+func propertiesToTree(properties map[string]string) map[string]interface{} {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	root := make(map[string]interface{})
+	for _, key := range keys {
+		pieces := strings.Split(key, ".")
+		node := root
+		for i, piece := range pieces {
+			if i == len(pieces)-1 {
+				if _, isTree := node[piece].(map[string]interface{}); !isTree {
+					node[piece] = inferScalar(properties[key])
+				}
+				break
+			}
+			child, ok := node[piece].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[piece] = child
+			}
+			node = child
+		}
+	}
+	return root
+}
+
+// inferScalar 推断properties配置值的类型
+func inferScalar(value string) interface{} {
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
+
+// diffProperties 对比配置项的变更
+func diffProperties(oldProperties, newProperties map[string]string) map[string]*model.ConfigPropertyChange {
+	changes := make(map[string]*model.ConfigPropertyChange)
+	for key, oldValue := range oldProperties {
+		newValue, ok := newProperties[key]
+		if !ok {
+			changes[key] = &model.ConfigPropertyChange{
+				Key:        key,
+				OldValue:   oldValue,
+				ChangeType: model.Deleted,
+			}
+			continue
+		}
+		if newValue != oldValue {
+			changes[key] = &model.ConfigPropertyChange{
+				Key:        key,
+				OldValue:   oldValue,
+				NewValue:   newValue,
+				ChangeType: model.Modified,
+			}
+		}
+	}
+	for key, newValue := range newProperties {
+		if _, ok := oldProperties[key]; !ok {
+			changes[key] = &model.ConfigPropertyChange{
+				Key:        key,
+				NewValue:   newValue,
+				ChangeType: model.Added,
+			}
+		}
+	}
+	return changes
+}
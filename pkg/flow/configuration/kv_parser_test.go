@@ -0,0 +1,133 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestParsePropertiesContent 测试properties格式的分隔符、注释、续行以及转义字符
+func TestParsePropertiesContent(t *testing.T) {
+	content := "# comment\n" +
+		"! comment\n" +
+		"  a=1\n" +
+		"b : 2\n" +
+		"c 3\n" +
+		"d\t\t= 4 \n" +
+		"e\n" +
+		"f = multi \\\n" +
+		"    line \\\n" +
+		"    # not comment\n" +
+		"g = ends with backslash \\\\\n" +
+		"h\\ key\\=x = v\\:1\n" +
+		"i = \\u4e2d\\u6587\\t\\n\n" +
+		"j = \\uD83D\\uDE00\n" +
+		"k = \\q\n" +
+		"=no key\n" +
+		"l = last \\"
+	properties, err := parsePropertiesContent(content)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"a":       "1",
+		"b":       "2",
+		"c":       "3",
+		"d":       "4 ",
+		"e":       "",
+		"f":       "multi line # not comment",
+		"g":       "ends with backslash \\",
+		"h key=x": "v:1",
+		"i":       "中文\t\n",
+		"j":       "\U0001F600",
+		"k":       "q",
+		"l":       "last ",
+	}, properties)
+
+	_, err = parsePropertiesContent("a = \\u12")
+	assert.NotNil(t, err)
+	_, err = parsePropertiesContent("a = \\uzzzz")
+	assert.NotNil(t, err)
+}
+
+// TestParseProperties 测试yaml以及json格式展开为扁平化的配置项
+func TestParseProperties(t *testing.T) {
+	properties, err := parseProperties(model.ConfigFileFormatYaml,
+		"server:\n  port: 8080\n  hosts:\n    - a\n    - b\nempty:\n")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"server.port":     "8080",
+		"server.hosts[0]": "a",
+		"server.hosts[1]": "b",
+		"empty":           "",
+	}, properties)
+
+	properties, err = parseProperties(model.ConfigFileFormatJSON, `{"id": 12345678901234567890, "a": {"b": true}}`)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"id":  "12345678901234567890",
+		"a.b": "true",
+	}, properties)
+
+	_, err = parseProperties(model.ConfigFileFormatYaml, "- a\n- b\n")
+	assert.NotNil(t, err)
+	_, err = parseProperties(model.ConfigFileFormatJSON, "{")
+	assert.NotNil(t, err)
+	properties, err = parseProperties(model.ConfigFileFormatJSON, "  ")
+	assert.Nil(t, err)
+	assert.Empty(t, properties)
+}
+
+// TestPropertiesToTree 测试同时存在a以及a.b时以多层级配置为准
+func TestPropertiesToTree(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		tree := propertiesToTree(map[string]string{
+			"a":     "1",
+			"a.b":   "2",
+			"a.b.c": "true",
+			"d":     "text",
+			"e.f":   "1.5",
+		})
+		assert.Equal(t, map[string]interface{}{
+			"a": map[string]interface{}{
+				"b": map[string]interface{}{
+					"c": true,
+				},
+			},
+			"d": "text",
+			"e": map[string]interface{}{
+				"f": 1.5,
+			},
+		}, tree)
+	}
+}
+
+// TestDiffProperties 测试配置项的新增、修改以及删除
+func TestDiffProperties(t *testing.T) {
+	changes := diffProperties(
+		map[string]string{"a": "1", "b": "2", "c": "3"},
+		map[string]string{"a": "1", "b": "20", "d": "4"})
+	assert.Equal(t, map[string]*model.ConfigPropertyChange{
+		"b": {Key: "b", OldValue: "2", NewValue: "20", ChangeType: model.Modified},
+		"c": {Key: "c", OldValue: "3", ChangeType: model.Deleted},
+		"d": {Key: "d", NewValue: "4", ChangeType: model.Added},
+	}, changes)
+	assert.Empty(t, diffProperties(map[string]string{"a": "1"}, map[string]string{"a": "1"}))
+}
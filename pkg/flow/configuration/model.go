@@ -29,16 +29,20 @@ type defaultConfigFile struct {
 
 	fileRepo *ConfigFileRepo
 	content  string
+	// 配置内容变更时的处理函数，在触发配置文件变更事件之前执行
+	contentHandler func(newContent string)
 
 	lock                sync.RWMutex
 	changeListeners     []func(event model.ConfigFileChangeEvent)
 	changeListenerChans []chan model.ConfigFileChangeEvent
 }
 
-func newDefaultConfigFile(metadata model.ConfigFileMetadata, repo *ConfigFileRepo) *defaultConfigFile {
+func newDefaultConfigFile(metadata model.ConfigFileMetadata, repo *ConfigFileRepo,
+	contentHandler func(newContent string)) *defaultConfigFile {
 	configFile := &defaultConfigFile{
-		fileRepo: repo,
-		content:  repo.GetContent(),
+		fileRepo:       repo,
+		content:        repo.GetContent(),
+		contentHandler: contentHandler,
 	}
 	configFile.Namespace = metadata.GetNamespace()
 	configFile.FileGroup = metadata.GetFileGroup()
@@ -61,6 +65,11 @@ func (c *defaultConfigFile) HasContent() bool {
 	return c.content != "" && c.content != NotExistedFileContent
 }
 
+// IsStale 配置内容是否来自本地缓存且尚未与服务端同步，实现 model.StaleConfigFile
+func (c *defaultConfigFile) IsStale() bool {
+	return c.fileRepo.isStale()
+}
+
 func (c *defaultConfigFile) repoChangeListener(configFileMetadata model.ConfigFileMetadata, newContent string) error {
 	oldContent := c.content
 
@@ -89,6 +98,9 @@ func (c *defaultConfigFile) repoChangeListener(configFileMetadata model.ConfigFi
 	}
 	c.content = newContent
 
+	if c.contentHandler != nil {
+		c.contentHandler(newContent)
+	}
 	c.fireChangeEvent(event)
 	return nil
 }
@@ -0,0 +1,182 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+const (
+	// configPersistSubDir 配置文件缓存在本地缓存目录下的子目录
+	configPersistSubDir = "config"
+	// patternConfigFile 配置文件缓存的文件名格式
+	patternConfigFile = "config#%s#%s#%s.json"
+)
+
+// persistedConfigFile 持久化到本地的配置文件，加密配置文件保存服务端返回的密文以及数据密钥标签
+type persistedConfigFile struct {
+	Namespace string                           `json:"namespace"`
+	FileGroup string                           `json:"fileGroup"`
+	FileName  string                           `json:"fileName"`
+	Content   string                           `json:"content"`
+	Version   uint64                           `json:"version"`
+	Md5       string                           `json:"md5"`
+	Encrypted bool                             `json:"encrypted"`
+	Tags      []*configconnector.ConfigFileTag `json:"tags"`
+}
+
+// configFilePersistHandler 配置文件持久化工具类
+// 从服务端拉取成功的配置文件保存到consumer.localCache.persistDir/config目录，配置中心不可用时从该目录加载；
+// 保存的是未经过配置过滤链处理的服务端原始内容，加载后重新执行过滤链，加密配置文件不以明文形式落盘，
+// 只有过滤链能够解开缓存中的数据密钥时才能使用
+type configFilePersistHandler struct {
+	persistDir    string
+	maxWriteRetry int
+	maxReadRetry  int
+	retryInterval time.Duration
+}
+
+// newConfigFilePersistHandler 创建配置文件持久化工具类，未开启本地缓存持久化或者目录不可用时返回nil
+func newConfigFilePersistHandler(cfg config.Configuration) *configFilePersistHandler {
+	localCacheCfg := cfg.GetConsumer().GetLocalCache()
+	if !localCacheCfg.IsPersistEnable() {
+		return nil
+	}
+	persistDir := filepath.Join(model.ReplaceHomeVar(localCacheCfg.GetPersistDir()), configPersistSubDir)
+	if err := model.EnsureAndVerifyDir(persistDir); err != nil {
+		log.GetBaseLogger().Errorf("[Config] fail to init config file persist dir %s, err: %v", persistDir, err)
+		return nil
+	}
+	return &configFilePersistHandler{
+		persistDir:    persistDir,
+		maxWriteRetry: localCacheCfg.GetPersistMaxWriteRetry(),
+		maxReadRetry:  localCacheCfg.GetPersistMaxReadRetry(),
+		retryInterval: localCacheCfg.GetPersistRetryInterval(),
+	}
+}
+
+// fileName 配置文件对应的缓存文件名
+func (p *configFilePersistHandler) fileName(metadata model.ConfigFileMetadata) string {
+	return filepath.Join(p.persistDir, fmt.Sprintf(patternConfigFile, url.QueryEscape(metadata.GetNamespace()),
+		url.QueryEscape(metadata.GetFileGroup()), url.QueryEscape(metadata.GetFileName())))
+}
+
+// save 将服务端返回的原始配置文件写入本地缓存
+func (p *configFilePersistHandler) save(configFile *configconnector.ConfigFile) {
+	value := &persistedConfigFile{
+		Namespace: configFile.GetNamespace(),
+		FileGroup: configFile.GetFileGroup(),
+		FileName:  configFile.GetFileName(),
+		Content:   configFile.GetContent(),
+		Version:   configFile.GetVersion(),
+		Md5:       configFile.GetMd5(),
+		Encrypted: configFile.GetEncrypted(),
+		Tags:      configFile.Tags,
+	}
+	cacheFile := p.fileName(configFile)
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.GetBaseLogger().Errorf("[Config] fail to marshal config file %s, err: %v", cacheFile, err)
+		return
+	}
+	for retryTimes := 0; retryTimes <= p.maxWriteRetry; retryTimes++ {
+		if err = writeFileAtomic(cacheFile, data); err == nil {
+			log.GetBaseLogger().Infof("[Config] success to persist config file %s, version %d",
+				cacheFile, value.Version)
+			return
+		}
+		log.GetBaseLogger().Warnf("[Config] fail to persist config file %s, retry times %d, err: %v",
+			cacheFile, retryTimes, err)
+		time.Sleep(p.retryInterval)
+	}
+}
+
+// load 从本地缓存加载服务端返回的原始配置文件，加密配置文件需要经过配置过滤链解密
+func (p *configFilePersistHandler) load(metadata model.ConfigFileMetadata) (*configconnector.ConfigFile, error) {
+	cacheFile := p.fileName(metadata)
+	var (
+		value   *persistedConfigFile
+		lastErr error
+	)
+	for retryTimes := 0; retryTimes <= p.maxReadRetry; retryTimes++ {
+		data, err := ioutil.ReadFile(cacheFile)
+		if err != nil {
+			// 文件不存在或者无法打开，重试没有意义
+			return nil, model.NewSDKError(model.ErrCodeDiskError, err, "fail to read config file cache %s", cacheFile)
+		}
+		value = &persistedConfigFile{}
+		if lastErr = json.Unmarshal(data, value); lastErr == nil {
+			break
+		}
+		// 解码失败可能是读到了部分数据，重试
+		time.Sleep(p.retryInterval)
+	}
+	if lastErr != nil {
+		return nil, model.NewSDKError(model.ErrCodeDiskError, lastErr,
+			"fail to unmarshal config file cache %s", cacheFile)
+	}
+	return &configconnector.ConfigFile{
+		Namespace: value.Namespace,
+		FileGroup: value.FileGroup,
+		FileName:  value.FileName,
+		Content:   value.Content,
+		Version:   value.Version,
+		Md5:       value.Md5,
+		Encrypted: value.Encrypted,
+		Tags:      value.Tags,
+	}, nil
+}
+
+// delete 删除配置文件的本地缓存
+func (p *configFilePersistHandler) delete(metadata model.ConfigFileMetadata) {
+	cacheFile := p.fileName(metadata)
+	for retryTimes := 0; retryTimes <= p.maxWriteRetry; retryTimes++ {
+		err := os.Remove(cacheFile)
+		if err == nil || os.IsNotExist(err) {
+			return
+		}
+		log.GetBaseLogger().Warnf("[Config] fail to delete config file cache %s, retry times %d, err: %v",
+			cacheFile, retryTimes, err)
+		time.Sleep(p.retryInterval)
+	}
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免进程异常退出时留下不完整的缓存文件
+func writeFileAtomic(file string, data []byte) error {
+	tmpFile := file + ".tmp"
+	if err := ioutil.WriteFile(tmpFile, data, 0600); err != nil {
+		_ = os.Remove(tmpFile)
+		return err
+	}
+	if err := os.Rename(tmpFile, file); err != nil {
+		_ = os.Remove(tmpFile)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configuration
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/log/logtest"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configfilter"
+)
+
+func init() {
+	logtest.DiscardLoggers()
+}
+
+// testConfigConnector 返回固定配置文件的配置中心连接器
+type testConfigConnector struct {
+	configconnector.ConfigConnector
+	mutex      sync.Mutex
+	err        error
+	configFile *configconnector.ConfigFile
+}
+
+func (c *testConfigConnector) set(configFile *configconnector.ConfigFile, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.configFile = configFile
+	c.err = err
+}
+
+func (c *testConfigConnector) GetConfigFile(*configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &configconnector.ConfigFileResponse{
+		Code:       uint32(apimodel.Code_ExecuteSuccess),
+		ConfigFile: deepCloneConfigFile(c.configFile),
+	}, nil
+}
+
+// reverseFilter 模拟解密过滤器，使用数据密钥将倒序的配置内容还原
+type reverseFilter struct {
+	configfilter.ConfigFilter
+}
+
+func (f *reverseFilter) DoFilter(_ *configconnector.ConfigFile,
+	next configfilter.ConfigFileHandleFunc) configfilter.ConfigFileHandleFunc {
+	return func(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+		resp, err := next(configFile)
+		if err != nil || !resp.GetConfigFile().GetEncrypted() {
+			return resp, err
+		}
+		if resp.GetConfigFile().GetDataKey() != "test-key" {
+			return nil, errors.New("invalid data key")
+		}
+		resp.ConfigFile.Content = reverse(resp.ConfigFile.Content)
+		return resp, nil
+	}
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func newEncryptedConfigFile(content string, version uint64) *configconnector.ConfigFile {
+	return &configconnector.ConfigFile{
+		Namespace: "default",
+		FileGroup: "group",
+		FileName:  "app.yaml",
+		Content:   reverse(content),
+		Version:   version,
+		Encrypted: true,
+		Tags: []*configconnector.ConfigFileTag{
+			{Key: configconnector.ConfigFileTagKeyDataKey, Value: "test-key"},
+		},
+	}
+}
+
+func newTestPersistHandler(t *testing.T) *configFilePersistHandler {
+	dir, err := ioutil.TempDir("", "polaris-config")
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	return &configFilePersistHandler{persistDir: dir, retryInterval: time.Millisecond}
+}
+
+var testFileMetadata = &model.DefaultConfigFileMetadata{
+	Namespace: "default",
+	FileGroup: "group",
+	FileName:  "app.yaml",
+}
+
+// TestPersistRawConfigFile 测试加密配置文件以服务端返回的密文以及数据密钥落盘
+func TestPersistRawConfigFile(t *testing.T) {
+	connector := &testConfigConnector{}
+	connector.set(newEncryptedConfigFile("password: hello", 1), nil)
+	persistHandler := newTestPersistHandler(t)
+	chain := configfilter.Chain{&reverseFilter{}}
+
+	repo, err := newConfigFileRepo(testFileMetadata, connector, chain, nil, persistHandler)
+	assert.Nil(t, err)
+	assert.Equal(t, "password: hello", repo.GetContent())
+	assert.False(t, repo.isStale())
+
+	data, err := ioutil.ReadFile(persistHandler.fileName(testFileMetadata))
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(string(data), "password: hello"))
+	cached, err := persistHandler.load(testFileMetadata)
+	assert.Nil(t, err)
+	assert.Equal(t, reverse("password: hello"), cached.GetContent())
+	assert.Equal(t, "test-key", cached.GetDataKey())
+	assert.True(t, cached.GetEncrypted())
+}
+
+// TestColdStartFromCache 测试配置中心不可用时使用本地缓存启动，长轮询恢复后与服务端重新同步
+func TestColdStartFromCache(t *testing.T) {
+	connector := &testConfigConnector{}
+	connector.set(newEncryptedConfigFile("password: hello", 1), nil)
+	persistHandler := newTestPersistHandler(t)
+	chain := configfilter.Chain{&reverseFilter{}}
+	_, err := newConfigFileRepo(testFileMetadata, connector, chain, nil, persistHandler)
+	assert.Nil(t, err)
+
+	// 配置中心不可用，重新启动时从本地缓存加载，并经过过滤链解密
+	connector.set(nil, errors.New("config server is unavailable"))
+	repo, err := newConfigFileRepo(testFileMetadata, connector, chain, nil, persistHandler)
+	assert.Nil(t, err)
+	assert.Equal(t, "password: hello", repo.GetContent())
+	assert.True(t, repo.isStale())
+	configFile := newDefaultConfigFile(testFileMetadata, repo, nil)
+	staleFile, ok := model.ConfigFile(configFile).(model.StaleConfigFile)
+	assert.True(t, ok)
+	assert.True(t, staleFile.IsStale())
+
+	// 配置中心恢复，与服务端重新同步，即使版本号没有变化也以服务端为准
+	connector.set(newEncryptedConfigFile("password: world", 1), nil)
+	flow := &ConfigFileFlow{repos: []*ConfigFileRepo{repo}}
+	flow.reconcileStaleRepos()
+	assert.False(t, repo.isStale())
+	assert.False(t, staleFile.IsStale())
+	assert.Equal(t, "password: world", configFile.GetContent())
+	cached, err := persistHandler.load(testFileMetadata)
+	assert.Nil(t, err)
+	assert.Equal(t, reverse("password: world"), cached.GetContent())
+
+	// 已经同步的配置不再重复拉取
+	connector.set(nil, errors.New("config server is unavailable"))
+	flow.reconcileStaleRepos()
+	assert.Equal(t, "password: world", configFile.GetContent())
+}
+
+// TestLoadFromPersistFilterFail 测试本地缓存无法通过过滤链处理时不使用缓存
+func TestLoadFromPersistFilterFail(t *testing.T) {
+	persistHandler := newTestPersistHandler(t)
+	configFile := newEncryptedConfigFile("password: hello", 1)
+	configFile.SetTag(configconnector.ConfigFileTagKeyDataKey, "other-key")
+	persistHandler.save(configFile)
+
+	repo := &ConfigFileRepo{
+		chain:              configfilter.Chain{&reverseFilter{}},
+		configFileMetadata: testFileMetadata,
+		persistHandler:     persistHandler,
+	}
+	assert.False(t, repo.loadFromPersist())
+	assert.False(t, repo.isStale())
+
+	repo.persistHandler = nil
+	assert.False(t, repo.loadFromPersist())
+}
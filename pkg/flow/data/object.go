@@ -18,6 +18,7 @@
 package data
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -186,6 +187,8 @@ type CommonInstancesRequest struct {
 	LbPolicy string
 	// 路由插件列表
 	Routers []servicerouter.ServiceRouter
+	// 调用的接口方法
+	Method string
 }
 
 // clearValues 清理请求体
@@ -199,6 +202,9 @@ func (c *CommonInstancesRequest) clearValues(cfg config.Configuration) {
 	c.Trigger.Clear()
 	c.Criteria.ReplicateInfo.Count = 0
 	c.Criteria.ReplicateInfo.Nodes = nil
+	c.Criteria.Method = ""
+	c.Criteria.ExcludeInstances = nil
+	c.Criteria.ExcludeHosts = nil
 	c.DoLoadBalance = false
 	c.HasSrcService = false
 	c.SkipRouteFilter = false
@@ -206,10 +212,12 @@ func (c *CommonInstancesRequest) clearValues(cfg config.Configuration) {
 	c.response = nil
 	c.LbPolicy = ""
 	c.Routers = nil
+	c.Method = ""
 }
 
 // InitByGetOneRequest 通过获取单个请求初始化通用请求对象
-func (c *CommonInstancesRequest) InitByGetOneRequest(request *model.GetOneInstanceRequest, cfg config.Configuration) {
+func (c *CommonInstancesRequest) InitByGetOneRequest(
+	ctx context.Context, request *model.GetOneInstanceRequest, cfg config.Configuration) {
 	c.clearValues(cfg)
 	c.FlowID = request.FlowID
 	c.DstService.Service = request.Service
@@ -239,7 +247,11 @@ func (c *CommonInstancesRequest) InitByGetOneRequest(request *model.GetOneInstan
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
 	c.LbPolicy = request.LbPolicy
-	BuildControlParam(request, cfg, &c.ControlParam)
+	c.Method = request.Method
+	c.Criteria.Method = request.Method
+	c.Criteria.ExcludeInstances = request.ExcludeInstances
+	c.Criteria.ExcludeHosts = request.ExcludeHosts
+	BuildControlParam(ctx, request, cfg, &c.ControlParam)
 }
 
 func (c *CommonInstancesRequest) InitByProcessLoadBalanceRequest(
@@ -253,6 +265,8 @@ func (c *CommonInstancesRequest) InitByProcessLoadBalanceRequest(
 	c.DoLoadBalance = true
 	c.Criteria.HashKey = request.HashKey
 	c.Criteria.ReplicateInfo.Count = request.ReplicateCount
+	c.Criteria.ExcludeInstances = request.ExcludeInstances
+	c.Criteria.ExcludeHosts = request.ExcludeHosts
 	c.LbPolicy = request.LbPolicy
 	if len(c.LbPolicy) == 0 {
 		c.LbPolicy = cfg.GetConsumer().GetLoadbalancer().GetType()
@@ -291,11 +305,12 @@ func (c *CommonInstancesRequest) InitByProcessRoutersRequest(
 	c.CallResult.APIName = model.ApiProcessRouters
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
-	BuildControlParam(request, cfg, &c.ControlParam)
+	BuildControlParam(context.Background(), request, cfg, &c.ControlParam)
 }
 
 // InitByGetMultiRequest 通过获取多个请求初始化通用请求对象
-func (c *CommonInstancesRequest) InitByGetMultiRequest(request *model.GetInstancesRequest, cfg config.Configuration) {
+func (c *CommonInstancesRequest) InitByGetMultiRequest(
+	ctx context.Context, request *model.GetInstancesRequest, cfg config.Configuration) {
 	c.clearValues(cfg)
 	c.FlowID = request.FlowID
 	c.DstService.Service = request.Service
@@ -319,11 +334,12 @@ func (c *CommonInstancesRequest) InitByGetMultiRequest(request *model.GetInstanc
 	c.CallResult.APIName = model.ApiGetInstances
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
-	BuildControlParam(request, cfg, &c.ControlParam)
+	BuildControlParam(ctx, request, cfg, &c.ControlParam)
 }
 
 // InitByGetAllRequest 通过获取全部请求初始化通用请求对象
-func (c *CommonInstancesRequest) InitByGetAllRequest(request *model.GetAllInstancesRequest, cfg config.Configuration) {
+func (c *CommonInstancesRequest) InitByGetAllRequest(
+	ctx context.Context, request *model.GetAllInstancesRequest, cfg config.Configuration) {
 	c.clearValues(cfg)
 	c.FlowID = request.FlowID
 	c.DstService.Service = request.Service
@@ -335,7 +351,7 @@ func (c *CommonInstancesRequest) InitByGetAllRequest(request *model.GetAllInstan
 	c.CallResult.APIName = model.ApiGetAllInstances
 	c.CallResult.RetStatus = model.RetSuccess
 	c.CallResult.RetCode = model.ErrCodeSuccess
-	BuildControlParam(request, cfg, &c.ControlParam)
+	BuildControlParam(ctx, request, cfg, &c.ControlParam)
 }
 
 // RefreshByRedirect 通过重定向服务来进行刷新
@@ -466,7 +482,7 @@ func (cr *ServicesRequest) SetServices(mc model.Services) {
 }
 
 // InitByGetServicesRequest 初始化请求
-func (cr *ServicesRequest) InitByGetServicesRequest(
+func (cr *ServicesRequest) InitByGetServicesRequest(ctx context.Context,
 	eventType model.EventType, request *model.GetServicesRequest, cfg config.Configuration) {
 	cr.clearValues()
 	cr.FlowID = request.FlowID
@@ -476,7 +492,7 @@ func (cr *ServicesRequest) InitByGetServicesRequest(
 	cr.DstService.Namespace = request.Namespace
 	cr.DstService.Service = request.Business
 	cr.Trigger.EnableServices = true
-	BuildControlParam(request, cfg, &cr.ControlParam)
+	BuildControlParam(ctx, request, cfg, &cr.ControlParam)
 }
 
 // BuildServicesResponse 构建答复
@@ -506,7 +522,7 @@ func (cr *CommonRuleRequest) clearValues(cfg config.Configuration) {
 }
 
 // InitByGetRuleRequest 通过获取路由规则请求初始化通用请求对象
-func (cr *CommonRuleRequest) InitByGetRuleRequest(
+func (cr *CommonRuleRequest) InitByGetRuleRequest(ctx context.Context,
 	eventType model.EventType, request *model.GetServiceRuleRequest, cfg config.Configuration) {
 	cr.clearValues(cfg)
 	cr.FlowID = request.FlowID
@@ -517,7 +533,7 @@ func (cr *CommonRuleRequest) InitByGetRuleRequest(
 	cr.DstService.Service = request.Service
 	cr.DstService.Type = eventType
 	cr.response = request.GetResponse()
-	BuildControlParam(request, cfg, &cr.ControlParam)
+	BuildControlParam(ctx, request, cfg, &cr.ControlParam)
 }
 
 // BuildServiceRuleResponse 构建规则查询应答
@@ -583,7 +599,8 @@ func parseArguments(arguments []model.Argument) map[int]map[string]string {
 }
 
 // InitByGetQuotaRequest 初始化配额获取请求
-func (cl *CommonRateLimitRequest) InitByGetQuotaRequest(request *model.QuotaRequestImpl, cfg config.Configuration) {
+func (cl *CommonRateLimitRequest) InitByGetQuotaRequest(
+	ctx context.Context, request *model.QuotaRequestImpl, cfg config.Configuration) {
 	cl.clearValues()
 	cl.QuotaRequest = request
 	cl.DstService.Namespace = request.GetNamespace()
@@ -595,7 +612,7 @@ func (cl *CommonRateLimitRequest) InitByGetQuotaRequest(request *model.QuotaRequ
 	cl.CallResult.APIName = model.ApiGetQuota
 	cl.CallResult.RetStatus = model.RetSuccess
 	cl.CallResult.RetCode = model.ErrCodeSuccess
-	BuildControlParam(request, cfg, &cl.ControlParam)
+	BuildControlParam(ctx, request, cfg, &cl.ControlParam)
 
 	// 限流相关同步请求，减少重试此数和重试间隔
 	if cl.ControlParam.MaxRetry > 2 {
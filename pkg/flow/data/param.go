@@ -18,6 +18,7 @@
 package data
 
 import (
+	"context"
 	"time"
 
 	"github.com/modern-go/reflect2"
@@ -38,8 +39,8 @@ type ControlParamProvider interface {
 	SetRetryCount(int)
 }
 
-// BuildControlParam 为服务注册的请求设置默认值
-func BuildControlParam(
+// BuildControlParam 为服务注册的请求设置默认值，ctx为调用方上下文
+func BuildControlParam(ctx context.Context,
 	provider ControlParamProvider, cfg config.Configuration, param *model.ControlParam) {
 	if reflect2.IsNil(provider) || nil == provider.GetTimeoutPtr() {
 		param.Timeout = cfg.GetGlobal().GetAPI().GetTimeout()
@@ -52,6 +53,7 @@ func BuildControlParam(
 		param.MaxRetry = *provider.GetRetryCountPtr()
 	}
 	param.RetryInterval = cfg.GetGlobal().GetAPI().GetRetryInterval()
+	param.Context = ctx
 	if !reflect2.IsNil(provider) {
 		provider.SetTimeout(param.Timeout)
 		provider.SetRetryCount(param.MaxRetry)
@@ -18,6 +18,7 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -36,6 +37,7 @@ import (
 	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
 	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/pkg/plugin/weightadjuster"
 )
 
 // GetServerConnector 加载连接器插件
@@ -115,6 +117,16 @@ func GetCircuitBreakers(
 	return cbreakers, nil
 }
 
+// GetWeightAdjuster 获取动态权重调整插件
+func GetWeightAdjuster(cfg config.Configuration, supplier plugin.Supplier) (weightadjuster.WeightAdjuster, error) {
+	adjusterType := cfg.GetConsumer().GetWeightAdjuster().GetType()
+	targetPlugin, err := supplier.GetPlugin(common.TypeWeightAdjuster, adjusterType)
+	if err != nil {
+		return nil, err
+	}
+	return targetPlugin.(weightadjuster.WeightAdjuster), nil
+}
+
 // GetHealthCheckers 获取健康探测插件列表
 func GetHealthCheckers(cfg config.Configuration, supplier plugin.Supplier) ([]healthcheck.HealthChecker, error) {
 	names := cfg.GetConsumer().GetHealthCheck().GetChain()
@@ -191,6 +203,44 @@ func GetLoadBalancerByLbType(lbType string, supplier plugin.Supplier) (loadbalan
 // SingleInvoke 同步调用的通用方法定义
 type SingleInvoke func(request interface{}) (interface{}, error)
 
+// CheckContextDone 检查调用方上下文是否已经结束，已结束则返回对应的SDK错误
+func CheckContextDone(ctx context.Context, name string) model.SDKError {
+	if ctx == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return NewContextError(ctx.Err(), name)
+	default:
+		return nil
+	}
+}
+
+// NewContextError 将上下文错误转换为SDK错误，调用方超时与取消使用不同的错误码
+func NewContextError(ctxErr error, name string) model.SDKError {
+	if ctxErr == context.DeadlineExceeded {
+		return model.NewSDKError(model.ErrCodeAPITimeoutError, ctxErr,
+			"%s exceeds the deadline of caller context", name)
+	}
+	return model.NewSDKError(model.ErrCodeAPICanceled, ctxErr, "%s is canceled by caller context", name)
+}
+
+// SleepWithContext 等待重试间隔，调用方上下文结束时提前返回false
+func SleepWithContext(ctx context.Context, interval time.Duration) bool {
+	if ctx == nil {
+		time.Sleep(interval)
+		return true
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
 // RetrySyncCall 通用的带重试的同步调用逻辑
 func RetrySyncCall(name string, svcKey *model.ServiceKey,
 	request interface{}, call SingleInvoke, param *model.ControlParam) (interface{}, model.SDKError) {
@@ -199,6 +249,9 @@ func RetrySyncCall(name string, svcKey *model.ServiceKey,
 	var err error
 	retryInterval := param.RetryInterval
 	for retryTimes < param.MaxRetry {
+		if ctxErr := CheckContextDone(param.Context, name); ctxErr != nil {
+			return resp, ctxErr
+		}
 		startTime := clock.GetClock().Now()
 		resp, err = call(request)
 		consumeTime := clock.GetClock().Now().Sub(startTime)
@@ -213,7 +266,9 @@ func RetrySyncCall(name string, svcKey *model.ServiceKey,
 		if retryTimes >= param.MaxRetry {
 			break
 		}
-		time.Sleep(retryInterval)
+		if !SleepWithContext(param.Context, retryInterval) {
+			return resp, NewContextError(param.Context.Err(), name)
+		}
 		log.GetBaseLogger().Warnf("retry %s for timeout, consume time %v,"+
 			" Namespace: %s, Service: %s, retry times: %d",
 			name, consumeTime, svcKey.Namespace, svcKey.Service, retryTimes)
@@ -19,15 +19,20 @@ package detect
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/modern-go/reflect2"
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/healthcheck"
 	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
@@ -162,6 +167,9 @@ func (c *HealthCheckCallBack) processHealthCheck(svc *model.ServiceKey, instance
 }
 
 func (c *HealthCheckCallBack) doConcurrentHealthCheck(instance model.Instance) (bool, time.Time) {
+	if success, curTime, detected := c.doRuleHealthCheck(instance); detected {
+		return success, curTime
+	}
 	curTime := time.Now()
 	for _, checker := range c.healthCheckers {
 		result, err := checker.DetectInstance(instance)
@@ -181,6 +189,67 @@ func (c *HealthCheckCallBack) doConcurrentHealthCheck(instance model.Instance) (
 	return true, curTime
 }
 
+// doRuleHealthCheck 按照服务端下发的探测规则进行探活，没有可用的探测规则时返回detected为false
+func (c *HealthCheckCallBack) doRuleHealthCheck(instance model.Instance) (bool, time.Time, bool) {
+	rules := c.getFaultDetectRules(&model.ServiceKey{
+		Namespace: instance.GetNamespace(),
+		Service:   instance.GetService(),
+	})
+	curTime := time.Now()
+	var detected bool
+	for _, rule := range rules {
+		protocol := strings.ToLower(rule.GetProtocol().String())
+		for _, checker := range c.healthCheckers {
+			ruleChecker, ok := checker.(healthcheck.RuleHealthChecker)
+			if !ok || checker.Name() != protocol {
+				continue
+			}
+			result, err := ruleChecker.DetectInstanceWithRule(instance, rule)
+			if err != nil {
+				log.GetDetectLogger().Errorf("[HealthCheck] fail to detect by rule %s, err: %v", rule.GetName(), err)
+				continue
+			}
+			if result == nil {
+				continue
+			}
+			detected = true
+			if !result.IsSuccess() {
+				return false, result.GetDetectTime(), true
+			}
+			curTime = result.GetDetectTime()
+		}
+	}
+	return true, curTime, detected
+}
+
+// getFaultDetectRules 获取服务的主动探测规则，规则未加载时发起异步加载
+func (c *HealthCheckCallBack) getFaultDetectRules(svc *model.ServiceKey) []*apifault.FaultDetectRule {
+	svcRule := c.registry.GetServiceFaultDetectRule(svc, true)
+	if !svcRule.IsInitialized() {
+		if _, err := c.registry.LoadServiceFaultDetectRule(svc); err != nil {
+			log.GetDetectLogger().Errorf("[HealthCheck] fail to load faultDetect rule for %s, err: %v", *svc, err)
+		}
+		return nil
+	}
+	if reflect2.IsNil(svcRule.GetValue()) || nil != svcRule.GetValidateError() {
+		return nil
+	}
+	var rules []*apifault.FaultDetectRule
+	for _, rule := range svcRule.GetValue().(*apifault.FaultDetector).GetRules() {
+		target := rule.GetTargetService()
+		if !matchName(target.GetNamespace(), svc.Namespace) || !matchName(target.GetService(), svc.Service) {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// matchName 匹配服务名或命名空间，空值和*代表全部匹配
+func matchName(ruleValue string, value string) bool {
+	return len(ruleValue) == 0 || ruleValue == pb.MatchAll || ruleValue == value
+}
+
 // doHealthCheckService 对一组服务进行探活逻辑
 func (c *HealthCheckCallBack) doHealthCheckService(svcInstances model.ServiceInstances) error {
 	if len(c.healthCheckers) == 0 || len(svcInstances.GetInstances()) == 0 {
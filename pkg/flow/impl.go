@@ -18,6 +18,7 @@
 package flow
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/flow/quota"
 	"github.com/polarismesh/polaris-go/pkg/flow/registerstate"
 	"github.com/polarismesh/polaris-go/pkg/flow/schedule"
+	"github.com/polarismesh/polaris-go/pkg/flow/weightadjust"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/model/pb"
@@ -45,6 +47,7 @@ import (
 	statreporter "github.com/polarismesh/polaris-go/pkg/plugin/metrics"
 	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/pkg/plugin/weightadjuster"
 )
 
 // Engine 编排调度引擎，API相关逻辑在这里执行
@@ -81,6 +84,12 @@ type Engine struct {
 	circuitBreakTask *cbcheck.CircuitBreakCallBack
 	// 熔断插件链
 	circuitBreakerChain []circuitbreaker.InstanceCircuitBreaker
+	// 实时动态权重调整任务队列
+	rtWeightAdjustChan chan<- *model.PriorityTask
+	// 动态权重调整公共任务信息
+	weightAdjustTask *weightadjust.WeightAdjustCallBack
+	// 动态权重调整插件
+	weightAdjuster weightadjuster.WeightAdjuster
 	// 修改消息订阅插件链
 	subscribe *subscribeChannel
 	// 配置中心门面类
@@ -166,6 +175,23 @@ func InitFlowEngine(flowEngine *Engine, initContext plugin.InitContext) error {
 			return err
 		}
 	}
+	// 加载动态权重调整插件
+	if cfg.GetConsumer().GetWeightAdjuster().IsEnable() {
+		flowEngine.weightAdjuster, err = data.GetWeightAdjuster(cfg, plugins)
+		if err != nil {
+			return err
+		}
+		flowEngine.rtWeightAdjustChan, flowEngine.weightAdjustTask, err = flowEngine.addPeriodicWeightAdjustTask()
+		if err != nil {
+			return err
+		}
+	}
+	// 启动新增实例预热任务，全局或者任意服务开启预热时运行
+	if cfg.GetConsumer().IsAnyWarmupEnabled() {
+		if _, err = flowEngine.addPeriodicWarmupTask(); err != nil {
+			return err
+		}
+	}
 	flowEngine.watchEngine = NewWatchEngine(flowEngine.registry)
 	flowEngine.subscribe = &subscribeChannel{
 		registerServices: []model.ServiceKey{},
@@ -235,7 +261,7 @@ func (e *Engine) WatchService(req *model.WatchServiceRequest) (*model.WatchServi
 	allInsReq := &model.GetAllInstancesRequest{}
 	allInsReq.Namespace = req.Key.Namespace
 	allInsReq.Service = req.Key.Service
-	allInsRsp, err := e.SyncGetAllInstances(allInsReq)
+	allInsRsp, err := e.SyncGetAllInstances(context.Background(), allInsReq)
 	if err != nil {
 		return nil, err
 	}
@@ -67,11 +67,14 @@ func (s *SingleNotifyContext) Err() model.SDKError {
 }
 
 // Wait notify 异步任务执行回调函数
-func (s *SingleNotifyContext) Wait(timeout time.Duration) bool {
+// 调用方上下文结束时提前返回，返回值同超时
+func (s *SingleNotifyContext) Wait(callerCtx context.Context, timeout time.Duration) bool {
 	afterTimer := time.After(timeout)
 	select {
 	case <-afterTimer:
 		return true
+	case <-callerCtx.Done():
+		return true
 	case <-s.notifier.GetContext().Done():
 		log.GetBaseLogger().Debugf("context %s has been notified", *s.name)
 		return false
@@ -122,8 +125,8 @@ func (c *CombineNotifyContext) logNotifier(operation string, notifier *SingleNot
 }
 
 // Wait notify 异步任务执行回调函数
-// 返回值，是否超时
-func (c *CombineNotifyContext) Wait(timeout time.Duration) (exceedTime bool) {
+// 返回值，是否超时，调用方上下文结束时提前返回，返回值同超时
+func (c *CombineNotifyContext) Wait(callerCtx context.Context, timeout time.Duration) (exceedTime bool) {
 	var restWait = atomic.LoadInt32(&c.waitCount)
 	if restWait == 0 {
 		return false
@@ -153,6 +156,8 @@ func (c *CombineNotifyContext) Wait(timeout time.Duration) (exceedTime bool) {
 			select {
 			case <-afterTimer:
 				return
+			case <-callerCtx.Done():
+				return
 			case <-notifier.notifier.GetContext().Done():
 				doneKeyChan <- notifier.name.Operation
 				nextWait := atomic.AddInt32(&c.waitCount, -1)
@@ -29,7 +29,6 @@ import (
 
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
-	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/model/pb"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
@@ -112,7 +111,11 @@ func (f *FlowQuotaAssistant) TaskValues() model.TaskValues {
 func (f *FlowQuotaAssistant) Init(engine model.Engine, cfg config.Configuration, supplier plugin.Supplier) error {
 	f.engine = engine
 	f.supplier = supplier
-	f.asyncRateLimitConnector = NewAsyncRateLimitConnector(engine.GetContext(), cfg)
+	asyncRateLimitConnector, err := NewAsyncRateLimitConnector(engine.GetContext(), cfg)
+	if err != nil {
+		return err
+	}
+	f.asyncRateLimitConnector = asyncRateLimitConnector
 	f.enable = cfg.GetProvider().GetRateLimit().IsEnable()
 	if !f.enable {
 		return nil
@@ -308,55 +311,6 @@ func (f *FlowQuotaAssistant) lookupRateLimitWindow(
 	return windows, nil
 }
 
-func matchStringValue(matchString *apimodel.MatchString, value string, ruleCache model.RuleCache) bool {
-	if pb.IsMatchAllValue(matchString) {
-		return true
-	}
-	matchType := matchString.GetType()
-	matchValue := matchString.GetValue().GetValue()
-
-	switch matchType {
-	case apimodel.MatchString_EXACT:
-		return value == matchValue
-	case apimodel.MatchString_REGEX:
-		regexObj, err := ruleCache.GetRegexMatcher(matchValue)
-		if nil != err {
-			log.GetBaseLogger().Errorf("regex compile error. ruleMetaValueStr: %s, value: %s, errors: %s",
-				matchValue, value, err)
-			return false
-		}
-		m, err := regexObj.FindStringMatch(value)
-		if err != nil {
-			log.GetBaseLogger().Errorf("regex match error. ruleMetaValueStr: %s, value: %s, errors: %s",
-				matchValue, value, err)
-			return false
-		}
-		if m == nil || m.String() == "" {
-			return false
-		}
-		return true
-	case apimodel.MatchString_NOT_EQUALS:
-		return value != matchValue
-	case apimodel.MatchString_IN:
-		tokens := strings.Split(matchValue, ",")
-		for _, token := range tokens {
-			if token == value {
-				return true
-			}
-		}
-		return false
-	case apimodel.MatchString_NOT_IN:
-		tokens := strings.Split(matchValue, ",")
-		for _, token := range tokens {
-			if token == value {
-				return false
-			}
-		}
-		return true
-	}
-	return false
-}
-
 // lookupRule 寻址规则
 func lookupRules(svcRule model.ServiceRule, method string, arguments map[int]map[string]string) []*apitraffic.Rule {
 	if reflect2.IsNil(svcRule) || reflect2.IsNil(svcRule.GetValue()) {
@@ -384,7 +338,7 @@ func lookupRules(svcRule model.ServiceRule, method string, arguments map[int]map
 		}
 		methodMatcher := rule.Method
 		if nil != methodMatcher {
-			matchMethod := matchStringValue(methodMatcher, method, ruleCache)
+			matchMethod := pb.MatchStringValue(methodMatcher, method, ruleCache)
 			if !matchMethod {
 				continue
 			}
@@ -402,7 +356,7 @@ func lookupRules(svcRule model.ServiceRule, method string, arguments map[int]map
 				if !ok {
 					matched = false
 				} else {
-					matched = matchStringValue(argumentMatcher.GetValue(), labelValue, ruleCache)
+					matched = pb.MatchStringValue(argumentMatcher.GetValue(), labelValue, ruleCache)
 				}
 				if !matched {
 					break
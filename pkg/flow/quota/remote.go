@@ -30,13 +30,14 @@ import (
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/modern-go/reflect2"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	rlimitV2 "github.com/polarismesh/polaris-go/pkg/model/pb/metric/v2"
+	"github.com/polarismesh/polaris-go/pkg/network"
 )
 
 // ResponseCallBack 应答回调函数
@@ -178,7 +179,8 @@ func (s *StreamCounterSet) HasInitialized(svcKey model.ServiceKey, labels string
 // createConnection 创建连接
 func (s *StreamCounterSet) createConnection() (*grpc.ClientConn, error) {
 	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts = append(opts, grpc.WithTransportCredentials(s.asyncConnector.creds))
+	opts = append(opts, grpc.WithPerRPCCredentials(s.asyncConnector.tokenCreds))
 	opts = append(opts, grpc.WithBlock())
 	ctx, cancel := context.WithTimeout(context.Background(), s.asyncConnector.connTimeout)
 	defer cancel()
@@ -608,16 +610,25 @@ type asyncRateLimitConnector struct {
 	reconnectInterval time.Duration
 	// 协议
 	protocol string
+	// 与server通信的传输层凭证
+	creds credentials.TransportCredentials
+	// 访问server的鉴权凭证
+	tokenCreds credentials.PerRPCCredentials
 }
 
 // NewAsyncRateLimitConnector .
-func NewAsyncRateLimitConnector(valueCtx model.ValueContext, cfg config.Configuration) AsyncRateLimitConnector {
+func NewAsyncRateLimitConnector(valueCtx model.ValueContext,
+	cfg config.Configuration) (AsyncRateLimitConnector, error) {
 	connTimeout := cfg.GetGlobal().GetServerConnector().GetConnectTimeout()
 	msgTimeout := cfg.GetGlobal().GetServerConnector().GetMessageTimeout()
 	protocol := cfg.GetGlobal().GetServerConnector().GetProtocol()
 	purgeInterval := cfg.GetProvider().GetRateLimit().GetPurgeInterval()
 	connIdleTimeout := cfg.GetGlobal().GetServerConnector().GetConnectionIdleTimeout()
 	reconnectInterval := cfg.GetGlobal().GetServerConnector().GetReconnectInterval()
+	tokenProvider, err := network.NewTokenProvider(cfg.GetGlobal().GetServerConnector())
+	if err != nil {
+		return nil, model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create token provider")
+	}
 	return &asyncRateLimitConnector{
 		mutex:             &sync.RWMutex{},
 		streams:           make(map[HostIdentifier]*StreamCounterSet),
@@ -630,7 +641,9 @@ func NewAsyncRateLimitConnector(valueCtx model.ValueContext, cfg config.Configur
 		once:              &sync.Once{},
 		clientHostMutex:   &sync.Mutex{},
 		protocol:          protocol,
-	}
+		creds:             network.NewTransportCredentials(cfg.GetGlobal().GetServerConnector().GetTLS()),
+		tokenCreds:        network.NewTokenCredentials(tokenProvider),
+	}, nil
 }
 
 // dropStreamCounterSet 淘汰流管理器
@@ -711,7 +724,7 @@ func (a *asyncRateLimitConnector) GetMessageSender(
 		})
 		a.taskValues = taskValues
 	})
-	instanceResp, err := engine.SyncGetOneInstance(req)
+	instanceResp, err := engine.SyncGetOneInstance(context.Background(), req)
 	if err != nil {
 		return nil, err
 	}
@@ -18,6 +18,7 @@
 package quota
 
 import (
+	"context"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -394,6 +395,8 @@ func NewRateLimitWindow(windowSet *RateLimitWindowSet, rule *apitraffic.Rule,
 		window.remoteCluster.Service = windowSet.flowAssistant.remoteService
 	}
 	window.syncParam.ControlParam = commonRequest.ControlParam
+	// 窗口的生命周期长于单次配额请求，不持有调用方上下文
+	window.syncParam.ControlParam.Context = context.Background()
 
 	window.rateLimiter = createBehavior(windowSet.flowAssistant.supplier, rule.GetAction().GetValue())
 	// 初始化流量整形窗口
@@ -18,6 +18,7 @@
 package startup
 
 import (
+	"context"
 	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
@@ -63,7 +64,7 @@ func (s *ServerServiceCallBack) Process(
 	request.Namespace = discoverService.Namespace
 	request.Service = discoverService.Service
 	commonRequest := &data.CommonInstancesRequest{}
-	commonRequest.InitByGetMultiRequest(request, s.cfg)
+	commonRequest.InitByGetMultiRequest(context.Background(), request, s.cfg)
 	err := s.engine.SyncGetResources(commonRequest)
 	if err != nil {
 		sdkErr := err.(model.SDKError)
@@ -18,6 +18,7 @@
 package flow
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -25,8 +26,10 @@ import (
 	"github.com/polarismesh/polaris-go/pkg/flow/cbcheck"
 	"github.com/polarismesh/polaris-go/pkg/flow/data"
 	"github.com/polarismesh/polaris-go/pkg/flow/registerstate"
+	"github.com/polarismesh/polaris-go/pkg/flow/weightadjust"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
 	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
@@ -83,10 +86,11 @@ func (e *Engine) syncConsumerInitCallServiceAndFinalize(commonRequest *data.Cons
 }
 
 // SyncGetOneInstance 同步获取服务实例
-func (e *Engine) SyncGetOneInstance(req *model.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
+func (e *Engine) SyncGetOneInstance(
+	ctx context.Context, req *model.GetOneInstanceRequest) (*model.OneInstanceResponse, error) {
 	// 方法开始时间
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
-	commonRequest.InitByGetOneRequest(req, e.configuration)
+	commonRequest.InitByGetOneRequest(ctx, req, e.configuration)
 	resp, err := e.doSyncGetOneInstance(commonRequest)
 	e.syncInstancesReportAndFinalize(commonRequest)
 	return resp, err
@@ -96,6 +100,9 @@ func (e *Engine) SyncGetOneInstance(req *model.GetOneInstanceRequest) (*model.On
 func (e *Engine) doSyncGetOneInstance(commonRequest *data.CommonInstancesRequest) (*model.OneInstanceResponse, error) {
 	startTime := e.globalCtx.Now()
 	err := e.syncGetWrapInstances(commonRequest)
+	if err == nil {
+		err = e.checkResourceCircuitBreaker(commonRequest)
+	}
 	consumeTime := e.globalCtx.Since(startTime)
 	if err != nil {
 		(&commonRequest.CallResult).SetFail(model.GetErrorCodeFromError(err), consumeTime)
@@ -104,6 +111,21 @@ func (e *Engine) doSyncGetOneInstance(commonRequest *data.CommonInstancesRequest
 	return e.doLoadBalanceToOneInstance(startTime, commonRequest)
 }
 
+// checkResourceCircuitBreaker 判断被调服务及接口是否已经被熔断
+func (e *Engine) checkResourceCircuitBreaker(commonRequest *data.CommonInstancesRequest) error {
+	for _, cbreaker := range e.circuitBreakerChain {
+		resourceBreaker, ok := cbreaker.(circuitbreaker.ResourceCircuitBreaker)
+		if !ok {
+			continue
+		}
+		if err := resourceBreaker.CheckResource(&commonRequest.DstService, commonRequest.Method); err != nil {
+			return model.NewSDKError(model.ErrCodeCircuitBreakerError, err,
+				"service %s is circuit broken", commonRequest.DstService)
+		}
+	}
+	return nil
+}
+
 func (e *Engine) doLoadBalanceToOneInstance(
 	startTime time.Time, commonRequest *data.CommonInstancesRequest) (*model.OneInstanceResponse, error) {
 	balancer, err := e.getLoadBalancer(commonRequest.DstInstances, commonRequest.LbPolicy)
@@ -141,6 +163,9 @@ func (e *Engine) SyncGetResources(req model.CacheValueQuery) error {
 	var totalConsumedTime, totalSleepTime time.Duration
 outLoop:
 	for retryTimes < param.MaxRetry {
+		if ctxErr := data.CheckContextDone(param.Context, "SyncGetResources"); ctxErr != nil {
+			return ctxErr
+		}
 		startTime := e.globalCtx.Now()
 		// 尝试获取本地缓存的值
 		combineContext, err = getAndLoadCacheValues(e.registry, req, retryTimes < param.MaxRetry)
@@ -154,7 +179,7 @@ outLoop:
 		// 发起并等待远程的结果
 		retryTimes++
 		syncCtx := combineContext
-		exceedTimeout := syncCtx.Wait(param.Timeout)
+		exceedTimeout := syncCtx.Wait(param.Context, param.Timeout)
 		// 计算请求耗时
 		consumedTime := e.globalCtx.Since(startTime)
 		totalConsumedTime += consumedTime
@@ -166,7 +191,9 @@ outLoop:
 		}
 		if exceedTimeout {
 			// 只有网络错误才可以重试
-			time.Sleep(param.RetryInterval)
+			if !data.SleepWithContext(param.Context, param.RetryInterval) {
+				return data.NewContextError(param.Context.Err(), "SyncGetResources")
+			}
 			totalSleepTime += param.RetryInterval
 			continue
 		}
@@ -277,18 +304,20 @@ func verifyCluster(svcInstances model.ServiceInstances, cluster *model.Cluster)
 }
 
 // SyncGetInstances 同步获取服务实例
-func (e *Engine) SyncGetInstances(req *model.GetInstancesRequest) (*model.InstancesResponse, error) {
+func (e *Engine) SyncGetInstances(
+	ctx context.Context, req *model.GetInstancesRequest) (*model.InstancesResponse, error) {
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
-	commonRequest.InitByGetMultiRequest(req, e.configuration)
+	commonRequest.InitByGetMultiRequest(ctx, req, e.configuration)
 	resp, err := e.doSyncGetInstances(commonRequest)
 	e.syncInstancesReportAndFinalize(commonRequest)
 	return resp, err
 }
 
 // SyncGetAllInstances 同步获取服务实例
-func (e *Engine) SyncGetAllInstances(req *model.GetAllInstancesRequest) (*model.InstancesResponse, error) {
+func (e *Engine) SyncGetAllInstances(
+	ctx context.Context, req *model.GetAllInstancesRequest) (*model.InstancesResponse, error) {
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
-	commonRequest.InitByGetAllRequest(req, e.configuration)
+	commonRequest.InitByGetAllRequest(ctx, req, e.configuration)
 	resp, err := e.doSyncGetAllInstances(commonRequest)
 	e.syncInstancesReportAndFinalize(commonRequest)
 	return resp, err
@@ -332,25 +361,40 @@ func (e *Engine) doSyncGetInstances(commonRequest *data.CommonInstancesRequest)
 }
 
 // SyncRegisterV2 async-regis
-func (e *Engine) SyncRegisterV2(request *model.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+func (e *Engine) SyncRegisterV2(
+	ctx context.Context, request *model.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
 	request.SetDefaultTTL()
 
-	resp, err := e.doSyncRegister(request, registerstate.CreateRegisterV2Header())
+	resp, err := e.doSyncRegister(ctx, request, registerstate.CreateRegisterV2Header())
 	if err != nil {
 		return nil, err
 	}
 
-	e.registerStates.PutRegister(request, e.doSyncRegister, e.SyncHeartbeat)
+	// 后台心跳及重注册任务的生命周期不跟随本次调用的上下文
+	e.registerStates.PutRegister(request, e.backgroundRegister, e.backgroundHeartbeat)
 	return resp, nil
 }
 
+// backgroundRegister 后台重注册任务使用的注册方法
+func (e *Engine) backgroundRegister(
+	instance *model.InstanceRegisterRequest, header map[string]string) (*model.InstanceRegisterResponse, error) {
+	return e.doSyncRegister(context.Background(), instance, header)
+}
+
+// backgroundHeartbeat 后台心跳任务使用的心跳上报方法
+func (e *Engine) backgroundHeartbeat(instance *model.InstanceHeartbeatRequest) error {
+	return e.SyncHeartbeat(context.Background(), instance)
+}
+
 // SyncRegister 同步进行服务注册
-func (e *Engine) SyncRegister(instance *model.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
-	return e.doSyncRegister(instance, nil)
+func (e *Engine) SyncRegister(
+	ctx context.Context, instance *model.InstanceRegisterRequest) (*model.InstanceRegisterResponse, error) {
+	return e.doSyncRegister(ctx, instance, nil)
 }
 
 // doSyncRegister 同步进行服务注册
-func (e *Engine) doSyncRegister(instance *model.InstanceRegisterRequest, header map[string]string) (*model.InstanceRegisterResponse, error) {
+func (e *Engine) doSyncRegister(ctx context.Context,
+	instance *model.InstanceRegisterRequest, header map[string]string) (*model.InstanceRegisterResponse, error) {
 	// 调用api的结果上报
 	apiCallResult := &model.APICallResult{
 		APICallKey: model.APICallKey{
@@ -363,7 +407,7 @@ func (e *Engine) doSyncRegister(instance *model.InstanceRegisterRequest, header
 		_ = e.reportAPIStat(apiCallResult)
 	}()
 	param := &model.ControlParam{}
-	data.BuildControlParam(instance, e.configuration, param)
+	data.BuildControlParam(ctx, instance, e.configuration, param)
 	// 方法开始时间
 	startTime := e.globalCtx.Now()
 	svcKey := model.ServiceKey{Namespace: instance.Namespace, Service: instance.Service}
@@ -372,8 +416,11 @@ func (e *Engine) doSyncRegister(instance *model.InstanceRegisterRequest, header
 	if instance.Location == nil {
 		instance.Location = e.globalCtx.GetCurrentLocation().GetLocation()
 	}
+	// 调用方上下文只设置到请求副本上，不修改用户传入的请求对象
+	callRequest := *instance
+	callRequest.SetContext(ctx)
 
-	resp, err := data.RetrySyncCall("register", &svcKey, instance, func(request interface{}) (interface{}, error) {
+	resp, err := data.RetrySyncCall("register", &svcKey, &callRequest, func(request interface{}) (interface{}, error) {
 		return e.connector.RegisterInstance(request.(*model.InstanceRegisterRequest), header)
 	}, param)
 	consumeTime := e.globalCtx.Since(startTime)
@@ -386,7 +433,7 @@ func (e *Engine) doSyncRegister(instance *model.InstanceRegisterRequest, header
 }
 
 // SyncDeregister 同步进行服务反注册
-func (e *Engine) SyncDeregister(instance *model.InstanceDeRegisterRequest) error {
+func (e *Engine) SyncDeregister(ctx context.Context, instance *model.InstanceDeRegisterRequest) error {
 	e.registerStates.RemoveRegister(instance)
 	// 调用api的结果上报
 	apiCallResult := &model.APICallResult{
@@ -400,11 +447,13 @@ func (e *Engine) SyncDeregister(instance *model.InstanceDeRegisterRequest) error
 		_ = e.reportAPIStat(apiCallResult)
 	}()
 	param := &model.ControlParam{}
-	data.BuildControlParam(instance, e.configuration, param)
+	data.BuildControlParam(ctx, instance, e.configuration, param)
 	// 方法开始时间
 	startTime := e.globalCtx.Now()
 	svcKey := model.ServiceKey{Namespace: instance.Namespace, Service: instance.Service}
-	_, err := data.RetrySyncCall("deregister", &svcKey, instance, func(request interface{}) (interface{}, error) {
+	callRequest := *instance
+	callRequest.SetContext(ctx)
+	_, err := data.RetrySyncCall("deregister", &svcKey, &callRequest, func(request interface{}) (interface{}, error) {
 		return nil, e.connector.DeregisterInstance(request.(*model.InstanceDeRegisterRequest))
 	}, param)
 	consumeTime := e.globalCtx.Since(startTime)
@@ -417,7 +466,7 @@ func (e *Engine) SyncDeregister(instance *model.InstanceDeRegisterRequest) error
 }
 
 // SyncHeartbeat 同步进行心跳上报
-func (e *Engine) SyncHeartbeat(instance *model.InstanceHeartbeatRequest) error {
+func (e *Engine) SyncHeartbeat(ctx context.Context, instance *model.InstanceHeartbeatRequest) error {
 	// 调用api的结果上报
 	apiCallResult := &model.APICallResult{
 		APICallKey: model.APICallKey{
@@ -430,11 +479,13 @@ func (e *Engine) SyncHeartbeat(instance *model.InstanceHeartbeatRequest) error {
 		_ = e.reportAPIStat(apiCallResult)
 	}()
 	param := &model.ControlParam{}
-	data.BuildControlParam(instance, e.configuration, param)
+	data.BuildControlParam(ctx, instance, e.configuration, param)
 	// 方法开始时间
 	startTime := e.globalCtx.Now()
 	svcKey := model.ServiceKey{Namespace: instance.Namespace, Service: instance.Service}
-	_, err := data.RetrySyncCall("heartbeat", &svcKey, instance, func(request interface{}) (interface{}, error) {
+	callRequest := *instance
+	callRequest.SetContext(ctx)
+	_, err := data.RetrySyncCall("heartbeat", &svcKey, &callRequest, func(request interface{}) (interface{}, error) {
 		return nil, e.connector.Heartbeat(request.(*model.InstanceHeartbeatRequest))
 	}, param)
 	consumeTime := e.globalCtx.Since(startTime)
@@ -446,6 +497,20 @@ func (e *Engine) SyncHeartbeat(instance *model.InstanceHeartbeatRequest) error {
 	return err
 }
 
+// SyncForceCircuitBreaker 手动设置实例的熔断状态，状态的过期依赖定时熔断检查任务，因此需要开启熔断
+func (e *Engine) SyncForceCircuitBreaker(req *model.ForceCircuitBreakerRequest) error {
+	if nil == e.circuitBreakTask {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+			"fail to force circuitbreaker status, circuitbreaker is not enabled")
+	}
+	err := e.circuitBreakTask.GetForcedCircuitBreaker().Force(req.Instance, req.Status, req.TTL)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeCircuitBreakerError, err,
+			"fail to force circuitbreaker status of instance %s", req.Instance.GetId())
+	}
+	return nil
+}
+
 // SyncUpdateServiceCallResult 同步上报调用结果信息
 func (e *Engine) SyncUpdateServiceCallResult(result *model.ServiceCallResult) error {
 	commonRequest := data.PoolGetCommonServiceCallResultRequest(e.plugins)
@@ -464,13 +529,19 @@ func (e *Engine) SyncUpdateServiceCallResult(result *model.ServiceCallResult) er
 
 // realSyncUpdateServiceCallResult 同步上报调用结果信息 实际处理函数
 func (e *Engine) realSyncUpdateServiceCallResult(result *model.ServiceCallResult) error {
+	// 先通知插件，保证在途请求等状态总能被释放
+	e.notifyServiceCallResult(result)
 	// 当前处理熔断和服务调用统计上报
 	if err := e.reportSvcStat(result); err != nil {
 		return err
 	}
+	if err := e.realTimeAdjustDynamicWeight(result); err != nil {
+		return err
+	}
 	if nil == e.rtCircuitBreakChan || len(e.circuitBreakerChain) == 0 {
 		return nil
 	}
+	e.circuitBreakTask.GetMethodCircuitBreaker().Stat(result, result.Method)
 	var rtTask *cbcheck.RealTimeLimitTask
 	for _, cbreaker := range e.circuitBreakerChain {
 		cbName := cbreaker.Name()
@@ -502,11 +573,52 @@ func (e *Engine) realSyncUpdateServiceCallResult(result *model.ServiceCallResult
 	return nil
 }
 
+// notifyServiceCallResult 将调用结果通知给关注调用结果的插件，例如根据在途请求数进行选择的负载均衡插件
+func (e *Engine) notifyServiceCallResult(result *model.ServiceCallResult) {
+	handlers := e.plugins.GetEventSubscribers(common.OnServiceCallResult)
+	if len(handlers) == 0 {
+		return
+	}
+	eventObj := &common.PluginEvent{
+		EventType:   common.OnServiceCallResult,
+		EventObject: result,
+	}
+	for _, h := range handlers {
+		_ = h.Callback(eventObj)
+	}
+}
+
+// realTimeAdjustDynamicWeight 统计调用结果，并在需要时立刻触发服务的动态权重调整
+func (e *Engine) realTimeAdjustDynamicWeight(result *model.ServiceCallResult) error {
+	if nil == e.rtWeightAdjustChan || nil == e.weightAdjuster {
+		return nil
+	}
+	needAdjust, err := e.weightAdjuster.RealTimeAdjustDynamicWeight(result)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeInternalError, err,
+			"fail to do real time weight adjust in %s", e.weightAdjuster.Name())
+	}
+	if !needAdjust {
+		return nil
+	}
+	svcKey := model.ServiceKey{
+		Namespace: result.GetNamespace(),
+		Service:   result.GetService(),
+	}
+	rtWeightAdjustTask := &model.PriorityTask{
+		Name:     fmt.Sprintf("real-time-wa-%s", result.GetID()),
+		CallBack: weightadjust.NewWeightAdjustRealTimeCallBack(e.weightAdjustTask, svcKey),
+	}
+	log.GetBaseLogger().Debugf("realTime weight adjust task %s for %s generated", rtWeightAdjustTask.Name, svcKey)
+	e.rtWeightAdjustChan <- rtWeightAdjustTask
+	return nil
+}
+
 // SyncGetServices 获取服务列表
-func (e *Engine) SyncGetServices(eventType model.EventType,
+func (e *Engine) SyncGetServices(ctx context.Context, eventType model.EventType,
 	req *model.GetServicesRequest) (*model.ServicesResponse, error) {
 	commonRequest := data.PoolGetServicesRequest()
-	commonRequest.InitByGetServicesRequest(eventType, req, e.configuration)
+	commonRequest.InitByGetServicesRequest(ctx, eventType, req, e.configuration)
 	resp, err := e.doSyncGetServices(commonRequest)
 	e.syncServicesAndFinalize(commonRequest)
 	return resp, err
@@ -522,10 +634,10 @@ func (e *Engine) doSyncGetServices(commonRequest *data.ServicesRequest) (*model.
 }
 
 // SyncGetServiceRule 同步获取服务规则
-func (e *Engine) SyncGetServiceRule(
+func (e *Engine) SyncGetServiceRule(ctx context.Context,
 	eventType model.EventType, req *model.GetServiceRuleRequest) (*model.ServiceRuleResponse, error) {
 	commonRequest := data.PoolGetCommonRuleRequest()
-	commonRequest.InitByGetRuleRequest(eventType, req, e.configuration)
+	commonRequest.InitByGetRuleRequest(ctx, eventType, req, e.configuration)
 	resp, err := e.doSyncGetServiceRule(commonRequest)
 	e.syncRuleReportAndFinalize(commonRequest)
 	return resp, err
@@ -542,6 +654,10 @@ func (e *Engine) doSyncGetServiceRule(commonRequest *data.CommonRuleRequest) (*m
 		Operation:  keyDstRoute}
 	apiStartTime := e.globalCtx.Now()
 	for retryTimes < maxRetryTimes {
+		if ctxErr := data.CheckContextDone(commonRequest.ControlParam.Context, "SyncGetServiceRule"); ctxErr != nil {
+			(&commonRequest.CallResult).SetFail(ctxErr.ErrorCode(), e.globalCtx.Since(apiStartTime))
+			return nil, ctxErr
+		}
 		startTime := e.globalCtx.Now()
 		svcRule := e.registry.GetServiceRouteRule(&commonRequest.DstService.ServiceKey, false)
 		if svcRule.IsInitialized() {
@@ -556,12 +672,12 @@ func (e *Engine) doSyncGetServiceRule(commonRequest *data.CommonRuleRequest) (*m
 		}
 		singleCtx := NewSingleNotifyContext(svcRuleKey, notifier)
 		retryTimes++
-		exceedTimeout := singleCtx.Wait(commonRequest.ControlParam.Timeout)
+		exceedTimeout := singleCtx.Wait(commonRequest.ControlParam.Context, commonRequest.ControlParam.Timeout)
 		// 计算请求耗时
 		consumedTime := e.globalCtx.Since(startTime)
 		if exceedTimeout {
 			// 只有网络错误才可以重试
-			time.Sleep(commonRequest.ControlParam.RetryInterval)
+			data.SleepWithContext(commonRequest.ControlParam.Context, commonRequest.ControlParam.RetryInterval)
 			log.GetBaseLogger().Warnf("retry GetRoutes for timeout, consume time %v,"+
 				" Namespace: %s, Service: %s, retry times: %d",
 				consumedTime, commonRequest.DstService.Namespace, commonRequest.DstService.Service, retryTimes)
@@ -615,7 +731,7 @@ func (e *Engine) realInitCalleeService(req *model.InitCalleeServiceRequest,
 	startTime := e.globalCtx.Now()
 	commonRequest := data.PoolGetCommonInstancesRequest(e.plugins)
 	defer data.PoolPutCommonInstancesRequest(commonRequest)
-	commonRequest.InitByGetAllRequest(&getAllReq, e.configuration)
+	commonRequest.InitByGetAllRequest(context.Background(), &getAllReq, e.configuration)
 	_, err := e.doSyncGetAllInstances(commonRequest)
 	costTime := e.globalCtx.Since(startTime)
 	if err != nil {
@@ -632,6 +748,37 @@ func (e *Engine) SyncGetConfigFile(namespace, fileGroup, fileName string) (model
 	return e.configFileFlow.GetConfigFile(namespace, fileGroup, fileName)
 }
 
+// SyncGetConfigKVFile 同步获取结构化配置文件
+func (e *Engine) SyncGetConfigKVFile(namespace, fileGroup, fileName string,
+	format model.ConfigFileFormat) (model.ConfigKVFile, error) {
+	return e.configFileFlow.GetConfigKVFile(namespace, fileGroup, fileName, format)
+}
+
+// SyncGetConfigGroup 同步获取配置分组
+func (e *Engine) SyncGetConfigGroup(namespace, group string) (model.ConfigFileGroup, error) {
+	return e.configFileFlow.GetConfigGroup(namespace, group)
+}
+
+// SyncCreateConfigFile 同步创建配置文件
+func (e *Engine) SyncCreateConfigFile(req *model.ConfigFileRequest) error {
+	return e.configFileFlow.CreateConfigFile(req)
+}
+
+// SyncUpdateConfigFile 同步更新配置文件
+func (e *Engine) SyncUpdateConfigFile(req *model.ConfigFileRequest) error {
+	return e.configFileFlow.UpdateConfigFile(req)
+}
+
+// SyncPublishConfigFile 同步发布配置文件
+func (e *Engine) SyncPublishConfigFile(metadata model.ConfigFileMetadata) error {
+	return e.configFileFlow.PublishConfigFile(metadata)
+}
+
+// SyncDeleteConfigFile 同步删除配置文件
+func (e *Engine) SyncDeleteConfigFile(metadata model.ConfigFileMetadata) error {
+	return e.configFileFlow.DeleteConfigFile(metadata)
+}
+
 // WatchAllInstances 监听所有的实例
 func (e *Engine) WatchAllInstances(request *model.WatchAllInstancesRequest) (*model.WatchAllInstancesResponse, error) {
 	return e.watchEngine.WatchAllInstances(request)
@@ -18,11 +18,15 @@
 package flow
 
 import (
+	"time"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/flow/cbcheck"
 	"github.com/polarismesh/polaris-go/pkg/flow/detect"
 	"github.com/polarismesh/polaris-go/pkg/flow/schedule"
 	"github.com/polarismesh/polaris-go/pkg/flow/startup"
+	"github.com/polarismesh/polaris-go/pkg/flow/warmup"
+	"github.com/polarismesh/polaris-go/pkg/flow/weightadjust"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 )
@@ -33,8 +37,13 @@ const (
 	taskClientReport  = "clientReportTask"
 	taskServerService = "syncGetServerService"
 	taskHealthCheck   = "healthCheckTask"
+	taskWeightAdjust  = "weightAdjustTask"
+	taskWarmup        = "warmupTask"
 )
 
+// 实例预热百分比的计算周期
+const warmupCheckPeriod = time.Second
+
 // ScheduleTask 调度任务
 func (e *Engine) ScheduleTask(task *model.PeriodicTask) (chan<- *model.PriorityTask, model.TaskValues) {
 	routine := schedule.NewTaskRoutine(task)
@@ -44,7 +53,7 @@ func (e *Engine) ScheduleTask(task *model.PeriodicTask) (chan<- *model.PriorityT
 
 // addPeriodicCircuitBreakTask 添加定时熔断任务
 func (e *Engine) addPeriodicCircuitBreakTask() (chan<- *model.PriorityTask, *cbcheck.CircuitBreakCallBack, error) {
-	callback, err := cbcheck.NewCircuitBreakCallBack(e.configuration, e.plugins)
+	callback, err := cbcheck.NewCircuitBreakCallBack(e.configuration, e.plugins, e)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -64,6 +73,51 @@ func (e *Engine) addPeriodicCircuitBreakTask() (chan<- *model.PriorityTask, *cbc
 	return rtChan, callback, nil
 }
 
+// addPeriodicWeightAdjustTask 添加定时动态权重调整任务
+func (e *Engine) addPeriodicWeightAdjustTask() (
+	chan<- *model.PriorityTask, *weightadjust.WeightAdjustCallBack, error) {
+	callback, err := weightadjust.NewWeightAdjustCallBack(e.configuration, e.plugins)
+	if err != nil {
+		return nil, nil, err
+	}
+	rtChan, taskValues := e.ScheduleTask(&model.PeriodicTask{
+		Name:         taskWeightAdjust,
+		CallBack:     callback,
+		TakePriority: true,
+		LongRun:      false,
+		Period:       e.configuration.GetConsumer().GetWeightAdjuster().GetAdjustPeriod() / 2,
+	})
+	svcEventHandler := &schedule.ServiceEventHandler{TaskValues: taskValues}
+	// 注入服务回调函数
+	e.plugins.RegisterEventSubscriber(common.OnServiceAdded, common.PluginEventHandler{
+		Callback: svcEventHandler.OnServiceAdded})
+	e.plugins.RegisterEventSubscriber(common.OnServiceDeleted, common.PluginEventHandler{
+		Callback: svcEventHandler.OnServiceDeleted})
+	return rtChan, callback, nil
+}
+
+// addPeriodicWarmupTask 添加定时实例预热任务
+func (e *Engine) addPeriodicWarmupTask() (model.TaskValues, error) {
+	callback, err := warmup.NewWarmupCallBack(e.configuration, e.plugins, warmupCheckPeriod)
+	if err != nil {
+		return nil, err
+	}
+	_, taskValues := e.ScheduleTask(&model.PeriodicTask{
+		Name:         taskWarmup,
+		CallBack:     callback,
+		TakePriority: false,
+		LongRun:      false,
+		Period:       warmupCheckPeriod / 2,
+	})
+	svcEventHandler := &schedule.ServiceEventHandler{TaskValues: taskValues}
+	// 注入服务回调函数
+	e.plugins.RegisterEventSubscriber(common.OnServiceAdded, common.PluginEventHandler{
+		Callback: svcEventHandler.OnServiceAdded})
+	e.plugins.RegisterEventSubscriber(common.OnServiceDeleted, common.PluginEventHandler{
+		Callback: svcEventHandler.OnServiceDeleted})
+	return taskValues, nil
+}
+
 // addClientReportTask 添加客户端定期上报任务
 func (e *Engine) addClientReportTask() (model.TaskValues, error) {
 	callback, err := startup.NewReportClientCallBack(e.configuration, e.plugins, e.globalCtx)
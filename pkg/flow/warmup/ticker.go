@@ -0,0 +1,165 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package warmup
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+)
+
+const (
+	// 预热百分比变化小于该值时不更新，避免频繁重建实例索引
+	minPercentStep = 5
+	// 完整权重对应的百分比
+	fullPercent = 100
+)
+
+// NewWarmupCallBack 创建定时实例预热任务回调
+func NewWarmupCallBack(cfg config.Configuration, supplier plugin.Supplier,
+	interval time.Duration) (*WarmupCallBack, error) {
+	var err error
+	callBack := &WarmupCallBack{}
+	if callBack.registry, err = data.GetRegistry(cfg, supplier); err != nil {
+		return nil, err
+	}
+	callBack.consumerConfig = cfg.GetConsumer()
+	callBack.interval = interval
+	return callBack, nil
+}
+
+// WarmupCallBack 定时实例预热任务回调，按照预热曲线逐步提升新增实例的权重
+type WarmupCallBack struct {
+	// 本地缓存
+	registry localregistry.LocalRegistry
+	// 被调端配置，用于获取全局以及服务级的预热配置
+	consumerConfig config.ConsumerConfig
+	// 轮询间隔
+	interval time.Duration
+}
+
+// Process 执行任务
+func (c *WarmupCallBack) Process(
+	taskKey interface{}, taskValue interface{}, lastProcessTime time.Time) model.TaskResult {
+	if !lastProcessTime.IsZero() && time.Since(lastProcessTime) < c.interval {
+		return model.SKIP
+	}
+	svc := taskKey.(model.ServiceKey)
+	request, err := c.doWarmupForService(svc)
+	if err != nil {
+		log.GetBaseLogger().Errorf("fail to do timing warmup for %s, error: %v", svc, err)
+		return model.CONTINUE
+	}
+	if nil != request {
+		log.GetBaseLogger().Debugf("success to timing warmup for %s, result is %s", svc, request.String())
+	}
+	return model.CONTINUE
+}
+
+// OnTaskEvent 任务事件回调
+func (c *WarmupCallBack) OnTaskEvent(event model.TaskEvent) {
+
+}
+
+// doWarmupForService 计算服务下各实例的预热百分比，变化足够大时更新到本地缓存
+func (c *WarmupCallBack) doWarmupForService(svc model.ServiceKey) (*localregistry.ServiceUpdateRequest, error) {
+	svcInstances := c.registry.GetInstances(&svc, false, true)
+	if !svcInstances.IsInitialized() || len(svcInstances.GetInstances()) == 0 {
+		return nil, nil
+	}
+	warmupConfig := c.consumerConfig.GetEffectiveWarmup(svc.Namespace, svc.Service)
+	now := time.Now()
+	var updateRequest *localregistry.ServiceUpdateRequest
+	for _, instance := range svcInstances.GetInstances() {
+		holder, ok := instance.(model.WarmupWeightHolder)
+		if !ok {
+			continue
+		}
+		current := holder.GetWarmupWeightPercent()
+		percent := fullPercent
+		if warmupConfig.IsEnable() {
+			if startTime := getStartTime(instance, warmupConfig.GetStartTimeMetadataKey()); !startTime.IsZero() {
+				percent = CalcWarmupPercent(now.Sub(startTime), warmupConfig.GetWindow(),
+					warmupConfig.GetCurve(), warmupConfig.GetMinWeightPercent())
+			}
+		}
+		if percent == current || (percent < fullPercent && int(math.Abs(float64(percent-current))) < minPercentStep) {
+			continue
+		}
+		if nil == updateRequest {
+			updateRequest = &localregistry.ServiceUpdateRequest{ServiceKey: svc}
+		}
+		updateRequest.Properties = append(updateRequest.Properties, localregistry.InstanceProperties{
+			ID:         instance.GetId(),
+			Service:    &updateRequest.ServiceKey,
+			Properties: map[string]interface{}{localregistry.PropertyWarmupWeight: percent},
+		})
+	}
+	if nil == updateRequest {
+		return nil, nil
+	}
+	return updateRequest, c.registry.UpdateInstances(updateRequest)
+}
+
+// getStartTime 获取实例的预热开始时间，优先使用元数据中记录的启动时间，其次为SDK首次发现实例的时间，
+// 服务首次加载时已经存在且没有启动时间元数据的实例返回零值，视为已完成预热
+func getStartTime(instance model.Instance, metadataKey string) time.Time {
+	if len(metadataKey) > 0 {
+		if value, ok := instance.GetMetadata()[metadataKey]; ok && len(value) > 0 {
+			if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return time.Unix(0, millis*int64(time.Millisecond))
+			}
+			if startTime, err := time.Parse(time.RFC3339, value); err == nil {
+				return startTime
+			}
+			log.GetBaseLogger().Warnf("invalid warmup start time %s of instance %s", value, instance.GetId())
+		}
+	}
+	if localValue, ok := instance.(local.InstanceLocalValue); ok {
+		return localValue.GetFirstSeenTime()
+	}
+	return time.Time{}
+}
+
+// CalcWarmupPercent 根据预热开始后经过的时间计算权重百分比，minPercent为预热开始时的百分比
+// linear曲线按照时间线性增长，aggressive曲线按照时间的平方根增长
+func CalcWarmupPercent(elapsed time.Duration, window time.Duration, curve string, minPercent int) int {
+	if window <= 0 || elapsed >= window {
+		return fullPercent
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	ratio := float64(elapsed) / float64(window)
+	if curve == config.WarmupCurveAggressive {
+		ratio = math.Sqrt(ratio)
+	}
+	percent := minPercent + int(float64(fullPercent-minPercent)*ratio)
+	if percent > fullPercent {
+		return fullPercent
+	}
+	return percent
+}
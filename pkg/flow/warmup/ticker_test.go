@@ -0,0 +1,42 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package warmup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+// TestCalcWarmupPercent 测试预热曲线的权重百分比计算
+func TestCalcWarmupPercent(t *testing.T) {
+	window := 60 * time.Second
+	assert.Equal(t, 10, CalcWarmupPercent(0, window, config.WarmupCurveLinear, 10))
+	assert.Equal(t, 55, CalcWarmupPercent(30*time.Second, window, config.WarmupCurveLinear, 10))
+	assert.Equal(t, 100, CalcWarmupPercent(window, window, config.WarmupCurveLinear, 10))
+	assert.Equal(t, 100, CalcWarmupPercent(2*window, window, config.WarmupCurveLinear, 10))
+	// 激进曲线前期增长更快
+	assert.Equal(t, 55, CalcWarmupPercent(15*time.Second, window, config.WarmupCurveAggressive, 10))
+	assert.True(t, CalcWarmupPercent(15*time.Second, window, config.WarmupCurveAggressive, 10) >
+		CalcWarmupPercent(15*time.Second, window, config.WarmupCurveLinear, 10))
+	// 时钟回拨时按照预热开始处理
+	assert.Equal(t, 10, CalcWarmupPercent(-time.Second, window, config.WarmupCurveLinear, 10))
+}
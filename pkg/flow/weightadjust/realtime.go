@@ -0,0 +1,53 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package weightadjust
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// NewWeightAdjustRealTimeCallBack 创建实时动态权重调整任务
+func NewWeightAdjustRealTimeCallBack(
+	callBack *WeightAdjustCallBack, svcKey model.ServiceKey) *WeightAdjustRealTimeCallBack {
+	return &WeightAdjustRealTimeCallBack{
+		commonCallBack: callBack,
+		svcKey:         svcKey,
+	}
+}
+
+// WeightAdjustRealTimeCallBack 实时动态权重调整任务回调
+type WeightAdjustRealTimeCallBack struct {
+	commonCallBack *WeightAdjustCallBack
+	svcKey         model.ServiceKey
+}
+
+// Process 处理实时任务
+func (c *WeightAdjustRealTimeCallBack) Process() {
+	request, err := c.commonCallBack.doWeightAdjustForService(c.svcKey)
+	var resultStr = "nil"
+	if nil != request {
+		resultStr = request.String()
+	}
+	if err != nil {
+		log.GetBaseLogger().Errorf("fail to do realtime weight adjust for %s, result is %s, error: %v",
+			c.svcKey, resultStr, err)
+		return
+	}
+	log.GetBaseLogger().Infof("success to realtime weight adjust for %s, result is %s", c.svcKey, resultStr)
+}
@@ -0,0 +1,108 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package weightadjust
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/flow/data"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+	"github.com/polarismesh/polaris-go/pkg/plugin/weightadjuster"
+)
+
+// NewWeightAdjustCallBack 创建定时动态权重调整任务回调
+func NewWeightAdjustCallBack(cfg config.Configuration, supplier plugin.Supplier) (*WeightAdjustCallBack, error) {
+	var err error
+	callBack := &WeightAdjustCallBack{}
+	if callBack.registry, err = data.GetRegistry(cfg, supplier); err != nil {
+		return nil, err
+	}
+	if callBack.weightAdjuster, err = data.GetWeightAdjuster(cfg, supplier); err != nil {
+		return nil, err
+	}
+	callBack.interval = cfg.GetConsumer().GetWeightAdjuster().GetAdjustPeriod()
+	return callBack, nil
+}
+
+// WeightAdjustCallBack 定时动态权重调整任务回调
+type WeightAdjustCallBack struct {
+	// 动态权重调整器
+	weightAdjuster weightadjuster.WeightAdjuster
+	// 本地缓存
+	registry localregistry.LocalRegistry
+	// 轮询间隔
+	interval time.Duration
+}
+
+// Process 执行任务
+func (c *WeightAdjustCallBack) Process(
+	taskKey interface{}, taskValue interface{}, lastProcessTime time.Time) model.TaskResult {
+	if !lastProcessTime.IsZero() && time.Since(lastProcessTime) < c.interval {
+		return model.SKIP
+	}
+	svc := taskKey.(model.ServiceKey)
+	request, err := c.doWeightAdjustForService(svc)
+	var resultStr = "nil"
+	if nil != request {
+		resultStr = request.String()
+	}
+	if err != nil {
+		log.GetBaseLogger().Errorf(
+			"fail to do timing weight adjust for %s, result is %s, error: %v", svc, resultStr, err)
+		return model.CONTINUE
+	}
+	log.GetBaseLogger().Debugf("success to timing weight adjust for %s, result is %s", svc, resultStr)
+	return model.CONTINUE
+}
+
+// OnTaskEvent 任务事件回调
+func (c *WeightAdjustCallBack) OnTaskEvent(event model.TaskEvent) {
+
+}
+
+// doWeightAdjustForService 对服务进行动态权重调整
+func (c *WeightAdjustCallBack) doWeightAdjustForService(
+	svc model.ServiceKey) (*localregistry.ServiceUpdateRequest, error) {
+	svcInstances := c.registry.GetInstances(&svc, false, true)
+	if !svcInstances.IsInitialized() || len(svcInstances.GetInstances()) == 0 {
+		return nil, nil
+	}
+	weights, err := c.weightAdjuster.TimingAdjustDynamicWeight(svcInstances)
+	if err != nil {
+		return nil, err
+	}
+	if len(weights) == 0 {
+		return nil, nil
+	}
+	updateRequest := &localregistry.ServiceUpdateRequest{
+		ServiceKey: svc,
+		Properties: make([]localregistry.InstanceProperties, 0, len(weights)),
+	}
+	for _, weight := range weights {
+		updateRequest.Properties = append(updateRequest.Properties, localregistry.InstanceProperties{
+			ID:         weight.InstanceID,
+			Service:    &updateRequest.ServiceKey,
+			Properties: map[string]interface{}{localregistry.PropertyDynamicWeight: weight},
+		})
+	}
+	return updateRequest, c.registry.UpdateInstances(updateRequest)
+}
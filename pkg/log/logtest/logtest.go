@@ -0,0 +1,60 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package logtest 单元测试使用的日志工具
+package logtest
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// DiscardLogger 丢弃所有日志的日志对象
+type DiscardLogger struct{}
+
+// Tracef 丢弃trace级别的日志
+func (DiscardLogger) Tracef(string, ...interface{}) {}
+
+// Debugf 丢弃debug级别的日志
+func (DiscardLogger) Debugf(string, ...interface{}) {}
+
+// Infof 丢弃info级别的日志
+func (DiscardLogger) Infof(string, ...interface{}) {}
+
+// Warnf 丢弃warn级别的日志
+func (DiscardLogger) Warnf(string, ...interface{}) {}
+
+// Errorf 丢弃error级别的日志
+func (DiscardLogger) Errorf(string, ...interface{}) {}
+
+// Fatalf 丢弃fatal级别的日志
+func (DiscardLogger) Fatalf(string, ...interface{}) {}
+
+// IsLevelEnabled 所有级别均不打印
+func (DiscardLogger) IsLevelEnabled(int) bool { return false }
+
+// SetLogLevel 忽略日志级别设置
+func (DiscardLogger) SetLogLevel(int) error { return nil }
+
+// DiscardLoggers 单元测试不初始化日志插件，将全局的各类日志对象设置为丢弃打印的日志
+func DiscardLoggers() {
+	log.SetBaseLogger(DiscardLogger{})
+	log.SetStatLogger(DiscardLogger{})
+	log.SetStatReportLogger(DiscardLogger{})
+	log.SetDetectLogger(DiscardLogger{})
+	log.SetCacheLogger(DiscardLogger{})
+	log.SetNetworkLogger(DiscardLogger{})
+}
@@ -535,7 +535,7 @@ func (i *InstanceSet) GetSelector(id int32) ExtendedSelector {
 
 // addInstance 加入实例到实例集合中
 func (i *InstanceSet) addInstance(index int, instance Instance) {
-	weight := instance.GetWeight()
+	weight := GetEffectiveWeight(instance)
 	if weight > i.maxWeight {
 		i.maxWeight = weight
 	}
@@ -17,6 +17,15 @@
 
 package model
 
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
 // ChangeType 配置文件变更类型
 type ChangeType int
 
@@ -69,6 +78,144 @@ type ConfigFile interface {
 	AddChangeListener(cb OnConfigFileChange)
 }
 
+// StaleConfigFile 配置文件对象可选实现的接口，判断配置内容是否来自本地缓存
+type StaleConfigFile interface {
+	// IsStale 配置中心不可用时使用本地缓存启动，在与服务端重新同步之前返回true
+	IsStale() bool
+}
+
+// ConfigFileFormat 结构化配置文件的格式
+type ConfigFileFormat string
+
+const (
+	// ConfigFileFormatUnknown 未知格式
+	ConfigFileFormatUnknown ConfigFileFormat = ""
+	// ConfigFileFormatYaml yaml格式
+	ConfigFileFormatYaml ConfigFileFormat = "yaml"
+	// ConfigFileFormatJSON json格式
+	ConfigFileFormatJSON ConfigFileFormat = "json"
+	// ConfigFileFormatProperties properties格式
+	ConfigFileFormatProperties ConfigFileFormat = "properties"
+)
+
+// ParseConfigFileFormat 根据配置文件名的后缀获取配置文件格式
+func ParseConfigFileFormat(fileName string) ConfigFileFormat {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".yaml", ".yml":
+		return ConfigFileFormatYaml
+	case ".json":
+		return ConfigFileFormatJSON
+	case ".properties":
+		return ConfigFileFormatProperties
+	default:
+		return ConfigFileFormatUnknown
+	}
+}
+
+// ConfigPropertyChange 配置项变更信息
+type ConfigPropertyChange struct {
+	// Key 配置项名称，多层级的配置项以.连接，数组元素以[下标]表示
+	Key string
+	// OldValue 变更之前的值
+	OldValue string
+	// NewValue 变更之后的值
+	NewValue string
+	// ChangeType 变更类型
+	ChangeType ChangeType
+}
+
+// ConfigKVFileChangeEvent 结构化配置文件的配置项变更事件
+type ConfigKVFileChangeEvent struct {
+	ConfigFileMetadata ConfigFileMetadata
+
+	// Changes 发生变更的配置项，key为配置项名称
+	Changes map[string]*ConfigPropertyChange
+}
+
+// OnConfigKVFileChange 结构化配置文件配置项变更回调监听器
+type OnConfigKVFileChange func(event ConfigKVFileChangeEvent)
+
+// ConfigKVFile 结构化配置文件对象，支持 yaml、json、properties 格式
+// 多层级的配置项以.连接作为名称，数组元素以[下标]表示，例如 server.ports[0]
+type ConfigKVFile interface {
+	ConfigFile
+	// GetFormat 获取配置文件格式
+	GetFormat() ConfigFileFormat
+	// GetPropertyNames 获取所有的配置项名称
+	GetPropertyNames() []string
+	// GetProperty 获取配置项的值，配置项不存在时返回默认值
+	GetProperty(key string, defaultValue string) string
+	// GetIntProperty 获取int类型的配置项，配置项不存在或者类型转换失败时返回默认值
+	GetIntProperty(key string, defaultValue int) int
+	// GetInt64Property 获取int64类型的配置项，配置项不存在或者类型转换失败时返回默认值
+	GetInt64Property(key string, defaultValue int64) int64
+	// GetFloat64Property 获取float64类型的配置项，配置项不存在或者类型转换失败时返回默认值
+	GetFloat64Property(key string, defaultValue float64) float64
+	// GetBoolProperty 获取bool类型的配置项，配置项不存在或者类型转换失败时返回默认值
+	GetBoolProperty(key string, defaultValue bool) bool
+	// GetDurationProperty 获取时间类型的配置项，格式如 1s、500ms，配置项不存在或者类型转换失败时返回默认值
+	GetDurationProperty(key string, defaultValue time.Duration) time.Duration
+	// GetStringSliceProperty 获取以,分隔的字符串数组配置项，配置项不存在时返回默认值
+	GetStringSliceProperty(key string, defaultValue []string) []string
+	// Unmarshal 将配置文件内容解析到结构体中
+	Unmarshal(v interface{}) error
+	// AddKVChangeListenerWithChannel 增加配置项变更监听器
+	AddKVChangeListenerWithChannel() <-chan ConfigKVFileChangeEvent
+	// AddKVChangeListener 增加配置项变更监听器
+	AddKVChangeListener(cb OnConfigKVFileChange)
+}
+
+// SimpleConfigFile 配置分组中已发布的配置文件信息，不包含配置内容
+type SimpleConfigFile struct {
+	Namespace string
+	FileGroup string
+	FileName  string
+	// Version 配置文件发布版本
+	Version uint64
+	// Md5 配置内容的MD5值
+	Md5 string
+}
+
+// ConfigGroupFileChange 配置分组中单个配置文件的变更信息
+type ConfigGroupFileChange struct {
+	FileName string
+	// OldVersion 变更之前的发布版本，新增文件为0
+	OldVersion uint64
+	// NewVersion 变更之后的发布版本，删除文件为0
+	NewVersion uint64
+	// ChangeType 变更类型，重新发布为 Modified
+	ChangeType ChangeType
+}
+
+// ConfigGroupChangeEvent 配置分组变更事件
+type ConfigGroupChangeEvent struct {
+	Namespace string
+	Group     string
+	// Changes 发生变更的配置文件，按照文件名排序
+	Changes []*ConfigGroupFileChange
+	// Files 变更之后分组下已发布的配置文件
+	Files []*SimpleConfigFile
+}
+
+// OnConfigGroupChange 配置分组变更回调监听器
+type OnConfigGroupChange func(event ConfigGroupChangeEvent)
+
+// ConfigFileGroup 配置分组对象，分组下的配置文件新增、删除或者重新发布时触发变更事件
+type ConfigFileGroup interface {
+	// GetNamespace 获取命名空间
+	GetNamespace() string
+	// GetGroup 获取配置分组名
+	GetGroup() string
+	// GetRevision 获取配置分组的版本
+	GetRevision() string
+	// GetFiles 获取分组下已发布的配置文件，按照文件名排序
+	GetFiles() []*SimpleConfigFile
+	// AddChangeListenerWithChannel 增加配置分组变更监听器
+	AddChangeListenerWithChannel() <-chan ConfigGroupChangeEvent
+	// AddChangeListener 增加配置分组变更监听器
+	AddChangeListener(cb OnConfigGroupChange)
+}
+
 // DefaultConfigFileMetadata 默认 ConfigFileMetadata 实现类
 type DefaultConfigFileMetadata struct {
 	Namespace string
@@ -90,3 +237,49 @@ func (m *DefaultConfigFileMetadata) GetFileGroup() string {
 func (m *DefaultConfigFileMetadata) GetFileName() string {
 	return m.FileName
 }
+
+// ConfigFileRequest 创建、更新配置文件的请求
+type ConfigFileRequest struct {
+	DefaultConfigFileMetadata
+	// Content 配置文件内容
+	Content string
+	// Encrypted 是否加密存储，SDK 生成数据密钥加密配置内容，数据密钥使用服务端公钥加密后随请求提交
+	Encrypted bool
+	// EncryptAlgo 加密算法，为空时使用 config.configFilter 中配置的第一个加密算法
+	EncryptAlgo string
+	// Tags 配置文件标签
+	Tags map[string]string
+}
+
+// Validate 校验创建、更新配置文件的请求
+func (r *ConfigFileRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ConfigFileRequest can not be nil")
+	}
+	var errs error
+	if err := ValidateConfigFileMetadata(&r.DefaultConfigFileMetadata); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if !r.Encrypted && len(r.EncryptAlgo) > 0 {
+		errs = multierror.Append(errs, fmt.Errorf("ConfigFileRequest: encryptAlgo is set but encrypted is false"))
+	}
+	if errs != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, errs, "fail to validate ConfigFileRequest: ")
+	}
+	return nil
+}
+
+// ValidateConfigFileMetadata 校验配置文件元信息
+func ValidateConfigFileMetadata(metadata ConfigFileMetadata) error {
+	var errs error
+	if len(metadata.GetNamespace()) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("config file namespace should not be empty"))
+	}
+	if len(metadata.GetFileGroup()) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("config file group should not be empty"))
+	}
+	if len(metadata.GetFileName()) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("config file name should not be empty"))
+	}
+	return errs
+}
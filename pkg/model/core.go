@@ -33,8 +33,12 @@ const (
 	EventRouting EventType = 0x2002
 	// EventRateLimiting 限流配置事件
 	EventRateLimiting EventType = 0x2003
+	// EventCircuitBreaker 熔断规则事件
+	EventCircuitBreaker EventType = 0x2004
 	// EventServices 批量服务
 	EventServices EventType = 0x2005
+	// EventFaultDetect 主动探测规则事件
+	EventFaultDetect EventType = 0x2006
 )
 
 // RegistryValue 存储于sdk缓存中的对象，包括服务实例和服务路由
@@ -62,17 +66,21 @@ func (e EventType) String() string {
 var (
 	// 路由规则到日志回显
 	eventTypeToPresent = map[EventType]string{
-		EventInstances:    "instance",
-		EventRouting:      "routing",
-		EventRateLimiting: "rate_limiting",
-		EventServices:     "services",
+		EventInstances:      "instance",
+		EventRouting:        "routing",
+		EventRateLimiting:   "rate_limiting",
+		EventCircuitBreaker: "circuit_breaker",
+		EventServices:       "services",
+		EventFaultDetect:    "fault_detect",
 	}
 
 	presentToEventType = map[string]EventType{
-		"instance":      EventInstances,
-		"routing":       EventRouting,
-		"rate_limiting": EventRateLimiting,
-		"services":      EventServices,
+		"instance":        EventInstances,
+		"routing":         EventRouting,
+		"rate_limiting":   EventRateLimiting,
+		"circuit_breaker": EventCircuitBreaker,
+		"services":        EventServices,
+		"fault_detect":    EventFaultDetect,
 	}
 )
 
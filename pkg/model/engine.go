@@ -18,6 +18,7 @@
 package model
 
 import (
+	"context"
 	"time"
 )
 
@@ -44,6 +45,8 @@ type ControlParam struct {
 	Timeout       time.Duration
 	MaxRetry      int
 	RetryInterval time.Duration
+	// 调用方上下文，取消或超时后不再继续等待和重试
+	Context context.Context
 }
 
 // CacheValueQuery 缓存查询请求对象
@@ -76,32 +79,34 @@ type Engine interface {
 	Destroy() error
 	// SyncGetResources 同步加载资源，可通过配置参数指定一次同时加载多个资源
 	SyncGetResources(req CacheValueQuery) error
-	// SyncGetOneInstance 同步获取负载均衡后的服务实例
-	SyncGetOneInstance(req *GetOneInstanceRequest) (*OneInstanceResponse, error)
-	// SyncGetInstances 同步获取批量服务实例
-	SyncGetInstances(req *GetInstancesRequest) (*InstancesResponse, error)
-	// SyncGetAllInstances 同步获取全量服务实例
-	SyncGetAllInstances(req *GetAllInstancesRequest) (*InstancesResponse, error)
-	// SyncRegisterV2 同步进行服务注册，并且会自动进行心跳上报动作
-	SyncRegisterV2(Instance *InstanceRegisterRequest) (*InstanceRegisterResponse, error)
-	// SyncRegister 同步进行服务注册
-	SyncRegister(instance *InstanceRegisterRequest) (*InstanceRegisterResponse, error)
-	// SyncDeregister 同步进行服务反注册
-	SyncDeregister(instance *InstanceDeRegisterRequest) error
-	// SyncHeartbeat 同步进行心跳上报
-	SyncHeartbeat(instance *InstanceHeartbeatRequest) error
+	// SyncGetOneInstance 同步获取负载均衡后的服务实例，ctx结束后不再等待及重试
+	SyncGetOneInstance(ctx context.Context, req *GetOneInstanceRequest) (*OneInstanceResponse, error)
+	// SyncGetInstances 同步获取批量服务实例，ctx结束后不再等待及重试
+	SyncGetInstances(ctx context.Context, req *GetInstancesRequest) (*InstancesResponse, error)
+	// SyncGetAllInstances 同步获取全量服务实例，ctx结束后不再等待及重试
+	SyncGetAllInstances(ctx context.Context, req *GetAllInstancesRequest) (*InstancesResponse, error)
+	// SyncRegisterV2 同步进行服务注册，并且会自动进行心跳上报动作，ctx只作用于本次注册
+	SyncRegisterV2(ctx context.Context, Instance *InstanceRegisterRequest) (*InstanceRegisterResponse, error)
+	// SyncRegister 同步进行服务注册，ctx结束后不再重试
+	SyncRegister(ctx context.Context, instance *InstanceRegisterRequest) (*InstanceRegisterResponse, error)
+	// SyncDeregister 同步进行服务反注册，ctx结束后不再重试
+	SyncDeregister(ctx context.Context, instance *InstanceDeRegisterRequest) error
+	// SyncHeartbeat 同步进行心跳上报，ctx结束后不再重试
+	SyncHeartbeat(ctx context.Context, instance *InstanceHeartbeatRequest) error
 	// SyncUpdateServiceCallResult 上报调用结果信息
 	SyncUpdateServiceCallResult(result *ServiceCallResult) error
+	// SyncForceCircuitBreaker 手动设置实例的熔断状态，在有效期内熔断器不再对该实例进行状态转换
+	SyncForceCircuitBreaker(req *ForceCircuitBreakerRequest) error
 	// SyncReportStat 上报实例统计信息
 	SyncReportStat(typ MetricType, stat InstanceGauge) error
-	// SyncGetServiceRule 同步获取服务规则
-	SyncGetServiceRule(
+	// SyncGetServiceRule 同步获取服务规则，ctx结束后不再等待及重试
+	SyncGetServiceRule(ctx context.Context,
 		eventType EventType, req *GetServiceRuleRequest) (*ServiceRuleResponse, error)
-	// SyncGetServices 同步获取批量服务
-	SyncGetServices(
+	// SyncGetServices 同步获取批量服务，ctx结束后不再等待及重试
+	SyncGetServices(ctx context.Context,
 		eventType EventType, req *GetServicesRequest) (*ServicesResponse, error)
-	// AsyncGetQuota 同步获取配额信息
-	AsyncGetQuota(request *QuotaRequestImpl) (*QuotaFutureImpl, error)
+	// AsyncGetQuota 同步获取配额信息，ctx结束后不再等待限流规则的加载
+	AsyncGetQuota(ctx context.Context, request *QuotaRequestImpl) (*QuotaFutureImpl, error)
 	// ScheduleTask 启动定时任务
 	ScheduleTask(task *PeriodicTask) (chan<- *PriorityTask, TaskValues)
 	// WatchService 监听服务的change
@@ -112,6 +117,18 @@ type Engine interface {
 	InitCalleeService(req *InitCalleeServiceRequest) error
 	// SyncGetConfigFile 同步获取配置文件
 	SyncGetConfigFile(namespace, fileGroup, fileName string) (ConfigFile, error)
+	// SyncGetConfigKVFile 同步获取结构化配置文件，format为空时根据文件名后缀判断格式
+	SyncGetConfigKVFile(namespace, fileGroup, fileName string, format ConfigFileFormat) (ConfigKVFile, error)
+	// SyncGetConfigGroup 同步获取配置分组
+	SyncGetConfigGroup(namespace, group string) (ConfigFileGroup, error)
+	// SyncCreateConfigFile 同步创建配置文件
+	SyncCreateConfigFile(req *ConfigFileRequest) error
+	// SyncUpdateConfigFile 同步更新配置文件
+	SyncUpdateConfigFile(req *ConfigFileRequest) error
+	// SyncPublishConfigFile 同步发布配置文件
+	SyncPublishConfigFile(metadata ConfigFileMetadata) error
+	// SyncDeleteConfigFile 同步删除配置文件
+	SyncDeleteConfigFile(metadata ConfigFileMetadata) error
 	// ProcessRouters 执行路由链过滤，返回经过路由后的实例列表
 	ProcessRouters(req *ProcessRoutersRequest) (*InstancesResponse, error)
 	// ProcessLoadBalance 执行负载均衡策略，返回负载均衡后的实例
@@ -78,8 +78,16 @@ const (
 	ErrCodeMeshConfigNotFound ErrCode = BaseIndexErrCode + 20
 	// ErrCodeConsumerInitCalleeError 初始化服务运行中需要的被调服务失败
 	ErrCodeConsumerInitCalleeError ErrCode = BaseIndexErrCode + 21
+	// ErrCodeAPICanceled 调用方上下文已取消
+	ErrCodeAPICanceled ErrCode = BaseIndexErrCode + 22
+	// ErrCodeAPIUnauthorized 访问server鉴权失败，未携带合法的访问凭证或者没有资源的访问权限
+	ErrCodeAPIUnauthorized ErrCode = BaseIndexErrCode + 23
+	// ErrCodeConfigFileConflict 配置文件写操作冲突，配置文件已存在或者已被其他请求修改
+	ErrCodeConfigFileConflict ErrCode = BaseIndexErrCode + 24
+	// ErrCodeConfigFileNotFound 配置文件写操作的目标配置文件不存在
+	ErrCodeConfigFileNotFound ErrCode = BaseIndexErrCode + 25
 	// ErrCodeCount 接口错误码数量，每添加了一个错误码，将这个数值加1
-	ErrCodeCount = 23
+	ErrCodeCount = 27
 )
 
 const (
@@ -246,6 +254,10 @@ var errCodeString = map[ErrCode]string{
 	ErrCodeDstMetaMismatch:         "ErrCodeDstMetaMismatch",
 	ErrCodeMeshConfigNotFound:      "ErrCodeMeshConfigNotFound",
 	ErrCodeConsumerInitCalleeError: "ErrCodeConsumerInitCalleeError",
+	ErrCodeAPICanceled:             "ErrCodeAPICanceled",
+	ErrCodeAPIUnauthorized:         "ErrCodeAPIUnauthorized",
+	ErrCodeConfigFileConflict:      "ErrCodeConfigFileConflict",
+	ErrCodeConfigFileNotFound:      "ErrCodeConfigFileNotFound",
 }
 
 var errCodeArray = []ErrCode{ErrCodeSuccess, ErrCodeUnknown, ErrCodeAPIInvalidArgument,
@@ -254,6 +266,7 @@ var errCodeArray = []ErrCode{ErrCodeSuccess, ErrCodeUnknown, ErrCodeAPIInvalidAr
 	ErrCodeAPIInstanceNotFound, ErrCodeInvalidRule, ErrCodeRouteRuleNotMatch, ErrCodeInvalidResponse,
 	ErrCodeInternalError, ErrCodeServiceNotFound, ErrCodeServerException, ErrCodeLocationNotFound,
 	ErrCodeLocationMismatch, ErrCodeDstMetaMismatch, ErrCodeMeshConfigNotFound, ErrCodeConsumerInitCalleeError,
+	ErrCodeAPICanceled, ErrCodeAPIUnauthorized, ErrCodeConfigFileConflict, ErrCodeConfigFileNotFound,
 }
 
 // ErrCodeFromIndex 根据错误码索引返回错误码
@@ -305,6 +318,10 @@ var errCodeTypeMap = map[ErrCode]ErrCodeType{
 	ErrCodeDstMetaMismatch:         UserError,
 	ErrCodeMeshConfigNotFound:      UserError,
 	ErrCodeConsumerInitCalleeError: UserError,
+	ErrCodeAPICanceled:             UserError,
+	ErrCodeAPIUnauthorized:         UserError,
+	ErrCodeConfigFileConflict:      UserError,
+	ErrCodeConfigFileNotFound:      UserError,
 }
 
 // GetErrCodeType 获取错误码类型
@@ -20,6 +20,7 @@ package local
 import (
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/polarismesh/polaris-go/pkg/metric"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -34,15 +35,24 @@ type InstanceLocalValue interface {
 	GetCircuitBreakerStatus() model.CircuitBreakerStatus
 	// GetActiveDetectStatus 实例的健康检查状态
 	GetActiveDetectStatus() model.ActiveDetectStatus
+	// GetDynamicWeight 实例的动态权重，未经过动态调整时返回nil
+	GetDynamicWeight() *model.InstanceWeight
+	// GetMethodCircuitBreakerStatus 实例在某个接口上的熔断状态
+	GetMethodCircuitBreakerStatus(method string) model.CircuitBreakerStatus
 	GetExtendedData(pluginIndex int32) interface{}
 	SetExtendedData(pluginIndex int32, data interface{})
+	// GetFirstSeenTime SDK首次发现该实例的时间，服务首次加载时已经存在的实例返回零值
+	GetFirstSeenTime() time.Time
+	// GetWarmupWeightPercent 实例预热期间的权重百分比，不在预热期内时返回100
+	GetWarmupWeightPercent() int
 }
 
 // NewInstanceLocalValue 创建默认的实例本地信息
 func NewInstanceLocalValue() InstanceLocalValue {
 	return &DefaultInstanceLocalValue{
-		sliceWindows: make(map[int32][]*metric.SliceWindow, 0),
-		extendedData: &sync.Map{},
+		sliceWindows:   make(map[int32][]*metric.SliceWindow, 0),
+		extendedData:   &sync.Map{},
+		methodCBStatus: &sync.Map{},
 	}
 }
 
@@ -54,6 +64,13 @@ type DefaultInstanceLocalValue struct {
 	extendedData *sync.Map
 	cbStatus     atomic.Value
 	odStatus     atomic.Value
+	weight       atomic.Value
+	// 接口级熔断状态，key为接口名
+	methodCBStatus *sync.Map
+	// SDK首次发现该实例的时间，单位纳秒
+	firstSeenTime int64
+	// 预热权重百分比，0表示未进行过预热计算
+	warmupPercent int32
 }
 
 // GetSliceWindows 获取滑窗
@@ -85,11 +102,49 @@ func (lv *DefaultInstanceLocalValue) SetCircuitBreakerStatus(st model.CircuitBre
 	lv.cbStatus.Store(st)
 }
 
+// SetMethodCircuitBreakerStatus 设置接口级熔断信息
+func (lv *DefaultInstanceLocalValue) SetMethodCircuitBreakerStatus(method string, st model.CircuitBreakerStatus) {
+	lv.methodCBStatus.Store(method, st)
+}
+
 // SetActiveDetectStatus 设置健康检测信息
 func (lv *DefaultInstanceLocalValue) SetActiveDetectStatus(st model.ActiveDetectStatus) {
 	lv.odStatus.Store(st)
 }
 
+// SetFirstSeenTime 设置SDK首次发现该实例的时间
+func (lv *DefaultInstanceLocalValue) SetFirstSeenTime(seenTime time.Time) {
+	atomic.StoreInt64(&lv.firstSeenTime, seenTime.UnixNano())
+}
+
+// GetFirstSeenTime 返回SDK首次发现该实例的时间
+func (lv *DefaultInstanceLocalValue) GetFirstSeenTime() time.Time {
+	value := atomic.LoadInt64(&lv.firstSeenTime)
+	if value == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, value)
+}
+
+// SetWarmupWeightPercent 设置预热权重百分比
+func (lv *DefaultInstanceLocalValue) SetWarmupWeightPercent(percent int) {
+	atomic.StoreInt32(&lv.warmupPercent, int32(percent))
+}
+
+// GetWarmupWeightPercent 返回预热权重百分比
+func (lv *DefaultInstanceLocalValue) GetWarmupWeightPercent() int {
+	value := atomic.LoadInt32(&lv.warmupPercent)
+	if value <= 0 || value > 100 {
+		return 100
+	}
+	return int(value)
+}
+
+// SetDynamicWeight 设置动态权重
+func (lv *DefaultInstanceLocalValue) SetDynamicWeight(weight *model.InstanceWeight) {
+	lv.weight.Store(weight)
+}
+
 // GetCircuitBreakerStatus 返回熔断信息
 func (lv *DefaultInstanceLocalValue) GetCircuitBreakerStatus() model.CircuitBreakerStatus {
 	res := lv.cbStatus.Load()
@@ -99,6 +154,15 @@ func (lv *DefaultInstanceLocalValue) GetCircuitBreakerStatus() model.CircuitBrea
 	return res.(model.CircuitBreakerStatus)
 }
 
+// GetMethodCircuitBreakerStatus 返回接口级熔断信息
+func (lv *DefaultInstanceLocalValue) GetMethodCircuitBreakerStatus(method string) model.CircuitBreakerStatus {
+	res, ok := lv.methodCBStatus.Load(method)
+	if !ok {
+		return nil
+	}
+	return res.(model.CircuitBreakerStatus)
+}
+
 // GetActiveDetectStatus 返回健康检测信息
 func (lv *DefaultInstanceLocalValue) GetActiveDetectStatus() model.ActiveDetectStatus {
 	res := lv.odStatus.Load()
@@ -108,6 +172,15 @@ func (lv *DefaultInstanceLocalValue) GetActiveDetectStatus() model.ActiveDetectS
 	return res.(model.ActiveDetectStatus)
 }
 
+// GetDynamicWeight 返回动态权重
+func (lv *DefaultInstanceLocalValue) GetDynamicWeight() *model.InstanceWeight {
+	res := lv.weight.Load()
+	if nil == res {
+		return nil
+	}
+	return res.(*model.InstanceWeight)
+}
+
 // ServiceLocalValue 服务localvalue接口
 type ServiceLocalValue interface {
 	// 通过插件ID获取服务级缓存数据
@@ -30,6 +30,7 @@ import (
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
 
+	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
@@ -57,6 +58,56 @@ func isMatchAllValueString(value string) bool {
 	return len(value) == 0 || value == MatchAll
 }
 
+// MatchStringValue 判断值是否满足匹配规则
+func MatchStringValue(matchString *apimodel.MatchString, value string, ruleCache model.RuleCache) bool {
+	if IsMatchAllValue(matchString) {
+		return true
+	}
+	matchType := matchString.GetType()
+	matchValue := matchString.GetValue().GetValue()
+
+	switch matchType {
+	case apimodel.MatchString_EXACT:
+		return value == matchValue
+	case apimodel.MatchString_REGEX:
+		regexObj, err := ruleCache.GetRegexMatcher(matchValue)
+		if nil != err {
+			log.GetBaseLogger().Errorf("regex compile error. ruleMetaValueStr: %s, value: %s, errors: %s",
+				matchValue, value, err)
+			return false
+		}
+		m, err := regexObj.FindStringMatch(value)
+		if err != nil {
+			log.GetBaseLogger().Errorf("regex match error. ruleMetaValueStr: %s, value: %s, errors: %s",
+				matchValue, value, err)
+			return false
+		}
+		if m == nil || m.String() == "" {
+			return false
+		}
+		return true
+	case apimodel.MatchString_NOT_EQUALS:
+		return value != matchValue
+	case apimodel.MatchString_IN:
+		tokens := strings.Split(matchValue, ",")
+		for _, token := range tokens {
+			if token == value {
+				return true
+			}
+		}
+		return false
+	case apimodel.MatchString_NOT_IN:
+		tokens := strings.Split(matchValue, ",")
+		for _, token := range tokens {
+			if token == value {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 // ParseRuleValue 解析出具体的规则值
 func (r *RateLimitingAssistant) ParseRuleValue(resp *apiservice.DiscoverResponse) (proto.Message, string) {
 	var revision string
@@ -0,0 +1,215 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/modern-go/reflect2"
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// DefaultCircuitBreakSleepWindow 熔断规则默认的半开等待时间，单位秒
+	DefaultCircuitBreakSleepWindow = 30
+	// DefaultCircuitBreakConsecutiveSuccess 熔断规则默认的半开恢复所需连续成功数
+	DefaultCircuitBreakConsecutiveSuccess = 3
+	// DefaultCircuitBreakInterval 熔断规则默认的错误率统计周期，单位秒
+	DefaultCircuitBreakInterval = 60
+	// DefaultCircuitBreakMinimumRequest 熔断规则默认的错误率统计最小请求数
+	DefaultCircuitBreakMinimumRequest = 10
+	// DefaultFaultDetectInterval 探测规则默认的探测周期，单位秒
+	DefaultFaultDetectInterval = 10
+	// DefaultFaultDetectTimeout 探测规则默认的探测超时时间，单位秒
+	DefaultFaultDetectTimeout = 1
+)
+
+// CircuitBreakAssistant 熔断规则解析助手
+type CircuitBreakAssistant struct {
+}
+
+// ParseRuleValue 解析出具体的规则值
+func (c *CircuitBreakAssistant) ParseRuleValue(resp *apiservice.DiscoverResponse) (proto.Message, string) {
+	var revision string
+	cbValue := resp.CircuitBreaker
+	if nil != cbValue {
+		revision = cbValue.GetRevision().GetValue()
+	}
+	return cbValue, revision
+}
+
+// SetDefault 设置默认值，并将规则按照匹配的精确程度排序，精确匹配主调服务的规则优先
+func (c *CircuitBreakAssistant) SetDefault(message proto.Message) {
+	if reflect2.IsNil(message) {
+		return
+	}
+	cbValue := message.(*apifault.CircuitBreaker)
+	rules := cbValue.GetRules()
+	if len(rules) == 0 {
+		return
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return getCircuitBreakRuleLevel(rules[i]) > getCircuitBreakRuleLevel(rules[j])
+	})
+	for _, rule := range rules {
+		if nil == rule.GetRecoverCondition() {
+			rule.RecoverCondition = &apifault.RecoverCondition{}
+		}
+		if rule.GetRecoverCondition().GetSleepWindow() == 0 {
+			rule.GetRecoverCondition().SleepWindow = DefaultCircuitBreakSleepWindow
+		}
+		if rule.GetRecoverCondition().GetConsecutiveSuccess() == 0 {
+			rule.GetRecoverCondition().ConsecutiveSuccess = DefaultCircuitBreakConsecutiveSuccess
+		}
+		for _, trigger := range rule.GetTriggerCondition() {
+			if trigger.GetTriggerType() != apifault.TriggerCondition_ERROR_RATE {
+				continue
+			}
+			if trigger.GetInterval() == 0 {
+				trigger.Interval = DefaultCircuitBreakInterval
+			}
+			if trigger.GetMinimumRequest() == 0 {
+				trigger.MinimumRequest = DefaultCircuitBreakMinimumRequest
+			}
+		}
+	}
+}
+
+// getCircuitBreakRuleLevel 计算规则匹配的精确程度，数值越大越精确
+func getCircuitBreakRuleLevel(rule *apifault.CircuitBreakerRule) int {
+	var level int
+	source := rule.GetRuleMatcher().GetSource()
+	if !isMatchAllValueString(source.GetNamespace()) {
+		level++
+	}
+	if !isMatchAllValueString(source.GetService()) {
+		level++
+	}
+	return level
+}
+
+// Validate 规则校验
+func (c *CircuitBreakAssistant) Validate(message proto.Message, ruleCache model.RuleCache) error {
+	if reflect2.IsNil(message) {
+		return nil
+	}
+	cbValue := message.(*apifault.CircuitBreaker)
+	for _, rule := range cbValue.GetRules() {
+		if !rule.GetEnable() {
+			continue
+		}
+		if rule.GetLevel() == apifault.Level_UNKNOWN {
+			return fmt.Errorf("fail to validate circuitbreaker rule %s, level is unknown", rule.GetName())
+		}
+		if len(rule.GetTriggerCondition()) == 0 {
+			return fmt.Errorf("fail to validate circuitbreaker rule %s, triggerCondition is empty", rule.GetName())
+		}
+		for _, trigger := range rule.GetTriggerCondition() {
+			switch trigger.GetTriggerType() {
+			case apifault.TriggerCondition_ERROR_RATE:
+				if trigger.GetErrorPercent() == 0 || trigger.GetErrorPercent() > 100 {
+					return fmt.Errorf("fail to validate circuitbreaker rule %s, errorPercent %d must in (0, 100]",
+						rule.GetName(), trigger.GetErrorPercent())
+				}
+			case apifault.TriggerCondition_CONSECUTIVE_ERROR:
+				if trigger.GetErrorCount() == 0 {
+					return fmt.Errorf("fail to validate circuitbreaker rule %s, errorCount must be greater than 0",
+						rule.GetName())
+				}
+			default:
+				return fmt.Errorf("fail to validate circuitbreaker rule %s, unknown triggerType %v",
+					rule.GetName(), trigger.GetTriggerType())
+			}
+		}
+		if err := validateMatchString(rule.GetRuleMatcher().GetDestination().GetMethod(), ruleCache); err != nil {
+			return fmt.Errorf("fail to validate circuitbreaker rule %s, method invalid: %v", rule.GetName(), err)
+		}
+		for _, errCondition := range rule.GetErrorConditions() {
+			if err := validateMatchString(errCondition.GetCondition(), ruleCache); err != nil {
+				return fmt.Errorf("fail to validate circuitbreaker rule %s, errorCondition invalid: %v",
+					rule.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// FaultDetectAssistant 主动探测规则解析助手
+type FaultDetectAssistant struct {
+}
+
+// ParseRuleValue 解析出具体的规则值
+func (f *FaultDetectAssistant) ParseRuleValue(resp *apiservice.DiscoverResponse) (proto.Message, string) {
+	var revision string
+	fdValue := resp.FaultDetector
+	if nil != fdValue {
+		revision = fdValue.GetRevision()
+	}
+	return fdValue, revision
+}
+
+// SetDefault 设置默认值
+func (f *FaultDetectAssistant) SetDefault(message proto.Message) {
+	if reflect2.IsNil(message) {
+		return
+	}
+	fdValue := message.(*apifault.FaultDetector)
+	for _, rule := range fdValue.GetRules() {
+		if rule.GetInterval() == 0 {
+			rule.Interval = DefaultFaultDetectInterval
+		}
+		if rule.GetTimeout() == 0 {
+			rule.Timeout = DefaultFaultDetectTimeout
+		}
+	}
+}
+
+// Validate 规则校验
+func (f *FaultDetectAssistant) Validate(message proto.Message, ruleCache model.RuleCache) error {
+	if reflect2.IsNil(message) {
+		return nil
+	}
+	fdValue := message.(*apifault.FaultDetector)
+	for _, rule := range fdValue.GetRules() {
+		if rule.GetProtocol() == apifault.FaultDetectRule_UNKNOWN {
+			return fmt.Errorf("fail to validate faultDetect rule %s, protocol is unknown", rule.GetName())
+		}
+		if err := validateMatchString(rule.GetTargetService().GetMethod(), ruleCache); err != nil {
+			return fmt.Errorf("fail to validate faultDetect rule %s, method invalid: %v", rule.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// validateMatchString 校验匹配字符串，并预先编译正则表达式
+func validateMatchString(matchString *apimodel.MatchString, ruleCache model.RuleCache) error {
+	if nil == matchString || matchString.GetType() != apimodel.MatchString_REGEX {
+		return nil
+	}
+	if len(matchString.GetValue().GetValue()) == 0 {
+		return nil
+	}
+	_, err := ruleCache.GetRegexMatcher(matchString.GetValue().GetValue())
+	return err
+}
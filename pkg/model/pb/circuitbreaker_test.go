@@ -0,0 +1,67 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package pb
+
+import (
+	"testing"
+
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSourceRule(name string, namespace string, service string) *apifault.CircuitBreakerRule {
+	return &apifault.CircuitBreakerRule{
+		Name:   name,
+		Enable: true,
+		Level:  apifault.Level_SERVICE,
+		RuleMatcher: &apifault.RuleMatcher{
+			Source: &apifault.RuleMatcher_SourceService{Namespace: namespace, Service: service},
+		},
+		TriggerCondition: []*apifault.TriggerCondition{
+			{TriggerType: apifault.TriggerCondition_ERROR_RATE, ErrorPercent: 50},
+		},
+	}
+}
+
+// TestCircuitBreakAssistantSetDefault 测试熔断规则按照主调服务匹配的精确程度排序，并设置默认值
+func TestCircuitBreakAssistantSetDefault(t *testing.T) {
+	cbValue := &apifault.CircuitBreaker{
+		Rules: []*apifault.CircuitBreakerRule{
+			newSourceRule("all", "*", "*"),
+			newSourceRule("namespace", "test", "*"),
+			newSourceRule("service", "test", "caller"),
+			newSourceRule("all-2", "", ""),
+		},
+	}
+	assistant := &CircuitBreakAssistant{}
+	assistant.SetDefault(cbValue)
+
+	var names []string
+	for _, rule := range cbValue.GetRules() {
+		names = append(names, rule.GetName())
+	}
+	// 精确程度相同的规则保持原有顺序
+	assert.Equal(t, []string{"service", "namespace", "all", "all-2"}, names)
+
+	rule := cbValue.GetRules()[0]
+	assert.EqualValues(t, DefaultCircuitBreakSleepWindow, rule.GetRecoverCondition().GetSleepWindow())
+	assert.EqualValues(t, DefaultCircuitBreakConsecutiveSuccess, rule.GetRecoverCondition().GetConsecutiveSuccess())
+	assert.EqualValues(t, DefaultCircuitBreakInterval, rule.GetTriggerCondition()[0].GetInterval())
+	assert.EqualValues(t, DefaultCircuitBreakMinimumRequest, rule.GetTriggerCondition()[0].GetMinimumRequest())
+	assert.Nil(t, assistant.Validate(cbValue, nil))
+}
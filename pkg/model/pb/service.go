@@ -23,6 +23,7 @@ import (
 	"io"
 	"sort"
 	"sync/atomic"
+	"time"
 
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
@@ -359,11 +360,37 @@ func (i *InstanceInProto) GetCircuitBreakerStatus() model.CircuitBreakerStatus {
 	return i.localValue.GetCircuitBreakerStatus()
 }
 
+// GetMethodCircuitBreakerStatus instance method circuit breaker status.
+func (i *InstanceInProto) GetMethodCircuitBreakerStatus(method string) model.CircuitBreakerStatus {
+	return i.localValue.GetMethodCircuitBreakerStatus(method)
+}
+
 // GetActiveDetectStatus instance dynamic weight.
 func (i *InstanceInProto) GetActiveDetectStatus() model.ActiveDetectStatus {
 	return i.localValue.GetActiveDetectStatus()
 }
 
+// GetDynamicWeight instance dynamic weight.
+func (i *InstanceInProto) GetDynamicWeight() *model.InstanceWeight {
+	return i.localValue.GetDynamicWeight()
+}
+
+// GetFirstSeenTime 获取SDK首次发现该实例的时间.
+func (i *InstanceInProto) GetFirstSeenTime() time.Time {
+	if nil == i.localValue {
+		return time.Time{}
+	}
+	return i.localValue.GetFirstSeenTime()
+}
+
+// GetWarmupWeightPercent 获取实例预热期间的权重百分比.
+func (i *InstanceInProto) GetWarmupWeightPercent() int {
+	if nil == i.localValue {
+		return 100
+	}
+	return i.localValue.GetWarmupWeightPercent()
+}
+
 // IsHealthy instance health status.
 func (i *InstanceInProto) IsHealthy() bool {
 	return i.GetHealthy().GetValue()
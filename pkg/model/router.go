@@ -103,6 +103,11 @@ type ProcessLoadBalanceRequest struct {
 	HashKey []byte
 	// ReplicateCount indicate the sibling count in consist hash ring, optional.
 	ReplicateCount int
+	// ExcludeInstances indicate the instances to skip in load balance, optional.
+	// Usually they are the instances already tried by the previous attempts.
+	ExcludeInstances []Instance
+	// ExcludeHosts indicate the addresses to skip in load balance, in host or host:port format, optional.
+	ExcludeHosts []string
 	// response, internal data, not for user to set.
 	response InstancesResponse
 }
@@ -18,6 +18,7 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -212,6 +213,65 @@ type InstanceWeight struct {
 	DynamicWeight uint32
 }
 
+// String 节点权重ToString
+func (w *InstanceWeight) String() string {
+	return fmt.Sprintf("{instanceID: %s, dynamicWeight: %d}", w.InstanceID, w.DynamicWeight)
+}
+
+// DynamicWeightHolder 持有动态权重的实例
+type DynamicWeightHolder interface {
+	// GetDynamicWeight 获取实例的动态权重，未经过动态调整时返回nil
+	GetDynamicWeight() *InstanceWeight
+}
+
+// WarmupWeightHolder 持有预热权重的实例
+type WarmupWeightHolder interface {
+	// GetWarmupWeightPercent 获取实例预热期间的权重百分比，不在预热期内时返回100
+	GetWarmupWeightPercent() int
+}
+
+// GetDynamicWeight 获取实例的动态权重，未经过动态调整时返回静态权重，不包含预热的折算
+func GetDynamicWeight(instance Instance) int {
+	if holder, ok := instance.(DynamicWeightHolder); ok {
+		if dynamicWeight := holder.GetDynamicWeight(); nil != dynamicWeight {
+			return int(dynamicWeight.DynamicWeight)
+		}
+	}
+	return instance.GetWeight()
+}
+
+// GetEffectiveWeight 获取实例实际生效的权重，经过动态调整的实例以动态权重为准，否则为静态权重；
+// 处于预热期的实例再按照预热百分比折算，权重不为0的实例折算后至少为1
+func GetEffectiveWeight(instance Instance) int {
+	weight := GetDynamicWeight(instance)
+	if holder, ok := instance.(WarmupWeightHolder); ok && weight > 0 {
+		if percent := holder.GetWarmupWeightPercent(); percent < 100 {
+			weight = weight * percent / 100
+			if weight == 0 {
+				weight = 1
+			}
+		}
+	}
+	return weight
+}
+
+// MethodCircuitBreakerHolder 持有接口级熔断状态的实例
+type MethodCircuitBreakerHolder interface {
+	// GetMethodCircuitBreakerStatus 获取实例在某个接口上的熔断状态，该接口未发生过熔断时返回nil
+	GetMethodCircuitBreakerStatus(method string) CircuitBreakerStatus
+}
+
+// GetMethodCircuitBreakerStatus 获取实例在某个接口上的熔断状态
+func GetMethodCircuitBreakerStatus(instance Instance, method string) CircuitBreakerStatus {
+	if len(method) == 0 {
+		return nil
+	}
+	if holder, ok := instance.(MethodCircuitBreakerHolder); ok {
+		return holder.GetMethodCircuitBreakerStatus(method)
+	}
+	return nil
+}
+
 // FailOverHandler 元数据路由兜底策略
 type FailOverHandler int
 
@@ -269,6 +329,12 @@ type GetOneInstanceRequest struct {
 	LbPolicy string
 	// 金丝雀
 	Canary string
+	// 可选，调用的接口方法，用于服务及接口级的熔断判断
+	Method string
+	// 可选，负载均衡时需要排除的实例，一般为重试前已经调用失败的实例
+	ExcludeInstances []Instance
+	// 可选，负载均衡时需要排除的地址，格式为host或者host:port
+	ExcludeHosts []string
 }
 
 // SetTimeout 设置超时时间
@@ -824,7 +890,11 @@ type RateLimitGauge struct {
 	Result    QuotaResultCode
 }
 
+// CircuitBreakerOperator 运维人员手动设置实例熔断状态时，熔断状态中记录的熔断器名
+const CircuitBreakerOperator = "operator"
+
 // CircuitBreakGauge Circuit Break Gauge
+// 熔断状态变更的原因可以通过CBStatus.GetCircuitBreaker()获取，运维人员手动设置时为CircuitBreakerOperator
 type CircuitBreakGauge struct {
 	EmptyInstanceGauge
 	ChangeInstance Instance
@@ -850,6 +920,38 @@ func (cbg *CircuitBreakGauge) Validate() error {
 	return NewSDKError(ErrCodeAPIInvalidArgument, nil, "empty change instance")
 }
 
+// ForceCircuitBreakerRequest 手动设置实例熔断状态的请求
+type ForceCircuitBreakerRequest struct {
+	// 必选，需要设置熔断状态的实例，可以通过GetAllInstances获取
+	Instance Instance
+	// 必选，固定的熔断状态，Open代表将实例摘除，Close代表将实例恢复；
+	// Close同样固定状态，有效期内即使调用持续失败也不会被熔断器熔断
+	Status Status
+	// 必选，状态的固定时长，到期后实例恢复为关闭状态，重新由熔断器进行判断
+	TTL time.Duration
+}
+
+// Validate 校验手动设置熔断状态的请求
+func (r *ForceCircuitBreakerRequest) Validate() error {
+	if nil == r {
+		return NewSDKError(ErrCodeAPIInvalidArgument, nil, "ForceCircuitBreakerRequest can not be nil")
+	}
+	var errs error
+	if nil == r.Instance || reflect2.IsNil(r.Instance) {
+		errs = multierror.Append(errs, fmt.Errorf("ForceCircuitBreakerRequest: instance can not be empty"))
+	}
+	if r.Status != Open && r.Status != Close {
+		errs = multierror.Append(errs, fmt.Errorf("ForceCircuitBreakerRequest: status should be Open or Close"))
+	}
+	if r.TTL <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("ForceCircuitBreakerRequest: ttl should be greater than 0"))
+	}
+	if errs != nil {
+		return NewSDKError(ErrCodeAPIInvalidArgument, errs, "fail to validate ForceCircuitBreakerRequest: ")
+	}
+	return nil
+}
+
 // APICallKey API调用的唯一标识
 type APICallKey struct {
 	// 调用的API接口名字
@@ -1058,6 +1160,8 @@ type InstanceHeartbeatRequest struct {
 	Timeout *time.Duration
 	// 可选，重试次数，默认直接获取全局的超时配置
 	RetryCount *int
+	// 调用方上下文，由流程引擎设置到发往服务端的请求副本上，默认context.Background()
+	ctx context.Context
 }
 
 // String 打印消息内容
@@ -1086,6 +1190,19 @@ func (g *InstanceHeartbeatRequest) GetRetryCountPtr() *int {
 	return g.RetryCount
 }
 
+// SetContext 设置调用方上下文
+func (g *InstanceHeartbeatRequest) SetContext(ctx context.Context) {
+	g.ctx = ctx
+}
+
+// GetContext 获取调用方上下文
+func (g *InstanceHeartbeatRequest) GetContext() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
+}
+
 // Validate 校验InstanceDeRegisterRequest
 func (g *InstanceHeartbeatRequest) Validate() error {
 	if nil == g {
@@ -1135,6 +1252,8 @@ type InstanceDeRegisterRequest struct {
 	Timeout *time.Duration
 	// 可选，重试次数，默认直接获取全局的超时配置
 	RetryCount *int
+	// 调用方上下文，由流程引擎设置到发往服务端的请求副本上，默认context.Background()
+	ctx context.Context
 }
 
 // String 打印消息内容
@@ -1163,6 +1282,19 @@ func (g *InstanceDeRegisterRequest) GetRetryCountPtr() *int {
 	return g.RetryCount
 }
 
+// SetContext 设置调用方上下文
+func (g *InstanceDeRegisterRequest) SetContext(ctx context.Context) {
+	g.ctx = ctx
+}
+
+// GetContext 获取调用方上下文
+func (g *InstanceDeRegisterRequest) GetContext() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
+}
+
 // Validate 校验InstanceDeRegisterRequest
 func (g *InstanceDeRegisterRequest) Validate() error {
 	if nil == g {
@@ -1251,6 +1383,8 @@ type InstanceRegisterRequest struct {
 	RetryCount *int
 	// 可选，指定实例id
 	InstanceId string
+	// 调用方上下文，由流程引擎设置到发往服务端的请求副本上，默认context.Background()
+	ctx context.Context
 }
 
 // String 打印消息内容
@@ -1298,6 +1432,19 @@ func (g *InstanceRegisterRequest) GetRetryCountPtr() *int {
 	return g.RetryCount
 }
 
+// SetContext 设置调用方上下文
+func (g *InstanceRegisterRequest) SetContext(ctx context.Context) {
+	g.ctx = ctx
+}
+
+// GetContext 获取调用方上下文
+func (g *InstanceRegisterRequest) GetContext() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
+}
+
 // GetLocation 获取实例的地址信息
 func (g *InstanceRegisterRequest) GetLocation() *Location {
 	return g.Location
@@ -115,7 +115,7 @@ func (s *ServerAddressList) getServerAddress(hashKey []byte) (string, model.Inst
 		// 获取系统服务，不重试，超时时间设为300ms
 		req.SetRetryCount(0)
 		req.SetTimeout(getAddressTimeout)
-		resp, err := engine.SyncGetOneInstance(req)
+		resp, err := engine.SyncGetOneInstance(context.Background(), req)
 		if err != nil {
 			return "", nil, err
 		}
@@ -0,0 +1,196 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+)
+
+// NewTransportCredentials 根据TLS配置创建与server通信的传输层凭证，未启用TLS时使用明文传输
+func NewTransportCredentials(cfg config.TLSConfig) credentials.TransportCredentials {
+	if nil == cfg || !cfg.IsEnable() {
+		return insecure.NewCredentials()
+	}
+	return newReloadableCredentials(cfg, cfg.GetServerName())
+}
+
+// newReloadableCredentials 创建支持证书热加载的传输层凭证
+func newReloadableCredentials(cfg config.TLSConfig, serverName string) *reloadableCredentials {
+	return &reloadableCredentials{
+		cfg:        cfg,
+		serverName: serverName,
+		mutex:      &sync.Mutex{},
+	}
+}
+
+// reloadableCredentials 支持证书热加载的TLS传输层凭证
+// 每次握手前按照检查周期判断证书文件是否发生变更，变更后重新加载，轮转后的证书对新建连接生效
+type reloadableCredentials struct {
+	cfg        config.TLSConfig
+	serverName string
+	mutex      *sync.Mutex
+	// 当前生效的TLS配置
+	tlsConfig *tls.Config
+	// 加载时各证书文件的修改时间
+	modTimes map[string]time.Time
+	// 上一次检查证书文件的时间
+	lastCheckTime time.Time
+}
+
+// ClientHandshake 使用当前生效的证书进行客户端握手
+func (r *reloadableCredentials) ClientHandshake(
+	ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	tlsConfig, err := r.getTLSConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	return credentials.NewTLS(tlsConfig).ClientHandshake(ctx, authority, rawConn)
+}
+
+// ServerHandshake SDK只作为客户端，不支持服务端握手
+func (r *reloadableCredentials) ServerHandshake(net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("server handshake is not supported")
+}
+
+// Info 获取协议信息
+func (r *reloadableCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{
+		SecurityProtocol: "tls",
+		SecurityVersion:  "1.2",
+		ServerName:       r.serverName,
+	}
+}
+
+// Clone 复制凭证
+func (r *reloadableCredentials) Clone() credentials.TransportCredentials {
+	return newReloadableCredentials(r.cfg, r.serverName)
+}
+
+// OverrideServerName 覆盖校验证书时使用的域名
+func (r *reloadableCredentials) OverrideServerName(serverName string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.serverName = serverName
+	r.tlsConfig = nil
+	return nil
+}
+
+// getTLSConfig 获取当前生效的TLS配置，到达检查周期并且证书文件发生变更时进行重新加载
+// 重新加载失败时继续使用原有的证书，避免证书轮转过程中的中间状态导致连接不可用
+func (r *reloadableCredentials) getTLSConfig() (*tls.Config, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := time.Now()
+	if nil != r.tlsConfig && now.Sub(r.lastCheckTime) < r.cfg.GetReloadInterval() {
+		return r.tlsConfig, nil
+	}
+	r.lastCheckTime = now
+	modTimes, err := r.statFiles()
+	if err == nil && nil != r.tlsConfig && !isModTimesChanged(r.modTimes, modTimes) {
+		return r.tlsConfig, nil
+	}
+	var tlsConfig *tls.Config
+	if err == nil {
+		tlsConfig, err = loadTLSConfig(r.cfg, r.serverName)
+	}
+	if err != nil {
+		if nil != r.tlsConfig {
+			log.GetNetworkLogger().Errorf("fail to reload tls certificates, use the previous ones, err: %v", err)
+			return r.tlsConfig, nil
+		}
+		return nil, err
+	}
+	if nil != r.tlsConfig {
+		log.GetNetworkLogger().Infof("tls certificates reloaded, modTimes %v", modTimes)
+	}
+	r.tlsConfig = tlsConfig
+	r.modTimes = modTimes
+	return r.tlsConfig, nil
+}
+
+// statFiles 获取各证书文件的修改时间
+func (r *reloadableCredentials) statFiles() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time, 3)
+	for _, file := range []string{r.cfg.GetCaFile(), r.cfg.GetCertFile(), r.cfg.GetKeyFile()} {
+		if len(file) == 0 {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[file] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// isModTimesChanged 判断证书文件是否发生了变更
+func isModTimesChanged(prev map[string]time.Time, next map[string]time.Time) bool {
+	if len(prev) != len(next) {
+		return true
+	}
+	for file, modTime := range next {
+		if prevTime, ok := prev[file]; !ok || !prevTime.Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTLSConfig 从证书文件加载TLS配置
+func loadTLSConfig(cfg config.TLSConfig, serverName string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         serverName,
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.IsInsecureSkipVerify(),
+	}
+	if caFile := cfg.GetCaFile(); len(caFile) > 0 {
+		caPem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read ca file %s: %v", caFile, err)
+		}
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("fail to parse ca file %s", caFile)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+	if len(cfg.GetCertFile()) > 0 {
+		cert, err := tls.LoadX509KeyPair(cfg.GetCertFile(), cfg.GetKeyFile())
+		if err != nil {
+			return nil, fmt.Errorf("fail to load client certificate %s: %v", cfg.GetCertFile(), err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,280 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log/logtest"
+)
+
+func init() {
+	logtest.DiscardLoggers()
+}
+
+// testCert 测试使用的证书以及私钥
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPem []byte
+	keyPem  []byte
+}
+
+// newTestCert 生成证书，parent为nil时生成自签名的CA证书
+func newTestCert(t *testing.T, commonName string, serial int64, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signerCert, signerKey := template, key
+	if nil == parent {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPem:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	}
+}
+
+func (c *testCert) tlsCertificate(t *testing.T) tls.Certificate {
+	cert, err := tls.X509KeyPair(c.certPem, c.keyPem)
+	assert.Nil(t, err)
+	return cert
+}
+
+// writeFile 写入文件并设置修改时间，保证热加载能够感知到变更
+func writeFile(t *testing.T, path string, content []byte, modTime time.Time) {
+	assert.Nil(t, ioutil.WriteFile(path, content, 0600))
+	assert.Nil(t, os.Chtimes(path, modTime, modTime))
+}
+
+// handshakeResult 服务端握手结果
+type handshakeResult struct {
+	err        error
+	clientCert *x509.Certificate
+}
+
+// startTLSServer 启动TLS服务端，clientCA不为nil时要求客户端证书
+func startTLSServer(t *testing.T, serverCert *testCert, clientCA *testCert) (string, chan handshakeResult) {
+	serverConfig := &tls.Config{Certificates: []tls.Certificate{serverCert.tlsCertificate(t)}}
+	if nil != clientCA {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCA.cert)
+		serverConfig.ClientCAs = pool
+		serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	results := make(chan handshakeResult, 16)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := tls.Server(conn, serverConfig)
+			_ = tlsConn.SetDeadline(time.Now().Add(5 * time.Second))
+			result := handshakeResult{err: tlsConn.Handshake()}
+			if result.err == nil {
+				if peerCerts := tlsConn.ConnectionState().PeerCertificates; len(peerCerts) > 0 {
+					result.clientCert = peerCerts[0]
+				}
+			}
+			results <- result
+			_ = tlsConn.Close()
+		}
+	}()
+	return listener.Addr().String(), results
+}
+
+// handshake 使用凭证与服务端握手，返回客户端以及服务端的握手结果
+func handshake(t *testing.T, creds *reloadableCredentials, address string,
+	results chan handshakeResult) (handshakeResult, error) {
+	rawConn, err := net.Dial("tcp", address)
+	assert.Nil(t, err)
+	defer rawConn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, clientErr := creds.ClientHandshake(ctx, "localhost", rawConn)
+	if clientErr == nil {
+		// TLS1.3下服务端在客户端握手完成后才校验客户端证书，读取一次以获取服务端的结果
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, _ = conn.Read(make([]byte, 1))
+	} else {
+		rawConn.Close()
+	}
+	select {
+	case result := <-results:
+		return result, clientErr
+	case <-time.After(5 * time.Second):
+		t.Fatal("server handshake is not finished")
+		return handshakeResult{}, clientErr
+	}
+}
+
+// newTestTLSConfig 创建启用TLS的配置，检查周期为0代表每次握手都检查证书文件
+func newTestTLSConfig() *config.TLSConfigImpl {
+	cfg := &config.TLSConfigImpl{}
+	cfg.SetDefault()
+	cfg.SetEnable(true)
+	cfg.SetReloadInterval(0)
+	return cfg
+}
+
+type tlsFixture struct {
+	dir        string
+	ca         *testCert
+	serverCert *testCert
+	caFile     string
+}
+
+func newTLSFixture(t *testing.T) *tlsFixture {
+	dir, err := ioutil.TempDir("", "polaris-tls")
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	ca := newTestCert(t, "test-ca", 1, nil)
+	f := &tlsFixture{
+		dir:        dir,
+		ca:         ca,
+		serverCert: newTestCert(t, "localhost", 2, ca),
+		caFile:     filepath.Join(dir, "ca.pem"),
+	}
+	writeFile(t, f.caFile, ca.certPem, time.Now())
+	return f
+}
+
+// TestServerTLS 测试单向认证，客户端通过CA证书校验server证书
+func TestServerTLS(t *testing.T) {
+	f := newTLSFixture(t)
+	address, results := startTLSServer(t, f.serverCert, nil)
+
+	cfg := newTestTLSConfig()
+	cfg.SetCaFile(f.caFile)
+	result, clientErr := handshake(t, newReloadableCredentials(cfg, "localhost"), address, results)
+	assert.Nil(t, clientErr)
+	assert.Nil(t, result.err)
+
+	// 没有配置CA证书时使用系统根证书，无法校验自签名的server证书
+	cfg = newTestTLSConfig()
+	_, clientErr = handshake(t, newReloadableCredentials(cfg, "localhost"), address, results)
+	assert.NotNil(t, clientErr)
+
+	// 跳过server证书校验
+	cfg.SetInsecureSkipVerify(true)
+	result, clientErr = handshake(t, newReloadableCredentials(cfg, "localhost"), address, results)
+	assert.Nil(t, clientErr)
+	assert.Nil(t, result.err)
+}
+
+// TestMutualTLS 测试双向认证，服务端要求客户端证书
+func TestMutualTLS(t *testing.T) {
+	f := newTLSFixture(t)
+	address, results := startTLSServer(t, f.serverCert, f.ca)
+
+	cfg := newTestTLSConfig()
+	cfg.SetCaFile(f.caFile)
+	result, _ := handshake(t, newReloadableCredentials(cfg, "localhost"), address, results)
+	assert.NotNil(t, result.err)
+
+	clientCert := newTestCert(t, "client-1", 3, f.ca)
+	cfg.SetCertFile(filepath.Join(f.dir, "client.pem"))
+	cfg.SetKeyFile(filepath.Join(f.dir, "client-key.pem"))
+	writeFile(t, cfg.GetCertFile(), clientCert.certPem, time.Now())
+	writeFile(t, cfg.GetKeyFile(), clientCert.keyPem, time.Now())
+	result, clientErr := handshake(t, newReloadableCredentials(cfg, "localhost"), address, results)
+	assert.Nil(t, clientErr)
+	assert.Nil(t, result.err)
+	assert.Equal(t, "client-1", result.clientCert.Subject.CommonName)
+}
+
+// TestCertificateReload 测试证书文件修改时间变更后重新加载客户端证书
+func TestCertificateReload(t *testing.T) {
+	f := newTLSFixture(t)
+	address, results := startTLSServer(t, f.serverCert, f.ca)
+
+	cfg := newTestTLSConfig()
+	cfg.SetCaFile(f.caFile)
+	cfg.SetCertFile(filepath.Join(f.dir, "client.pem"))
+	cfg.SetKeyFile(filepath.Join(f.dir, "client-key.pem"))
+	modTime := time.Now().Add(-time.Minute)
+	clientCert := newTestCert(t, "client-1", 3, f.ca)
+	writeFile(t, cfg.GetCertFile(), clientCert.certPem, modTime)
+	writeFile(t, cfg.GetKeyFile(), clientCert.keyPem, modTime)
+	creds := newReloadableCredentials(cfg, "localhost")
+	result, _ := handshake(t, creds, address, results)
+	assert.Nil(t, result.err)
+	assert.Equal(t, "client-1", result.clientCert.Subject.CommonName)
+
+	// 证书轮转
+	rotatedCert := newTestCert(t, "client-2", 4, f.ca)
+	modTime = modTime.Add(30 * time.Second)
+	writeFile(t, cfg.GetCertFile(), rotatedCert.certPem, modTime)
+	writeFile(t, cfg.GetKeyFile(), rotatedCert.keyPem, modTime)
+	result, _ = handshake(t, creds, address, results)
+	assert.Nil(t, result.err)
+	assert.Equal(t, "client-2", result.clientCert.Subject.CommonName)
+
+	// 轮转过程中文件不完整时继续使用原有的证书
+	modTime = modTime.Add(30 * time.Second)
+	writeFile(t, cfg.GetKeyFile(), []byte("broken"), modTime)
+	result, _ = handshake(t, creds, address, results)
+	assert.Nil(t, result.err)
+	assert.Equal(t, "client-2", result.clientCert.Subject.CommonName)
+}
@@ -0,0 +1,119 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc/credentials"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+const (
+	// HeaderKeyToken 携带鉴权凭证的grpc头
+	HeaderKeyToken = "x-polaris-token"
+	// DefaultTokenProvider 默认的鉴权凭证提供者，从global.serverConnector.token读取凭证
+	DefaultTokenProvider = "config"
+)
+
+var (
+	tokenProviderMutex    = &sync.RWMutex{}
+	tokenProviderCreators = map[string]TokenProviderCreator{
+		DefaultTokenProvider: NewConfigTokenProvider,
+	}
+)
+
+// TokenProvider 鉴权凭证提供者，可通过实现该接口对接外部的凭证管理系统
+type TokenProvider interface {
+	// GetToken 获取当前的鉴权凭证，返回空字符串时不携带凭证
+	GetToken(ctx context.Context) (string, error)
+}
+
+// TokenProviderCreator 鉴权凭证提供者的创建函数
+type TokenProviderCreator func(cfg config.ServerConnectorConfig) TokenProvider
+
+// RegisterTokenProvider 注册鉴权凭证提供者，需要在创建SDKContext之前注册，
+// 通过global.serverConnector.tokenProvider指定使用的提供者
+func RegisterTokenProvider(name string, creator TokenProviderCreator) {
+	tokenProviderMutex.Lock()
+	defer tokenProviderMutex.Unlock()
+	if _, ok := tokenProviderCreators[name]; ok {
+		panic(fmt.Sprintf("token provider %s has already existed", name))
+	}
+	tokenProviderCreators[name] = creator
+}
+
+// NewTokenProvider 根据global.serverConnector.tokenProvider创建鉴权凭证提供者
+func NewTokenProvider(cfg config.ServerConnectorConfig) (TokenProvider, error) {
+	name := cfg.GetTokenProvider()
+	if len(name) == 0 {
+		name = DefaultTokenProvider
+	}
+	tokenProviderMutex.RLock()
+	creator, ok := tokenProviderCreators[name]
+	tokenProviderMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("global.serverConnector.tokenProvider %s is not registered", name)
+	}
+	return creator(cfg), nil
+}
+
+// configTokenProvider 从global.serverConnector.token读取鉴权凭证，配置更新后立即生效
+type configTokenProvider struct {
+	cfg config.ServerConnectorConfig
+}
+
+// GetToken 获取当前的鉴权凭证
+func (c *configTokenProvider) GetToken(context.Context) (string, error) {
+	return c.cfg.GetToken(), nil
+}
+
+// NewConfigTokenProvider 创建基于配置的鉴权凭证提供者
+func NewConfigTokenProvider(cfg config.ServerConnectorConfig) TokenProvider {
+	return &configTokenProvider{cfg: cfg}
+}
+
+// NewTokenCredentials 创建在每次请求中携带鉴权凭证的grpc凭证
+func NewTokenCredentials(provider TokenProvider) credentials.PerRPCCredentials {
+	return &tokenCredentials{provider: provider}
+}
+
+// tokenCredentials 在每次请求的grpc头中携带鉴权凭证
+type tokenCredentials struct {
+	provider TokenProvider
+}
+
+// GetRequestMetadata 获取需要携带的grpc头
+func (t *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	token, err := t.provider.GetToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(token) == 0 {
+		return nil, nil
+	}
+	return map[string]string{HeaderKeyToken: token}, nil
+}
+
+// RequireTransportSecurity 凭证的安全性由global.serverConnector.tls保证，不强制要求TLS
+func (t *tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
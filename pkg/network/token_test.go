@@ -0,0 +1,84 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+)
+
+// staticTokenProvider 返回固定凭证的提供者
+type staticTokenProvider struct {
+	token string
+}
+
+func (s *staticTokenProvider) GetToken(context.Context) (string, error) {
+	return s.token, nil
+}
+
+// TestConfigTokenCredentials 测试基于配置的凭证注入到请求头中，配置更新后立即生效
+func TestConfigTokenCredentials(t *testing.T) {
+	cfg := &config.ServerConnectorConfigImpl{}
+	provider, err := NewTokenProvider(cfg)
+	assert.Nil(t, err)
+	creds := NewTokenCredentials(provider)
+
+	// 未配置凭证时不携带请求头
+	md, err := creds.GetRequestMetadata(context.Background())
+	assert.Nil(t, err)
+	assert.Empty(t, md)
+
+	cfg.SetToken("token-1")
+	md, err = creds.GetRequestMetadata(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{HeaderKeyToken: "token-1"}, md)
+
+	cfg.SetToken("token-2")
+	md, err = creds.GetRequestMetadata(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "token-2", md[HeaderKeyToken])
+}
+
+// TestRegisterTokenProvider 测试通过global.serverConnector.tokenProvider选择自定义的凭证提供者
+func TestRegisterTokenProvider(t *testing.T) {
+	RegisterTokenProvider("static", func(cfg config.ServerConnectorConfig) TokenProvider {
+		return &staticTokenProvider{token: "static-token"}
+	})
+	assert.Panics(t, func() {
+		RegisterTokenProvider("static", func(cfg config.ServerConnectorConfig) TokenProvider {
+			return nil
+		})
+	})
+
+	cfg := &config.ServerConnectorConfigImpl{}
+	cfg.SetToken("config-token")
+	cfg.SetTokenProvider("static")
+	provider, err := NewTokenProvider(cfg)
+	assert.Nil(t, err)
+	md, err := NewTokenCredentials(provider).GetRequestMetadata(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "static-token", md[HeaderKeyToken])
+
+	cfg.SetTokenProvider("unknown")
+	_, err = NewTokenProvider(cfg)
+	assert.NotNil(t, err)
+}
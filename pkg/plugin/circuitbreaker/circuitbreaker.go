@@ -35,6 +35,12 @@ type InstanceCircuitBreaker interface {
 	CircuitBreak(instances []model.Instance) (*Result, error)
 }
 
+// ResourceCircuitBreaker 服务及接口级熔断，熔断器可选实现该接口
+type ResourceCircuitBreaker interface {
+	// CheckResource 判断服务或者接口是否可以被调用，熔断器打开时返回错误
+	CheckResource(svcKey *model.ServiceKey, method string) error
+}
+
 // Result 熔断结算结果
 type Result struct {
 	Now time.Time
@@ -46,6 +52,8 @@ type Result struct {
 	InstancesToClose model.HashSet
 	// 该熔断器在实例进入半开状态后最多允许的请求数
 	RequestCountAfterHalfOpen int
+	// 单个实例进入半开状态后最多允许的请求数，key为实例ID，未设置的实例使用RequestCountAfterHalfOpen
+	InstanceRequestCountAfterHalfOpen map[string]int
 }
 
 // NewCircuitBreakerResult 创建熔断结果对象
@@ -57,8 +65,34 @@ func NewCircuitBreakerResult(now time.Time) *Result {
 		InstancesToOpen:     model.HashSet{}}
 }
 
+// SetInstanceRequestCountAfterHalfOpen 设置单个实例进入半开状态后最多允许的请求数
+func (r *Result) SetInstanceRequestCountAfterHalfOpen(instID string, count int) {
+	if nil == r.InstanceRequestCountAfterHalfOpen {
+		r.InstanceRequestCountAfterHalfOpen = make(map[string]int)
+	}
+	r.InstanceRequestCountAfterHalfOpen[instID] = count
+}
+
+// GetRequestCountAfterHalfOpen 获取实例进入半开状态后最多允许的请求数
+func (r *Result) GetRequestCountAfterHalfOpen(instID string) int {
+	if count, ok := r.InstanceRequestCountAfterHalfOpen[instID]; ok {
+		return count
+	}
+	return r.RequestCountAfterHalfOpen
+}
+
 // Merge merge results
 func (r *Result) Merge(result *Result) {
+	// 保留各实例的半开请求数，避免合并后统一使用合并目标的RequestCountAfterHalfOpen
+	allInstances := []model.HashSet{result.InstancesToOpen, result.InstancesToHalfOpen, result.InstancesToClose}
+	for _, instances := range allInstances {
+		for k := range instances {
+			instID := k.(string)
+			if count := result.GetRequestCountAfterHalfOpen(instID); count != r.RequestCountAfterHalfOpen {
+				r.SetInstanceRequestCountAfterHalfOpen(instID, count)
+			}
+		}
+	}
 	if len(result.InstancesToOpen) > 0 {
 		for k, v := range result.InstancesToOpen {
 			r.InstancesToOpen[k] = v
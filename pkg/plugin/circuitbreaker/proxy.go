@@ -41,6 +41,14 @@ func (p *Proxy) CircuitBreak(instances []model.Instance) (*Result, error) {
 	return cbResult, err
 }
 
+// CheckResource proxy ResourceCircuitBreaker CheckResource
+func (p *Proxy) CheckResource(svcKey *model.ServiceKey, method string) error {
+	if resourceBreaker, ok := p.InstanceCircuitBreaker.(ResourceCircuitBreaker); ok {
+		return resourceBreaker.CheckResource(svcKey, method)
+	}
+	return nil
+}
+
 // SetRealPlugin 设置
 func (p *Proxy) SetRealPlugin(plug plugin.Plugin, engine model.Engine) {
 	p.InstanceCircuitBreaker = plug.(InstanceCircuitBreaker)
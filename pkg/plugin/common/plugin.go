@@ -101,6 +101,8 @@ const (
 	OnRateLimitWindowCreated PluginEventType = 0x8008
 	// OnRateLimitWindowDeleted 一个限流规则的限流窗口被删除时触发的事件
 	OnRateLimitWindowDeleted PluginEventType = 0x8009
+	// OnServiceCallResult 用户上报了一次服务调用结果，事件对象为*model.ServiceCallResult
+	OnServiceCallResult PluginEventType = 0x800A
 )
 
 // PluginEvent 插件事件
@@ -30,6 +30,16 @@ type ConfigConnector interface {
 	GetConfigFile(configFile *ConfigFile) (*ConfigFileResponse, error)
 	// WatchConfigFiles Watch config files
 	WatchConfigFiles(configFileList []*ConfigFile) (*ConfigFileResponse, error)
+	// GetConfigGroup Get published config files of group
+	GetConfigGroup(group *ConfigGroup) (*ConfigGroupResponse, error)
+	// CreateConfigFile Create config file
+	CreateConfigFile(configFile *ConfigFile) (*ConfigFileResponse, error)
+	// UpdateConfigFile Update config file
+	UpdateConfigFile(configFile *ConfigFile) (*ConfigFileResponse, error)
+	// PublishConfigFile Publish config file
+	PublishConfigFile(configFile *ConfigFile) (*ConfigFileResponse, error)
+	// DeleteConfigFile Delete config file
+	DeleteConfigFile(configFile *ConfigFile) (*ConfigFileResponse, error)
 }
 
 // init
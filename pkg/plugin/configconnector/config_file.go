@@ -93,6 +93,17 @@ func (c *ConfigFile) GetDataKey() string {
 	return ""
 }
 
+// SetTag 设置配置文件标签，已存在时覆盖
+func (c *ConfigFile) SetTag(key string, value string) {
+	for _, tag := range c.Tags {
+		if tag.Key == key {
+			tag.Value = value
+			return
+		}
+	}
+	c.Tags = append(c.Tags, &ConfigFileTag{Key: key, Value: value})
+}
+
 // GetEncryptAlgo 获取配置文件数据加密算法
 func (c *ConfigFile) GetEncryptAlgo() string {
 	for _, tag := range c.Tags {
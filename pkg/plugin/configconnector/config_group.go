@@ -0,0 +1,70 @@
+/*
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ *  under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package configconnector
+
+// ConfigGroup 配置分组查询请求
+type ConfigGroup struct {
+	Namespace string
+	Group     string
+	// Revision 客户端当前的分组版本，与服务端一致时服务端返回 DataNoChange
+	Revision string
+}
+
+// GetNamespace 获取配置分组命名空间
+func (c *ConfigGroup) GetNamespace() string {
+	return c.Namespace
+}
+
+// GetGroup 获取配置分组名
+func (c *ConfigGroup) GetGroup() string {
+	return c.Group
+}
+
+// GetRevision 获取配置分组版本
+func (c *ConfigGroup) GetRevision() string {
+	return c.Revision
+}
+
+// ConfigGroupResponse 配置分组响应体
+type ConfigGroupResponse struct {
+	Code     uint32
+	Message  string
+	Revision string
+	// ConfigFiles 分组下已发布的配置文件，不包含配置内容
+	ConfigFiles []*ConfigFile
+}
+
+// GetCode 获取配置分组响应体code
+func (c *ConfigGroupResponse) GetCode() uint32 {
+	return c.Code
+}
+
+// GetMessage 获取配置分组响应体信息
+func (c *ConfigGroupResponse) GetMessage() string {
+	return c.Message
+}
+
+// GetRevision 获取配置分组版本
+func (c *ConfigGroupResponse) GetRevision() string {
+	return c.Revision
+}
+
+// GetConfigFiles 获取分组下已发布的配置文件
+func (c *ConfigGroupResponse) GetConfigFiles() []*ConfigFile {
+	return c.ConfigFiles
+}
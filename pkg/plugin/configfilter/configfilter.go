@@ -42,12 +42,32 @@ func (c Chain) Execute(configFile *configconnector.ConfigFile, next ConfigFileHa
 	return next(configFile)
 }
 
+// BeforeUpload 创建、更新配置文件之前，依次执行链中过滤器的上传处理
+func (c Chain) BeforeUpload(configFile *configconnector.ConfigFile) error {
+	for _, filter := range c {
+		uploadFilter, ok := filter.(UploadFilter)
+		if !ok {
+			continue
+		}
+		if err := uploadFilter.BeforeUpload(configFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ConfigFilter 配置过滤器接口
 type ConfigFilter interface {
 	plugin.Plugin
 	DoFilter(configFile *configconnector.ConfigFile, next ConfigFileHandleFunc) ConfigFileHandleFunc
 }
 
+// UploadFilter 配置文件上传过滤器，过滤器插件可选实现，在创建、更新配置文件之前对请求进行处理
+type UploadFilter interface {
+	// BeforeUpload 上传配置文件之前处理请求
+	BeforeUpload(configFile *configconnector.ConfigFile) error
+}
+
 func init() {
 	plugin.RegisterPluginInterface(common.TypeConfigFilter, new(ConfigFilter))
 }
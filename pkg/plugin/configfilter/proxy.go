@@ -42,6 +42,14 @@ func (p *Proxy) DoFilter(configFile *configconnector.ConfigFile, next ConfigFile
 	return p.ConfigFilter.DoFilter(configFile, next)
 }
 
+// BeforeUpload 上传配置文件之前处理请求，插件未实现 UploadFilter 时不做处理
+func (p *Proxy) BeforeUpload(configFile *configconnector.ConfigFile) error {
+	if uploadFilter, ok := p.ConfigFilter.(UploadFilter); ok {
+		return uploadFilter.BeforeUpload(configFile)
+	}
+	return nil
+}
+
 func init() {
 	plugin.RegisterPluginProxy(common.TypeConfigFilter, &Proxy{})
 }
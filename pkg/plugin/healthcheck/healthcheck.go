@@ -20,6 +20,8 @@ package healthcheck
 import (
 	"time"
 
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
@@ -33,6 +35,12 @@ type HealthChecker interface {
 	DetectInstance(model.Instance) (DetectResult, error)
 }
 
+// RuleHealthChecker 根据服务端下发的探测规则进行探测，探测器可选实现该接口
+type RuleHealthChecker interface {
+	// DetectInstanceWithRule 按照探测规则对单个实例进行探测，返回探测结果
+	DetectInstanceWithRule(model.Instance, *apifault.FaultDetectRule) (DetectResult, error)
+}
+
 // DetectResult 健康探测结果
 type DetectResult interface {
 	// IsSuccess 是否探测成功
@@ -18,6 +18,8 @@
 package healthcheck
 
 import (
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
@@ -41,6 +43,14 @@ func (p *Proxy) DetectInstance(inst model.Instance) (DetectResult, error) {
 	return result, err
 }
 
+// DetectInstanceWithRule proxy RuleHealthChecker DetectInstanceWithRule
+func (p *Proxy) DetectInstanceWithRule(inst model.Instance, rule *apifault.FaultDetectRule) (DetectResult, error) {
+	if ruleChecker, ok := p.HealthChecker.(RuleHealthChecker); ok {
+		return ruleChecker.DetectInstanceWithRule(inst, rule)
+	}
+	return nil, nil
+}
+
 // init 注册proxy
 func init() {
 	plugin.RegisterPluginProxy(common.TypeHealthCheck, &Proxy{})
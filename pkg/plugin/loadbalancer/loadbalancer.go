@@ -18,6 +18,9 @@
 package loadbalancer
 
 import (
+	"net"
+	"strconv"
+
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
@@ -35,6 +38,43 @@ type Criteria struct {
 	Cluster *model.Cluster
 	// 可选，对于有状态的负载均衡方式，这里给出备份节点的返回数据
 	ReplicateInfo ReplicateInfo
+	// 可选，调用的接口名，设置后会跳过该接口已经熔断的实例
+	Method string
+	// 可选，需要排除的实例
+	ExcludeInstances []model.Instance
+	// 可选，需要排除的地址，格式为host或者host:port
+	ExcludeHosts []string
+}
+
+// HasExclusion 是否设置了需要排除的实例
+func (c *Criteria) HasExclusion() bool {
+	return len(c.ExcludeInstances) > 0 || len(c.ExcludeHosts) > 0
+}
+
+// IsExcluded 判断实例是否需要排除，排除列表一般只包含少量重试过的实例，因此直接遍历
+func (c *Criteria) IsExcluded(instance model.Instance) bool {
+	if nil == instance {
+		return false
+	}
+	for _, excluded := range c.ExcludeInstances {
+		if nil == excluded {
+			continue
+		}
+		if (len(excluded.GetId()) > 0 && excluded.GetId() == instance.GetId()) ||
+			(excluded.GetHost() == instance.GetHost() && excluded.GetPort() == instance.GetPort()) {
+			return true
+		}
+	}
+	if len(c.ExcludeHosts) == 0 {
+		return false
+	}
+	address := net.JoinHostPort(instance.GetHost(), strconv.Itoa(int(instance.GetPort())))
+	for _, host := range c.ExcludeHosts {
+		if host == instance.GetHost() || host == address {
+			return true
+		}
+	}
+	return false
 }
 
 // ReplicateInfo 备份节点信息
@@ -50,6 +90,13 @@ type LoadBalancer interface {
 	ChooseInstance(criteria *Criteria, instances model.ServiceInstances) (model.Instance, error)
 }
 
+// InstanceAcceptor 【可选接口】负载均衡插件实现后，负载均衡代理最终接受并返回实例时回调，
+// 被熔断或者排除而重新选择的实例不会回调，用于在途请求数等只针对最终返回实例的统计
+type InstanceAcceptor interface {
+	// OnInstanceAccepted 实例被负载均衡代理接受，并返回给调用方
+	OnInstanceAccepted(instance model.Instance)
+}
+
 // init 初始化
 func init() {
 	plugin.RegisterPluginInterface(common.TypeLoadBalancer, new(LoadBalancer))
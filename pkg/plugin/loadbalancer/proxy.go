@@ -18,6 +18,7 @@
 package loadbalancer
 
 import (
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
@@ -41,8 +42,94 @@ type SelectStatus struct {
 	IncludeHalfOpen     bool
 }
 
+// 接口熔断或者实例被排除时重新进行负载均衡的最大次数
+const maxMethodRetryTimes = 3
+
 // ChooseInstance proxy LoadBalancer ChooseInstance
+// 只有最终返回的实例才会占用实例及接口的半开探测配额，并回调插件的InstanceAcceptor
 func (p *Proxy) ChooseInstance(criteria *Criteria, instances model.ServiceInstances) (model.Instance, error) {
+	result, err := p.chooseAcceptedInstance(criteria, instances)
+	if err != nil || nil == result {
+		return result, err
+	}
+	if acceptor, ok := p.LoadBalancer.(InstanceAcceptor); ok {
+		acceptor.OnInstanceAccepted(result)
+	}
+	return result, nil
+}
+
+// chooseAcceptedInstance 选择可以接受的实例，选中的实例在接口上已经熔断或者被排除时重新选择
+func (p *Proxy) chooseAcceptedInstance(criteria *Criteria, instances model.ServiceInstances) (model.Instance, error) {
+	result, err := p.chooseInstance(criteria, instances)
+	if err != nil || nil != result {
+		return result, err
+	}
+	// 选中的实例在该接口上已经熔断或者被排除，先重新进行负载均衡，保持原有的流量分布
+	for i := 0; i < maxMethodRetryTimes; i++ {
+		retryResult, retryErr := p.chooseInstance(criteria, instances)
+		if retryErr != nil {
+			break
+		}
+		if nil != retryResult {
+			return retryResult, nil
+		}
+	}
+	// 对于有状态的负载均衡方式，重试会得到同样的结果，因此从集群中挑选可以接受的实例
+	if nil != criteria.Cluster {
+		candidates, _ := criteria.Cluster.GetInstances()
+		if len(candidates) > 0 {
+			offset := rand.Intn(len(candidates))
+			for i := 0; i < len(candidates); i++ {
+				candidate := candidates[(offset+i)%len(candidates)]
+				if !model.IsInstanceAvailable(candidate) || !acceptInstance(criteria, candidate) {
+					continue
+				}
+				if allocateResult(criteria, candidate) {
+					return candidate, nil
+				}
+			}
+		}
+	}
+	if len(criteria.Method) == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"all available instances of service %s(namespace %s) are excluded in load balance",
+			instances.GetService(), instances.GetNamespace())
+	}
+	return nil, model.NewSDKError(model.ErrCodeCircuitBreakerError, nil,
+		"all instances of service %s(namespace %s) are circuit broken for method %s or excluded",
+		instances.GetService(), instances.GetNamespace(), criteria.Method)
+}
+
+// acceptInstance 判断实例是否可以作为负载均衡结果，只判断状态，不占用半开探测配额
+func acceptInstance(criteria *Criteria, instance model.Instance) bool {
+	if criteria.IsExcluded(instance) {
+		return false
+	}
+	if len(criteria.Method) == 0 {
+		return true
+	}
+	cbStatus := model.GetMethodCircuitBreakerStatus(instance, criteria.Method)
+	return cbStatus == nil || cbStatus.IsAvailable()
+}
+
+// allocateMethod 为最终返回的实例分配接口级熔断的半开探测配额
+func allocateMethod(instance model.Instance, method string) bool {
+	cbStatus := model.GetMethodCircuitBreakerStatus(instance, method)
+	return cbStatus == nil || cbStatus.Allocate()
+}
+
+// allocateResult 为最终返回的实例分配半开探测配额，先分配接口级配额，
+// 接口级配额不足时不再占用实例级配额
+func allocateResult(criteria *Criteria, instance model.Instance) bool {
+	if !allocateMethod(instance, criteria.Method) {
+		return false
+	}
+	cbStatus := instance.GetCircuitBreakerStatus()
+	return cbStatus == nil || cbStatus.Allocate()
+}
+
+// chooseInstance 进行实例级熔断感知的负载均衡，选中的实例被排除或者接口已熔断时返回nil
+func (p *Proxy) chooseInstance(criteria *Criteria, instances model.ServiceInstances) (model.Instance, error) {
 	// 第一次进行负载均衡，包括半开实例
 	criteria.Cluster.IncludeHalfOpen = true
 	firstResult, firstErr := p.LoadBalancer.ChooseInstance(criteria, instances)
@@ -50,6 +137,14 @@ func (p *Proxy) ChooseInstance(criteria *Criteria, instances model.ServiceInstan
 	if firstErr != nil {
 		return firstResult, firstErr
 	}
+	// 先判断是否可以接受，避免为不会返回的实例占用半开探测配额
+	if !acceptInstance(criteria, firstResult) {
+		return nil, nil
+	}
+	// 先分配接口级配额，接口级配额不足时该实例不可用，不占用实例级配额
+	if !allocateMethod(firstResult, criteria.Method) {
+		return nil, nil
+	}
 	// 熔断状态分配流量成功，返回结果
 	cbStatus := firstResult.GetCircuitBreakerStatus()
 	if cbStatus == nil || cbStatus.Allocate() {
@@ -61,11 +156,14 @@ func (p *Proxy) ChooseInstance(criteria *Criteria, instances model.ServiceInstan
 	secondResult, secondErr := p.LoadBalancer.ChooseInstance(criteria, instances)
 	// 如果没有出现错误，那么直接返回第二次的结果
 	if secondErr == nil {
-		return secondResult, secondErr
+		if !acceptInstance(criteria, secondResult) || !allocateMethod(secondResult, criteria.Method) {
+			return nil, nil
+		}
+		return secondResult, nil
 	}
 	// 否则，直接返回第一次的结果
 	// 目前可能的情况是，所有实例都是半开，所以第二次负载均衡会返回实例权重为0的错误，第一次返回了一个半开实例；
-	// 在这种情况下，选择返回第一次的结果，即一个配额用完的半开实例
+	// 在这种情况下，选择返回第一次的结果，即一个配额用完的半开实例，接口级配额已经在第一次分配
 	return firstResult, nil
 }
 
@@ -0,0 +1,183 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// testStatus 半开状态，记录探测配额的分配次数
+type testStatus struct {
+	model.CircuitBreakerStatus
+	status    model.Status
+	quota     int
+	allocated int
+}
+
+func (s *testStatus) GetStatus() model.Status {
+	return s.status
+}
+
+func (s *testStatus) IsAvailable() bool {
+	return s.status == model.Close || (s.status == model.HalfOpen && s.quota > s.allocated)
+}
+
+func (s *testStatus) Allocate() bool {
+	if s.status != model.HalfOpen {
+		return s.status == model.Close
+	}
+	s.allocated++
+	return s.allocated <= s.quota
+}
+
+// testInstance 只实现负载均衡代理需要的方法
+type testInstance struct {
+	model.Instance
+	id           string
+	port         uint32
+	cbStatus     model.CircuitBreakerStatus
+	methodStatus map[string]model.CircuitBreakerStatus
+}
+
+func (i *testInstance) GetId() string {
+	return i.id
+}
+
+func (i *testInstance) GetHost() string {
+	return "127.0.0.1"
+}
+
+func (i *testInstance) GetPort() uint32 {
+	return i.port
+}
+
+func (i *testInstance) GetCircuitBreakerStatus() model.CircuitBreakerStatus {
+	return i.cbStatus
+}
+
+func (i *testInstance) GetMethodCircuitBreakerStatus(method string) model.CircuitBreakerStatus {
+	if status, ok := i.methodStatus[method]; ok {
+		return status
+	}
+	return nil
+}
+
+// sequenceBalancer 按照顺序返回实例
+type sequenceBalancer struct {
+	LoadBalancer
+	results []model.Instance
+	index   int
+}
+
+func (b *sequenceBalancer) ChooseInstance(criteria *Criteria, instances model.ServiceInstances) (model.Instance, error) {
+	result := b.results[b.index%len(b.results)]
+	b.index++
+	return result, nil
+}
+
+// TestProxyRejectedInstanceKeepsQuota 测试被排除或者接口熔断的实例不占用半开探测配额
+func TestProxyRejectedInstanceKeepsQuota(t *testing.T) {
+	excluded := &testInstance{id: "excluded", port: 8001,
+		cbStatus: &testStatus{status: model.HalfOpen, quota: 1}}
+	methodOpen := &testInstance{id: "methodOpen", port: 8002,
+		cbStatus: &testStatus{status: model.HalfOpen, quota: 1},
+		methodStatus: map[string]model.CircuitBreakerStatus{
+			"/hello": &testStatus{status: model.Open},
+		}}
+	methodHalfOpen := &testInstance{id: "methodHalfOpen", port: 8003,
+		methodStatus: map[string]model.CircuitBreakerStatus{
+			"/hello": &testStatus{status: model.HalfOpen, quota: 1},
+		}}
+	proxy := &Proxy{LoadBalancer: &sequenceBalancer{
+		results: []model.Instance{excluded, methodOpen, methodHalfOpen},
+	}}
+	criteria := &Criteria{
+		Cluster:          &model.Cluster{},
+		Method:           "/hello",
+		ExcludeInstances: []model.Instance{excluded},
+	}
+
+	result, err := proxy.ChooseInstance(criteria, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "methodHalfOpen", result.GetId())
+	assert.Equal(t, 0, excluded.cbStatus.(*testStatus).allocated)
+	assert.Equal(t, 0, methodOpen.cbStatus.(*testStatus).allocated)
+	assert.Equal(t, 0, methodOpen.methodStatus["/hello"].(*testStatus).allocated)
+	// 只有最终返回的实例占用接口级探测配额
+	assert.Equal(t, 1, methodHalfOpen.methodStatus["/hello"].(*testStatus).allocated)
+}
+
+// acceptingBalancer 记录被负载均衡代理接受的实例
+type acceptingBalancer struct {
+	sequenceBalancer
+	accepted []string
+}
+
+func (b *acceptingBalancer) OnInstanceAccepted(instance model.Instance) {
+	b.accepted = append(b.accepted, instance.GetId())
+}
+
+// TestProxyAcceptReturnedInstance 测试只有最终返回的实例回调InstanceAcceptor
+func TestProxyAcceptReturnedInstance(t *testing.T) {
+	excluded := &testInstance{id: "excluded", port: 8001}
+	methodOpen := &testInstance{id: "methodOpen", port: 8002,
+		methodStatus: map[string]model.CircuitBreakerStatus{
+			"/hello": &testStatus{status: model.Open},
+		}}
+	available := &testInstance{id: "available", port: 8003}
+	balancer := &acceptingBalancer{sequenceBalancer: sequenceBalancer{
+		results: []model.Instance{excluded, methodOpen, available},
+	}}
+	proxy := &Proxy{LoadBalancer: balancer}
+	criteria := &Criteria{
+		Cluster:          &model.Cluster{},
+		Method:           "/hello",
+		ExcludeInstances: []model.Instance{excluded},
+	}
+
+	result, err := proxy.ChooseInstance(criteria, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "available", result.GetId())
+	assert.Equal(t, []string{"available"}, balancer.accepted)
+}
+
+// TestProxyAllocateMethodQuotaFirst 测试接口级配额不足时不占用实例级半开探测配额
+func TestProxyAllocateMethodQuotaFirst(t *testing.T) {
+	exhausted := &testInstance{id: "exhausted", port: 8001,
+		cbStatus: &testStatus{status: model.HalfOpen, quota: 1},
+		methodStatus: map[string]model.CircuitBreakerStatus{
+			"/hello": &testStatus{status: model.HalfOpen, quota: 0},
+		}}
+	available := &testInstance{id: "available", port: 8002}
+	proxy := &Proxy{LoadBalancer: &sequenceBalancer{
+		results: []model.Instance{exhausted, available},
+	}}
+	criteria := &Criteria{
+		Cluster: &model.Cluster{},
+		Method:  "/hello",
+	}
+
+	result, err := proxy.ChooseInstance(criteria, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "available", result.GetId())
+	assert.Equal(t, 0, exhausted.cbStatus.(*testStatus).allocated)
+}
@@ -31,10 +31,29 @@ import (
 const (
 	// PropertyCircuitBreakerStatus InstanceProperties中Properties的key,熔断结果状态
 	PropertyCircuitBreakerStatus = "CircuitBreakerStatus"
+	// PropertyMethodCircuitBreakerStatus InstanceProperties中Properties的key,接口级熔断结果状态
+	PropertyMethodCircuitBreakerStatus = "MethodCircuitBreakerStatus"
 	// PropertyHealthCheckStatus InstanceProperties中Properties的key,健康探测结果状态
 	PropertyHealthCheckStatus = "HealthCheckStatus"
+	// PropertyDynamicWeight InstanceProperties中Properties的key,动态权重
+	PropertyDynamicWeight = "DynamicWeight"
+	// PropertyWarmupWeight InstanceProperties中Properties的key,预热权重百分比，值类型为int
+	PropertyWarmupWeight = "WarmupWeight"
 )
 
+// MethodCircuitBreakerStatus 实例在某个接口上的熔断状态，作为PropertyMethodCircuitBreakerStatus的值
+type MethodCircuitBreakerStatus struct {
+	// 接口名
+	Method string
+	// 熔断状态
+	Status model.CircuitBreakerStatus
+}
+
+// String ToString方法
+func (m *MethodCircuitBreakerStatus) String() string {
+	return fmt.Sprintf("{method: %s, status: %v}", m.Method, m.Status)
+}
+
 // InstanceProperties 待更新的实例属性
 type InstanceProperties struct {
 	Service    *model.ServiceKey
@@ -124,6 +143,14 @@ type RuleRegistry interface {
 	GetServiceRateLimitRule(key *model.ServiceKey, includeCache bool) model.ServiceRule
 	// LoadServiceRateLimitRule 非阻塞发起限流规则加载
 	LoadServiceRateLimitRule(key *model.ServiceKey) (*common.Notifier, error)
+	// GetServiceCircuitBreakerRule 非阻塞获取熔断规则
+	GetServiceCircuitBreakerRule(key *model.ServiceKey, includeCache bool) model.ServiceRule
+	// LoadServiceCircuitBreakerRule 非阻塞发起熔断规则加载
+	LoadServiceCircuitBreakerRule(key *model.ServiceKey) (*common.Notifier, error)
+	// GetServiceFaultDetectRule 非阻塞获取主动探测规则
+	GetServiceFaultDetectRule(key *model.ServiceKey, includeCache bool) model.ServiceRule
+	// LoadServiceFaultDetectRule 非阻塞发起主动探测规则加载
+	LoadServiceFaultDetectRule(key *model.ServiceKey) (*common.Notifier, error)
 	// GetServicesByMeta 非阻塞获取批量服务
 	GetServicesByMeta(key *model.ServiceKey, includeCache bool) model.Services
 	// LoadServices 非阻塞加载批量服务
@@ -67,6 +67,18 @@ func (p *Proxy) LoadServiceRateLimitRule(key *model.ServiceKey) (*common.Notifie
 	return result, err
 }
 
+// LoadServiceCircuitBreakerRule proxy LocalRegistry LoadServiceCircuitBreakerRule
+func (p *Proxy) LoadServiceCircuitBreakerRule(key *model.ServiceKey) (*common.Notifier, error) {
+	result, err := p.LocalRegistry.LoadServiceCircuitBreakerRule(key)
+	return result, err
+}
+
+// LoadServiceFaultDetectRule proxy LocalRegistry LoadServiceFaultDetectRule
+func (p *Proxy) LoadServiceFaultDetectRule(key *model.ServiceKey) (*common.Notifier, error) {
+	result, err := p.LocalRegistry.LoadServiceFaultDetectRule(key)
+	return result, err
+}
+
 // init 注册proxy
 func init() {
 	plugin.RegisterPluginProxy(common.TypeLocalRegistry, &Proxy{})
@@ -33,26 +33,34 @@ import (
 	_ "github.com/polarismesh/polaris-go/plugin/circuitbreaker/errorcheck"
 	_ "github.com/polarismesh/polaris-go/plugin/circuitbreaker/errorcount"
 	_ "github.com/polarismesh/polaris-go/plugin/circuitbreaker/errorrate"
+	_ "github.com/polarismesh/polaris-go/plugin/circuitbreaker/outlierdetection"
+	_ "github.com/polarismesh/polaris-go/plugin/circuitbreaker/rulebased"
+	_ "github.com/polarismesh/polaris-go/plugin/circuitbreaker/slowcallrate"
 	_ "github.com/polarismesh/polaris-go/plugin/configconnector/polaris"
 	_ "github.com/polarismesh/polaris-go/plugin/configfilter/crypto"
 	_ "github.com/polarismesh/polaris-go/plugin/configfilter/crypto/aes"
 	_ "github.com/polarismesh/polaris-go/plugin/healthcheck/http"
 	_ "github.com/polarismesh/polaris-go/plugin/healthcheck/tcp"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/hash"
+	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/leastrequest"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/maglev"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/ringhash"
 	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/weightedrandom"
+	_ "github.com/polarismesh/polaris-go/plugin/loadbalancer/weightedroundrobin"
 	_ "github.com/polarismesh/polaris-go/plugin/localregistry/inmemory"
 	_ "github.com/polarismesh/polaris-go/plugin/location"
 	_ "github.com/polarismesh/polaris-go/plugin/logger/zaplog"
 	_ "github.com/polarismesh/polaris-go/plugin/metrics/prometheus"
 	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/reject"
 	_ "github.com/polarismesh/polaris-go/plugin/ratelimiter/unirate"
+	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/file"
 	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/grpc"
+	_ "github.com/polarismesh/polaris-go/plugin/serverconnector/xds"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/canary"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/dstmeta"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/filteronly"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/nearbybase"
+	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/priority"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/rulebase"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/setdivision"
 	_ "github.com/polarismesh/polaris-go/plugin/servicerouter/zeroprotect"
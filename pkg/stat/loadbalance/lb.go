@@ -27,6 +27,12 @@ import (
 type LoadBalanceGauge struct {
 	model.EmptyInstanceGauge
 	Inst model.Instance
+	// Overflow 有界负载一致性hash中，hash命中的实例超过负载上限，顺延选择了Inst
+	Overflow bool
+	// HashedInst hash命中但超过负载上限的实例
+	HashedInst model.Instance
+	// LoadBound 选择时实例在途请求数的上限
+	LoadBound int64
 }
 
 // LoadBalanceGauge池子
@@ -50,3 +56,19 @@ func PoolPutLoadBalanceStat(gauge *LoadBalanceGauge) {
 func (l *LoadBalanceGauge) GetCalledInstance() model.Instance {
 	return l.Inst
 }
+
+// GetNamespace 获取服务的命名空间
+func (l *LoadBalanceGauge) GetNamespace() string {
+	if nil == l.Inst {
+		return ""
+	}
+	return l.Inst.GetNamespace()
+}
+
+// GetService 获取服务名
+func (l *LoadBalanceGauge) GetService() string {
+	if nil == l.Inst {
+		return ""
+	}
+	return l.Inst.GetService()
+}
@@ -59,40 +59,48 @@ const (
 	MethodRealTimeAdjustDynamicWeight
 	MethodTimingAdjustDynamicWeight
 	MethodWatchService
+	MethodGetServiceCircuitBreakerRule
+	MethodLoadServiceCircuitBreakerRule
+	MethodGetServiceFaultDetectRule
+	MethodLoadServiceFaultDetectRule
 )
 
 // 将plugin的api编号转化为名字
 var methodMap = map[PluginAPI]string{
-	MethodReportAlarm:                 "ReportAlarm",
-	MethodStat:                        "Stat",
-	MethodCircuitBreak:                "CircuitBreak",
-	MethodChooseInstance:              "ChooseInstance",
-	MethodGetServices:                 "GetServices",
-	MethodGetInstances:                "GetInstances",
-	MethodLoadInstances:               "LoadInstances",
-	MethodUpdateInstances:             "UpdateInstances",
-	MethodPersistMessage:              "PersistMessage",
-	MethodLoadPersistedMessage:        "LoadPersistedMessage",
-	MethodGetServiceRouteRule:         "GetServiceRouteRule",
-	MethodLoadServiceRouteRule:        "LoadServiceRouteRule",
-	MethodGetServiceRateLimitRule:     "GetServiceRateLimitRule",
-	MethodLoadServiceRateLimitRule:    "LoadServiceRateLimitRule",
-	MethodDetectInstance:              "DetectInstance",
-	MethodInitQuota:                   "InitQuota",
-	MethodRegisterServiceHandler:      "RegisterServiceHandler",
-	MethodDeRegisterServiceHandler:    "DeRegisterServiceHandler",
-	MethodRegisterInstance:            "RegisterInstance",
-	MethodDeregisterInstance:          "DeregisterInstance",
-	MethodHeartbeat:                   "Heartbeat",
-	MethodReportClient:                "ReportClient",
-	MethodUpdateServers:               "UpdateServers",
-	MethodGetRateLimitConnector:       "GetRateLimitConnector",
-	MethodEnable:                      "Enable",
-	MethodGetFilteredInstances:        "GetFilteredInstances",
-	MethodReportStat:                  "ReportStat",
-	MethodRealTimeAdjustDynamicWeight: "RealTimeAdjustDynamicWeight",
-	MethodTimingAdjustDynamicWeight:   "TimingAdjustDynamicWeight",
-	MethodWatchService:                "WatchService",
+	MethodReportAlarm:                   "ReportAlarm",
+	MethodStat:                          "Stat",
+	MethodCircuitBreak:                  "CircuitBreak",
+	MethodChooseInstance:                "ChooseInstance",
+	MethodGetServices:                   "GetServices",
+	MethodGetInstances:                  "GetInstances",
+	MethodLoadInstances:                 "LoadInstances",
+	MethodUpdateInstances:               "UpdateInstances",
+	MethodPersistMessage:                "PersistMessage",
+	MethodLoadPersistedMessage:          "LoadPersistedMessage",
+	MethodGetServiceRouteRule:           "GetServiceRouteRule",
+	MethodLoadServiceRouteRule:          "LoadServiceRouteRule",
+	MethodGetServiceRateLimitRule:       "GetServiceRateLimitRule",
+	MethodLoadServiceRateLimitRule:      "LoadServiceRateLimitRule",
+	MethodDetectInstance:                "DetectInstance",
+	MethodInitQuota:                     "InitQuota",
+	MethodRegisterServiceHandler:        "RegisterServiceHandler",
+	MethodDeRegisterServiceHandler:      "DeRegisterServiceHandler",
+	MethodRegisterInstance:              "RegisterInstance",
+	MethodDeregisterInstance:            "DeregisterInstance",
+	MethodHeartbeat:                     "Heartbeat",
+	MethodReportClient:                  "ReportClient",
+	MethodUpdateServers:                 "UpdateServers",
+	MethodGetRateLimitConnector:         "GetRateLimitConnector",
+	MethodEnable:                        "Enable",
+	MethodGetFilteredInstances:          "GetFilteredInstances",
+	MethodReportStat:                    "ReportStat",
+	MethodRealTimeAdjustDynamicWeight:   "RealTimeAdjustDynamicWeight",
+	MethodTimingAdjustDynamicWeight:     "TimingAdjustDynamicWeight",
+	MethodWatchService:                  "WatchService",
+	MethodGetServiceCircuitBreakerRule:  "GetServiceCircuitBreakerRule",
+	MethodLoadServiceCircuitBreakerRule: "LoadServiceCircuitBreakerRule",
+	MethodGetServiceFaultDetectRule:     "GetServiceFaultDetectRule",
+	MethodLoadServiceFaultDetectRule:    "LoadServiceFaultDetectRule",
 }
 
 // GetPluginAPIName 获取插件api的名字
@@ -100,6 +100,12 @@ func (h *HalfOpenConversionHandler) halfOpenByCheck(instance model.Instance, sta
 
 // OpenToHalfOpen 熔断器从打开到半开
 func (h *HalfOpenConversionHandler) OpenToHalfOpen(instance model.Instance, now time.Time, cbName string) bool {
+	return h.OpenToHalfOpenAfter(instance, now, cbName, h.cbCfg.GetSleepWindow())
+}
+
+// OpenToHalfOpenAfter 熔断器从打开到半开，熔断持续时间由熔断器自行指定
+func (h *HalfOpenConversionHandler) OpenToHalfOpenAfter(
+	instance model.Instance, now time.Time, cbName string, sleepWindow time.Duration) bool {
 	cbStatus := instance.GetCircuitBreakerStatus()
 	if nil == cbStatus || cbStatus.GetCircuitBreaker() != cbName || cbStatus.GetStatus() != model.Open {
 		// 判断状态以及是否当前熔断器
@@ -114,7 +120,7 @@ func (h *HalfOpenConversionHandler) OpenToHalfOpen(instance model.Instance, now
 	if nil != result {
 		return *result
 	}
-	return halfOpenByTimeout(now, startTime, h.cbCfg.GetSleepWindow())
+	return halfOpenByTimeout(now, startTime, sleepWindow)
 }
 
 // halfOpenByTimeout 打开了探测，通过探测结果来判断半开
@@ -142,7 +142,7 @@ func (g *CircuitBreaker) Stat(gauge model.InstanceGauge) (bool, error) {
 func (g *CircuitBreaker) GetErrorCountConfig(namespace string, service string) config.ErrorCountConfig {
 	cfg := g.cfg
 	serviceSp := g.wholeCfg.GetConsumer().GetServiceSpecific(namespace, service)
-	if serviceSp != nil {
+	if serviceSp != nil && serviceSp.GetServiceCircuitBreaker() != nil {
 		cfg = serviceSp.GetServiceCircuitBreaker().GetErrorCountConfig()
 	}
 	return cfg
@@ -0,0 +1,160 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package outlierdetection
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// 定义离群检测熔断配置的默认值
+const (
+	// DefaultRequestVolumeThreshold 实例在统计窗口内的请求数达到该阈值才参与离群计算，默认10
+	DefaultRequestVolumeThreshold = 10
+	// DefaultMinimumHosts 满足请求数阈值的实例数达到该值才进行离群计算，默认5
+	DefaultMinimumHosts = 5
+	// DefaultStdevFactor 成功率低于(平均值 - 标准差 * 因子)的实例视为离群实例，默认1.9
+	DefaultStdevFactor float64 = 1.9
+	// DefaultMaxEjectionPercent 同时被离群熔断的实例的最大百分比，默认10
+	DefaultMaxEjectionPercent = 10
+	// MaxEjectionPercent 最大熔断实例百分比的上限
+	MaxEjectionPercent = 100
+	// DefaultBaseEjectionTime 首次熔断的持续时间，默认30s
+	DefaultBaseEjectionTime = 30 * time.Second
+	// DefaultMaxEjectionTime 重复熔断时熔断持续时间的上限，默认5分钟
+	DefaultMaxEjectionTime = 300 * time.Second
+	// MinEjectionTime 最小的熔断持续时间，1s
+	MinEjectionTime = 1 * time.Second
+	// DefaultMetricStatTimeWindow 成功率统计时间窗口，默认1分钟
+	DefaultMetricStatTimeWindow = 60 * time.Second
+	// MinMetricStatTimeWindow 最小成功率统计时间窗口，1s
+	MinMetricStatTimeWindow = 1 * time.Second
+	// DefaultMetricNumBuckets 统计窗口细分的桶数量，默认5
+	DefaultMetricNumBuckets = 5
+	// MinMetricStatBucketSize 最小的滑窗时间片，1ms
+	MinMetricStatBucketSize = 1 * time.Millisecond
+)
+
+// Config 基于成功率离群检测熔断器的配置结构
+type Config struct {
+	RequestVolumeThreshold int            `yaml:"requestVolumeThreshold" json:"requestVolumeThreshold"`
+	MinimumHosts           int            `yaml:"minimumHosts" json:"minimumHosts"`
+	StdevFactor            float64        `yaml:"stdevFactor" json:"stdevFactor"`
+	MaxEjectionPercent     int            `yaml:"maxEjectionPercent" json:"maxEjectionPercent"`
+	BaseEjectionTime       *time.Duration `yaml:"baseEjectionTime" json:"baseEjectionTime"`
+	MaxEjectionTime        *time.Duration `yaml:"maxEjectionTime" json:"maxEjectionTime"`
+	MetricStatTimeWindow   *time.Duration `yaml:"metricStatTimeWindow" json:"metricStatTimeWindow"`
+	MetricNumBuckets       int            `yaml:"metricNumBuckets" json:"metricNumBuckets"`
+}
+
+// Verify 检验离群检测熔断配置
+func (r *Config) Verify() error {
+	var errs error
+	if r.RequestVolumeThreshold <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("outlierDetection.requestVolumeThreshold must be greater than 0"))
+	}
+	if r.MinimumHosts <= 1 {
+		errs = multierror.Append(errs, fmt.Errorf("outlierDetection.minimumHosts must be greater than 1"))
+	}
+	if r.StdevFactor <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("outlierDetection.stdevFactor must be greater than 0"))
+	}
+	if r.MaxEjectionPercent <= 0 || r.MaxEjectionPercent > MaxEjectionPercent {
+		errs = multierror.Append(errs, fmt.Errorf(
+			"outlierDetection.maxEjectionPercent must be greater than 0 and lower than %d", MaxEjectionPercent))
+	}
+	if nil != r.BaseEjectionTime && *r.BaseEjectionTime < MinEjectionTime {
+		errs = multierror.Append(errs,
+			fmt.Errorf("outlierDetection.baseEjectionTime must be greater than %v", MinEjectionTime))
+	}
+	if nil != r.BaseEjectionTime && nil != r.MaxEjectionTime && *r.MaxEjectionTime < *r.BaseEjectionTime {
+		errs = multierror.Append(errs,
+			fmt.Errorf("outlierDetection.maxEjectionTime must be greater than baseEjectionTime"))
+	}
+	if nil != r.MetricStatTimeWindow && *r.MetricStatTimeWindow < MinMetricStatTimeWindow {
+		errs = multierror.Append(errs,
+			fmt.Errorf("outlierDetection.metricStatTimeWindow must be greater than %v", MinMetricStatTimeWindow))
+	}
+	if r.MetricNumBuckets <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("outlierDetection.metricNumBuckets must be greater than 0"))
+	}
+	if nil != r.MetricStatTimeWindow && r.MetricNumBuckets > 0 && r.GetBucketInterval() < MinMetricStatBucketSize {
+		errs = multierror.Append(errs,
+			fmt.Errorf("bucketSize(metricStatTimeWindow/metricNumBuckets) must be greater than %v",
+				MinMetricStatBucketSize))
+	}
+	return errs
+}
+
+// GetBucketInterval 获取滑桶时间间隔
+func (r *Config) GetBucketInterval() time.Duration {
+	bucketSize := math.Ceil(float64(*r.MetricStatTimeWindow) / float64(r.MetricNumBuckets))
+	return time.Duration(bucketSize)
+}
+
+// SetDefault 设置离群检测熔断配置的默认值
+func (r *Config) SetDefault() {
+	if r.RequestVolumeThreshold == 0 {
+		r.RequestVolumeThreshold = DefaultRequestVolumeThreshold
+	}
+	if r.MinimumHosts == 0 {
+		r.MinimumHosts = DefaultMinimumHosts
+	}
+	if r.StdevFactor == 0 {
+		r.StdevFactor = DefaultStdevFactor
+	}
+	if r.MaxEjectionPercent == 0 {
+		r.MaxEjectionPercent = DefaultMaxEjectionPercent
+	}
+	if nil == r.BaseEjectionTime {
+		r.BaseEjectionTime = model.ToDurationPtr(DefaultBaseEjectionTime)
+	}
+	if nil == r.MaxEjectionTime {
+		maxEjectionTime := DefaultMaxEjectionTime
+		if *r.BaseEjectionTime > maxEjectionTime {
+			maxEjectionTime = *r.BaseEjectionTime
+		}
+		r.MaxEjectionTime = model.ToDurationPtr(maxEjectionTime)
+	}
+	if nil == r.MetricStatTimeWindow {
+		r.MetricStatTimeWindow = model.ToDurationPtr(DefaultMetricStatTimeWindow)
+	}
+	if r.MetricNumBuckets == 0 {
+		r.MetricNumBuckets = DefaultMetricNumBuckets
+	}
+}
+
+// GetBaseEjectionTime 首次熔断的持续时间
+func (r *Config) GetBaseEjectionTime() time.Duration {
+	return *r.BaseEjectionTime
+}
+
+// GetMaxEjectionTime 熔断持续时间的上限
+func (r *Config) GetMaxEjectionTime() time.Duration {
+	return *r.MaxEjectionTime
+}
+
+// GetMetricStatTimeWindow 成功率统计时间窗口
+func (r *Config) GetMetricStatTimeWindow() time.Duration {
+	return *r.MetricStatTimeWindow
+}
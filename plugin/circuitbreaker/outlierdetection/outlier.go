@@ -0,0 +1,387 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package outlierdetection
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/metric"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+	common2 "github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+	"github.com/polarismesh/polaris-go/plugin/circuitbreaker/common"
+)
+
+// CircuitBreaker 基于成功率离群检测的熔断器
+// 每个检测周期计算服务下各实例成功率的平均值与标准差，成功率明显低于其他实例的实例会被熔断，
+// 同时被熔断的实例数不超过配置的百分比，重复被熔断的实例熔断时间按指数增长
+type CircuitBreaker struct {
+	*plugin.PluginBase
+	cfg             *Config
+	registry        localregistry.LocalRegistry
+	halfOpenHandler *common.HalfOpenConversionHandler
+	// 离群计算结果的有效期，保证同一个检测周期内只计算一次
+	evaluateInterval time.Duration
+	// 各服务的离群计算结果，key为model.ServiceKey，value为*serviceEvaluation
+	evaluations *sync.Map
+}
+
+// instanceStat 单个实例上的离群检测统计
+type instanceStat struct {
+	window *metric.SliceWindow
+	// 实例被离群熔断的次数，实例在检测周期内表现正常时逐步衰减
+	ejectionCount int32
+}
+
+// serviceEvaluation 单个服务的离群计算结果
+type serviceEvaluation struct {
+	mutex        sync.Mutex
+	evaluateTime time.Time
+	// 待熔断的离群实例ID
+	outliers map[string]bool
+}
+
+// candidate 参与离群计算的实例
+type candidate struct {
+	id           string
+	stat         *instanceStat
+	requestCount int64
+	successRate  float64
+}
+
+// Type 插件类型
+func (g *CircuitBreaker) Type() common2.Type {
+	return common2.TypeCircuitBreaker
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *CircuitBreaker) Name() string {
+	return config.DefaultCircuitBreakerOutlierDetection
+}
+
+// Init 初始化插件
+func (g *CircuitBreaker) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.cfg = ctx.Config.GetConsumer().GetCircuitBreaker().GetPluginConfig(g.Name()).(*Config)
+	registryPlugin, err := ctx.Plugins.GetPlugin(common2.TypeLocalRegistry,
+		ctx.Config.GetConsumer().GetLocalCache().GetType())
+	if err != nil {
+		return err
+	}
+	g.registry = registryPlugin.(localregistry.LocalRegistry)
+	g.halfOpenHandler = common.NewHalfOpenConversionHandler(ctx.Config)
+	g.evaluateInterval = ctx.Config.GetConsumer().GetCircuitBreaker().GetCheckPeriod() / 2
+	g.evaluations = &sync.Map{}
+	ctx.Plugins.RegisterEventSubscriber(common2.OnInstanceLocalValueCreated, common2.PluginEventHandler{
+		Callback: g.generateInstanceStat,
+	})
+	ctx.Plugins.RegisterEventSubscriber(common2.OnServiceDeleted, common2.PluginEventHandler{
+		Callback: g.onServiceDeleted,
+	})
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *CircuitBreaker) Destroy() error {
+	return nil
+}
+
+// IsEnable enable
+func (g *CircuitBreaker) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// 统计维度
+const (
+	// 总请求数
+	keyRequestCount = iota
+	// 成功数
+	keySuccessCount
+	// 总统计维度
+	maxDimension
+)
+
+var (
+	addMetricWindow = func(gauge model.InstanceGauge, bucket *metric.Bucket) int64 {
+		bucket.AddMetric(keyRequestCount, 1)
+		if gauge.GetRetStatus() == model.RetSuccess {
+			bucket.AddMetric(keySuccessCount, 1)
+		}
+		return 0
+	}
+)
+
+// 为新创建的实例生成离群检测统计
+func (g *CircuitBreaker) generateInstanceStat(event *common2.PluginEvent) error {
+	localValue := event.EventObject.(*local.DefaultInstanceLocalValue)
+	localValue.SetExtendedData(g.ID(), &instanceStat{
+		window: metric.NewSliceWindow(g.Name(), g.cfg.MetricNumBuckets, g.cfg.GetBucketInterval(),
+			maxDimension, clock.GetClock().Now().UnixNano()),
+	})
+	return nil
+}
+
+// 服务从缓存中删除时，清理该服务的离群计算结果
+func (g *CircuitBreaker) onServiceDeleted(event *common2.PluginEvent) error {
+	svcEventObject := event.EventObject.(*common2.ServiceEventObject)
+	if svcEventObject.SvcEventKey.Type != model.EventInstances {
+		return nil
+	}
+	g.evaluations.Delete(svcEventObject.SvcEventKey.ServiceKey)
+	return nil
+}
+
+// 获取实例的离群检测统计
+func (g *CircuitBreaker) getInstanceStat(instance model.Instance) *instanceStat {
+	localValue, ok := instance.(local.InstanceLocalValue)
+	if !ok {
+		return nil
+	}
+	stat, ok := localValue.GetExtendedData(g.ID()).(*instanceStat)
+	if !ok {
+		return nil
+	}
+	return stat
+}
+
+// Stat 实时上报调用结果并进行成功率统计
+func (g *CircuitBreaker) Stat(gauge model.InstanceGauge) (bool, error) {
+	instance := gauge.GetCalledInstance()
+	cbStatus := instance.GetCircuitBreakerStatus()
+	if nil != cbStatus && cbStatus.GetStatus() == model.Open {
+		// 熔断状态不进行统计
+		return false, nil
+	}
+	if nil != cbStatus && cbStatus.GetStatus() == model.HalfOpen && cbStatus.GetCircuitBreaker() == g.Name() {
+		return g.halfOpenHandler.StatHalfOpenCalls(cbStatus, gauge), nil
+	}
+	stat := g.getInstanceStat(instance)
+	if nil == stat {
+		return false, nil
+	}
+	stat.window.AddGauge(gauge, addMetricWindow)
+	return false, nil
+}
+
+// CircuitBreak 熔断计算
+// 定期或触发式进行熔断计算，返回需要进行状态转换的实例ID
+// 入参包括全量服务实例，以及当前周期的健康探测结果
+func (g *CircuitBreaker) CircuitBreak(instances []model.Instance) (*circuitbreaker.Result, error) {
+	result := circuitbreaker.NewCircuitBreakerResult(clock.GetClock().Now())
+	for _, instance := range instances {
+		stat := g.getInstanceStat(instance)
+		if nil == stat {
+			continue
+		}
+		if g.closeToOpen(instance, result.Now) {
+			ejectionCount := atomic.AddInt32(&stat.ejectionCount, 1)
+			log.GetDetectLogger().Warnf("%s: close to open, instance(id=%s, address=%s:%d), ejection count %d",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort(), ejectionCount)
+			result.InstancesToOpen.Add(instance.GetId())
+			continue
+		}
+		if g.halfOpenHandler.OpenToHalfOpenAfter(instance, result.Now, g.Name(), g.getEjectionTime(stat)) {
+			log.GetDetectLogger().Infof("%s: open to halfOpen, instance(id=%s, address=%s:%d)",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort())
+			result.InstancesToHalfOpen.Add(instance.GetId())
+			continue
+		}
+		switch g.halfOpenHandler.HalfOpenConversion(result.Now, instance, g.Name()) {
+		case common.ToOpen:
+			ejectionCount := atomic.AddInt32(&stat.ejectionCount, 1)
+			log.GetDetectLogger().Warnf("%s: halfOpen to open, instance(id=%s, address=%s:%d), ejection count %d",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort(), ejectionCount)
+			result.InstancesToOpen.Add(instance.GetId())
+		case common.ToClose:
+			log.GetDetectLogger().Infof("%s: halfOpen to close, instance(id=%s, address=%s:%d)",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort())
+			result.InstancesToClose.Add(instance.GetId())
+		}
+	}
+	if result.IsEmpty() {
+		return nil, nil
+	}
+	result.RequestCountAfterHalfOpen = g.halfOpenHandler.GetRequestCountAfterHalfOpen()
+	return result, nil
+}
+
+// getEjectionTime 获取实例本次熔断的持续时间
+func (g *CircuitBreaker) getEjectionTime(stat *instanceStat) time.Duration {
+	return calcEjectionTime(g.cfg.GetBaseEjectionTime(), g.cfg.GetMaxEjectionTime(),
+		atomic.LoadInt32(&stat.ejectionCount))
+}
+
+// closeToOpen 熔断器从关闭到打开，离群实例在每次计算结果中只会被取出一次
+func (g *CircuitBreaker) closeToOpen(instance model.Instance, now time.Time) bool {
+	cbStatus := instance.GetCircuitBreakerStatus()
+	if nil != cbStatus && cbStatus.GetStatus() != model.Close {
+		return false
+	}
+	svcKey := model.ServiceKey{Namespace: instance.GetNamespace(), Service: instance.GetService()}
+	value, _ := g.evaluations.LoadOrStore(svcKey, &serviceEvaluation{})
+	evaluation := value.(*serviceEvaluation)
+	evaluation.mutex.Lock()
+	defer evaluation.mutex.Unlock()
+	if evaluation.evaluateTime.IsZero() || now.Sub(evaluation.evaluateTime) >= g.evaluateInterval {
+		evaluation.evaluateTime = now
+		evaluation.outliers = g.detectOutliers(&svcKey, now)
+	}
+	if !evaluation.outliers[instance.GetId()] {
+		return false
+	}
+	delete(evaluation.outliers, instance.GetId())
+	return true
+}
+
+// detectOutliers 对服务下的全部实例进行离群计算，返回需要熔断的实例ID
+func (g *CircuitBreaker) detectOutliers(svcKey *model.ServiceKey, now time.Time) map[string]bool {
+	svcInstances := g.registry.GetInstances(svcKey, false, true)
+	if !svcInstances.IsInitialized() {
+		return nil
+	}
+	allInstances := svcInstances.GetInstances()
+	timeRange := &metric.TimeRange{
+		Start: now.Add(0 - g.cfg.GetMetricStatTimeWindow()),
+		End:   now.Add(g.cfg.GetBucketInterval()),
+	}
+	var ejectedCount int
+	candidates := make([]*candidate, 0, len(allInstances))
+	for _, instance := range allInstances {
+		cbStatus := instance.GetCircuitBreakerStatus()
+		if nil != cbStatus && cbStatus.GetStatus() != model.Close {
+			if cbStatus.GetCircuitBreaker() == g.Name() {
+				ejectedCount++
+			}
+			continue
+		}
+		stat := g.getInstanceStat(instance)
+		if nil == stat {
+			continue
+		}
+		values := stat.window.CalcMetricsInMultiDimensions([]int{keyRequestCount, keySuccessCount}, timeRange)
+		c := &candidate{id: instance.GetId(), stat: stat, requestCount: values[0]}
+		if values[0] > 0 {
+			c.successRate = float64(values[1]) / float64(values[0])
+		}
+		candidates = append(candidates, c)
+	}
+	samples := make([]*candidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.requestCount >= int64(g.cfg.RequestVolumeThreshold) {
+			samples = append(samples, c)
+		}
+	}
+	var outliers map[string]bool
+	if len(samples) >= g.cfg.MinimumHosts {
+		maxEjections := calcMaxEjections(len(allInstances), g.cfg.MaxEjectionPercent) - ejectedCount
+		outliers = selectOutliers(samples, g.cfg.StdevFactor, maxEjections)
+	}
+	// 本周期内表现正常的实例，熔断次数逐步衰减
+	for _, c := range candidates {
+		if !outliers[c.id] {
+			decreaseEjectionCount(c.stat)
+		}
+	}
+	if len(outliers) > 0 {
+		log.GetDetectLogger().Infof("%s: detect outliers %v for service %s, sample count %d, ejected count %d",
+			g.Name(), outliers, *svcKey, len(samples), ejectedCount)
+	}
+	return outliers
+}
+
+// decreaseEjectionCount 熔断次数减一，最小为0
+func decreaseEjectionCount(stat *instanceStat) {
+	for {
+		count := atomic.LoadInt32(&stat.ejectionCount)
+		if count <= 0 || atomic.CompareAndSwapInt32(&stat.ejectionCount, count, count-1) {
+			return
+		}
+	}
+}
+
+// calcMaxEjections 计算服务下允许同时熔断的实例数，至少允许熔断一个实例
+func calcMaxEjections(totalCount int, maxEjectionPercent int) int {
+	maxEjections := totalCount * maxEjectionPercent / 100
+	if maxEjections < 1 {
+		return 1
+	}
+	return maxEjections
+}
+
+// selectOutliers 选出成功率低于(平均值 - 标准差 * 因子)的实例，成功率越低越优先熔断，最多选出maxEjections个
+func selectOutliers(samples []*candidate, stdevFactor float64, maxEjections int) map[string]bool {
+	if maxEjections <= 0 || len(samples) == 0 {
+		return nil
+	}
+	var sum float64
+	for _, sample := range samples {
+		sum += sample.successRate
+	}
+	mean := sum / float64(len(samples))
+	var variance float64
+	for _, sample := range samples {
+		variance += (sample.successRate - mean) * (sample.successRate - mean)
+	}
+	stdev := math.Sqrt(variance / float64(len(samples)))
+	threshold := mean - stdev*stdevFactor
+	outliers := make([]*candidate, 0)
+	for _, sample := range samples {
+		if sample.successRate < threshold {
+			outliers = append(outliers, sample)
+		}
+	}
+	sort.SliceStable(outliers, func(i, j int) bool {
+		return outliers[i].successRate < outliers[j].successRate
+	})
+	if len(outliers) > maxEjections {
+		outliers = outliers[:maxEjections]
+	}
+	result := make(map[string]bool, len(outliers))
+	for _, outlier := range outliers {
+		result[outlier.id] = true
+	}
+	return result
+}
+
+// calcEjectionTime 计算熔断持续时间，第n次熔断的持续时间为基础时间的2^(n-1)倍，不超过最大熔断时间
+func calcEjectionTime(
+	baseEjectionTime time.Duration, maxEjectionTime time.Duration, ejectionCount int32) time.Duration {
+	ejectionTime := baseEjectionTime
+	for i := int32(1); i < ejectionCount && ejectionTime < maxEjectionTime; i++ {
+		ejectionTime *= 2
+	}
+	if ejectionTime > maxEjectionTime {
+		return maxEjectionTime
+	}
+	return ejectionTime
+}
+
+// init 插件注册
+func init() {
+	plugin.RegisterConfigurablePlugin(&CircuitBreaker{}, &Config{})
+}
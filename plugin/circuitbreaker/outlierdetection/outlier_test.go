@@ -0,0 +1,73 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package outlierdetection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSamples(successRates map[string]float64) []*candidate {
+	samples := make([]*candidate, 0, len(successRates))
+	for id, successRate := range successRates {
+		samples = append(samples, &candidate{id: id, stat: &instanceStat{}, requestCount: 100, successRate: successRate})
+	}
+	return samples
+}
+
+// TestSelectOutliers 测试按照成功率的平均值与标准差选出离群实例
+func TestSelectOutliers(t *testing.T) {
+	samples := newSamples(map[string]float64{
+		"a": 0.99, "b": 0.98, "c": 0.99, "d": 1, "e": 0.97, "f": 0.99, "g": 0.98, "h": 0.99, "i": 0.5, "j": 0.2})
+	// 成功率最低的实例优先熔断
+	assert.Equal(t, map[string]bool{"j": true}, selectOutliers(samples, 1, 1))
+	assert.Equal(t, map[string]bool{"i": true, "j": true}, selectOutliers(samples, 1, 5))
+	// 因子越大越难被判定为离群实例
+	assert.Equal(t, map[string]bool{"j": true}, selectOutliers(samples, 1.9, 5))
+	assert.Empty(t, selectOutliers(samples, 1, 0))
+
+	// 服务整体劣化时，成功率相近的实例都不会被熔断
+	degraded := newSamples(map[string]float64{"a": 0.3, "b": 0.31, "c": 0.29, "d": 0.3, "e": 0.3})
+	assert.Empty(t, selectOutliers(degraded, 1.9, 5))
+}
+
+// TestCalcMaxEjections 测试最大熔断实例数计算
+func TestCalcMaxEjections(t *testing.T) {
+	assert.Equal(t, 1, calcMaxEjections(5, 10))
+	assert.Equal(t, 2, calcMaxEjections(20, 10))
+	assert.Equal(t, 10, calcMaxEjections(20, 50))
+}
+
+// TestCalcEjectionTime 测试重复熔断时熔断时间指数增长
+func TestCalcEjectionTime(t *testing.T) {
+	base := 30 * time.Second
+	maxTime := 300 * time.Second
+	assert.Equal(t, base, calcEjectionTime(base, maxTime, 0))
+	assert.Equal(t, base, calcEjectionTime(base, maxTime, 1))
+	assert.Equal(t, 60*time.Second, calcEjectionTime(base, maxTime, 2))
+	assert.Equal(t, 120*time.Second, calcEjectionTime(base, maxTime, 3))
+	assert.Equal(t, maxTime, calcEjectionTime(base, maxTime, 5))
+	assert.Equal(t, maxTime, calcEjectionTime(base, maxTime, 100))
+
+	stat := &instanceStat{ejectionCount: 1}
+	decreaseEjectionCount(stat)
+	decreaseEjectionCount(stat)
+	assert.Equal(t, int32(0), stat.ejectionCount)
+}
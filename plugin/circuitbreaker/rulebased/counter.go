@@ -0,0 +1,249 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package rulebased
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/metric"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+)
+
+// 错误率统计滑窗的分桶数
+const errRateBucketCount = 10
+
+// 统计维度
+const (
+	// 总请求数
+	keyRequestCount = iota
+	// 错误数
+	keyFailCount
+	// 总统计维度
+	maxDimension
+)
+
+var addMetricWindow = func(isError bool) metric.AddBucketFunc {
+	return func(gauge model.InstanceGauge, bucket *metric.Bucket) int64 {
+		bucket.AddMetric(keyRequestCount, 1)
+		if isError {
+			bucket.AddMetric(keyFailCount, 1)
+		}
+		return 0
+	}
+}
+
+// ruleCounter 单条熔断规则在单个资源（服务、接口或实例）上的统计数据以及熔断状态
+type ruleCounter struct {
+	mutex sync.Mutex
+	// 统计所依据的熔断规则
+	rule *apifault.CircuitBreakerRule
+	// 规则的错误率统计滑窗，下标与规则的触发条件一致，连续错误条件对应nil
+	windows []*metric.SliceWindow
+	// 连续错误数
+	consecutiveErrors uint32
+	// 是否已经达到熔断条件
+	triggered bool
+	// 熔断状态，仅用于服务及接口级熔断，实例级熔断状态保存在实例上
+	status model.Status
+	// 熔断状态开始时间
+	statusTime time.Time
+	// 半开后的连续成功数
+	halfOpenSuccess uint32
+}
+
+// newRuleCounter 创建规则统计对象
+func newRuleCounter(rule *apifault.CircuitBreakerRule) *ruleCounter {
+	counter := &ruleCounter{
+		rule:   rule,
+		status: model.Close,
+	}
+	counter.resetWindows()
+	return counter
+}
+
+// resetWindows 重建统计滑窗
+func (c *ruleCounter) resetWindows() {
+	now := clock.GetClock().Now().UnixNano()
+	c.windows = make([]*metric.SliceWindow, len(c.rule.GetTriggerCondition()))
+	for i, trigger := range c.rule.GetTriggerCondition() {
+		if trigger.GetTriggerType() != apifault.TriggerCondition_ERROR_RATE {
+			continue
+		}
+		interval := time.Duration(trigger.GetInterval()) * time.Second
+		c.windows[i] = metric.NewSliceWindow(c.rule.GetName(), errRateBucketCount,
+			interval/errRateBucketCount, maxDimension, now)
+	}
+	c.consecutiveErrors = 0
+	c.triggered = false
+}
+
+// isSameRule 判断规则是否已经发生变更
+func (c *ruleCounter) isSameRule(rule *apifault.CircuitBreakerRule) bool {
+	return c.rule.GetId() == rule.GetId() && c.rule.GetRevision() == rule.GetRevision()
+}
+
+// getSleepWindow 获取熔断后进入半开状态的等待时间
+func (c *ruleCounter) getSleepWindow() time.Duration {
+	return time.Duration(c.rule.GetRecoverCondition().GetSleepWindow()) * time.Second
+}
+
+// getConsecutiveSuccess 获取半开后恢复所需的连续成功数
+func (c *ruleCounter) getConsecutiveSuccess() uint32 {
+	return c.rule.GetRecoverCondition().GetConsecutiveSuccess()
+}
+
+// stat 统计一次调用，返回本次调用是否使得规则首次达到熔断条件
+func (c *ruleCounter) stat(gauge model.InstanceGauge, isError bool, now time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.statLocked(gauge, isError, now)
+}
+
+func (c *ruleCounter) statLocked(gauge model.InstanceGauge, isError bool, now time.Time) bool {
+	if isError {
+		c.consecutiveErrors++
+	} else {
+		c.consecutiveErrors = 0
+	}
+	var matched bool
+	for i, trigger := range c.rule.GetTriggerCondition() {
+		switch trigger.GetTriggerType() {
+		case apifault.TriggerCondition_CONSECUTIVE_ERROR:
+			if c.consecutiveErrors >= trigger.GetErrorCount() {
+				matched = true
+			}
+		case apifault.TriggerCondition_ERROR_RATE:
+			window := c.windows[i]
+			window.AddGauge(gauge, addMetricWindow(isError))
+			timeRange := &metric.TimeRange{
+				Start: now.Add(-time.Duration(trigger.GetInterval()) * time.Second),
+				End:   now.Add(window.GetBucketInterval()),
+			}
+			values := window.CalcMetricsInMultiDimensions([]int{keyRequestCount, keyFailCount}, timeRange)
+			reqCount, failCount := values[0], values[1]
+			if reqCount > 0 && reqCount >= int64(trigger.GetMinimumRequest()) &&
+				failCount*100 >= int64(trigger.GetErrorPercent())*reqCount {
+				matched = true
+			}
+		}
+	}
+	if !matched || c.triggered {
+		return false
+	}
+	c.triggered = true
+	return true
+}
+
+// takeTriggered 获取并清理熔断条件的触发标识，熔断后重新开始统计
+func (c *ruleCounter) takeTriggered() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.triggered {
+		return false
+	}
+	c.resetWindows()
+	return true
+}
+
+// statResource 统计服务或接口级的调用，并进行熔断状态转换，返回状态是否发生变更
+func (c *ruleCounter) statResource(gauge model.InstanceGauge, isError bool, now time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tryHalfOpenLocked(now)
+	switch c.status {
+	case model.Open:
+		return false
+	case model.HalfOpen:
+		if isError {
+			c.toStatusLocked(model.Open, now)
+			return true
+		}
+		c.halfOpenSuccess++
+		if c.halfOpenSuccess >= c.getConsecutiveSuccess() {
+			c.toStatusLocked(model.Close, now)
+			return true
+		}
+		return false
+	default:
+		if c.statLocked(gauge, isError, now) {
+			c.toStatusLocked(model.Open, now)
+			return true
+		}
+		return false
+	}
+}
+
+// allow 判断服务或接口是否可以被调用
+func (c *ruleCounter) allow(now time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tryHalfOpenLocked(now)
+	return c.status != model.Open
+}
+
+// getStatus 获取服务或接口级熔断状态
+func (c *ruleCounter) getStatus() model.Status {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.status
+}
+
+// tryHalfOpenLocked 熔断等待时间过后转换为半开状态
+func (c *ruleCounter) tryHalfOpenLocked(now time.Time) {
+	if c.status == model.Open && now.Sub(c.statusTime) >= c.getSleepWindow() {
+		c.toStatusLocked(model.HalfOpen, now)
+	}
+}
+
+func (c *ruleCounter) toStatusLocked(status model.Status, now time.Time) {
+	c.status = status
+	c.statusTime = now
+	c.halfOpenSuccess = 0
+	c.resetWindows()
+}
+
+// isErrorCall 根据规则的错误判断条件，判断本次调用是否为错误调用
+func isErrorCall(rule *apifault.CircuitBreakerRule, gauge model.InstanceGauge, ruleCache model.RuleCache) bool {
+	if gauge.GetRetStatus() == model.RetFail || gauge.GetRetStatus() == model.RetTimeout {
+		return true
+	}
+	for _, errCondition := range rule.GetErrorConditions() {
+		switch errCondition.GetInputType() {
+		case apifault.ErrorCondition_RET_CODE:
+			retCode := strconv.Itoa(int(gauge.GetRetCodeValue()))
+			if pb.MatchStringValue(errCondition.GetCondition(), retCode, ruleCache) {
+				return true
+			}
+		case apifault.ErrorCondition_DELAY:
+			threshold, err := strconv.Atoi(errCondition.GetCondition().GetValue().GetValue())
+			if err != nil || nil == gauge.GetDelay() {
+				continue
+			}
+			if *gauge.GetDelay() >= time.Duration(threshold)*time.Millisecond {
+				return true
+			}
+		}
+	}
+	return false
+}
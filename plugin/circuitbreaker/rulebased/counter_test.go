@@ -0,0 +1,94 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package rulebased
+
+import (
+	"testing"
+	"time"
+
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+func newTestRule(trigger *apifault.TriggerCondition) *apifault.CircuitBreakerRule {
+	return &apifault.CircuitBreakerRule{
+		Id:               "rule-1",
+		Name:             "rule-1",
+		Enable:           true,
+		Level:            apifault.Level_SERVICE,
+		TriggerCondition: []*apifault.TriggerCondition{trigger},
+		RecoverCondition: &apifault.RecoverCondition{SleepWindow: 1, ConsecutiveSuccess: 2},
+	}
+}
+
+func newTestGauge(status model.RetStatus) *model.ServiceCallResult {
+	return &model.ServiceCallResult{RetStatus: status}
+}
+
+// TestConsecutiveErrorTrigger 测试连续错误数达到阈值时触发熔断，且只触发一次
+func TestConsecutiveErrorTrigger(t *testing.T) {
+	counter := newRuleCounter(newTestRule(&apifault.TriggerCondition{
+		TriggerType: apifault.TriggerCondition_CONSECUTIVE_ERROR,
+		ErrorCount:  3,
+	}))
+	now := time.Now()
+	var triggered int
+	for i := 0; i < 5; i++ {
+		if counter.stat(newTestGauge(model.RetFail), true, now) {
+			triggered++
+		}
+		if i == 0 && counter.stat(newTestGauge(model.RetSuccess), false, now) {
+			t.Fatalf("success call should not trigger circuitbreaker")
+		}
+	}
+	if triggered != 1 {
+		t.Fatalf("triggered %d times, expect 1", triggered)
+	}
+	if !counter.takeTriggered() || counter.takeTriggered() {
+		t.Fatalf("triggered flag should be taken only once")
+	}
+}
+
+// TestResourceStatusConversion 测试服务级熔断的打开、半开以及恢复
+func TestResourceStatusConversion(t *testing.T) {
+	counter := newRuleCounter(newTestRule(&apifault.TriggerCondition{
+		TriggerType: apifault.TriggerCondition_CONSECUTIVE_ERROR,
+		ErrorCount:  2,
+	}))
+	now := time.Now()
+	counter.statResource(newTestGauge(model.RetFail), true, now)
+	if !counter.statResource(newTestGauge(model.RetFail), true, now) || counter.getStatus() != model.Open {
+		t.Fatalf("status should be open, now is %v", counter.getStatus())
+	}
+	if counter.allow(now) {
+		t.Fatalf("resource should not be allowed when circuitbreaker is open")
+	}
+	now = now.Add(time.Second)
+	if !counter.allow(now) || counter.getStatus() != model.HalfOpen {
+		t.Fatalf("status should be halfOpen after sleep window, now is %v", counter.getStatus())
+	}
+	counter.statResource(newTestGauge(model.RetSuccess), false, now)
+	if counter.getStatus() != model.HalfOpen {
+		t.Fatalf("status should keep halfOpen before consecutive success reached")
+	}
+	counter.statResource(newTestGauge(model.RetSuccess), false, now)
+	if counter.getStatus() != model.Close {
+		t.Fatalf("status should be close, now is %v", counter.getStatus())
+	}
+}
@@ -0,0 +1,408 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package rulebased
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modern-go/reflect2"
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+	common2 "github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/localregistry"
+	"github.com/polarismesh/polaris-go/plugin/circuitbreaker/common"
+)
+
+// CircuitBreaker 基于服务端下发熔断规则的熔断器，支持服务、接口以及实例级熔断
+type CircuitBreaker struct {
+	*plugin.PluginBase
+	registry        localregistry.LocalRegistry
+	halfOpenHandler *common.HalfOpenConversionHandler
+	// 服务及接口级熔断统计，key为model.ServiceKey，value为*resourceCounters
+	resourceCounters *sync.Map
+}
+
+// resourceCounters 单个服务下的服务及接口级熔断统计
+type resourceCounters struct {
+	mutex    sync.RWMutex
+	counters map[resourceKey]*ruleCounter
+}
+
+// resourceKey 服务及接口级熔断资源的唯一标识
+type resourceKey struct {
+	ruleID string
+	level  apifault.Level
+	method string
+}
+
+// instanceCounters 单个实例上的实例级熔断统计
+type instanceCounters struct {
+	mutex sync.Mutex
+	// 各规则的统计，key为规则ID
+	counters map[string]*ruleCounter
+	// 触发实例熔断的规则ID
+	openRuleID string
+}
+
+// Type 插件类型
+func (g *CircuitBreaker) Type() common2.Type {
+	return common2.TypeCircuitBreaker
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *CircuitBreaker) Name() string {
+	return config.DefaultCircuitBreakerRuleBased
+}
+
+// Init 初始化插件
+func (g *CircuitBreaker) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	registryPlugin, err := ctx.Plugins.GetPlugin(common2.TypeLocalRegistry,
+		ctx.Config.GetConsumer().GetLocalCache().GetType())
+	if err != nil {
+		return err
+	}
+	g.registry = registryPlugin.(localregistry.LocalRegistry)
+	g.halfOpenHandler = common.NewHalfOpenConversionHandler(ctx.Config)
+	g.resourceCounters = &sync.Map{}
+	ctx.Plugins.RegisterEventSubscriber(common2.OnInstanceLocalValueCreated, common2.PluginEventHandler{
+		Callback: g.generateInstanceCounters,
+	})
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *CircuitBreaker) Destroy() error {
+	return nil
+}
+
+// IsEnable enable
+func (g *CircuitBreaker) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+// 为新创建的实例生成熔断统计
+func (g *CircuitBreaker) generateInstanceCounters(event *common2.PluginEvent) error {
+	localValue := event.EventObject.(*local.DefaultInstanceLocalValue)
+	localValue.SetExtendedData(g.ID(), &instanceCounters{counters: make(map[string]*ruleCounter)})
+	return nil
+}
+
+// 获取实例的熔断统计
+func (g *CircuitBreaker) getInstanceCounters(instance model.Instance) *instanceCounters {
+	localValue, ok := instance.(local.InstanceLocalValue)
+	if !ok {
+		return nil
+	}
+	counters, ok := localValue.GetExtendedData(g.ID()).(*instanceCounters)
+	if !ok {
+		return nil
+	}
+	return counters
+}
+
+// getServiceRule 获取服务的熔断规则，规则未加载时发起异步加载
+func (g *CircuitBreaker) getServiceRule(svcKey *model.ServiceKey) (*apifault.CircuitBreaker, model.RuleCache) {
+	svcRule := g.registry.GetServiceCircuitBreakerRule(svcKey, true)
+	if !svcRule.IsInitialized() {
+		if _, err := g.registry.LoadServiceCircuitBreakerRule(svcKey); err != nil {
+			log.GetBaseLogger().Errorf("%s: fail to load circuitbreaker rule for %s, error: %v",
+				g.Name(), *svcKey, err)
+		}
+		return nil, nil
+	}
+	if reflect2.IsNil(svcRule.GetValue()) || nil != svcRule.GetValidateError() {
+		return nil, nil
+	}
+	return svcRule.GetValue().(*apifault.CircuitBreaker), svcRule.GetRuleCache()
+}
+
+// getCallInfo 获取调用的接口以及主调服务
+func getCallInfo(gauge model.InstanceGauge) (string, *model.ServiceInfo) {
+	callResult, ok := gauge.(*model.ServiceCallResult)
+	if !ok {
+		return "", nil
+	}
+	return callResult.Method, callResult.SourceService
+}
+
+// matchRule 判断调用是否命中熔断规则
+func matchRule(rule *apifault.CircuitBreakerRule, svcKey *model.ServiceKey, method string,
+	caller *model.ServiceInfo, ruleCache model.RuleCache) bool {
+	if !rule.GetEnable() {
+		return false
+	}
+	destination := rule.GetRuleMatcher().GetDestination()
+	if !matchName(destination.GetNamespace(), svcKey.Namespace) ||
+		!matchName(destination.GetService(), svcKey.Service) {
+		return false
+	}
+	source := rule.GetRuleMatcher().GetSource()
+	var callerNamespace, callerService string
+	if nil != caller {
+		callerNamespace, callerService = caller.Namespace, caller.Service
+	}
+	if !matchName(source.GetNamespace(), callerNamespace) || !matchName(source.GetService(), callerService) {
+		return false
+	}
+	if rule.GetLevel() == apifault.Level_SERVICE {
+		return true
+	}
+	return pb.MatchStringValue(destination.GetMethod(), method, ruleCache)
+}
+
+// matchName 匹配服务名或命名空间，空值和*代表全部匹配
+func matchName(ruleValue string, value string) bool {
+	return len(ruleValue) == 0 || ruleValue == pb.MatchAll || ruleValue == value
+}
+
+// Stat 进行调用统计，返回当前实例是否需要进行立即熔断
+func (g *CircuitBreaker) Stat(gauge model.InstanceGauge) (bool, error) {
+	svcKey := &model.ServiceKey{Namespace: gauge.GetNamespace(), Service: gauge.GetService()}
+	cbValue, ruleCache := g.getServiceRule(svcKey)
+	if nil == cbValue {
+		return false, nil
+	}
+	method, caller := getCallInfo(gauge)
+	now := clock.GetClock().Now()
+	var needInstanceBreak bool
+	matchedLevels := make(map[apifault.Level]bool)
+	for _, rule := range cbValue.GetRules() {
+		level := rule.GetLevel()
+		if matchedLevels[level] || !matchRule(rule, svcKey, method, caller, ruleCache) {
+			continue
+		}
+		// 同一级别只使用最精确匹配的规则
+		matchedLevels[level] = true
+		isError := isErrorCall(rule, gauge, ruleCache)
+		switch level {
+		case apifault.Level_SERVICE, apifault.Level_METHOD:
+			g.statResource(svcKey, rule, method, gauge, isError, now)
+		case apifault.Level_INSTANCE:
+			needInstanceBreak = g.statInstance(rule, gauge, isError, now) || needInstanceBreak
+		default:
+			log.GetBaseLogger().Debugf("%s: circuitbreaker level %v in rule %s is not supported",
+				g.Name(), level, rule.GetName())
+		}
+	}
+	return needInstanceBreak, nil
+}
+
+// statResource 统计服务及接口级熔断
+func (g *CircuitBreaker) statResource(svcKey *model.ServiceKey, rule *apifault.CircuitBreakerRule, method string,
+	gauge model.InstanceGauge, isError bool, now time.Time) {
+	key := resourceKey{ruleID: rule.GetId(), level: rule.GetLevel()}
+	if rule.GetLevel() == apifault.Level_METHOD {
+		key.method = method
+	}
+	value, _ := g.resourceCounters.LoadOrStore(*svcKey, &resourceCounters{
+		counters: make(map[resourceKey]*ruleCounter)})
+	svcCounters := value.(*resourceCounters)
+	svcCounters.mutex.Lock()
+	counter, ok := svcCounters.counters[key]
+	if !ok || !counter.isSameRule(rule) {
+		counter = newRuleCounter(rule)
+		svcCounters.counters[key] = counter
+	}
+	svcCounters.mutex.Unlock()
+	if counter.statResource(gauge, isError, now) {
+		log.GetDetectLogger().Infof("%s: resource(service=%s, namespace=%s, method=%s, level=%v) "+
+			"circuitbreaker status changed to %v by rule %s", g.Name(), svcKey.Service, svcKey.Namespace,
+			key.method, key.level, counter.getStatus(), rule.GetName())
+	}
+}
+
+// statInstance 统计实例级熔断，返回实例是否需要进行立即熔断
+func (g *CircuitBreaker) statInstance(
+	rule *apifault.CircuitBreakerRule, gauge model.InstanceGauge, isError bool, now time.Time) bool {
+	instance := gauge.GetCalledInstance()
+	if reflect2.IsNil(instance) {
+		return false
+	}
+	cbStatus := instance.GetCircuitBreakerStatus()
+	if nil != cbStatus && cbStatus.GetStatus() == model.Open {
+		// 熔断状态不进行统计
+		return false
+	}
+	if nil != cbStatus && cbStatus.GetStatus() == model.HalfOpen {
+		if cbStatus.GetCircuitBreaker() != g.Name() {
+			return false
+		}
+		return g.halfOpenHandler.StatHalfOpenCalls(cbStatus, gauge)
+	}
+	counters := g.getInstanceCounters(instance)
+	if nil == counters {
+		return false
+	}
+	counters.mutex.Lock()
+	counter, ok := counters.counters[rule.GetId()]
+	if !ok || !counter.isSameRule(rule) {
+		counter = newRuleCounter(rule)
+		counters.counters[rule.GetId()] = counter
+	}
+	counters.mutex.Unlock()
+	if counter.stat(gauge, isError, now) {
+		log.GetBaseLogger().Infof("instance(service=%s, namespace=%s, host=%s, port=%d, instanceId=%s) "+
+			"stat trigger circuitbreaker rule %s", gauge.GetService(), gauge.GetNamespace(),
+			instance.GetHost(), instance.GetPort(), instance.GetId(), rule.GetName())
+		return true
+	}
+	return false
+}
+
+// CheckResource 判断服务或者接口是否可以被调用，熔断器打开时返回错误
+func (g *CircuitBreaker) CheckResource(svcKey *model.ServiceKey, method string) error {
+	value, ok := g.resourceCounters.Load(*svcKey)
+	if !ok {
+		return nil
+	}
+	svcCounters := value.(*resourceCounters)
+	validRules := g.getValidRules(svcKey)
+	now := clock.GetClock().Now()
+	svcCounters.mutex.Lock()
+	defer svcCounters.mutex.Unlock()
+	for key, counter := range svcCounters.counters {
+		if rule, ok := validRules[key.ruleID]; !ok || !counter.isSameRule(rule) {
+			// 规则已经被删除或者变更，清理统计数据
+			delete(svcCounters.counters, key)
+			continue
+		}
+		if key.level == apifault.Level_METHOD && key.method != method {
+			continue
+		}
+		if !counter.allow(now) {
+			return fmt.Errorf("%s: resource(service=%s, namespace=%s, method=%s) is broken by rule %s",
+				g.Name(), svcKey.Service, svcKey.Namespace, key.method, counter.rule.GetName())
+		}
+	}
+	return nil
+}
+
+// getValidRules 获取服务当前生效的熔断规则
+func (g *CircuitBreaker) getValidRules(svcKey *model.ServiceKey) map[string]*apifault.CircuitBreakerRule {
+	rules := make(map[string]*apifault.CircuitBreakerRule)
+	cbValue, _ := g.getServiceRule(svcKey)
+	for _, rule := range cbValue.GetRules() {
+		if rule.GetEnable() {
+			rules[rule.GetId()] = rule
+		}
+	}
+	return rules
+}
+
+// CircuitBreak 熔断计算
+// 定期或触发式进行熔断计算，返回需要进行状态转换的实例ID
+// 入参包括全量服务实例，以及当前周期的健康探测结果
+func (g *CircuitBreaker) CircuitBreak(instances []model.Instance) (*circuitbreaker.Result, error) {
+	result := circuitbreaker.NewCircuitBreakerResult(clock.GetClock().Now())
+	result.RequestCountAfterHalfOpen = g.halfOpenHandler.GetRequestCountAfterHalfOpen()
+	for _, instance := range instances {
+		counters := g.getInstanceCounters(instance)
+		if nil == counters {
+			continue
+		}
+		if counter := g.closeToOpen(instance, counters); nil != counter {
+			log.GetDetectLogger().Warnf("%s: close to open, instance(id=%s, address=%s:%d) by rule %s",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort(), counter.rule.GetName())
+			result.InstancesToOpen.Add(instance.GetId())
+			continue
+		}
+		if g.openToHalfOpen(instance, counters, result.Now) {
+			log.GetDetectLogger().Infof("%s: open to halfOpen, instance(id=%s, address=%s:%d)",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort())
+			result.InstancesToHalfOpen.Add(instance.GetId())
+			result.SetInstanceRequestCountAfterHalfOpen(instance.GetId(), g.getRequestCountAfterHalfOpen(counters))
+			continue
+		}
+		switch g.halfOpenHandler.HalfOpenConversion(result.Now, instance, g.Name()) {
+		case common.ToOpen:
+			log.GetDetectLogger().Warnf("%s: halfOpen to open, instance(id=%s, address=%s:%d)",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort())
+			result.InstancesToOpen.Add(instance.GetId())
+		case common.ToClose:
+			log.GetDetectLogger().Infof("%s: halfOpen to close, instance(id=%s, address=%s:%d)",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort())
+			result.InstancesToClose.Add(instance.GetId())
+		}
+	}
+	if result.IsEmpty() {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// closeToOpen 熔断器从关闭到打开，返回触发熔断的规则统计
+func (g *CircuitBreaker) closeToOpen(instance model.Instance, counters *instanceCounters) *ruleCounter {
+	cbStatus := instance.GetCircuitBreakerStatus()
+	if nil != cbStatus && cbStatus.GetStatus() != model.Close {
+		return nil
+	}
+	counters.mutex.Lock()
+	defer counters.mutex.Unlock()
+	for ruleID, counter := range counters.counters {
+		if counter.takeTriggered() {
+			counters.openRuleID = ruleID
+			return counter
+		}
+	}
+	return nil
+}
+
+// openToHalfOpen 熔断器从打开到半开，等待时间以触发熔断的规则为准
+func (g *CircuitBreaker) openToHalfOpen(instance model.Instance, counters *instanceCounters, now time.Time) bool {
+	cbStatus := instance.GetCircuitBreakerStatus()
+	if nil == cbStatus || cbStatus.GetCircuitBreaker() != g.Name() || cbStatus.GetStatus() != model.Open {
+		return false
+	}
+	counters.mutex.Lock()
+	counter, ok := counters.counters[counters.openRuleID]
+	counters.mutex.Unlock()
+	if !ok {
+		// 规则已经变更，使用全局的熔断配置进行恢复
+		return g.halfOpenHandler.OpenToHalfOpen(instance, now, g.Name())
+	}
+	if !instance.IsHealthy() || instance.IsIsolated() {
+		return false
+	}
+	return now.Sub(cbStatus.GetStartTime()) >= counter.getSleepWindow()
+}
+
+// getRequestCountAfterHalfOpen 获取实例半开后允许的请求数，以触发熔断的规则为准
+func (g *CircuitBreaker) getRequestCountAfterHalfOpen(counters *instanceCounters) int {
+	counters.mutex.Lock()
+	defer counters.mutex.Unlock()
+	if counter, ok := counters.counters[counters.openRuleID]; ok && counter.getConsecutiveSuccess() > 0 {
+		return int(counter.getConsecutiveSuccess())
+	}
+	return g.halfOpenHandler.GetRequestCountAfterHalfOpen()
+}
+
+// init 插件注册
+func init() {
+	plugin.RegisterPlugin(&CircuitBreaker{})
+}
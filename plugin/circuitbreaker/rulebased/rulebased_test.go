@@ -0,0 +1,100 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package rulebased
+
+import (
+	"testing"
+	"time"
+
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+)
+
+func newMatchRule(level apifault.Level, callerService string, method string) *apifault.CircuitBreakerRule {
+	rule := newTestRule(&apifault.TriggerCondition{
+		TriggerType: apifault.TriggerCondition_CONSECUTIVE_ERROR,
+		ErrorCount:  3,
+	})
+	rule.Level = level
+	rule.RuleMatcher = &apifault.RuleMatcher{
+		Source: &apifault.RuleMatcher_SourceService{Namespace: "*", Service: callerService},
+		Destination: &apifault.RuleMatcher_DestinationService{
+			Namespace: "default",
+			Service:   "echo",
+			Method: &apimodel.MatchString{
+				Type:  apimodel.MatchString_EXACT,
+				Value: wrapperspb.String(method),
+			},
+		},
+	}
+	return rule
+}
+
+// TestMatchRule 测试熔断规则对被调服务、主调服务以及接口的匹配
+func TestMatchRule(t *testing.T) {
+	svcKey := &model.ServiceKey{Namespace: "default", Service: "echo"}
+	caller := &model.ServiceInfo{Namespace: "test", Service: "caller"}
+
+	rule := newMatchRule(apifault.Level_METHOD, "caller", "/hello")
+	assert.True(t, matchRule(rule, svcKey, "/hello", caller, nil))
+	assert.False(t, matchRule(rule, svcKey, "/world", caller, nil))
+	// 主调服务不匹配，或者没有携带主调服务
+	assert.False(t, matchRule(rule, svcKey, "/hello", &model.ServiceInfo{Service: "other"}, nil))
+	assert.False(t, matchRule(rule, svcKey, "/hello", nil, nil))
+	// 被调服务不匹配
+	assert.False(t, matchRule(rule, &model.ServiceKey{Namespace: "default", Service: "other"}, "/hello", caller, nil))
+
+	// 服务级规则不匹配接口，主调服务为*时匹配全部
+	rule = newMatchRule(apifault.Level_SERVICE, "*", "/hello")
+	assert.True(t, matchRule(rule, svcKey, "/world", nil, nil))
+
+	rule.Enable = false
+	assert.False(t, matchRule(rule, svcKey, "/world", nil, nil))
+}
+
+// TestStatInstanceWithoutCalledInstance 测试没有被调实例的调用结果不进行实例级统计
+func TestStatInstanceWithoutCalledInstance(t *testing.T) {
+	g := &CircuitBreaker{}
+	rule := newMatchRule(apifault.Level_INSTANCE, "*", "")
+	assert.False(t, g.statInstance(rule, newTestGauge(model.RetFail), true, time.Now()))
+}
+
+// TestResultRequestCountAfterHalfOpen 测试合并熔断结果时保留各实例的半开请求数
+func TestResultRequestCountAfterHalfOpen(t *testing.T) {
+	now := time.Now()
+	result := circuitbreaker.NewCircuitBreakerResult(now)
+	result.RequestCountAfterHalfOpen = 10
+	result.InstancesToHalfOpen.Add("instance-1")
+	result.SetInstanceRequestCountAfterHalfOpen("instance-1", 3)
+
+	other := circuitbreaker.NewCircuitBreakerResult(now)
+	other.RequestCountAfterHalfOpen = 10
+	other.InstancesToHalfOpen.Add("instance-2")
+	other.SetInstanceRequestCountAfterHalfOpen("instance-2", 5)
+	other.InstancesToOpen.Add("instance-3")
+	result.Merge(other)
+
+	assert.Equal(t, 3, result.GetRequestCountAfterHalfOpen("instance-1"))
+	assert.Equal(t, 5, result.GetRequestCountAfterHalfOpen("instance-2"))
+	assert.Equal(t, 10, result.GetRequestCountAfterHalfOpen("instance-3"))
+}
@@ -0,0 +1,165 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slowcallrate
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// 定义慢调用比例熔断配置的默认值
+const (
+	// DefaultSlowCallDurationThreshold 调用时延不低于该值时视为慢调用，默认1s
+	DefaultSlowCallDurationThreshold = 1 * time.Second
+	// MinSlowCallDurationThreshold 最小慢调用时延阈值，1ms
+	MinSlowCallDurationThreshold = 1 * time.Millisecond
+	// DefaultSlowCallRatePercent 触发熔断的慢调用比例，默认50
+	DefaultSlowCallRatePercent = 50
+	// MaxSlowCallRatePercent 最大慢调用比例
+	MaxSlowCallRatePercent = 100
+	// DefaultRequestVolumeThreshold 只有请求数达到某个阈值才执行熔断计算，默认10
+	DefaultRequestVolumeThreshold = 10
+	// DefaultMetricStatTimeWindow 慢调用比例统计时间窗口，默认1分钟
+	DefaultMetricStatTimeWindow = 60 * time.Second
+	// MinMetricStatTimeWindow 最小慢调用比例统计时间窗口，1s
+	MinMetricStatTimeWindow = 1 * time.Second
+	// DefaultMetricNumBuckets 统计窗口细分的桶数量，默认5
+	DefaultMetricNumBuckets = 5
+	// MinMetricStatBucketSize 最小的滑窗时间片，1ms
+	MinMetricStatBucketSize = 1 * time.Millisecond
+)
+
+// Config 基于慢调用比例熔断器的配置结构
+type Config struct {
+	// 慢调用时延阈值
+	SlowCallDurationThreshold *time.Duration `yaml:"slowCallDurationThreshold" json:"slowCallDurationThreshold"`
+	// 触发熔断的慢调用比例，取值范围(0, 100]
+	SlowCallRatePercent int `yaml:"slowCallRatePercent" json:"slowCallRatePercent"`
+	// 只有请求数达到该阈值才执行熔断计算
+	RequestVolumeThreshold int `yaml:"requestVolumeThreshold" json:"requestVolumeThreshold"`
+	// 慢调用比例统计时间窗口
+	MetricStatTimeWindow *time.Duration `yaml:"metricStatTimeWindow" json:"metricStatTimeWindow"`
+	// 统计窗口细分的桶数量
+	MetricNumBuckets int `yaml:"metricNumBuckets" json:"metricNumBuckets"`
+}
+
+// Verify 检验慢调用比例熔断配置
+func (r *Config) Verify() error {
+	var errs error
+	if nil != r.SlowCallDurationThreshold && *r.SlowCallDurationThreshold < MinSlowCallDurationThreshold {
+		errs = multierror.Append(errs, fmt.Errorf(
+			"slowCallRate.slowCallDurationThreshold must be greater than %v", MinSlowCallDurationThreshold))
+	}
+	if r.SlowCallRatePercent <= 0 || r.SlowCallRatePercent > MaxSlowCallRatePercent {
+		errs = multierror.Append(errs, fmt.Errorf(
+			"slowCallRate.slowCallRatePercent must be greater than 0 and lower than %d", MaxSlowCallRatePercent))
+	}
+	if r.RequestVolumeThreshold <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("slowCallRate.requestVolumeThreshold must be greater than 0"))
+	}
+	if nil != r.MetricStatTimeWindow && *r.MetricStatTimeWindow < MinMetricStatTimeWindow {
+		errs = multierror.Append(errs,
+			fmt.Errorf("slowCallRate.metricStatTimeWindow must be greater than %v", MinMetricStatTimeWindow))
+	}
+	if r.MetricNumBuckets <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("slowCallRate.metricNumBuckets must be greater than 0"))
+	}
+	if nil != r.MetricStatTimeWindow && r.MetricNumBuckets > 0 && r.GetBucketInterval() < MinMetricStatBucketSize {
+		errs = multierror.Append(errs,
+			fmt.Errorf("bucketSize(metricStatTimeWindow/metricNumBuckets) must be greater than %v",
+				MinMetricStatBucketSize))
+	}
+	return errs
+}
+
+// Inherit 服务级配置中未设置的配置项，继承全局的慢调用比例熔断配置
+func (r *Config) Inherit(global config.BaseConfig) {
+	globalCfg, ok := global.(*Config)
+	if !ok {
+		return
+	}
+	if nil == r.SlowCallDurationThreshold {
+		r.SlowCallDurationThreshold = globalCfg.SlowCallDurationThreshold
+	}
+	if r.SlowCallRatePercent == 0 {
+		r.SlowCallRatePercent = globalCfg.SlowCallRatePercent
+	}
+	if r.RequestVolumeThreshold == 0 {
+		r.RequestVolumeThreshold = globalCfg.RequestVolumeThreshold
+	}
+	if nil == r.MetricStatTimeWindow {
+		r.MetricStatTimeWindow = globalCfg.MetricStatTimeWindow
+	}
+	if r.MetricNumBuckets == 0 {
+		r.MetricNumBuckets = globalCfg.MetricNumBuckets
+	}
+}
+
+// VerifyWithGlobal 滑窗按照全局配置生成，服务级的统计时间窗口不能超过全局的统计时间窗口
+func (r *Config) VerifyWithGlobal(global config.BaseConfig) error {
+	globalCfg, ok := global.(*Config)
+	if !ok || nil == r.MetricStatTimeWindow || nil == globalCfg.MetricStatTimeWindow {
+		return nil
+	}
+	if *r.MetricStatTimeWindow > *globalCfg.MetricStatTimeWindow {
+		return fmt.Errorf("slowCallRate.metricStatTimeWindow %v of service must not be greater than global %v",
+			*r.MetricStatTimeWindow, *globalCfg.MetricStatTimeWindow)
+	}
+	return nil
+}
+
+// GetBucketInterval 获取滑桶时间间隔
+func (r *Config) GetBucketInterval() time.Duration {
+	bucketSize := math.Ceil(float64(*r.MetricStatTimeWindow) / float64(r.MetricNumBuckets))
+	return time.Duration(bucketSize)
+}
+
+// SetDefault 设置慢调用比例熔断配置的默认值
+func (r *Config) SetDefault() {
+	if nil == r.SlowCallDurationThreshold {
+		r.SlowCallDurationThreshold = model.ToDurationPtr(DefaultSlowCallDurationThreshold)
+	}
+	if r.SlowCallRatePercent == 0 {
+		r.SlowCallRatePercent = DefaultSlowCallRatePercent
+	}
+	if r.RequestVolumeThreshold == 0 {
+		r.RequestVolumeThreshold = DefaultRequestVolumeThreshold
+	}
+	if nil == r.MetricStatTimeWindow {
+		r.MetricStatTimeWindow = model.ToDurationPtr(DefaultMetricStatTimeWindow)
+	}
+	if r.MetricNumBuckets == 0 {
+		r.MetricNumBuckets = DefaultMetricNumBuckets
+	}
+}
+
+// GetSlowCallDurationThreshold 慢调用时延阈值
+func (r *Config) GetSlowCallDurationThreshold() time.Duration {
+	return *r.SlowCallDurationThreshold
+}
+
+// GetMetricStatTimeWindow 慢调用比例统计时间窗口
+func (r *Config) GetMetricStatTimeWindow() time.Duration {
+	return *r.MetricStatTimeWindow
+}
@@ -0,0 +1,255 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slowcallrate
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/metric"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/circuitbreaker"
+	common2 "github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/plugin/circuitbreaker/common"
+)
+
+// CircuitBreaker 基于慢调用比例的熔断器，调用时延不低于阈值的调用视为慢调用，
+// 统计周期内慢调用比例达到阈值时熔断实例，半开期间出现慢调用同样视为探测失败
+type CircuitBreaker struct {
+	*plugin.PluginBase
+	wholeCfg        config.Configuration
+	cfg             *Config
+	halfOpenHandler *common.HalfOpenConversionHandler
+}
+
+// Type 插件类型
+func (g *CircuitBreaker) Type() common2.Type {
+	return common2.TypeCircuitBreaker
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *CircuitBreaker) Name() string {
+	return config.DefaultCircuitBreakerSlowCallRate
+}
+
+// Init 初始化插件
+func (g *CircuitBreaker) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.wholeCfg = ctx.Config
+	g.cfg = ctx.Config.GetConsumer().GetCircuitBreaker().GetPluginConfig(g.Name()).(*Config)
+	ctx.Plugins.RegisterEventSubscriber(common2.OnInstanceLocalValueCreated, common2.PluginEventHandler{
+		Callback: g.generateSliceWindow,
+	})
+	g.halfOpenHandler = common.NewHalfOpenConversionHandler(ctx.Config)
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *CircuitBreaker) Destroy() error {
+	return nil
+}
+
+// IsEnable enable
+func (g *CircuitBreaker) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
+}
+
+const (
+	// 慢调用统计窗口下标
+	metricIdxSlowCall = iota
+	// 最大窗口下标
+	metricIdxMax
+)
+
+// 统计维度
+const (
+	// 总请求数
+	keyRequestCount = iota
+	// 慢调用数
+	keySlowCount
+	// 总统计维度
+	maxDimension
+)
+
+var (
+	addSlowCall = func(gauge model.InstanceGauge, bucket *metric.Bucket) int64 {
+		bucket.AddMetric(keyRequestCount, 1)
+		bucket.AddMetric(keySlowCount, 1)
+		return 0
+	}
+	addNormalCall = func(gauge model.InstanceGauge, bucket *metric.Bucket) int64 {
+		bucket.AddMetric(keyRequestCount, 1)
+		return 0
+	}
+)
+
+// slowCallGauge 半开期间的慢调用，按照失败调用进行统计
+type slowCallGauge struct {
+	model.InstanceGauge
+}
+
+// GetRetStatus 慢调用视为调用失败
+func (s *slowCallGauge) GetRetStatus() model.RetStatus {
+	return model.RetFail
+}
+
+// isSlowCall 判断是否为慢调用，没有上报时延的调用不参与统计
+func isSlowCall(gauge model.InstanceGauge, threshold time.Duration) (bool, bool) {
+	delay := gauge.GetDelay()
+	if nil == delay {
+		return false, false
+	}
+	return *delay >= threshold, true
+}
+
+// GetSlowCallRateConfig 获取服务的慢调用比例熔断配置，服务级配置优先
+func (g *CircuitBreaker) GetSlowCallRateConfig(namespace string, service string) *Config {
+	serviceSp := g.wholeCfg.GetConsumer().GetServiceSpecific(namespace, service)
+	if nil == serviceSp || nil == serviceSp.GetServiceCircuitBreaker() {
+		return g.cfg
+	}
+	if cfg, ok := serviceSp.GetServiceCircuitBreaker().GetPluginConfig(g.Name()).(*Config); ok {
+		return cfg
+	}
+	return g.cfg
+}
+
+// 获取实例的滑窗
+func (g *CircuitBreaker) getSliceWindows(instance model.Instance) []*metric.SliceWindow {
+	instanceInProto := instance.(*pb.InstanceInProto)
+	return instanceInProto.GetSliceWindows(g.ID())
+}
+
+// Stat 实时上报调用结果并进行慢调用统计
+func (g *CircuitBreaker) Stat(gauge model.InstanceGauge) (bool, error) {
+	instance := gauge.GetCalledInstance()
+	cbStatus := instance.GetCircuitBreakerStatus()
+	if nil != cbStatus && cbStatus.GetStatus() == model.Open {
+		// 熔断状态不进行统计
+		return false, nil
+	}
+	cfg := g.GetSlowCallRateConfig(gauge.GetNamespace(), gauge.GetService())
+	slow, hasDelay := isSlowCall(gauge, cfg.GetSlowCallDurationThreshold())
+	if nil != cbStatus && cbStatus.GetStatus() == model.HalfOpen && cbStatus.GetCircuitBreaker() == g.Name() {
+		if slow {
+			return g.halfOpenHandler.StatHalfOpenCalls(cbStatus, &slowCallGauge{InstanceGauge: gauge}), nil
+		}
+		return g.halfOpenHandler.StatHalfOpenCalls(cbStatus, gauge), nil
+	}
+	if !hasDelay {
+		return false, nil
+	}
+	metricWindows := g.getSliceWindows(instance)
+	if slow {
+		metricWindows[metricIdxSlowCall].AddGauge(gauge, addSlowCall)
+	} else {
+		metricWindows[metricIdxSlowCall].AddGauge(gauge, addNormalCall)
+	}
+	return false, nil
+}
+
+// exceedSlowCallRate 判断慢调用比例是否达到熔断阈值，返回慢调用比例
+func exceedSlowCallRate(reqCount int64, slowCount int64, cfg *Config) (float64, bool) {
+	if reqCount == 0 || reqCount < int64(cfg.RequestVolumeThreshold) {
+		// 未达到起始请求数阈值
+		return 0, false
+	}
+	slowRatio := float64(slowCount) / float64(reqCount)
+	return slowRatio, slowRatio >= float64(cfg.SlowCallRatePercent)/100
+}
+
+// 熔断器从关闭到打开
+func (g *CircuitBreaker) closeToOpen(instance model.Instance, metricWindow *metric.SliceWindow, now time.Time) bool {
+	cbStatus := instance.GetCircuitBreakerStatus()
+	if nil != cbStatus && cbStatus.GetStatus() != model.Close {
+		return false
+	}
+	cfg := g.GetSlowCallRateConfig(instance.GetNamespace(), instance.GetService())
+	timeRange := &metric.TimeRange{
+		Start: now.Add(0 - cfg.GetMetricStatTimeWindow()),
+		End:   now.Add(metricWindow.GetBucketInterval()),
+	}
+	values := metricWindow.CalcMetricsInMultiDimensions([]int{keyRequestCount, keySlowCount}, timeRange)
+	slowRatio, exceeded := exceedSlowCallRate(values[0], values[1], cfg)
+	if exceeded {
+		log.GetDetectLogger().Infof(
+			"closeToOpen %s: instance(id=%s, address=%s:%d) match condition for slowRatio=%.2f(threshold=%d%%)",
+			g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort(), slowRatio, cfg.SlowCallRatePercent)
+	}
+	return exceeded
+}
+
+// 生成滑窗，滑窗的时间片按照全局配置生成，服务级配置的统计周期不超过全局配置的统计周期
+func (g *CircuitBreaker) generateSliceWindow(event *common2.PluginEvent) error {
+	localValue := event.EventObject.(*local.DefaultInstanceLocalValue)
+	metricWindows := make([]*metric.SliceWindow, metricIdxMax)
+	metricWindows[metricIdxSlowCall] = metric.NewSliceWindow(g.Name(),
+		g.cfg.MetricNumBuckets, g.cfg.GetBucketInterval(), maxDimension, clock.GetClock().Now().UnixNano())
+	localValue.SetSliceWindows(g.ID(), metricWindows)
+	return nil
+}
+
+// CircuitBreak 熔断计算
+// 定期或触发式进行熔断计算，返回需要进行状态转换的实例ID
+// 入参包括全量服务实例，以及当前周期的健康探测结果
+func (g *CircuitBreaker) CircuitBreak(instances []model.Instance) (*circuitbreaker.Result, error) {
+	result := circuitbreaker.NewCircuitBreakerResult(clock.GetClock().Now())
+	for _, instance := range instances {
+		metricWindows := g.getSliceWindows(instance)
+		if len(metricWindows) == 0 {
+			continue
+		}
+		if g.closeToOpen(instance, metricWindows[metricIdxSlowCall], result.Now) {
+			log.GetDetectLogger().Warnf("%s: close to open, instance(id=%s, address=%s:%d)",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort())
+			result.InstancesToOpen.Add(instance.GetId())
+			continue
+		}
+		if g.halfOpenHandler.OpenToHalfOpen(instance, result.Now, g.Name()) {
+			log.GetDetectLogger().Infof("%s: open to halfOpen, instance(id=%s, address=%s:%d)",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort())
+			result.InstancesToHalfOpen.Add(instance.GetId())
+			continue
+		}
+		switch g.halfOpenHandler.HalfOpenConversion(result.Now, instance, g.Name()) {
+		case common.ToOpen:
+			log.GetDetectLogger().Warnf("%s: halfOpen to open, instance(id=%s, address=%s:%d)",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort())
+			result.InstancesToOpen.Add(instance.GetId())
+		case common.ToClose:
+			log.GetDetectLogger().Infof("%s: halfOpen to close, instance(id=%s, address=%s:%d)",
+				g.Name(), instance.GetId(), instance.GetHost(), instance.GetPort())
+			result.InstancesToClose.Add(instance.GetId())
+		}
+	}
+	if result.IsEmpty() {
+		return nil, nil
+	}
+	result.RequestCountAfterHalfOpen = g.halfOpenHandler.GetRequestCountAfterHalfOpen()
+	return result, nil
+}
+
+// init 插件注册
+func init() {
+	plugin.RegisterConfigurablePlugin(&CircuitBreaker{}, &Config{})
+}
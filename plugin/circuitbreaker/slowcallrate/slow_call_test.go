@@ -0,0 +1,120 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package slowcallrate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// TestIsSlowCall 测试慢调用判断，半开期间的慢调用视为失败调用
+func TestIsSlowCall(t *testing.T) {
+	result := &model.ServiceCallResult{RetStatus: model.RetSuccess}
+	slow, hasDelay := isSlowCall(result, time.Second)
+	assert.False(t, slow)
+	assert.False(t, hasDelay)
+
+	result.SetDelay(500 * time.Millisecond)
+	slow, hasDelay = isSlowCall(result, time.Second)
+	assert.False(t, slow)
+	assert.True(t, hasDelay)
+
+	result.SetDelay(time.Second)
+	slow, _ = isSlowCall(result, time.Second)
+	assert.True(t, slow)
+
+	gauge := &slowCallGauge{InstanceGauge: result}
+	assert.Equal(t, model.RetFail, gauge.GetRetStatus())
+	assert.Equal(t, time.Second, *gauge.GetDelay())
+}
+
+// TestExceedSlowCallRate 测试慢调用比例阈值判断
+func TestExceedSlowCallRate(t *testing.T) {
+	cfg := &Config{}
+	cfg.SetDefault()
+	assert.Nil(t, cfg.Verify())
+
+	// 未达到起始请求数阈值
+	_, exceeded := exceedSlowCallRate(9, 9, cfg)
+	assert.False(t, exceeded)
+	ratio, exceeded := exceedSlowCallRate(10, 4, cfg)
+	assert.False(t, exceeded)
+	assert.Equal(t, 0.4, ratio)
+	ratio, exceeded = exceedSlowCallRate(10, 5, cfg)
+	assert.True(t, exceeded)
+	assert.Equal(t, 0.5, ratio)
+
+	cfg.SlowCallRatePercent = 101
+	assert.NotNil(t, cfg.Verify())
+}
+
+const serviceSpecificConfig = `
+global:
+  api:
+    bindIP: 127.0.0.1
+  serverConnector:
+    addresses:
+      - 127.0.0.1:8091
+consumer:
+  circuitBreaker:
+    plugin:
+      slowCallRate:
+        slowCallDurationThreshold: 3s
+        slowCallRatePercent: 80
+        metricStatTimeWindow: 2m
+  servicesSpecific:
+    - namespace: Test
+      service: TestName
+      circuitBreaker:
+        plugin:
+          errorCount:
+            continuousErrorThreshold: 5
+          slowCallRate:
+            requestVolumeThreshold: 20
+            metricStatTimeWindow: 30s
+`
+
+// TestServiceSpecificConfigInherit 测试服务级慢调用比例熔断配置继承全局配置中未设置的配置项
+func TestServiceSpecificConfigInherit(t *testing.T) {
+	cfg, err := config.LoadConfiguration([]byte(serviceSpecificConfig))
+	assert.Nil(t, err)
+	cb := &CircuitBreaker{wholeCfg: cfg}
+	cb.cfg = cfg.GetConsumer().GetCircuitBreaker().GetPluginConfig(cb.Name()).(*Config)
+
+	svcCfg := cb.GetSlowCallRateConfig("Test", "TestName")
+	assert.Equal(t, 3*time.Second, svcCfg.GetSlowCallDurationThreshold())
+	assert.Equal(t, 80, svcCfg.SlowCallRatePercent)
+	assert.Equal(t, 20, svcCfg.RequestVolumeThreshold)
+	assert.Equal(t, 30*time.Second, svcCfg.GetMetricStatTimeWindow())
+	assert.Equal(t, DefaultMetricNumBuckets, svcCfg.MetricNumBuckets)
+	// 未配置服务级熔断配置的服务使用全局配置
+	assert.True(t, cb.cfg == cb.GetSlowCallRateConfig("Test", "Other"))
+	assert.Equal(t, DefaultRequestVolumeThreshold, cb.cfg.RequestVolumeThreshold)
+
+	// 服务级的统计时间窗口不能超过全局的统计时间窗口
+	_, err = config.LoadConfiguration([]byte(strings.Replace(serviceSpecificConfig,
+		"metricStatTimeWindow: 30s", "metricStatTimeWindow: 5m", 1)))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "must not be greater than global")
+}
@@ -25,8 +25,10 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
 	"github.com/polarismesh/specification/source/go/api/v1/config_manage"
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/polarismesh/polaris-go/pkg/clock"
@@ -55,6 +57,10 @@ type Connector struct {
 	connManager           network.ConnectionManager
 	connectionIdleTimeout time.Duration
 	valueCtx              model.ValueContext
+	// 与server通信的传输层凭证
+	creds credentials.TransportCredentials
+	// 访问server的鉴权凭证
+	tokenCreds credentials.PerRPCCredentials
 	// 有没有打印过connManager ready的信息，用于避免重复打印
 	hasPrintedReady uint32
 }
@@ -84,6 +90,22 @@ func (c *Connector) Init(ctx *plugin.InitContext) error {
 	c.connManager = connManager
 	c.connectionIdleTimeout = ctx.Config.GetGlobal().GetServerConnector().GetConnectionIdleTimeout()
 	c.valueCtx = ctx.ValueCtx
+	// 配置中心未单独启用TLS时，与服务发现共用global.serverConnector.tls
+	tlsCfg := ctx.Config.GetConfigFile().GetConfigConnectorConfig().GetTLS()
+	if !tlsCfg.IsEnable() {
+		tlsCfg = ctx.Config.GetGlobal().GetServerConnector().GetTLS()
+	}
+	c.creds = network.NewTransportCredentials(tlsCfg)
+	// 配置中心未单独配置鉴权时，与服务发现共用global.serverConnector的鉴权配置
+	var tokenCfg config.ServerConnectorConfig = ctx.Config.GetConfigFile().GetConfigConnectorConfig()
+	if len(tokenCfg.GetToken()) == 0 && len(tokenCfg.GetTokenProvider()) == 0 {
+		tokenCfg = ctx.Config.GetGlobal().GetServerConnector()
+	}
+	tokenProvider, err := network.NewTokenProvider(tokenCfg)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create token provider")
+	}
+	c.tokenCreds = network.NewTokenCredentials(tokenProvider)
 	protocol := ctx.Config.GetConfigFile().GetConfigConnectorConfig().GetProtocol()
 	if protocol == c.Name() {
 		log.GetBaseLogger().Infof("set %s plugin as connectionCreator", c.Name())
@@ -172,6 +194,141 @@ func (c *Connector) WatchConfigFiles(configFileList []*configconnector.ConfigFil
 	return c.handleResponse(request.String(), reqID, opKey, pbResp, err, conn, startTime)
 }
 
+// GetConfigGroup Get published config files of group.
+func (c *Connector) GetConfigGroup(group *configconnector.ConfigGroup) (*configconnector.ConfigGroupResponse, error) {
+	var err error
+	if err = c.waitDiscoverReady(); err != nil {
+		return nil, err
+	}
+	opKey := connector.OpKeyGetConfigGroup
+	startTime := clock.GetClock().Now()
+	// 获取server连接
+	conn, err := c.connManager.GetConnection(opKey, config.ConfigCluster)
+	if err != nil {
+		return nil, connector.NetworkError(c.connManager, conn, int32(model.ErrCodeConnectError), err, startTime,
+			fmt.Sprintf("fail to get connection, opKey %s", opKey))
+	}
+	// 释放server连接
+	defer conn.Release(opKey)
+	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
+	reqID := connector.NextGetConfigGroupReqID()
+	ctx, cancel := connector.CreateHeaderContextWithReqId(0, reqID)
+	if cancel != nil {
+		defer cancel()
+	}
+	request := &config_manage.ConfigFileGroupRequest{
+		Revision: wrapperspb.String(group.GetRevision()),
+		ConfigFileGroup: &config_manage.ConfigFileGroup{
+			Namespace: wrapperspb.String(group.GetNamespace()),
+			Name:      wrapperspb.String(group.GetGroup()),
+		},
+	}
+	// 打印请求报文
+	if log.GetBaseLogger().IsLevelEnabled(log.DebugLog) {
+		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(request)
+		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
+	}
+	pbResp, err := configClient.GetConfigFileMetadataList(ctx, request)
+	if err = c.checkResponse(request.String(), reqID, opKey, pbResp, err, conn, startTime); err != nil {
+		return nil, err
+	}
+	configFiles := make([]*configconnector.ConfigFile, 0, len(pbResp.GetConfigFileInfos()))
+	for _, info := range pbResp.GetConfigFileInfos() {
+		configFiles = append(configFiles, transferFromClientConfigFileInfo(info))
+	}
+	return &configconnector.ConfigGroupResponse{
+		Code:        pbResp.GetCode().GetValue(),
+		Message:     pbResp.GetInfo().GetValue(),
+		Revision:    pbResp.GetRevision().GetValue(),
+		ConfigFiles: configFiles,
+	}, nil
+}
+
+// CreateConfigFile Create config file.
+func (c *Connector) CreateConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	request := transferToConfigFile(configFile)
+	return c.writeConfigFile(connector.OpKeyCreateConfigFile, connector.NextCreateConfigFileReqID(), request,
+		func(ctx context.Context, client config_manage.PolarisConfigGRPCClient) (*config_manage.ConfigClientResponse, error) {
+			return client.CreateConfigFile(ctx, request)
+		})
+}
+
+// UpdateConfigFile Update config file.
+func (c *Connector) UpdateConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	request := transferToConfigFile(configFile)
+	return c.writeConfigFile(connector.OpKeyUpdateConfigFile, connector.NextUpdateConfigFileReqID(), request,
+		func(ctx context.Context, client config_manage.PolarisConfigGRPCClient) (*config_manage.ConfigClientResponse, error) {
+			return client.UpdateConfigFile(ctx, request)
+		})
+}
+
+// PublishConfigFile Publish config file.
+func (c *Connector) PublishConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	request := transferToConfigFileRelease(configFile)
+	return c.writeConfigFile(connector.OpKeyPublishConfigFile, connector.NextPublishConfigFileReqID(), request,
+		func(ctx context.Context, client config_manage.PolarisConfigGRPCClient) (*config_manage.ConfigClientResponse, error) {
+			return client.PublishConfigFile(ctx, request)
+		})
+}
+
+// DeleteConfigFile Delete config file，北极星服务端的GRPC配置接口不提供删除能力.
+func (c *Connector) DeleteConfigFile(configFile *configconnector.ConfigFile) (*configconnector.ConfigFileResponse, error) {
+	return nil, model.NewSDKError(model.ErrCodePluginError, nil,
+		"config file %s/%s/%s can not be deleted, DeleteConfigFile is not supported by %s config connector",
+		configFile.GetNamespace(), configFile.GetFileGroup(), configFile.GetFileName(), c.Name())
+}
+
+// writeConfigFile 执行配置文件写操作.
+func (c *Connector) writeConfigFile(opKey string, reqID string, request proto.Message,
+	call func(context.Context, config_manage.PolarisConfigGRPCClient) (*config_manage.ConfigClientResponse, error),
+) (*configconnector.ConfigFileResponse, error) {
+	var err error
+	if err = c.waitDiscoverReady(); err != nil {
+		return nil, err
+	}
+	startTime := clock.GetClock().Now()
+	// 获取server连接
+	conn, err := c.connManager.GetConnection(opKey, config.ConfigCluster)
+	if err != nil {
+		return nil, connector.NetworkError(c.connManager, conn, int32(model.ErrCodeConnectError), err, startTime,
+			fmt.Sprintf("fail to get connection, opKey %s", opKey))
+	}
+	// 释放server连接
+	defer conn.Release(opKey)
+	configClient := config_manage.NewPolarisConfigGRPCClient(network.ToGRPCConn(conn.Conn))
+	ctx, cancel := connector.CreateHeaderContextWithReqId(0, reqID)
+	if cancel != nil {
+		defer cancel()
+	}
+	// 打印请求报文
+	if log.GetBaseLogger().IsLevelEnabled(log.DebugLog) {
+		reqJson, _ := (&jsonpb.Marshaler{}).MarshalToString(request)
+		log.GetBaseLogger().Debugf("request to send is %s, opKey %s, connID %s", reqJson, opKey, conn.ConnID)
+	}
+	pbResp, err := call(ctx, configClient)
+	return c.handleWriteResponse(request.String(), reqID, opKey, pbResp, err, conn, startTime)
+}
+
+// handleWriteResponse 处理配置文件写操作的应答，请求本身不合法的应答不认为是server异常，由调用方根据code进行处理.
+func (c *Connector) handleWriteResponse(request string, reqID string, opKey string,
+	response *config_manage.ConfigClientResponse, err error, conn *network.Connection, startTime time.Time,
+) (*configconnector.ConfigFileResponse, error) {
+	code := apimodel.Code(response.GetCode().GetValue())
+	isUserError := code == apimodel.Code_DataConflict ||
+		pb.ConvertServerErrorToRpcError(response.GetCode().GetValue()) == model.ErrCodeInvalidRequest
+	if err == nil && isUserError {
+		c.connManager.ReportSuccess(conn.ConnID, int32(model.ErrCodeInvalidRequest),
+			clock.GetClock().Now().Sub(startTime))
+		log.GetBaseLogger().Warnf("fail to %s, request %s, server code %d, reason %s, reqID %s",
+			opKey, request, code, response.GetInfo().GetValue(), reqID)
+		return &configconnector.ConfigFileResponse{
+			Code:    response.GetCode().GetValue(),
+			Message: response.GetInfo().GetValue(),
+		}, nil
+	}
+	return c.handleResponse(request, reqID, opKey, response, err, conn, startTime)
+}
+
 // IsEnable .插件开关.
 func (c *Connector) IsEnable(cfg config.Configuration) bool {
 	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
@@ -203,9 +360,30 @@ func (c *Connector) waitDiscoverReady() error {
 func (c *Connector) handleResponse(request string, reqID string, opKey string, response *config_manage.ConfigClientResponse,
 	err error, conn *network.Connection, startTime time.Time,
 ) (*configconnector.ConfigFileResponse, error) {
+	if err = c.checkResponse(request, reqID, opKey, response, err, conn, startTime); err != nil {
+		return nil, err
+	}
+	return &configconnector.ConfigFileResponse{
+		Code:       response.GetCode().GetValue(),
+		Message:    response.GetInfo().GetValue(),
+		ConfigFile: transferFromClientConfigFileInfo(response.GetConfigFile()),
+	}, nil
+}
+
+// configResponse 配置中心的应答报文
+type configResponse interface {
+	proto.Message
+	GetCode() *wrapperspb.UInt32Value
+	GetInfo() *wrapperspb.StringValue
+}
+
+// checkResponse 检查应答，并上报server的调用结果，非预期的应答返回错误.
+func (c *Connector) checkResponse(request string, reqID string, opKey string, response configResponse,
+	err error, conn *network.Connection, startTime time.Time,
+) error {
 	endTime := clock.GetClock().Now()
 	if err != nil {
-		return nil, connector.NetworkError(c.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
+		return connector.NetworkError(c.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to %s, request %s, "+
 				"reason is fail to send request, reqID %s, server %s", opKey, request, reqID, conn.ConnID))
 	}
@@ -219,18 +397,19 @@ func (c *Connector) handleResponse(request string, reqID string, opKey string, r
 	// 预期code，正常响应
 	if code == apimodel.Code_ExecuteSuccess || code == apimodel.Code_NotFoundResource || code == apimodel.Code_DataNoChange {
 		c.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
-		return &configconnector.ConfigFileResponse{
-			Code:       response.GetCode().GetValue(),
-			Message:    response.GetInfo().GetValue(),
-			ConfigFile: transferFromClientConfigFileInfo(response.GetConfigFile()),
-		}, nil
+		return nil
 	}
-	// 当server发生了内部错误时，上报调用服务失败
 	errMsg := fmt.Sprintf(
 		"fail to %s, request %s, server code %d, reason %s, server %s", opKey,
 		request, response.GetCode().GetValue(), response.GetInfo().GetValue(), conn.ConnID)
+	if serverCodeType == model.ErrCodeUnauthorized {
+		// 鉴权失败，server本身是正常的
+		c.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
+		return model.NewSDKError(model.ErrCodeAPIUnauthorized, nil, errMsg)
+	}
+	// 当server发生了内部错误时，上报调用服务失败
 	c.connManager.ReportFail(conn.ConnID, int32(model.ErrCodeServerError), endTime.Sub(startTime))
-	return nil, model.NewSDKError(model.ErrCodeServerException, nil, errMsg)
+	return model.NewSDKError(model.ErrCodeServerException, nil, errMsg)
 }
 
 func transferToClientConfigFileInfo(configFile *configconnector.ConfigFile) *config_manage.ClientConfigFileInfo {
@@ -243,6 +422,33 @@ func transferToClientConfigFileInfo(configFile *configconnector.ConfigFile) *con
 	}
 }
 
+func transferToConfigFile(configFile *configconnector.ConfigFile) *config_manage.ConfigFile {
+	tags := make([]*config_manage.ConfigFileTag, 0, len(configFile.Tags))
+	for _, tag := range configFile.Tags {
+		tags = append(tags, &config_manage.ConfigFileTag{
+			Key:   wrapperspb.String(tag.Key),
+			Value: wrapperspb.String(tag.Value),
+		})
+	}
+	return &config_manage.ConfigFile{
+		Namespace:   wrapperspb.String(configFile.GetNamespace()),
+		Group:       wrapperspb.String(configFile.GetFileGroup()),
+		Name:        wrapperspb.String(configFile.GetFileName()),
+		Content:     wrapperspb.String(configFile.GetContent()),
+		Encrypted:   wrapperspb.Bool(configFile.GetEncrypted()),
+		EncryptAlgo: wrapperspb.String(configFile.GetEncryptAlgo()),
+		Tags:        tags,
+	}
+}
+
+func transferToConfigFileRelease(configFile *configconnector.ConfigFile) *config_manage.ConfigFileRelease {
+	return &config_manage.ConfigFileRelease{
+		Namespace: wrapperspb.String(configFile.GetNamespace()),
+		Group:     wrapperspb.String(configFile.GetFileGroup()),
+		FileName:  wrapperspb.String(configFile.GetFileName()),
+	}
+}
+
 func transferFromClientConfigFileInfo(configFileInfo *config_manage.ClientConfigFileInfo) *configconnector.ConfigFile {
 	var tags []*configconnector.ConfigFileTag
 	for _, tag := range configFileInfo.GetTags() {
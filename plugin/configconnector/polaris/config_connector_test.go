@@ -0,0 +1,112 @@
+/*
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ *  under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ */
+
+package polaris
+
+import (
+	"testing"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/config_manage"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/log/logtest"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+)
+
+func init() {
+	logtest.DiscardLoggers()
+}
+
+// reportConnManager 记录调用结果上报的连接管理器
+type reportConnManager struct {
+	network.ConnectionManager
+	successCodes []int32
+	failCodes    []int32
+	downCount    int
+}
+
+func (m *reportConnManager) ReportSuccess(connID network.ConnID, retCode int32, timeout time.Duration) {
+	m.successCodes = append(m.successCodes, retCode)
+}
+
+func (m *reportConnManager) ReportFail(connID network.ConnID, retCode int32, timeout time.Duration) {
+	m.failCodes = append(m.failCodes, retCode)
+}
+
+func (m *reportConnManager) ReportConnectionDown(connID network.ConnID) {
+	m.downCount++
+}
+
+func newTestConfigFile() *configconnector.ConfigFile {
+	return &configconnector.ConfigFile{
+		Namespace: "default",
+		FileGroup: "group",
+		FileName:  "conf/app.yaml",
+	}
+}
+
+// TestDeleteConfigFile 测试GRPC配置接口不支持删除配置文件时明确返回错误
+func TestDeleteConfigFile(t *testing.T) {
+	c := &Connector{}
+	resp, err := c.DeleteConfigFile(newTestConfigFile())
+	assert.Nil(t, resp)
+	assert.NotNil(t, err)
+	assert.Equal(t, model.ErrCodePluginError, err.(model.SDKError).ErrorCode())
+}
+
+// TestHandleWriteResponse 测试写操作的请求不合法时返回应答码，不认为server异常
+func TestHandleWriteResponse(t *testing.T) {
+	connManager := &reportConnManager{}
+	c := &Connector{connManager: connManager}
+	conn := &network.Connection{}
+	newResp := func(code apimodel.Code) *config_manage.ConfigClientResponse {
+		return &config_manage.ConfigClientResponse{
+			Code: wrapperspb.UInt32(uint32(code)),
+			Info: wrapperspb.String(code.String()),
+		}
+	}
+
+	resp, err := c.handleWriteResponse("request", "req-1", "op",
+		newResp(apimodel.Code_DataConflict), nil, conn, time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(apimodel.Code_DataConflict), resp.Code)
+	resp, err = c.handleWriteResponse("request", "req-2", "op",
+		newResp(apimodel.Code_ExistedResource), nil, conn, time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(apimodel.Code_ExistedResource), resp.Code)
+	assert.Equal(t, 2, len(connManager.successCodes))
+	assert.Empty(t, connManager.failCodes)
+
+	resp, err = c.handleWriteResponse("request", "req-3", "op",
+		newResp(apimodel.Code_ExecuteSuccess), nil, conn, time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), resp.Code)
+
+	// server内部错误上报调用失败
+	_, err = c.handleWriteResponse("request", "req-4", "op",
+		newResp(apimodel.Code_StoreLayerException), nil, conn, time.Now())
+	assert.NotNil(t, err)
+	assert.Equal(t, model.ErrCodeServerException, err.(model.SDKError).ErrorCode())
+	assert.Equal(t, 1, len(connManager.failCodes))
+	assert.Equal(t, 0, connManager.downCount)
+}
@@ -28,6 +28,8 @@ import (
 // Config crypto filter config
 type Config struct {
 	Entries []ConfigEntry `yaml:"entries"`
+	// ServerPublicKey 服务端的RSA公钥（base64编码的PKCS1格式），上传加密配置时用于加密数据密钥
+	ServerPublicKey string `yaml:"serverPublicKey"`
 }
 
 // ConfigEntry config entry
@@ -167,6 +167,47 @@ func (c *CryptoFilter) DoFilter(configFile *configconnector.ConfigFile, next con
 	}
 }
 
+// BeforeUpload 上传加密配置文件时，生成数据密钥加密配置内容，未指定加密算法时使用第一个配置的加密算法；
+// 数据密钥使用服务端的RSA公钥加密后随请求上传，未配置服务端公钥时不允许上传加密配置
+func (c *CryptoFilter) BeforeUpload(configFile *configconnector.ConfigFile) error {
+	if !configFile.GetEncrypted() {
+		return nil
+	}
+	encryptAlgo := configFile.GetEncryptAlgo()
+	if encryptAlgo == "" && c.cfg != nil && len(c.cfg.Entries) > 0 {
+		encryptAlgo = c.cfg.Entries[0].Name
+	}
+	crypto, err := c.GetCrypto(encryptAlgo)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err,
+			"encrypt algorithm %q of config file %s is not supported", encryptAlgo, configFile.GetFileName())
+	}
+	if c.cfg == nil || c.cfg.ServerPublicKey == "" {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, nil,
+			"config.configFilter.plugin.crypto.serverPublicKey is required to upload encrypted config file %s",
+			configFile.GetFileName())
+	}
+	dataKey, err := crypto.GenerateKey()
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeInternalError, err,
+			"fail to generate data key for config file %s", configFile.GetFileName())
+	}
+	cipherContent, err := crypto.Encrypt(configFile.GetContent(), dataKey)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeInternalError, err,
+			"fail to encrypt config file %s", configFile.GetFileName())
+	}
+	cipherDataKey, err := rsa.EncryptToBase64(dataKey, c.cfg.ServerPublicKey)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err,
+			"fail to encrypt data key of config file %s with server public key", configFile.GetFileName())
+	}
+	configFile.Content = cipherContent
+	configFile.SetTag(configconnector.ConfigFileTagKeyEncryptAlgo, encryptAlgo)
+	configFile.SetTag(configconnector.ConfigFileTagKeyDataKey, cipherDataKey)
+	return nil
+}
+
 // GetCrypto get crypto by algorithm
 func (c *CryptoFilter) GetCrypto(algo string) (Crypto, error) {
 	crypto, ok := c.cryptos[algo]
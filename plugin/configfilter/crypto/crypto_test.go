@@ -0,0 +1,104 @@
+/*
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ *  under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ *
+ */
+
+package crypto
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/log/logtest"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/configconnector"
+	"github.com/polarismesh/polaris-go/plugin/configfilter/crypto/rsa"
+)
+
+func init() {
+	logtest.DiscardLoggers()
+}
+
+// xorCrypto 按数据密钥异或的测试加密算法
+type xorCrypto struct{}
+
+func (xorCrypto) GenerateKey() ([]byte, error) {
+	return []byte("data-key"), nil
+}
+
+func (xorCrypto) Encrypt(plaintext string, key []byte) (string, error) {
+	return base64.StdEncoding.EncodeToString(xorBytes([]byte(plaintext), key)), nil
+}
+
+func (xorCrypto) Decrypt(cryptotext string, key []byte) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(cryptotext)
+	if err != nil {
+		return "", err
+	}
+	return string(xorBytes(data, key)), nil
+}
+
+func xorBytes(data []byte, key []byte) []byte {
+	result := make([]byte, len(data))
+	for i := range data {
+		result[i] = data[i] ^ key[i%len(key)]
+	}
+	return result
+}
+
+// TestBeforeUpload 测试上传加密配置时加密配置内容，数据密钥使用服务端公钥加密后上传
+func TestBeforeUpload(t *testing.T) {
+	serverKey, err := rsa.GenerateRSAKey()
+	assert.Nil(t, err)
+	filter := &CryptoFilter{
+		cfg:     &Config{Entries: []ConfigEntry{{Name: "AES"}}, ServerPublicKey: serverKey.PublicKey},
+		cryptos: map[string]Crypto{"AES": xorCrypto{}},
+	}
+
+	configFile := &configconnector.ConfigFile{FileName: "app.yaml", Content: "plain"}
+	assert.Nil(t, filter.BeforeUpload(configFile))
+	assert.Empty(t, configFile.Tags)
+	assert.Equal(t, "plain", configFile.GetContent())
+
+	configFile.Encrypted = true
+	configFile.SetTag(configconnector.ConfigFileTagKeyDataKey, "raw-key")
+	assert.Nil(t, filter.BeforeUpload(configFile))
+	assert.Equal(t, "AES", configFile.GetEncryptAlgo())
+	assert.NotEqual(t, "plain", configFile.GetContent())
+	assert.NotEqual(t, "raw-key", configFile.GetDataKey())
+	// 服务端使用私钥解密数据密钥后，可以解密出原始配置内容
+	dataKey, err := rsa.DecryptFromBase64(configFile.GetDataKey(), serverKey.PrivateKey)
+	assert.Nil(t, err)
+	plainContent, err := xorCrypto{}.Decrypt(configFile.GetContent(), dataKey)
+	assert.Nil(t, err)
+	assert.Equal(t, "plain", plainContent)
+
+	configFile = &configconnector.ConfigFile{FileName: "app.yaml", Content: "plain", Encrypted: true}
+	configFile.SetTag(configconnector.ConfigFileTagKeyEncryptAlgo, "SM4")
+	err = filter.BeforeUpload(configFile)
+	assert.NotNil(t, err)
+	assert.Equal(t, model.ErrCodeAPIInvalidArgument, err.(model.SDKError).ErrorCode())
+
+	// 未配置服务端公钥时不允许以明文上传加密配置
+	filter.cfg.ServerPublicKey = ""
+	configFile = &configconnector.ConfigFile{FileName: "app.yaml", Content: "plain", Encrypted: true}
+	err = filter.BeforeUpload(configFile)
+	assert.NotNil(t, err)
+	assert.Equal(t, model.ErrCodeAPIInvalidArgument, err.(model.SDKError).ErrorCode())
+	assert.Equal(t, "plain", configFile.GetContent())
+}
@@ -18,10 +18,14 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -78,6 +82,56 @@ func (g *Detector) DetectInstance(ins model.Instance) (result healthcheck.Detect
 	return result, nil
 }
 
+// DetectInstanceWithRule 按照服务端下发的探测规则探测服务实例健康
+func (g *Detector) DetectInstanceWithRule(
+	ins model.Instance, rule *apifault.FaultDetectRule) (healthcheck.DetectResult, error) {
+	start := time.Now()
+	port := uint32(ins.GetPort())
+	if rule.GetPort() > 0 {
+		port = rule.GetPort()
+	}
+	address := fmt.Sprintf("%s:%d", ins.GetHost(), port)
+	success := g.doRuleHttpDetect(address, rule)
+	return &healthcheck.DetectResultImp{
+		Success:        success,
+		DetectTime:     start,
+		DetectInstance: ins,
+	}, nil
+}
+
+// doRuleHttpDetect 按照探测规则执行一次健康探测逻辑
+func (g *Detector) doRuleHttpDetect(address string, rule *apifault.FaultDetectRule) bool {
+	httpConfig := rule.GetHttpConfig()
+	method := httpConfig.GetMethod()
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	path := httpConfig.GetUrl()
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	request, err := http.NewRequest(method, "http://"+address+path, strings.NewReader(httpConfig.GetBody()))
+	if err != nil {
+		log.GetDetectLogger().Errorf("[HealthCheck][http] fail to build request for %s, rule %s, err is %v",
+			address, rule.GetName(), err)
+		return false
+	}
+	for _, header := range httpConfig.GetHeaders() {
+		request.Header.Add(header.GetKey(), header.GetValue())
+	}
+	c := &http.Client{
+		Timeout: time.Duration(rule.GetTimeout()) * time.Second,
+	}
+	resp, err := c.Do(request)
+	if err != nil {
+		log.GetDetectLogger().Errorf("[HealthCheck][http] fail to check %s, rule %s, err is %v",
+			address, rule.GetName(), err)
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest
+}
+
 // IsEnable .
 func (g *Detector) IsEnable(cfg config.Configuration) bool {
 	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
@@ -18,9 +18,13 @@
 package tcp
 
 import (
+	"bytes"
+	"fmt"
 	"net"
 	"time"
 
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -90,6 +94,64 @@ func (g *Detector) doTCPDetect(address string) bool {
 	return true
 }
 
+// DetectInstanceWithRule 按照服务端下发的探测规则探测服务实例健康
+func (g *Detector) DetectInstanceWithRule(
+	ins model.Instance, rule *apifault.FaultDetectRule) (healthcheck.DetectResult, error) {
+	start := time.Now()
+	port := uint32(ins.GetPort())
+	if rule.GetPort() > 0 {
+		port = rule.GetPort()
+	}
+	address := fmt.Sprintf("%s:%d", ins.GetHost(), port)
+	success := g.doRuleTCPDetect(address, rule)
+	return &healthcheck.DetectResultImp{
+		Success:        success,
+		DetectTime:     start,
+		DetectInstance: ins,
+	}, nil
+}
+
+// 探测应答的最大读取长度
+const maxReceiveBytes = 1024
+
+// doRuleTCPDetect 按照探测规则执行一次探测逻辑，配置了应答包时需要匹配其中之一
+func (g *Detector) doRuleTCPDetect(address string, rule *apifault.FaultDetectRule) bool {
+	timeout := time.Duration(rule.GetTimeout()) * time.Second
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		log.GetDetectLogger().Errorf("[HealthCheck][tcp] fail to check %s, rule %s, err is %v",
+			address, rule.GetName(), err)
+		return false
+	}
+	defer conn.Close()
+	tcpConfig := rule.GetTcpConfig()
+	if len(tcpConfig.GetSend()) == 0 {
+		return true
+	}
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	if _, err = conn.Write([]byte(tcpConfig.GetSend())); err != nil {
+		log.GetDetectLogger().Errorf("[HealthCheck][tcp] fail to send to %s, rule %s, err is %v",
+			address, rule.GetName(), err)
+		return false
+	}
+	if len(tcpConfig.GetReceive()) == 0 {
+		return true
+	}
+	buf := make([]byte, maxReceiveBytes)
+	n, err := conn.Read(buf)
+	if err != nil {
+		log.GetDetectLogger().Errorf("[HealthCheck][tcp] fail to receive from %s, rule %s, err is %v",
+			address, rule.GetName(), err)
+		return false
+	}
+	for _, receive := range tcpConfig.GetReceive() {
+		if bytes.HasPrefix(buf[:n], []byte(receive)) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEnable enable
 func (g *Detector) IsEnable(cfg config.Configuration) bool {
 	return cfg.GetGlobal().GetSystem().GetMode() != model.ModeWithAgent
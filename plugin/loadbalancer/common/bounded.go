@@ -0,0 +1,140 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	"github.com/polarismesh/polaris-go/pkg/stat/loadbalance"
+)
+
+// RingWalker 支持按照环的顺序遍历实例的选择器
+type RingWalker interface {
+	// Walk 从hashValue在环上对应的位置开始顺序遍历，visit返回true时停止遍历并返回当前节点的实例下标，
+	// 遍历一圈仍未停止时返回-1
+	Walk(hashValue uint64, visit func(index int) bool) int
+}
+
+// VerifyLoadFactor 校验有界负载的负载系数，0表示不启用，启用时必须大于1
+func VerifyLoadFactor(pluginName string, loadFactor float64) error {
+	if loadFactor != 0 && loadFactor <= 1 {
+		return fmt.Errorf("%s.loadFactor must be 0 or greater than 1", pluginName)
+	}
+	return nil
+}
+
+// BoundedLoad 有界负载的一致性hash，记录实例的在途请求数，
+// hash命中的实例在途请求数达到上限时，沿着环顺延到下一个未超过上限的实例，上限为平均在途请求数乘以负载系数
+// 参考论文：https://arxiv.org/abs/1608.01350
+type BoundedLoad struct {
+	loadFactor float64
+	valueCtx   model.ValueContext
+	// 实例的在途请求数，未启用时为nil
+	inflight *InflightTracker
+}
+
+// NewBoundedLoad 创建有界负载的公共逻辑，loadFactor为0时不启用
+func NewBoundedLoad(ctx *plugin.InitContext, pluginID int32, loadFactor float64) *BoundedLoad {
+	b := &BoundedLoad{
+		loadFactor: loadFactor,
+		valueCtx:   ctx.ValueCtx,
+	}
+	if !b.IsEnable() {
+		return b
+	}
+	b.inflight = NewInflightTracker(ctx, pluginID, func() InflightHolder {
+		return &InflightCounter{}
+	})
+	return b
+}
+
+// IsEnable 是否启用有界负载
+func (b *BoundedLoad) IsEnable() bool {
+	return b.loadFactor > 0
+}
+
+// CalcLoadBound 计算在途请求数的上限，为加上本次请求后的平均在途请求数乘以负载系数，向上取整
+func CalcLoadBound(loadFactor float64, totalInflight int64, instanceCount int) int64 {
+	if instanceCount <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(loadFactor * float64(totalInflight+1) / float64(instanceCount)))
+}
+
+// ChooseInstance 从hashValue对应的位置开始沿着环选择在途请求数未达到上限且未被排除的实例，
+// 顺延选择时上报负载均衡统计，没有可选实例时返回nil；在途请求数在负载均衡代理接受选择结果时增加
+func (b *BoundedLoad) ChooseInstance(criteria *loadbalancer.Criteria, hashValue uint64,
+	svcInstances model.ServiceInstances, targetInstances *model.InstanceSet, walker RingWalker) model.Instance {
+	allInstances := svcInstances.GetInstances()
+	var totalInflight int64
+	for _, weightedIndex := range targetInstances.GetInstances() {
+		totalInflight += b.inflight.Count(allInstances[weightedIndex.Index])
+	}
+	bound := CalcLoadBound(b.loadFactor, totalInflight, targetInstances.Count())
+	hashedIndex := -1
+	index := walker.Walk(hashValue, func(index int) bool {
+		if hashedIndex < 0 {
+			hashedIndex = index
+		}
+		instance := allInstances[index]
+		if criteria.HasExclusion() && criteria.IsExcluded(instance) {
+			return false
+		}
+		return b.inflight.Count(instance) < bound
+	})
+	if index < 0 {
+		return nil
+	}
+	instance := allInstances[index]
+	if index != hashedIndex {
+		b.reportOverflow(allInstances[hashedIndex], instance, bound)
+	}
+	return instance
+}
+
+// OnInstanceAccepted 负载均衡代理接受选择结果，启用有界负载时在途请求数加一
+func (b *BoundedLoad) OnInstanceAccepted(instance model.Instance) {
+	if b.IsEnable() {
+		b.inflight.OnInstanceAccepted(instance)
+	}
+}
+
+// reportOverflow 上报hash命中的实例超过负载上限的统计
+func (b *BoundedLoad) reportOverflow(hashedInstance model.Instance, instance model.Instance, bound int64) {
+	if nil == b.valueCtx {
+		return
+	}
+	engine := b.valueCtx.GetEngine()
+	if nil == engine {
+		return
+	}
+	gauge := &loadbalance.LoadBalanceGauge{
+		Inst:       instance,
+		Overflow:   true,
+		HashedInst: hashedInstance,
+		LoadBound:  bound,
+	}
+	if err := engine.SyncReportStat(model.LoadBalanceStat, gauge); err != nil {
+		log.GetBaseLogger().Errorf("fail to report load balance overflow, error %v", err)
+	}
+}
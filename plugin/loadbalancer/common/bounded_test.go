@@ -0,0 +1,126 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+)
+
+// TestCalcLoadBound 测试有界负载的在途请求数上限计算
+func TestCalcLoadBound(t *testing.T) {
+	// 没有在途请求时，上限至少为1，保证hash命中的实例可以被选中
+	assert.Equal(t, int64(1), CalcLoadBound(1.25, 0, 4))
+	// (7+1)/4*1.25=2.5，向上取整为3
+	assert.Equal(t, int64(3), CalcLoadBound(1.25, 7, 4))
+	assert.Equal(t, int64(0), CalcLoadBound(1.25, 7, 0))
+
+	assert.Nil(t, VerifyLoadFactor("ringhash", 0))
+	assert.Nil(t, VerifyLoadFactor("ringhash", 1.25))
+	assert.NotNil(t, VerifyLoadFactor("ringhash", 1))
+	assert.NotNil(t, VerifyLoadFactor("ringhash", -1))
+}
+
+// eventSupplier 只记录插件事件监听器的插件管理器
+type eventSupplier struct {
+	plugin.Supplier
+	handlers map[common.PluginEventType][]common.PluginEventHandler
+}
+
+func (s *eventSupplier) RegisterEventSubscriber(event common.PluginEventType, handler common.PluginEventHandler) {
+	s.handlers[event] = append(s.handlers[event], handler)
+}
+
+func (s *eventSupplier) fire(event common.PluginEventType, object interface{}) {
+	for _, handler := range s.handlers[event] {
+		_ = handler.Callback(&common.PluginEvent{EventType: event, EventObject: object})
+	}
+}
+
+// sequenceWalker 从hashValue对应的实例下标开始顺序遍历
+type sequenceWalker int
+
+func (w sequenceWalker) Walk(hashValue uint64, visit func(index int) bool) int {
+	for i := 0; i < int(w); i++ {
+		index := (int(hashValue) + i) % int(w)
+		if visit(index) {
+			return index
+		}
+	}
+	return -1
+}
+
+// TestBoundedLoadChooseInstance 测试在途请求数达到上限时顺延选择，只有负载均衡代理接受的实例才增加在途请求数
+func TestBoundedLoadChooseInstance(t *testing.T) {
+	supplier := &eventSupplier{handlers: map[common.PluginEventType][]common.PluginEventHandler{}}
+	b := NewBoundedLoad(&plugin.InitContext{Plugins: supplier}, 1, 1.25)
+	resp := &apiservice.DiscoverResponse{
+		Service: &apiservice.Service{Namespace: wrapperspb.String("default"), Name: wrapperspb.String("echo")},
+	}
+	for i, id := range []string{"a", "b", "c"} {
+		resp.Instances = append(resp.Instances, &apiservice.Instance{
+			Id:      wrapperspb.String(id),
+			Host:    wrapperspb.String("127.0.0.1"),
+			Port:    wrapperspb.UInt32(uint32(8001 + i)),
+			Weight:  wrapperspb.UInt32(100),
+			Healthy: wrapperspb.Bool(true),
+		})
+	}
+	svcInstances := pb.NewServiceInstancesInProto(resp, func(string) local.InstanceLocalValue {
+		localValue := local.NewInstanceLocalValue()
+		supplier.fire(common.OnInstanceLocalValueCreated, localValue)
+		return localValue
+	}, nil, nil)
+	criteria := &loadbalancer.Criteria{Cluster: model.NewCluster(svcInstances.GetServiceClusters(), nil)}
+	targetInstances := criteria.Cluster.GetClusterValue().GetInstancesSet(false, true)
+	choose := func() string {
+		return b.ChooseInstance(criteria, 0, svcInstances, targetInstances, sequenceWalker(3)).GetId()
+	}
+	a := svcInstances.GetInstance("a")
+
+	// 未被代理接受的选择结果不占用在途请求数
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, "a", choose())
+	}
+	assert.Equal(t, int64(0), b.inflight.Count(a))
+
+	// 上限为ceil(1.25*(2+1)/3)=2，hash命中的实例达到上限后顺延
+	b.OnInstanceAccepted(a)
+	b.OnInstanceAccepted(a)
+	assert.Equal(t, "b", choose())
+
+	result := &model.ServiceCallResult{}
+	result.SetCalledInstance(a)
+	supplier.fire(common.OnServiceCallResult, result)
+	supplier.fire(common.OnServiceCallResult, result)
+	assert.Equal(t, int64(0), b.inflight.Count(a))
+	assert.Equal(t, "a", choose())
+
+	criteria.ExcludeInstances = []model.Instance{a}
+	assert.Equal(t, "b", choose())
+}
@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+)
+
+const (
+	// 选中的实例被排除后，重新计算hash进行选择的最大次数
+	maxExcludeProbeTimes = 8
+	// 探测hash值的步长，使用黄金分割数使探测位置均匀分布
+	probeHashStep uint64 = 0x9E3779B97F4A7C15
+)
+
+// IndexSelector 根据负载均衡条件选择实例在服务实例列表中的下标
+type IndexSelector func(criteria *loadbalancer.Criteria) (int, error)
+
+// SelectWeightedExcluded 在未被排除的实例中按照权重随机选择，所有实例都被排除时返回nil
+func SelectWeightedExcluded(scalableRand *rand.ScalableRand, criteria *loadbalancer.Criteria,
+	svcInstances model.ServiceInstances, targetInstances *model.InstanceSet) model.Instance {
+	allInstances := svcInstances.GetInstances()
+	weightedIndexes := targetInstances.GetInstances()
+	candidates := make([]model.Instance, 0, len(weightedIndexes))
+	weights := make([]int, 0, len(weightedIndexes))
+	var totalWeight, lastAccumulate int
+	for _, weightedIndex := range weightedIndexes {
+		weight := weightedIndex.AccumulateWeight - lastAccumulate
+		lastAccumulate = weightedIndex.AccumulateWeight
+		instance := allInstances[weightedIndex.Index]
+		if weight <= 0 || criteria.IsExcluded(instance) {
+			continue
+		}
+		candidates = append(candidates, instance)
+		weights = append(weights, weight)
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return nil
+	}
+	selector := scalableRand.Intn(totalWeight)
+	for i, weight := range weights {
+		if selector < weight {
+			return candidates[i]
+		}
+		selector -= weight
+	}
+	return candidates[len(candidates)-1]
+}
+
+// ReselectExcluded 基于hash的负载均衡选中的实例被排除时，使用探测hash值重新选择，
+// 相同的hash值在相同的排除列表下结果稳定；多次探测仍然命中被排除的实例时，从hash位置开始顺序查找
+func ReselectExcluded(criteria *loadbalancer.Criteria, hashValue uint64,
+	svcInstances model.ServiceInstances, targetInstances *model.InstanceSet,
+	selectIndex IndexSelector) (model.Instance, error) {
+	allInstances := svcInstances.GetInstances()
+	probeCriteria := *criteria
+	probeCriteria.HashKey = nil
+	probeCriteria.ReplicateInfo = loadbalancer.ReplicateInfo{}
+	for i := 1; i <= maxExcludeProbeTimes; i++ {
+		probeCriteria.HashValue = hashValue + uint64(i)*probeHashStep
+		index, err := selectIndex(&probeCriteria)
+		if err != nil {
+			return nil, err
+		}
+		if index < 0 {
+			break
+		}
+		if instance := allInstances[index]; !criteria.IsExcluded(instance) {
+			return instance, nil
+		}
+	}
+	weightedIndexes := targetInstances.GetInstances()
+	if len(weightedIndexes) > 0 {
+		offset := int(hashValue % uint64(len(weightedIndexes)))
+		for i := 0; i < len(weightedIndexes); i++ {
+			instance := allInstances[weightedIndexes[(offset+i)%len(weightedIndexes)].Index]
+			if !criteria.IsExcluded(instance) {
+				return instance, nil
+			}
+		}
+	}
+	return nil, ExcludedError(svcInstances, targetInstances.Count())
+}
+
+// SelectorIndex 将扩展选择器转换为下标选择函数
+func SelectorIndex(selector model.ExtendedSelector) IndexSelector {
+	return func(criteria *loadbalancer.Criteria) (int, error) {
+		index, _, err := selector.Select(criteria)
+		return index, err
+	}
+}
+
+// ExcludedError 所有可用实例都被排除时返回的错误
+func ExcludedError(svcInstances model.ServiceInstances, count int) error {
+	return model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+		"all %d available instances of service %s(namespace %s) are excluded in load balance",
+		count, svcInstances.GetService(), svcInstances.GetNamespace())
+}
@@ -0,0 +1,142 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"sync/atomic"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// InflightCounter 实例的在途请求数
+type InflightCounter struct {
+	count int64
+}
+
+// GetInflightCounter 获取在途请求计数，实例扩展数据通过嵌入InflightCounter实现InflightHolder
+func (c *InflightCounter) GetInflightCounter() *InflightCounter {
+	return c
+}
+
+// Acquire 在途请求数加一
+func (c *InflightCounter) Acquire() {
+	atomic.AddInt64(&c.count, 1)
+}
+
+// Release 在途请求数减一，未经过负载均衡选择的实例上报调用结果时不会减为负数
+func (c *InflightCounter) Release() {
+	for {
+		current := atomic.LoadInt64(&c.count)
+		if current <= 0 {
+			return
+		}
+		if atomic.CompareAndSwapInt64(&c.count, current, current-1) {
+			return
+		}
+	}
+}
+
+// Count 获取在途请求数
+func (c *InflightCounter) Count() int64 {
+	return atomic.LoadInt64(&c.count)
+}
+
+// InflightHolder 包含在途请求计数的实例扩展数据
+type InflightHolder interface {
+	GetInflightCounter() *InflightCounter
+}
+
+// InflightTracker 基于实例扩展数据记录在途请求数，负载均衡代理接受选择结果时加一，上报调用结果时减一，
+// 因此被熔断或者排除而重新选择的实例不会占用在途请求数
+type InflightTracker struct {
+	pluginID int32
+	newData  func() InflightHolder
+}
+
+// NewInflightTracker 创建在途请求数记录，newData为每个实例创建扩展数据，扩展数据以pluginID为key保存
+func NewInflightTracker(ctx *plugin.InitContext, pluginID int32, newData func() InflightHolder) *InflightTracker {
+	t := &InflightTracker{
+		pluginID: pluginID,
+		newData:  newData,
+	}
+	ctx.Plugins.RegisterEventSubscriber(common.OnInstanceLocalValueCreated, common.PluginEventHandler{
+		Callback: t.generateInstanceData,
+	})
+	ctx.Plugins.RegisterEventSubscriber(common.OnServiceCallResult, common.PluginEventHandler{
+		Callback: t.onServiceCallResult,
+	})
+	return t
+}
+
+// 为新创建的实例生成扩展数据
+func (t *InflightTracker) generateInstanceData(event *common.PluginEvent) error {
+	localValue := event.EventObject.(*local.DefaultInstanceLocalValue)
+	localValue.SetExtendedData(t.pluginID, t.newData())
+	return nil
+}
+
+// 上报调用结果时释放在途请求
+func (t *InflightTracker) onServiceCallResult(event *common.PluginEvent) error {
+	result, ok := event.EventObject.(*model.ServiceCallResult)
+	if !ok || nil == result.GetCalledInstance() {
+		return nil
+	}
+	if counter := t.GetCounter(result.GetCalledInstance()); nil != counter {
+		counter.Release()
+	}
+	return nil
+}
+
+// GetData 获取实例的扩展数据，实例不是本地缓存的实例时返回nil
+func (t *InflightTracker) GetData(instance model.Instance) InflightHolder {
+	localValue, ok := instance.(local.InstanceLocalValue)
+	if !ok {
+		return nil
+	}
+	data, ok := localValue.GetExtendedData(t.pluginID).(InflightHolder)
+	if !ok {
+		return nil
+	}
+	return data
+}
+
+// GetCounter 获取实例的在途请求计数
+func (t *InflightTracker) GetCounter(instance model.Instance) *InflightCounter {
+	if data := t.GetData(instance); nil != data {
+		return data.GetInflightCounter()
+	}
+	return nil
+}
+
+// Count 获取实例的在途请求数
+func (t *InflightTracker) Count(instance model.Instance) int64 {
+	if counter := t.GetCounter(instance); nil != counter {
+		return counter.Count()
+	}
+	return 0
+}
+
+// OnInstanceAccepted 负载均衡代理接受选择结果，在途请求数加一
+func (t *InflightTracker) OnInstanceAccepted(instance model.Instance) {
+	if counter := t.GetCounter(instance); nil != counter {
+		counter.Acquire()
+	}
+}
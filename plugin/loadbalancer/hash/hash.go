@@ -81,12 +81,24 @@ func (g *LoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
 	if err != nil {
 		return nil, model.NewSDKError(model.ErrCodeInternalError, err, "fail to cal hash value")
 	}
-	targetValue := hashValue % uint64(targetInstances.TotalWeight())
-	weightedSlice := targetInstances
-	// 按照权重区间来寻找
-	targetIndex := search.BinarySearch(weightedSlice, uint64(targetValue))
-	instanceIndex := targetInstances.GetInstances()[targetIndex]
-	instance = svcInstances.GetInstances()[instanceIndex.Index]
+	selectIndex := func(criteria *loadbalancer.Criteria) (int, error) {
+		hashValue, err := lbcommon.CalcHashValue(criteria, g.hashFunc)
+		if err != nil {
+			return -1, model.NewSDKError(model.ErrCodeInternalError, err, "fail to cal hash value")
+		}
+		targetValue := hashValue % uint64(targetInstances.TotalWeight())
+		// 按照权重区间来寻找
+		targetIndex := search.BinarySearch(targetInstances, targetValue)
+		return targetInstances.GetInstances()[targetIndex].Index, nil
+	}
+	index, err := selectIndex(criteria)
+	if err != nil {
+		return nil, err
+	}
+	instance = svcInstances.GetInstances()[index]
+	if criteria.IsExcluded(instance) {
+		return lbcommon.ReselectExcluded(criteria, hashValue, svcInstances, targetInstances, selectIndex)
+	}
 	return instance, nil
 }
 
@@ -0,0 +1,69 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package leastrequest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// DefaultChoiceCount 默认每次随机挑选的候选实例数
+	DefaultChoiceCount = 2
+	// DefaultDecayTime 默认的时延衰减时间
+	DefaultDecayTime = 10 * time.Second
+)
+
+// Config 最少在途请求负载均衡配置对象
+type Config struct {
+	// 每次按权重随机挑选的候选实例数，从中选择负载最低的实例
+	ChoiceCount int `yaml:"choiceCount" json:"choiceCount"`
+	// 峰值EWMA时延的衰减时间，时间越长，历史时延的影响越久
+	DecayTime *time.Duration `yaml:"decayTime" json:"decayTime"`
+}
+
+// Verify 检验最少在途请求负载均衡配置
+func (c *Config) Verify() error {
+	var errs error
+	if c.ChoiceCount < 2 {
+		errs = multierror.Append(errs, fmt.Errorf("leastRequest.choiceCount must be greater than 1"))
+	}
+	if nil != c.DecayTime && *c.DecayTime <= 0 {
+		errs = multierror.Append(errs, fmt.Errorf("leastRequest.decayTime must be greater than 0"))
+	}
+	return errs
+}
+
+// SetDefault 设置最少在途请求负载均衡配置默认值
+func (c *Config) SetDefault() {
+	if c.ChoiceCount == 0 {
+		c.ChoiceCount = DefaultChoiceCount
+	}
+	if nil == c.DecayTime {
+		c.DecayTime = model.ToDurationPtr(DefaultDecayTime)
+	}
+}
+
+// GetDecayTime 峰值EWMA时延的衰减时间
+func (c *Config) GetDecayTime() time.Duration {
+	return *c.DecayTime
+}
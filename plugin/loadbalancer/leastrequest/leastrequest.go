@@ -0,0 +1,154 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package leastrequest
+
+import (
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/clock"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	lbcommon "github.com/polarismesh/polaris-go/plugin/loadbalancer/common"
+)
+
+// LoadBalancer 最少在途请求负载均衡插件
+// 每次按照权重（静态权重或者动态权重）随机挑选若干个候选实例，选择其中峰值EWMA时延与在途请求数乘积最小的实例；
+// 负载均衡代理接受选择结果时在途请求数加一，通过UpdateServiceCallResult上报调用结果时减一并更新时延，
+// 因此每次选择都需要上报调用结果
+type LoadBalancer struct {
+	*plugin.PluginBase
+	cfg          *Config
+	decayTime    float64
+	scalableRand *rand.ScalableRand
+	inflight     *lbcommon.InflightTracker
+}
+
+// Type 插件类型
+func (l *LoadBalancer) Type() common.Type {
+	return common.TypeLoadBalancer
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (l *LoadBalancer) Name() string {
+	return config.DefaultLoadBalancerLeastRequest
+}
+
+// Init 初始化插件
+func (l *LoadBalancer) Init(ctx *plugin.InitContext) error {
+	l.PluginBase = plugin.NewPluginBase(ctx)
+	l.cfg = ctx.Config.GetConsumer().GetLoadbalancer().GetPluginConfig(l.Name()).(*Config)
+	l.decayTime = float64(l.cfg.GetDecayTime())
+	l.scalableRand = rand.NewScalableRand()
+	l.inflight = lbcommon.NewInflightTracker(ctx, l.ID(), func() lbcommon.InflightHolder {
+		return &instanceStat{}
+	})
+	ctx.Plugins.RegisterEventSubscriber(common.OnServiceCallResult, common.PluginEventHandler{
+		Callback: l.onServiceCallResult,
+	})
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (l *LoadBalancer) Destroy() error {
+	return nil
+}
+
+// 获取实例的统计数据
+func (l *LoadBalancer) getInstanceStat(instance model.Instance) *instanceStat {
+	stat, ok := l.inflight.GetData(instance).(*instanceStat)
+	if !ok {
+		return nil
+	}
+	return stat
+}
+
+// 上报调用结果时更新时延，在途请求由InflightTracker释放
+func (l *LoadBalancer) onServiceCallResult(event *common.PluginEvent) error {
+	result, ok := event.EventObject.(*model.ServiceCallResult)
+	if !ok || nil == result.GetCalledInstance() {
+		return nil
+	}
+	stat := l.getInstanceStat(result.GetCalledInstance())
+	if nil == stat {
+		return nil
+	}
+	if delay := result.GetDelay(); nil != delay {
+		stat.observe(float64(*delay), clock.GetClock().Now().UnixNano(), l.decayTime)
+	}
+	return nil
+}
+
+// ChooseInstance 获取单个服务实例
+func (l *LoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
+	inputInstances model.ServiceInstances) (model.Instance, error) {
+	cluster := criteria.Cluster
+	svcClusters := inputInstances.GetServiceClusters()
+	svcInstances := svcClusters.GetServiceInstances()
+	targetInstances := lbcommon.SelectAvailableInstanceSet(cluster.GetClusterValue(), cluster.HasLimitedInstances,
+		cluster.IncludeHalfOpen)
+	if targetInstances.TotalWeight() == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"instances of %s in cluster %s all weight 0 (instance count %d) in load balance, includeHalfOpen: %v",
+			svcClusters.GetServiceKey(), *cluster, targetInstances.Count(), cluster.IncludeHalfOpen)
+	}
+	now := clock.GetClock().Now().UnixNano()
+	var (
+		chosen  model.Instance
+		minCost float64
+	)
+	for i := 0; i < l.cfg.ChoiceCount; i++ {
+		candidate := l.selectCandidate(criteria, svcInstances, targetInstances)
+		if nil == candidate {
+			return nil, lbcommon.ExcludedError(svcInstances, targetInstances.Count())
+		}
+		stat := l.getInstanceStat(candidate)
+		var cost float64
+		if nil != stat {
+			cost = stat.cost(now, l.decayTime)
+		}
+		if nil == chosen || cost < minCost {
+			chosen, minCost = candidate, cost
+		}
+	}
+	return chosen, nil
+}
+
+// OnInstanceAccepted 负载均衡代理接受选择结果，在途请求数加一
+func (l *LoadBalancer) OnInstanceAccepted(instance model.Instance) {
+	l.inflight.OnInstanceAccepted(instance)
+}
+
+// selectCandidate 按照权重随机挑选一个候选实例
+func (l *LoadBalancer) selectCandidate(criteria *loadbalancer.Criteria,
+	svcInstances model.ServiceInstances, targetInstances *model.InstanceSet) model.Instance {
+	if criteria.HasExclusion() {
+		return lbcommon.SelectWeightedExcluded(l.scalableRand, criteria, svcInstances, targetInstances)
+	}
+	index := rand.SelectWeightedRandItem(l.scalableRand, targetInstances)
+	if index < 0 {
+		return nil
+	}
+	return svcInstances.GetInstances()[targetInstances.GetInstances()[index].Index]
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&LoadBalancer{}, &Config{})
+}
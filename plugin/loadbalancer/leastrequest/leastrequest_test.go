@@ -0,0 +1,143 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package leastrequest
+
+import (
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+)
+
+// eventSupplier 只记录插件事件监听器的插件管理器
+type eventSupplier struct {
+	plugin.Supplier
+	handlers map[common.PluginEventType][]common.PluginEventHandler
+}
+
+func (s *eventSupplier) RegisterEventSubscriber(event common.PluginEventType, handler common.PluginEventHandler) {
+	s.handlers[event] = append(s.handlers[event], handler)
+}
+
+func (s *eventSupplier) fire(event common.PluginEventType, object interface{}) {
+	for _, handler := range s.handlers[event] {
+		_ = handler.Callback(&common.PluginEvent{EventType: event, EventObject: object})
+	}
+}
+
+// newTestBalancer 创建负载均衡插件以及带有插件统计数据的服务实例
+func newTestBalancer(t *testing.T, ids ...string) (*LoadBalancer, *eventSupplier, model.ServiceInstances) {
+	supplier := &eventSupplier{handlers: map[common.PluginEventType][]common.PluginEventHandler{}}
+	lb := &LoadBalancer{}
+	assert.Nil(t, lb.Init(&plugin.InitContext{
+		Config:      config.NewDefaultConfiguration(nil),
+		Plugins:     supplier,
+		PluginIndex: 1,
+	}))
+	// 候选实例数足够多，保证每次选择都能比较所有实例
+	lb.cfg.ChoiceCount = 64
+
+	resp := &apiservice.DiscoverResponse{
+		Service: &apiservice.Service{Namespace: wrapperspb.String("default"), Name: wrapperspb.String("echo")},
+	}
+	for i, id := range ids {
+		resp.Instances = append(resp.Instances, &apiservice.Instance{
+			Id:      wrapperspb.String(id),
+			Host:    wrapperspb.String("127.0.0.1"),
+			Port:    wrapperspb.UInt32(uint32(8001 + i)),
+			Weight:  wrapperspb.UInt32(100),
+			Healthy: wrapperspb.Bool(true),
+		})
+	}
+	svcInstances := pb.NewServiceInstancesInProto(resp, func(string) local.InstanceLocalValue {
+		localValue := local.NewInstanceLocalValue()
+		supplier.fire(common.OnInstanceLocalValueCreated, localValue)
+		return localValue
+	}, nil, nil)
+	now := time.Now().UnixNano()
+	for _, instance := range svcInstances.GetInstances() {
+		lb.getInstanceStat(instance).observe(float64(10*time.Millisecond), now, lb.decayTime)
+	}
+	return lb, supplier, svcInstances
+}
+
+func newCriteria(svcInstances model.ServiceInstances) *loadbalancer.Criteria {
+	return &loadbalancer.Criteria{Cluster: model.NewCluster(svcInstances.GetServiceClusters(), nil)}
+}
+
+// TestChooseInstance 测试选择在途请求数最少的实例，只有负载均衡代理接受的实例才增加在途请求数
+func TestChooseInstance(t *testing.T) {
+	lb, supplier, svcInstances := newTestBalancer(t, "a", "b", "c")
+	a, b, c := svcInstances.GetInstance("a"), svcInstances.GetInstance("b"), svcInstances.GetInstance("c")
+	lb.OnInstanceAccepted(a)
+	lb.OnInstanceAccepted(a)
+	lb.OnInstanceAccepted(b)
+
+	// 未被代理接受的选择结果不占用在途请求数
+	for i := 0; i < 10; i++ {
+		instance, err := lb.ChooseInstance(newCriteria(svcInstances), svcInstances)
+		assert.Nil(t, err)
+		assert.Equal(t, "c", instance.GetId())
+	}
+	assert.Equal(t, int64(0), lb.getInstanceStat(c).Count())
+
+	proxy := &loadbalancer.Proxy{LoadBalancer: lb}
+	instance, err := proxy.ChooseInstance(newCriteria(svcInstances), svcInstances)
+	assert.Nil(t, err)
+	assert.Equal(t, "c", instance.GetId())
+	assert.Equal(t, int64(1), lb.getInstanceStat(c).Count())
+
+	// 上报调用结果后释放在途请求
+	result := &model.ServiceCallResult{}
+	result.SetCalledInstance(c)
+	result.SetDelay(10 * time.Millisecond)
+	supplier.fire(common.OnServiceCallResult, result)
+	assert.Equal(t, int64(0), lb.getInstanceStat(c).Count())
+	supplier.fire(common.OnServiceCallResult, result)
+	assert.Equal(t, int64(0), lb.getInstanceStat(c).Count())
+}
+
+// TestChooseInstanceExclude 测试被排除的实例不参与选择
+func TestChooseInstanceExclude(t *testing.T) {
+	lb, _, svcInstances := newTestBalancer(t, "a", "b", "c")
+	a, b, c := svcInstances.GetInstance("a"), svcInstances.GetInstance("b"), svcInstances.GetInstance("c")
+	lb.OnInstanceAccepted(a)
+	lb.OnInstanceAccepted(a)
+	lb.OnInstanceAccepted(b)
+
+	criteria := newCriteria(svcInstances)
+	criteria.ExcludeInstances = []model.Instance{c}
+	instance, err := lb.ChooseInstance(criteria, svcInstances)
+	assert.Nil(t, err)
+	assert.Equal(t, "b", instance.GetId())
+
+	criteria = newCriteria(svcInstances)
+	criteria.ExcludeInstances = []model.Instance{a, b, c}
+	_, err = lb.ChooseInstance(criteria, svcInstances)
+	assert.NotNil(t, err)
+}
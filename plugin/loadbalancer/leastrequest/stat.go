@@ -0,0 +1,79 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package leastrequest
+
+import (
+	"math"
+	"sync"
+
+	lbcommon "github.com/polarismesh/polaris-go/plugin/loadbalancer/common"
+)
+
+// 没有时延数据但存在在途请求的实例的惩罚值，保证优先选择已知时延的实例，单位纳秒
+const unknownLatencyPenalty = float64(math.MaxInt64 >> 16)
+
+// instanceStat 实例的在途请求数以及峰值EWMA时延
+type instanceStat struct {
+	// 在途请求数，负载均衡代理接受选择结果时加一，上报调用结果时减一
+	lbcommon.InflightCounter
+	mutex sync.Mutex
+	// 峰值EWMA时延，单位纳秒
+	latency float64
+	// 上一次更新时延的时间，单位纳秒
+	lastUpdate int64
+}
+
+// observe 合入一次调用时延，时延高于当前值时直接取峰值，否则按照时间衰减进行加权平均
+func (s *instanceStat) observe(rtt float64, now int64, decayTime float64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.lastUpdate == 0 || rtt >= s.latency {
+		s.latency = rtt
+	} else {
+		w := decayWeight(now-s.lastUpdate, decayTime)
+		s.latency = s.latency*w + rtt*(1-w)
+	}
+	s.lastUpdate = now
+}
+
+// cost 计算实例的负载，为衰减后的时延乘以在途请求数加一
+// 长时间没有调用结果的实例时延会逐渐衰减，使其重新获得流量
+func (s *instanceStat) cost(now int64, decayTime float64) float64 {
+	outstanding := float64(s.Count())
+	s.mutex.Lock()
+	latency := s.latency
+	if s.lastUpdate > 0 {
+		latency *= decayWeight(now-s.lastUpdate, decayTime)
+	}
+	s.mutex.Unlock()
+	if latency == 0 {
+		if outstanding == 0 {
+			return 0
+		}
+		return unknownLatencyPenalty + outstanding
+	}
+	return latency * (outstanding + 1)
+}
+
+// decayWeight 历史值在经过elapsed时间后的权重
+func decayWeight(elapsed int64, decayTime float64) float64 {
+	if elapsed <= 0 {
+		return 1
+	}
+	return math.Exp(-float64(elapsed) / decayTime)
+}
@@ -0,0 +1,68 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package leastrequest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInstanceStatPeakEWMA 测试时延取峰值，并随时间衰减
+func TestInstanceStatPeakEWMA(t *testing.T) {
+	decayTime := float64(DefaultDecayTime)
+	stat := &instanceStat{}
+	now := time.Now().UnixNano()
+	stat.observe(float64(10*time.Millisecond), now, decayTime)
+	stat.observe(float64(50*time.Millisecond), now, decayTime)
+	// 时延升高时直接取峰值
+	assert.Equal(t, float64(50*time.Millisecond), stat.cost(now, decayTime))
+	// 时延降低时按照时间衰减进行加权平均
+	now += int64(DefaultDecayTime)
+	stat.observe(float64(10*time.Millisecond), now, decayTime)
+	cost := stat.cost(now, decayTime)
+	assert.True(t, cost > float64(10*time.Millisecond) && cost < float64(30*time.Millisecond))
+	// 长时间没有调用结果时逐渐衰减
+	assert.True(t, stat.cost(now+5*int64(DefaultDecayTime), decayTime) < cost/100)
+}
+
+// TestInstanceStatOutstanding 测试在途请求数对负载的影响
+func TestInstanceStatOutstanding(t *testing.T) {
+	decayTime := float64(DefaultDecayTime)
+	now := time.Now().UnixNano()
+	idle := &instanceStat{}
+	busy := &instanceStat{}
+	idle.observe(float64(10*time.Millisecond), now, decayTime)
+	busy.observe(float64(10*time.Millisecond), now, decayTime)
+	busy.Acquire()
+	busy.Acquire()
+	assert.True(t, busy.cost(now, decayTime) > idle.cost(now, decayTime))
+	busy.Release()
+	busy.Release()
+	assert.Equal(t, idle.cost(now, decayTime), busy.cost(now, decayTime))
+	// 未经过选择直接上报的结果不会使在途请求数变为负数
+	busy.Release()
+	assert.Equal(t, int64(0), busy.Count())
+
+	// 没有时延数据的实例，存在在途请求时优先选择已知时延的实例
+	unknown := &instanceStat{}
+	assert.Equal(t, float64(0), unknown.cost(now, decayTime))
+	unknown.Acquire()
+	assert.True(t, unknown.cost(now, decayTime) > busy.cost(now, decayTime))
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 
 	"github.com/polarismesh/polaris-go/pkg/algorithm/hash"
+	lbcommon "github.com/polarismesh/polaris-go/plugin/loadbalancer/common"
 )
 
 const (
@@ -35,6 +36,8 @@ const (
 type Config struct {
 	HashFunction string `yaml:"hashFunction" json:"hashFunction"`
 	TableSize    int    `yaml:"tableSize" json:"tableSize"`
+	// LoadFactor 有界负载系数，实例在途请求数上限为平均值乘以该系数，为0时不启用有界负载
+	LoadFactor float64 `yaml:"loadFactor" json:"loadFactor"`
 }
 
 // Verify 检验一致性hash配置
@@ -43,6 +46,9 @@ func (c *Config) Verify() error {
 	if !isPrime(c.TableSize) {
 		errs = multierror.Append(errs, fmt.Errorf("maglev.tableSize must be prime"))
 	}
+	if err := lbcommon.VerifyLoadFactor("maglev", c.LoadFactor); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
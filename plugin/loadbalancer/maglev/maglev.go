@@ -31,8 +31,9 @@ import (
 // maglev算法基于论文：https://static.googleusercontent.com/media/research.google.com/en//pubs/archive/44824.pdf
 type MaglevLoadBalancer struct {
 	*plugin.PluginBase
-	cfg      *Config
-	hashFunc hash.HashFuncWithSeed
+	cfg         *Config
+	hashFunc    hash.HashFuncWithSeed
+	boundedLoad *lbcommon.BoundedLoad
 }
 
 // Type 插件类型
@@ -54,6 +55,7 @@ func (m *MaglevLoadBalancer) Init(ctx *plugin.InitContext) error {
 	if err != nil {
 		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "fail to init hashFunc")
 	}
+	m.boundedLoad = lbcommon.NewBoundedLoad(ctx, m.ID(), m.cfg.LoadFactor)
 	return nil
 }
 
@@ -76,6 +78,11 @@ func (m *MaglevLoadBalancer) getOrBuildHashRing(instSet *model.InstanceSet) (mod
 	return tableSelector, err
 }
 
+// OnInstanceAccepted 负载均衡代理接受选择结果，启用有界负载时增加实例的在途请求数
+func (m *MaglevLoadBalancer) OnInstanceAccepted(instance model.Instance) {
+	m.boundedLoad.OnInstanceAccepted(instance)
+}
+
 // ChooseInstance 获取单个服务实例
 func (m *MaglevLoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
 	inputInstances model.ServiceInstances) (model.Instance, error) {
@@ -102,7 +109,27 @@ func (m *MaglevLoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
 		criteria.ReplicateInfo.Nodes = replicateNodes.GetInstances()
 	}
 
+	if m.boundedLoad.IsEnable() {
+		hashValue, err := lbcommon.CalcHashValue(criteria, m.hashFunc)
+		if err != nil {
+			return nil, model.NewSDKError(model.ErrCodeInternalError, err, "fail to cal hash value")
+		}
+		instance := m.boundedLoad.ChooseInstance(criteria, hashValue, svcInstances, targetInstances,
+			selector.(lbcommon.RingWalker))
+		if nil == instance {
+			return nil, lbcommon.ExcludedError(svcInstances, targetInstances.Count())
+		}
+		return instance, nil
+	}
 	instance := svcInstances.GetInstances()[index]
+	if criteria.IsExcluded(instance) {
+		hashValue, err := lbcommon.CalcHashValue(criteria, m.hashFunc)
+		if err != nil {
+			return nil, model.NewSDKError(model.ErrCodeInternalError, err, "fail to cal hash value")
+		}
+		return lbcommon.ReselectExcluded(criteria, hashValue, svcInstances, targetInstances,
+			lbcommon.SelectorIndex(selector))
+	}
 	return instance, nil
 }
 
@@ -63,7 +63,7 @@ func (t *TableSelector) buildTableEntries(
 		entry := &entries[i]
 		instanceIdx := &instanceSlice[i]
 		realInstance = instances[instanceIdx.Index]
-		normalizedWeight := float64(realInstance.GetWeight()) / float64(totalWeight)
+		normalizedWeight := float64(model.GetEffectiveWeight(realInstance)) / float64(totalWeight)
 		if maxNormalizedWeight < normalizedWeight {
 			maxNormalizedWeight = normalizedWeight
 		}
@@ -155,3 +155,18 @@ func (t *TableSelector) Select(value interface{}) (int, *model.ReplicateNodes, e
 	nodeIndex := int(hashValue % t.tableSize)
 	return t.nodes[nodeIndex].Index, nil, nil
 }
+
+// Walk 从hash值对应的表项开始顺序遍历，visit返回true时返回该表项的实例下标
+func (t *TableSelector) Walk(hashValue uint64, visit func(index int) bool) int {
+	if len(t.nodes) == 0 {
+		return -1
+	}
+	start := hashValue % t.tableSize
+	for i := uint64(0); i < t.tableSize; i++ {
+		index := t.nodes[(start+i)%t.tableSize].Index
+		if visit(index) {
+			return index
+		}
+	}
+	return -1
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 
 	"github.com/polarismesh/polaris-go/pkg/algorithm/hash"
+	lbcommon "github.com/polarismesh/polaris-go/plugin/loadbalancer/common"
 )
 
 const (
@@ -34,6 +35,8 @@ const (
 type Config struct {
 	HashFunction string `yaml:"hashFunction" json:"hashFunction"`
 	VnodeCount   int    `yaml:"vnodeCount" json:"vnodeCount"`
+	// LoadFactor 有界负载系数，实例在途请求数上限为平均值乘以该系数，为0时不启用有界负载
+	LoadFactor float64 `yaml:"loadFactor" json:"loadFactor"`
 }
 
 // Verify 检验一致性hash配置
@@ -42,6 +45,9 @@ func (c *Config) Verify() error {
 	if c.VnodeCount <= 0 {
 		errs = multierror.Append(errs, fmt.Errorf("ringhash.vnodeCount must be greater than 0"))
 	}
+	if err := lbcommon.VerifyLoadFactor("ringhash", c.LoadFactor); err != nil {
+		errs = multierror.Append(errs, err)
+	}
 	return errs
 }
 
@@ -106,7 +106,7 @@ func NewContinuum(
 	var err error
 	for _, instanceIdx := range instanceSlice {
 		realInstance = instances[instanceIdx.Index]
-		weight := realInstance.GetWeight()
+		weight := model.GetEffectiveWeight(realInstance)
 		pct := float64(weight) / float64(maxWeight)
 		limit := int(math.Floor(pct * float64(vnodeCount)))
 		for i := 0; i < limit; i++ {
@@ -214,6 +214,25 @@ func (c *ContinuumSelector) Select(value interface{}) (int, *model.ReplicateNode
 	}
 }
 
+// Walk 从hash值对应的环节点开始顺时针遍历，visit返回true时返回该节点的实例下标
+func (c *ContinuumSelector) Walk(hashValue uint64, visit func(index int) bool) int {
+	ringLen := len(c.ring)
+	if ringLen == 0 {
+		return -1
+	}
+	ringIndex := 0
+	if ringLen > 1 {
+		ringIndex = search.BinarySearch(c.ring, hashValue)
+	}
+	for i := 0; i < ringLen; i++ {
+		index := c.ring[(ringIndex+i)%ringLen].index
+		if visit(index) {
+			return index
+		}
+	}
+	return -1
+}
+
 // 通过hash值选择具体的节点
 func (c *ContinuumSelector) selectByHashValue(hashValue uint64, replicateCount int) (int, *model.ReplicateNodes) {
 	ringIndex := search.BinarySearch(c.ring, hashValue)
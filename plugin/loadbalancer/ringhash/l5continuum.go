@@ -89,13 +89,13 @@ func NewL5Continuum(
 	instances := svcInstances.GetInstances()
 	instanceSlice := instanceSet.GetInstances()
 	for _, instanceIdx := range instanceSlice {
-		ringLen += instances[instanceIdx.Index].GetWeight()
+		ringLen += model.GetEffectiveWeight(instances[instanceIdx.Index])
 	}
 	continuum.ring = make(points, 0, ringLen)
 	var hashValues = make(map[uint64]continuumPoint, ringLen)
 	for _, instanceIdx := range instanceSlice {
 		realInstance := instances[instanceIdx.Index]
-		weight := realInstance.GetWeight()
+		weight := model.GetEffectiveWeight(realInstance)
 		for i := 0; i < weight; i++ {
 			hashKey := fmt.Sprintf("%s:%d:%d", realInstance.GetHost(), i, realInstance.GetPort())
 			hashValue := uint64(murmur32.Sum32WithSeed([]byte(hashKey), 16))
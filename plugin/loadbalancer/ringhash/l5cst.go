@@ -20,6 +20,8 @@ package ringhash
 import (
 	"fmt"
 
+	murmur32 "github.com/spaolacci/murmur3"
+
 	mconfig "github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/model"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
@@ -95,6 +97,14 @@ func (l *L5CSTLoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
 	}
 
 	instance := svcInstances.GetInstances()[index]
+	if criteria.IsExcluded(instance) {
+		hashValue := criteria.HashValue
+		if len(criteria.HashKey) > 0 {
+			hashValue = uint64(murmur32.Sum32WithSeed(criteria.HashKey, 16))
+		}
+		return lbcommon.ReselectExcluded(criteria, hashValue, svcInstances, targetInstances,
+			lbcommon.SelectorIndex(selector))
+	}
 	return instance, nil
 }
 
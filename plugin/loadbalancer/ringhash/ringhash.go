@@ -30,8 +30,9 @@ import (
 // KetamaLoadBalancer ketama算法的一致性hash负载均衡器
 type KetamaLoadBalancer struct {
 	*plugin.PluginBase
-	cfg      *Config
-	hashFunc hash.HashFuncWithSeed
+	cfg         *Config
+	hashFunc    hash.HashFuncWithSeed
+	boundedLoad *lbcommon.BoundedLoad
 }
 
 // Type 插件类型
@@ -53,6 +54,7 @@ func (k *KetamaLoadBalancer) Init(ctx *plugin.InitContext) error {
 	if err != nil {
 		return model.NewSDKError(model.ErrCodeAPIInvalidArgument, err, "fail to init hashFunc")
 	}
+	k.boundedLoad = lbcommon.NewBoundedLoad(ctx, k.ID(), k.cfg.LoadFactor)
 	return nil
 }
 
@@ -75,6 +77,11 @@ func (k *KetamaLoadBalancer) getOrBuildHashRing(instSet *model.InstanceSet) (mod
 	return continuum, err
 }
 
+// OnInstanceAccepted 负载均衡代理接受选择结果，启用有界负载时增加实例的在途请求数
+func (k *KetamaLoadBalancer) OnInstanceAccepted(instance model.Instance) {
+	k.boundedLoad.OnInstanceAccepted(instance)
+}
+
 // ChooseInstance 获取单个服务实例
 func (k *KetamaLoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
 	inputInstances model.ServiceInstances) (model.Instance, error) {
@@ -101,7 +108,27 @@ func (k *KetamaLoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
 		criteria.ReplicateInfo.Nodes = nodes.GetInstances()
 	}
 
+	if k.boundedLoad.IsEnable() {
+		hashValue, err := lbcommon.CalcHashValue(criteria, k.hashFunc)
+		if err != nil {
+			return nil, model.NewSDKError(model.ErrCodeInternalError, err, "fail to cal hash value")
+		}
+		instance := k.boundedLoad.ChooseInstance(criteria, hashValue, svcInstances, targetInstances,
+			selector.(lbcommon.RingWalker))
+		if nil == instance {
+			return nil, lbcommon.ExcludedError(svcInstances, targetInstances.Count())
+		}
+		return instance, nil
+	}
 	instance := svcInstances.GetInstances()[index]
+	if criteria.IsExcluded(instance) {
+		hashValue, err := lbcommon.CalcHashValue(criteria, k.hashFunc)
+		if err != nil {
+			return nil, model.NewSDKError(model.ErrCodeInternalError, err, "fail to cal hash value")
+		}
+		return lbcommon.ReselectExcluded(criteria, hashValue, svcInstances, targetInstances,
+			lbcommon.SelectorIndex(selector))
+	}
 	return instance, nil
 }
 
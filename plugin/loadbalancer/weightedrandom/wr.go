@@ -58,12 +58,13 @@ func (g *WRLoadBalancer) Destroy() error {
 // ChooseInstance 获取单个服务实例
 func (g *WRLoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
 	svcInstances model.ServiceInstances) (model.Instance, error) {
-	return g.clusterBasedChooseInstance(criteria.IgnoreHalfOpen, criteria.Cluster, svcInstances.GetServiceClusters())
+	return g.clusterBasedChooseInstance(criteria, svcInstances.GetServiceClusters())
 }
 
 // 基于集群进行负载均衡选择，性能最高
-func (g *WRLoadBalancer) clusterBasedChooseInstance(ignoreHalfOpen bool,
-	cluster *model.Cluster, svcClusters model.ServiceClusters) (model.Instance, error) {
+func (g *WRLoadBalancer) clusterBasedChooseInstance(
+	criteria *loadbalancer.Criteria, svcClusters model.ServiceClusters) (model.Instance, error) {
+	cluster := criteria.Cluster
 	clusterValue := cluster.GetClusterValue()
 	var instance model.Instance
 	svcInstances := svcClusters.GetServiceInstances()
@@ -76,6 +77,14 @@ func (g *WRLoadBalancer) clusterBasedChooseInstance(ignoreHalfOpen bool,
 	}
 	// 优化进行随机半开节点的分配
 	instance = g.clusterBasedSelectWeightedInstance(svcInstances, targetInstances)
+	if criteria.HasExclusion() && (nil == instance || criteria.IsExcluded(instance)) {
+		// 选中的实例被排除时，在未被排除的实例中重新按照权重选择
+		instance = lbcommon.SelectWeightedExcluded(g.scalableRand, criteria, svcInstances, targetInstances)
+		if nil == instance {
+			return nil, lbcommon.ExcludedError(svcInstances, targetInstances.Count())
+		}
+		return instance, nil
+	}
 	if nil == instance {
 		// 一般不会走到这一步，除非BUG，这里只是做个预案
 		selector := g.getSelector(targetInstances.Count())
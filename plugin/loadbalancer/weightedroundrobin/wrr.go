@@ -0,0 +1,194 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package weightedroundrobin
+
+import (
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+	lbcommon "github.com/polarismesh/polaris-go/plugin/loadbalancer/common"
+)
+
+// LoadBalancer 平滑加权轮询负载均衡插件，算法与nginx的smooth weighted round robin一致
+// 轮询状态按照服务以及路由后的集群分别保存，缓存版本变化时按照实例ID继承原有的轮询进度
+type LoadBalancer struct {
+	*plugin.PluginBase
+	// 轮询状态，key为stateKey，value为*roundRobinState
+	states *sync.Map
+}
+
+// stateKey 轮询状态的标识
+type stateKey struct {
+	svcKey              model.ServiceKey
+	clusterKey          model.ClusterKey
+	hasLimitedInstances bool
+	includeHalfOpen     bool
+}
+
+// Type 插件类型
+func (l *LoadBalancer) Type() common.Type {
+	return common.TypeLoadBalancer
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (l *LoadBalancer) Name() string {
+	return config.DefaultLoadBalancerWRR
+}
+
+// Init 初始化插件
+func (l *LoadBalancer) Init(ctx *plugin.InitContext) error {
+	l.PluginBase = plugin.NewPluginBase(ctx)
+	l.states = &sync.Map{}
+	ctx.Plugins.RegisterEventSubscriber(common.OnServiceDeleted, common.PluginEventHandler{
+		Callback: l.onServiceDeleted,
+	})
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (l *LoadBalancer) Destroy() error {
+	return nil
+}
+
+// 服务从缓存中删除时，清理该服务的轮询状态
+func (l *LoadBalancer) onServiceDeleted(event *common.PluginEvent) error {
+	svcEventObject := event.EventObject.(*common.ServiceEventObject)
+	if svcEventObject.SvcEventKey.Type != model.EventInstances {
+		return nil
+	}
+	svcKey := svcEventObject.SvcEventKey.ServiceKey
+	l.states.Range(func(key, value interface{}) bool {
+		if key.(stateKey).svcKey == svcKey {
+			l.states.Delete(key)
+		}
+		return true
+	})
+	return nil
+}
+
+// ChooseInstance 获取单个服务实例
+func (l *LoadBalancer) ChooseInstance(criteria *loadbalancer.Criteria,
+	inputInstances model.ServiceInstances) (model.Instance, error) {
+	cluster := criteria.Cluster
+	svcClusters := inputInstances.GetServiceClusters()
+	svcInstances := svcClusters.GetServiceInstances()
+	targetInstances := lbcommon.SelectAvailableInstanceSet(cluster.GetClusterValue(), cluster.HasLimitedInstances,
+		cluster.IncludeHalfOpen)
+	if targetInstances.TotalWeight() == 0 {
+		return nil, model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"instances of %s in cluster %s all weight 0 (instance count %d) in load balance, includeHalfOpen: %v",
+			svcClusters.GetServiceKey(), *cluster, targetInstances.Count(), cluster.IncludeHalfOpen)
+	}
+	key := stateKey{
+		svcKey:              svcClusters.GetServiceKey(),
+		clusterKey:          cluster.ClusterKey,
+		hasLimitedInstances: cluster.HasLimitedInstances,
+		includeHalfOpen:     cluster.IncludeHalfOpen,
+	}
+	value, ok := l.states.Load(key)
+	if !ok {
+		value, _ = l.states.LoadOrStore(key, &roundRobinState{})
+	}
+	instance := value.(*roundRobinState).next(criteria, svcInstances, targetInstances)
+	if nil == instance {
+		return nil, lbcommon.ExcludedError(svcInstances, targetInstances.Count())
+	}
+	return instance, nil
+}
+
+// roundRobinState 单个集群的轮询状态，每个集群使用独立的锁
+type roundRobinState struct {
+	mutex sync.Mutex
+	// 构建轮询节点所基于的实例集合
+	instanceSet *model.InstanceSet
+	nodes       []*roundRobinNode
+}
+
+// roundRobinNode 轮询节点
+type roundRobinNode struct {
+	instance model.Instance
+	// 实际生效的权重
+	weight int
+	// 当前权重
+	current int
+}
+
+// next 选择下一个实例，排除列表中的实例不参与本次轮询，所有实例都被排除时返回nil
+func (s *roundRobinState) next(criteria *loadbalancer.Criteria,
+	svcInstances model.ServiceInstances, instanceSet *model.InstanceSet) model.Instance {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.instanceSet != instanceSet {
+		s.rebuild(svcInstances, instanceSet)
+	}
+	hasExclusion := criteria.HasExclusion()
+	var (
+		best        *roundRobinNode
+		totalWeight int
+	)
+	for _, node := range s.nodes {
+		if hasExclusion && criteria.IsExcluded(node.instance) {
+			continue
+		}
+		node.current += node.weight
+		totalWeight += node.weight
+		if nil == best || node.current > best.current {
+			best = node
+		}
+	}
+	if nil == best {
+		return nil
+	}
+	best.current -= totalWeight
+	return best.instance
+}
+
+// rebuild 实例集合变化后重建轮询节点，已有实例按照ID继承当前权重，新增实例的当前权重从0开始
+func (s *roundRobinState) rebuild(svcInstances model.ServiceInstances, instanceSet *model.InstanceSet) {
+	previous := make(map[string]*roundRobinNode, len(s.nodes))
+	for _, node := range s.nodes {
+		previous[node.instance.GetId()] = node
+	}
+	allInstances := svcInstances.GetInstances()
+	weightedIndexes := instanceSet.GetInstances()
+	nodes := make([]*roundRobinNode, 0, len(weightedIndexes))
+	var lastAccumulate int
+	for _, weightedIndex := range weightedIndexes {
+		weight := weightedIndex.AccumulateWeight - lastAccumulate
+		lastAccumulate = weightedIndex.AccumulateWeight
+		if weight <= 0 {
+			continue
+		}
+		node := &roundRobinNode{instance: allInstances[weightedIndex.Index], weight: weight}
+		if prev, ok := previous[node.instance.GetId()]; ok {
+			node.current = prev.current
+		}
+		nodes = append(nodes, node)
+	}
+	s.nodes = nodes
+	s.instanceSet = instanceSet
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterPlugin(&LoadBalancer{})
+}
@@ -0,0 +1,124 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package weightedroundrobin
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin/loadbalancer"
+)
+
+func newNode(id string, port uint32, weight int) *roundRobinNode {
+	svcKey := &model.ServiceKey{Namespace: "default", Service: "echo"}
+	instance := pb.NewInstanceInProto(&apiservice.Instance{
+		Id:   wrapperspb.String(id),
+		Host: wrapperspb.String("127.0.0.1"),
+		Port: wrapperspb.UInt32(port),
+	}, svcKey, nil)
+	return &roundRobinNode{instance: instance, weight: weight}
+}
+
+func sequence(state *roundRobinState, criteria *loadbalancer.Criteria, count int) string {
+	ids := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		ids = append(ids, state.next(criteria, nil, nil).GetId())
+	}
+	return strings.Join(ids, "")
+}
+
+// TestRoundRobinState_Smooth 测试平滑加权轮询的选择顺序
+func TestRoundRobinState_Smooth(t *testing.T) {
+	state := &roundRobinState{nodes: []*roundRobinNode{
+		newNode("a", 8001, 5), newNode("b", 8002, 1), newNode("c", 8003, 1)}}
+	criteria := &loadbalancer.Criteria{}
+	// 与nginx的选择顺序一致，权重高的实例不会连续被选中过多次
+	assert.Equal(t, "aabacaa", sequence(state, criteria, 7))
+	assert.Equal(t, "aabacaa", sequence(state, criteria, 7))
+}
+
+// TestRoundRobinState_Exclude 测试被排除的实例不参与轮询
+func TestRoundRobinState_Exclude(t *testing.T) {
+	nodes := []*roundRobinNode{newNode("a", 8001, 1), newNode("b", 8002, 1), newNode("c", 8003, 1)}
+	state := &roundRobinState{nodes: nodes}
+	criteria := &loadbalancer.Criteria{ExcludeInstances: []model.Instance{nodes[1].instance}}
+	assert.Equal(t, "acac", sequence(state, criteria, 4))
+
+	criteria.ExcludeInstances = []model.Instance{nodes[0].instance, nodes[1].instance, nodes[2].instance}
+	assert.Nil(t, state.next(criteria, nil, nil))
+}
+
+// newServiceInstances 构建服务实例集合，weights为实例ID到权重的映射，按照ids的顺序添加实例
+func newServiceInstances(ids string, weights map[string]uint32) model.ServiceInstances {
+	svcKey := &model.ServiceKey{Namespace: "default", Service: "echo"}
+	instances := make([]model.Instance, 0, len(ids))
+	for i, id := range ids {
+		instances = append(instances, pb.NewInstanceInProto(&apiservice.Instance{
+			Id:      wrapperspb.String(string(id)),
+			Host:    wrapperspb.String("127.0.0.1"),
+			Port:    wrapperspb.UInt32(uint32(8001 + i)),
+			Weight:  wrapperspb.UInt32(weights[string(id)]),
+			Healthy: wrapperspb.Bool(true),
+		}, svcKey, local.NewInstanceLocalValue()))
+	}
+	return model.NewDefaultServiceInstances(model.ServiceInfo{
+		Namespace: svcKey.Namespace,
+		Service:   svcKey.Service,
+	}, instances)
+}
+
+func chooseSequence(t *testing.T, lb *LoadBalancer, svcInstances model.ServiceInstances, count int) string {
+	criteria := &loadbalancer.Criteria{Cluster: model.NewCluster(svcInstances.GetServiceClusters(), nil)}
+	ids := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		instance, err := lb.ChooseInstance(criteria, svcInstances)
+		assert.Nil(t, err)
+		ids = append(ids, instance.GetId())
+	}
+	return strings.Join(ids, "")
+}
+
+// TestChooseInstance_Rebuild 测试实例集合变化后重建轮询节点，保留的实例按照ID继承当前权重
+func TestChooseInstance_Rebuild(t *testing.T) {
+	lb := &LoadBalancer{states: &sync.Map{}}
+	weights := map[string]uint32{"a": 5, "b": 1, "c": 1, "d": 1}
+	// 选择两次后当前权重为 a:-4 b:2 c:2
+	assert.Equal(t, "aa", chooseSequence(t, lb, newServiceInstances("abc", weights), 2))
+
+	// 删除b并新增d，a和c继承当前权重，d从0开始，第一次选择的是c而不是权重最高的a
+	assert.Equal(t, "caa", chooseSequence(t, lb, newServiceInstances("acd", weights), 3))
+	var states int
+	lb.states.Range(func(key, value interface{}) bool {
+		states++
+		currents := make(map[string]int)
+		for _, node := range value.(*roundRobinState).nodes {
+			currents[node.instance.GetId()] = node.current
+		}
+		assert.Equal(t, map[string]int{"a": -3, "c": -2, "d": 3}, currents)
+		return true
+	})
+	assert.Equal(t, 1, states)
+}
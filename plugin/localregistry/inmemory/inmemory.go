@@ -162,6 +162,8 @@ func (g *LocalCache) Init(ctx *plugin.InitContext) error {
 	g.eventToCacheHandlers[model.EventInstances] = g.newServiceCacheHandler()
 	g.eventToCacheHandlers[model.EventRouting] = g.newRuleCacheHandler()
 	g.eventToCacheHandlers[model.EventRateLimiting] = g.newRateLimitCacheHandler()
+	g.eventToCacheHandlers[model.EventCircuitBreaker] = g.newRuleCacheHandler()
+	g.eventToCacheHandlers[model.EventFaultDetect] = g.newRuleCacheHandler()
 	// 批量服务
 	g.eventToCacheHandlers[model.EventServices] = g.newServicesHandler()
 	g.cachePersistHandler, err = lrplug.NewCachePersistHandler(
@@ -436,12 +438,19 @@ func (g *LocalCache) messageToServiceInstances(cachedValue interface{}, value pr
 	var createLocalValueFunc = g.CreateDefaultInstanceLocalValue
 	if !reflect2.IsNil(cachedValue) {
 		svcInsts := cachedValue.(*pb.ServiceInstancesInProto)
+		warmupConfig := g.globalConfig.GetConsumer().GetEffectiveWarmup(svcKey.Namespace, svcKey.Service)
 		createLocalValueFunc = func(instId string) local.InstanceLocalValue {
 			localValue := svcInsts.GetInstanceLocalValue(instId)
 			if nil != localValue {
 				return localValue
 			}
 			newLocalValue := g.CreateDefaultInstanceLocalValue("")
+			// 服务首次加载之后新出现的实例，记录发现时间用于预热，并在预热任务计算之前以预热下限权重开始
+			defaultLocalValue := newLocalValue.(*local.DefaultInstanceLocalValue)
+			defaultLocalValue.SetFirstSeenTime(time.Now())
+			if warmupConfig.IsEnable() {
+				defaultLocalValue.SetWarmupWeightPercent(warmupConfig.GetMinWeightPercent())
+			}
 			return newLocalValue
 		}
 	}
@@ -566,10 +575,13 @@ func (g *LocalCache) loadRemoteValue(svcKey *model.ServiceEventKey, handler Cach
 	return actualSvcObject.GetNotifier(), nil
 }
 
-// UpdateInstances 批量更新服务实例状态，properties存放的是状态值，当前支持2个key
+// UpdateInstances 批量更新服务实例状态，properties存放的是状态值，当前支持5个key
 // 对同一个key的更新，请保持线程安全
-// 1. ReadyToServe: 故障熔断标识，true or false
-// 2. DynamicWeight：动态权重值
+// 1. CircuitBreakerStatus: 故障熔断状态
+// 2. HealthCheckStatus: 健康探测状态
+// 3. DynamicWeight：动态权重值
+// 4. MethodCircuitBreakerStatus: 接口级熔断状态
+// 5. WarmupWeight: 预热权重百分比
 func (g *LocalCache) UpdateInstances(svcUpdateReq *localregistry.ServiceUpdateRequest) error {
 	_, ok := g.serviceMap.Load(model.ServiceEventKey{
 		ServiceKey: svcUpdateReq.ServiceKey,
@@ -583,9 +595,12 @@ func (g *LocalCache) UpdateInstances(svcUpdateReq *localregistry.ServiceUpdateRe
 		e, _ := g.globalCtx.GetValue(model.ContextKeyEngine)
 		g.engine = e.(model.Engine)
 	}
+	// 需要重建缓存索引的服务实例
+	reloadInstances := make(map[*pb.ServiceInstancesInProto]bool)
 	for i := 0; i < len(svcUpdateReq.Properties); i++ {
-		// 更新实例的本地信息，包括熔断状态、健康检测状态
+		// 更新实例的本地信息，包括熔断状态、健康检测状态、动态权重
 		var cbStatusUpdated bool
+		var weightUpdated bool
 		property := svcUpdateReq.Properties[i]
 		instances := g.GetInstances(property.Service, true, true)
 		svcInstancesInProto := instances.(*pb.ServiceInstancesInProto)
@@ -613,14 +628,35 @@ func (g *LocalCache) UpdateInstances(svcUpdateReq *localregistry.ServiceUpdateRe
 				if err != nil {
 					log.GetBaseLogger().Errorf("fail to report circuitbreak change, error %v", err)
 				}
+			case localregistry.PropertyMethodCircuitBreakerStatus:
+				methodStatus := v.(*localregistry.MethodCircuitBreakerStatus)
+				localValues.SetMethodCircuitBreakerStatus(methodStatus.Method, methodStatus.Status)
+				// 接口级熔断不影响实例的集群分布，只在负载均衡时按接口进行过滤，因此不需要重建索引
+				err := g.engine.SyncReportStat(model.CircuitBreakStat, &model.CircuitBreakGauge{
+					ChangeInstance: updateInstance, Method: methodStatus.Method, CBStatus: methodStatus.Status})
+				if err != nil {
+					log.GetBaseLogger().Errorf("fail to report method circuitbreak change, error %v", err)
+				}
 			case localregistry.PropertyHealthCheckStatus:
 				localValues.SetActiveDetectStatus(v.(model.ActiveDetectStatus))
+			case localregistry.PropertyDynamicWeight:
+				preWeight := model.GetEffectiveWeight(updateInstance)
+				nextWeight := v.(*model.InstanceWeight)
+				localValues.SetDynamicWeight(nextWeight)
+				weightUpdated = weightUpdated || preWeight != model.GetEffectiveWeight(updateInstance)
+			case localregistry.PropertyWarmupWeight:
+				preWeight := model.GetEffectiveWeight(updateInstance)
+				localValues.SetWarmupWeightPercent(v.(int))
+				weightUpdated = weightUpdated || preWeight != model.GetEffectiveWeight(updateInstance)
 			}
 		}
-		if cbStatusUpdated {
-			svcInstancesInProto.ReloadServiceClusters()
+		if cbStatusUpdated || weightUpdated {
+			reloadInstances[svcInstancesInProto] = true
 		}
 	}
+	for svcInstancesInProto := range reloadInstances {
+		svcInstancesInProto.ReloadServiceClusters()
+	}
 	return nil
 }
 
@@ -684,6 +720,22 @@ func (g *LocalCache) GetServiceRateLimitRule(key *model.ServiceKey, includeCache
 	return svcRule
 }
 
+// GetServiceCircuitBreakerRule 非阻塞获取熔断规则
+func (g *LocalCache) GetServiceCircuitBreakerRule(key *model.ServiceKey, includeCache bool) model.ServiceRule {
+	svcEventKey := poolGetSvcEventKey(key, model.EventCircuitBreaker)
+	svcRule := g.GetServiceRule(svcEventKey, includeCache)
+	poolPutSvcEventKey(svcEventKey)
+	return svcRule
+}
+
+// GetServiceFaultDetectRule 非阻塞获取主动探测规则
+func (g *LocalCache) GetServiceFaultDetectRule(key *model.ServiceKey, includeCache bool) model.ServiceRule {
+	svcEventKey := poolGetSvcEventKey(key, model.EventFaultDetect)
+	svcRule := g.GetServiceRule(svcEventKey, includeCache)
+	poolPutSvcEventKey(svcEventKey)
+	return svcRule
+}
+
 // GetServiceRule 非阻塞获取规则信息
 func (g *LocalCache) GetServiceRule(svcEventKey *model.ServiceEventKey, includeCache bool) model.ServiceRule {
 	value, ok := g.serviceMap.Load(*svcEventKey)
@@ -1002,6 +1054,28 @@ func (g *LocalCache) LoadServiceRateLimitRule(key *model.ServiceKey) (*common.No
 	})
 }
 
+// LoadServiceCircuitBreakerRule 非阻塞发起熔断规则加载
+func (g *LocalCache) LoadServiceCircuitBreakerRule(key *model.ServiceKey) (*common.Notifier, error) {
+	return g.LoadServiceRule(&model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{
+			Namespace: key.Namespace,
+			Service:   key.Service,
+		},
+		Type: model.EventCircuitBreaker,
+	})
+}
+
+// LoadServiceFaultDetectRule 非阻塞发起主动探测规则加载
+func (g *LocalCache) LoadServiceFaultDetectRule(key *model.ServiceKey) (*common.Notifier, error) {
+	return g.LoadServiceRule(&model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{
+			Namespace: key.Namespace,
+			Service:   key.Service,
+		},
+		Type: model.EventFaultDetect,
+	})
+}
+
 // LoadServiceRule 非阻塞发起规则加载
 func (g *LocalCache) LoadServiceRule(svcEventKey *model.ServiceEventKey) (*common.Notifier, error) {
 	log.GetBaseLogger().Debugf("LoadServiceRule: serviceEvent %s", *svcEventKey)
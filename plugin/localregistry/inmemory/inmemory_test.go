@@ -0,0 +1,108 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+)
+
+// noEventSupplier 没有插件事件监听器的插件管理器
+type noEventSupplier struct {
+	plugin.Supplier
+}
+
+func (noEventSupplier) GetEventSubscribers(common.PluginEventType) []common.PluginEventHandler {
+	return nil
+}
+
+func newDiscoverResponse(service string, ids ...string) *apiservice.DiscoverResponse {
+	resp := &apiservice.DiscoverResponse{
+		Service: &apiservice.Service{Namespace: wrapperspb.String("default"), Name: wrapperspb.String(service)},
+	}
+	for i, id := range ids {
+		resp.Instances = append(resp.Instances, &apiservice.Instance{
+			Id:      wrapperspb.String(id),
+			Host:    wrapperspb.String("127.0.0.1"),
+			Port:    wrapperspb.UInt32(uint32(8001 + i)),
+			Weight:  wrapperspb.UInt32(100),
+			Healthy: wrapperspb.Bool(true),
+		})
+	}
+	return resp
+}
+
+// getBalancerWeights 获取负载均衡从集群中看到的实例权重
+func getBalancerWeights(svcInstances model.ServiceInstances) (map[string]int, int) {
+	cluster := model.NewCluster(svcInstances.GetServiceClusters(), nil)
+	instanceSet := cluster.GetClusterValue().GetAllInstanceSet()
+	weights := make(map[string]int)
+	for _, instance := range instanceSet.GetRealInstances() {
+		weights[instance.GetId()] = model.GetEffectiveWeight(instance)
+	}
+	return weights, instanceSet.TotalWeight()
+}
+
+// TestNewInstanceWarmupWeight 测试服务首次加载之后新出现的实例，在预热任务计算之前以预热下限权重参与负载均衡
+func TestNewInstanceWarmupWeight(t *testing.T) {
+	cfg := config.NewDefaultConfiguration(nil)
+	warmupConfig := &config.WarmupConfigImpl{}
+	warmupConfig.SetDefault()
+	warmupConfig.SetEnable(true)
+	warmupConfig.SetMinWeightPercent(20)
+	cfg.Consumer.ServicesSpecific = append(cfg.Consumer.ServicesSpecific, &config.ServiceSpecific{
+		Namespace: "default",
+		Service:   "warmup",
+		Warmup:    warmupConfig,
+	})
+	cache := &LocalCache{
+		globalConfig:            cfg,
+		plugins:                 noEventSupplier{},
+		svcToPluginValues:       map[model.ServiceKey]*pb.SvcPluginValues{},
+		namespaceToPluginValues: map[string]*pb.SvcPluginValues{},
+	}
+
+	// 服务首次加载时已经存在的实例不预热
+	cachedValue := cache.messageToServiceInstances(nil, newDiscoverResponse("warmup", "a"), nil, false)
+	weights, totalWeight := getBalancerWeights(cachedValue.(model.ServiceInstances))
+	assert.Equal(t, map[string]int{"a": 100}, weights)
+	assert.Equal(t, 100, totalWeight)
+
+	svcInstances := cache.messageToServiceInstances(
+		cachedValue, newDiscoverResponse("warmup", "a", "b"), nil, false).(model.ServiceInstances)
+	weights, totalWeight = getBalancerWeights(svcInstances)
+	assert.Equal(t, map[string]int{"a": 100, "b": 20}, weights)
+	assert.Equal(t, 120, totalWeight)
+
+	// 未开启预热的服务，新增实例以完整权重参与负载均衡
+	cachedValue = cache.messageToServiceInstances(nil, newDiscoverResponse("other", "a"), nil, false)
+	svcInstances = cache.messageToServiceInstances(
+		cachedValue, newDiscoverResponse("other", "a", "b"), nil, false).(model.ServiceInstances)
+	weights, totalWeight = getBalancerWeights(svcInstances)
+	assert.Equal(t, map[string]int{"a": 100, "b": 100}, weights)
+	assert.Equal(t, 200, totalWeight)
+}
@@ -289,7 +289,7 @@ func (s *CacheObject) OnServiceUpdate(event *serverconnector.ServiceEvent) {
 			switch event.Type {
 			case model.EventInstances:
 				atomic.StoreInt32(&cachedValue.(*pb.ServiceInstancesInProto).CacheLoaded, 0)
-			case model.EventRouting:
+			case model.EventRouting, model.EventCircuitBreaker, model.EventFaultDetect:
 				atomic.StoreInt32(&cachedValue.(*pb.ServiceRuleInProto).CacheLoaded, 0)
 			}
 		}
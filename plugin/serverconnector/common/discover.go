@@ -447,6 +447,10 @@ func (s *StreamingClient) checkErrorReport(grpcErr error, resp *apiservice.Disco
 		discoverErr = model.NewSDKError(model.ErrCodeInvalidResponse, grpcErr,
 			"invalid response from %s(%s), reqID %s",
 			s.connection.ConnID, s.connection.Address, s.reqID)
+		if IsUnauthorizedError(grpcErr) {
+			// 鉴权失败，不需要上报server的调用失败
+			return false, int32(model.ErrCodeAPIUnauthorized), discoverErr
+		}
 		// 由doSend关闭了连接
 		if !closeBySelf {
 			// 如果doSend发现有错误，allTaskTimeout或者idle，那么上报超时错误
@@ -0,0 +1,151 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"sync"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+// ResponseBuilder 根据服务事件KEY构建服务发现应答，数据尚未就绪时返回nil
+type ResponseBuilder func(svcEventKey model.ServiceEventKey) *apiservice.DiscoverResponse
+
+// pushEntry 已注册的服务监听器，以及最近一次推送的数据版本号
+type pushEntry struct {
+	handler  *serverconnector.ServiceEventHandler
+	notified bool
+	revision string
+}
+
+// EventPusher 服务事件推送器，适用于在本地构建服务发现应答的连接器
+// 所有事件都在同一个协程中推送，保证同一个监听器收到的事件有序，且只推送版本号发生变化的数据
+type EventPusher struct {
+	build    ResponseBuilder
+	lock     sync.Mutex
+	entries  map[model.ServiceEventKey]*pushEntry
+	trigger  chan struct{}
+	stopChan chan struct{}
+	stopOnce sync.Once
+	waiter   sync.WaitGroup
+}
+
+// NewEventPusher 创建服务事件推送器
+func NewEventPusher(build ResponseBuilder) *EventPusher {
+	return &EventPusher{
+		build:    build,
+		entries:  make(map[model.ServiceEventKey]*pushEntry),
+		trigger:  make(chan struct{}, 1),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start 启动推送协程
+func (p *EventPusher) Start() {
+	p.waiter.Add(1)
+	go p.run()
+}
+
+// Stop 停止推送协程，并等待正在进行的推送结束
+func (p *EventPusher) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopChan)
+	})
+	p.waiter.Wait()
+}
+
+// AddHandler 添加服务监听器，并触发一次推送
+func (p *EventPusher) AddHandler(handler *serverconnector.ServiceEventHandler) {
+	p.lock.Lock()
+	p.entries[*handler.ServiceEventKey] = &pushEntry{handler: handler}
+	p.lock.Unlock()
+	p.Trigger()
+}
+
+// RemoveHandler 删除服务监听器
+func (p *EventPusher) RemoveHandler(svcEventKey model.ServiceEventKey) {
+	p.lock.Lock()
+	delete(p.entries, svcEventKey)
+	p.lock.Unlock()
+}
+
+// Keys 获取所有已注册监听器的服务事件KEY
+func (p *EventPusher) Keys() []model.ServiceEventKey {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	keys := make([]model.ServiceEventKey, 0, len(p.entries))
+	for key := range p.entries {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Trigger 通知推送协程检查所有监听器的数据是否变更
+func (p *EventPusher) Trigger() {
+	select {
+	case p.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (p *EventPusher) run() {
+	defer p.waiter.Done()
+	for {
+		select {
+		case <-p.stopChan:
+			return
+		case <-p.trigger:
+			p.push()
+		}
+	}
+}
+
+// push 向数据版本发生变化的监听器推送服务事件，回调时不持有锁，监听器可以在回调中反注册
+func (p *EventPusher) push() {
+	p.lock.Lock()
+	entries := make([]*pushEntry, 0, len(p.entries))
+	for _, entry := range p.entries {
+		entries = append(entries, entry)
+	}
+	p.lock.Unlock()
+
+	for _, entry := range entries {
+		svcEventKey := *entry.handler.ServiceEventKey
+		resp := p.build(svcEventKey)
+		if resp == nil {
+			continue
+		}
+		revision := resp.GetService().GetRevision().GetValue()
+		p.lock.Lock()
+		current, ok := p.entries[svcEventKey]
+		if !ok || current != entry || (entry.notified && entry.revision == revision) {
+			p.lock.Unlock()
+			continue
+		}
+		entry.notified = true
+		entry.revision = revision
+		p.lock.Unlock()
+		entry.handler.Handler.OnServiceUpdate(&serverconnector.ServiceEvent{
+			ServiceEventKey: svcEventKey,
+			Value:           resp,
+		})
+	}
+}
@@ -0,0 +1,49 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// GenInstanceID 根据服务以及IP端口生成实例ID，用于数据源本身不提供实例ID的连接器
+func GenInstanceID(namespace, service, host string, port uint32) string {
+	h := sha1.New()
+	_, _ = fmt.Fprintf(h, "%s##%s##%s##%d", namespace, service, host, port)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// MessagesRevision 根据消息内容计算版本号，内容不变则版本号不变，用于数据源本身不提供版本号的连接器
+func MessagesRevision(messages ...proto.Message) string {
+	h := sha1.New()
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	for _, message := range messages {
+		buf.Reset()
+		if err := buf.Marshal(message); err != nil {
+			_, _ = fmt.Fprint(h, message)
+			continue
+		}
+		_, _ = h.Write(buf.Bytes())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
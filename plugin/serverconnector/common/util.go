@@ -24,6 +24,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/modern-go/reflect2"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
@@ -49,6 +50,10 @@ const (
 	reqIDPrefixRateLimitAcquire
 	reqIDPrefixGetConfigFile
 	reqIDPrefixWatchConfigFiles
+	reqIDPrefixCreateConfigFile
+	reqIDPrefixUpdateConfigFile
+	reqIDPrefixPublishConfigFile
+	reqIDPrefixGetConfigGroup
 )
 
 const (
@@ -63,6 +68,10 @@ const (
 	OpKeyRateLimitMetricReport = "RateLimitMetricReport"
 	OpKeyGetConfigFile         = "GetConfigFile"
 	OpKeyWatchConfigFiles      = "WatchConfigFiles"
+	OpKeyCreateConfigFile      = "CreateConfigFile"
+	OpKeyUpdateConfigFile      = "UpdateConfigFile"
+	OpKeyPublishConfigFile     = "PublishConfigFile"
+	OpKeyGetConfigGroup        = "GetConfigGroup"
 )
 
 // NextDiscoverReqID 生成GetInstances调用的请求Id
@@ -110,6 +119,26 @@ func NextWatchConfigFilesReqID() string {
 	return fmt.Sprintf("%d%d", reqIDPrefixWatchConfigFiles, uuid.New().ID())
 }
 
+// NextCreateConfigFileReqID 生成CreateConfigFile调用的请求Id
+func NextCreateConfigFileReqID() string {
+	return fmt.Sprintf("%d%d", reqIDPrefixCreateConfigFile, uuid.New().ID())
+}
+
+// NextUpdateConfigFileReqID 生成UpdateConfigFile调用的请求Id
+func NextUpdateConfigFileReqID() string {
+	return fmt.Sprintf("%d%d", reqIDPrefixUpdateConfigFile, uuid.New().ID())
+}
+
+// NextPublishConfigFileReqID 生成PublishConfigFile调用的请求Id
+func NextPublishConfigFileReqID() string {
+	return fmt.Sprintf("%d%d", reqIDPrefixPublishConfigFile, uuid.New().ID())
+}
+
+// NextGetConfigGroupReqID 生成GetConfigGroup调用的请求Id
+func NextGetConfigGroupReqID() string {
+	return fmt.Sprintf("%d%d", reqIDPrefixGetConfigGroup, uuid.New().ID())
+}
+
 // GetConnErrorCode 获取连接错误码
 func GetConnErrorCode(err error) int32 {
 	code, ok := status.FromError(err)
@@ -119,9 +148,30 @@ func GetConnErrorCode(err error) int32 {
 	return int32(model.ErrCodeNetworkError)
 }
 
+// IsUnauthorizedError 是否为server返回的鉴权失败错误
+func IsUnauthorizedError(err error) bool {
+	code, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	return code.Code() == codes.Unauthenticated || code.Code() == codes.PermissionDenied
+}
+
+// ServerUserErrCode 根据server返回码的类型获取对应的用户错误码，鉴权失败时返回独立的错误码
+func ServerUserErrCode(serverCodeType model.ErrCode) model.ErrCode {
+	if serverCodeType == model.ErrCodeUnauthorized {
+		return model.ErrCodeAPIUnauthorized
+	}
+	return model.ErrCodeServerUserError
+}
+
 // NetworkError 返回网络错误，并回收连接
 func NetworkError(connManager network.ConnectionManager, conn *network.Connection,
 	errCode int32, err error, startTime time.Time, msg string) model.SDKError {
+	if IsUnauthorizedError(err) {
+		// 鉴权失败与连接状态无关，不回收连接
+		return model.NewSDKError(model.ErrCodeAPIUnauthorized, err, msg)
+	}
 	endTime := clock.GetClock().Now()
 	if nil != conn {
 		connManager.ReportFail(conn.ConnID, errCode, endTime.Sub(startTime))
@@ -130,6 +180,18 @@ func NetworkError(connManager network.ConnectionManager, conn *network.Connectio
 	return model.NewSDKError(model.ErrCodeNetworkError, err, msg)
 }
 
+// CallerContextError 调用方上下文已结束时返回对应错误，此时请求失败与server连接无关，不回收连接
+func CallerContextError(parent context.Context, err error, msg string) model.SDKError {
+	if err == nil || parent == nil || parent.Err() == nil {
+		return nil
+	}
+	errCode := model.ErrCodeAPICanceled
+	if parent.Err() == context.DeadlineExceeded {
+		errCode = model.ErrCodeAPITimeoutError
+	}
+	return model.NewSDKError(errCode, err, msg)
+}
+
 // GetUpdateTaskRequestTime 获取一个updateTask的请求更新时间
 func GetUpdateTaskRequestTime(updateTask *serviceUpdateTask) time.Duration {
 	consumeTime := maxConnTimeout
@@ -154,27 +216,24 @@ func GetUpdateTaskRequestTime(updateTask *serviceUpdateTask) time.Duration {
 
 // CreateHeaderContext 创建传输grpc头的valueContext
 func CreateHeaderContext(timeout time.Duration, headers map[string]string) (context.Context, context.CancelFunc) {
-	md := metadata.New(headers)
-	var ctx context.Context
-	var cancel context.CancelFunc
-	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
-	} else {
-		ctx = context.Background()
-		cancel = nil
-	}
-	return metadata.NewOutgoingContext(ctx, md), cancel
+	return CreateHeaderContextWithParent(context.Background(), timeout, headers)
 }
 
 // CreateHeaderContextWithReqId 创建传输grpc头的valueContext
 func CreateHeaderContextWithReqId(timeout time.Duration, reqID string) (context.Context, context.CancelFunc) {
-	md := metadata.New(map[string]string{headerRequestID: reqID})
+	return CreateHeaderContextWithParent(context.Background(), timeout, map[string]string{headerRequestID: reqID})
+}
+
+// CreateHeaderContextWithParent 基于调用方上下文创建传输grpc头的valueContext，调用方取消时请求随之取消
+func CreateHeaderContextWithParent(parent context.Context, timeout time.Duration,
+	headers map[string]string) (context.Context, context.CancelFunc) {
+	md := metadata.New(headers)
 	var ctx context.Context
 	var cancel context.CancelFunc
 	if timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		ctx, cancel = context.WithTimeout(parent, timeout)
 	} else {
-		ctx = context.Background()
+		ctx = parent
 		cancel = nil
 	}
 	return metadata.NewOutgoingContext(ctx, md), cancel
@@ -0,0 +1,94 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package common
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/network"
+)
+
+// startTokenServer 启动校验鉴权凭证的grpc服务
+func startTokenServer(t *testing.T, token string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(network.HeaderKeyToken); len(values) != 1 || values[0] != token {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+// TestTokenUnauthorized 测试请求携带鉴权凭证，鉴权失败时返回ErrCodeAPIUnauthorized并且不回收连接
+func TestTokenUnauthorized(t *testing.T) {
+	address := startTokenServer(t, "secret")
+	cfg := &config.ServerConnectorConfigImpl{}
+	provider, err := network.NewTokenProvider(cfg)
+	assert.Nil(t, err)
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(network.NewTokenCredentials(provider)))
+	assert.Nil(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	check := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+	cfg.SetToken("wrong")
+	err = check()
+	assert.True(t, IsUnauthorizedError(err))
+	// 鉴权失败时不使用连接管理器回收连接
+	sdkErr := NetworkError(nil, &network.Connection{}, int32(model.ErrorCodeRpcError), err, time.Now(), "check")
+	assert.Equal(t, model.ErrCodeAPIUnauthorized, sdkErr.ErrorCode())
+
+	cfg.SetToken("secret")
+	assert.Nil(t, check())
+}
+
+// TestServerUserErrCode 测试server返回401时转换为ErrCodeAPIUnauthorized
+func TestServerUserErrCode(t *testing.T) {
+	assert.Equal(t, model.ErrCodeAPIUnauthorized, ServerUserErrCode(pb.ConvertServerErrorToRpcError(401000)))
+	assert.Equal(t, model.ErrCodeServerUserError, ServerUserErrCode(pb.ConvertServerErrorToRpcError(400001)))
+}
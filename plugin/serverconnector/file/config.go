@@ -0,0 +1,58 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// 默认的文件变更检查间隔
+	defaultRefreshInterval = 1 * time.Second
+)
+
+// Config 文件连接器插件级配置
+type Config struct {
+	// Path 服务数据文件路径，可以是单个文件，也可以是目录，目录下所有.yaml、.yml以及.json文件都会被加载
+	Path string `yaml:"path" json:"path"`
+	// RefreshInterval 检查文件变更的间隔
+	RefreshInterval *time.Duration `yaml:"refreshInterval" json:"refreshInterval"`
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if nil == c.RefreshInterval {
+		c.RefreshInterval = model.ToDurationPtr(defaultRefreshInterval)
+	}
+}
+
+// Verify 校验配置值，文件路径只有在启用该插件时才校验
+func (c *Config) Verify() error {
+	if nil == c.RefreshInterval {
+		return fmt.Errorf("file.refreshInterval not configured")
+	}
+	if *c.RefreshInterval < config.DefaultMinTimingInterval {
+		return fmt.Errorf("file.refreshInterval %v is less than minimal timing interval %v",
+			*c.RefreshInterval, config.DefaultMinTimingInterval)
+	}
+	return nil
+}
@@ -0,0 +1,415 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package file 基于本地文件的服务端连接器，从YAML/JSON文件中读取服务、实例、路由规则以及限流规则，
+// 适用于无法访问北极星服务端的离线环境
+package file
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+)
+
+const (
+	// 文件协议名
+	protocolFile = "file"
+)
+
+// 事件类型与服务发现应答类型的对应关系
+var eventTypeToResponseType = map[model.EventType]apiservice.DiscoverResponse_DiscoverResponseType{
+	model.EventInstances:      apiservice.DiscoverResponse_INSTANCE,
+	model.EventRouting:        apiservice.DiscoverResponse_ROUTING,
+	model.EventRateLimiting:   apiservice.DiscoverResponse_RATE_LIMIT,
+	model.EventCircuitBreaker: apiservice.DiscoverResponse_CIRCUIT_BREAKER,
+	model.EventServices:       apiservice.DiscoverResponse_SERVICES,
+	model.EventFaultDetect:    apiservice.DiscoverResponse_FAULT_DETECTOR,
+}
+
+// Connector 基于本地文件的服务端连接器，文件变更后自动重新加载并推送服务事件
+type Connector struct {
+	*plugin.PluginBase
+	*common.RunContext
+	// 插件级配置
+	cfg *Config
+	// 当前加载的文件集合签名
+	signature string
+	dataLock  sync.RWMutex
+	// 文件中定义的服务数据
+	services map[model.ServiceKey]*serviceData
+	// 通过RegisterInstance注册到本进程中的实例，不会写回文件
+	registered map[model.ServiceKey]map[string]*apiservice.Instance
+	// 服务事件推送器
+	pusher *connector.EventPusher
+	waiter sync.WaitGroup
+}
+
+// Type 插件类型
+func (f *Connector) Type() common.Type {
+	return common.TypeServerConnector
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (f *Connector) Name() string {
+	return protocolFile
+}
+
+// Init 初始化插件，首次加载失败时直接返回错误
+func (f *Connector) Init(ctx *plugin.InitContext) error {
+	f.RunContext = common.NewRunContext()
+	f.PluginBase = plugin.NewPluginBase(ctx)
+	cfgValue := ctx.Config.GetGlobal().GetServerConnector().GetPluginConfig(f.Name())
+	if cfgValue != nil {
+		f.cfg = cfgValue.(*Config)
+	}
+	if f.cfg == nil || len(f.cfg.Path) == 0 {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, nil,
+			"global.serverConnector.plugin.%s.path is empty", f.Name())
+	}
+	f.registered = make(map[model.ServiceKey]map[string]*apiservice.Instance)
+	f.pusher = connector.NewEventPusher(f.buildResponse)
+	if _, err := f.reload(); err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
+			"fail to load services from %s", f.cfg.Path)
+	}
+	return nil
+}
+
+// Start 启动文件变更检查以及事件推送协程
+func (f *Connector) Start() error {
+	f.pusher.Start()
+	f.waiter.Add(1)
+	go f.watchLoop()
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (f *Connector) Destroy() error {
+	_ = f.RunContext.Destroy()
+	f.waiter.Wait()
+	if f.pusher != nil {
+		f.pusher.Stop()
+	}
+	return nil
+}
+
+// IsEnable 只有服务端连接器协议配置为file时才启用
+func (f *Connector) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetServerConnector().GetProtocol() == protocolFile
+}
+
+// RegisterServiceHandler 注册服务监听器，数据会在推送协程中异步回调
+// 异常场景：当sdk已经退出过程中，则返回error
+func (f *Connector) RegisterServiceHandler(svcEventHandler *serverconnector.ServiceEventHandler) error {
+	if f.IsDestroyed() {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+			"RegisterServiceHandler: serverConnector has been destroyed")
+	}
+	log.GetBaseLogger().Infof("%s, file connector: register handler for %s",
+		f.GetSDKContextID(), *svcEventHandler.ServiceEventKey)
+	f.pusher.AddHandler(svcEventHandler)
+	return nil
+}
+
+// DeRegisterServiceHandler 反注册事件监听器
+// 异常场景：当sdk已经退出过程中，则返回error
+func (f *Connector) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	if f.IsDestroyed() {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+			"DeRegisterServiceHandler: serverConnector has been destroyed")
+	}
+	f.pusher.RemoveHandler(*key)
+	return nil
+}
+
+// RegisterInstance 将实例注册到本进程的内存中，同一进程内的服务发现可以感知到该实例
+func (f *Connector) RegisterInstance(req *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
+	instance := connector.RegisterRequestToProto(req)
+	fillInstance(req.Namespace, req.Service, instance)
+	svcKey := model.ServiceKey{Namespace: req.Namespace, Service: req.Service}
+	instanceID := instance.GetId().GetValue()
+
+	f.dataLock.Lock()
+	instances, ok := f.registered[svcKey]
+	if !ok {
+		instances = make(map[string]*apiservice.Instance)
+		f.registered[svcKey] = instances
+	}
+	_, existed := instances[instanceID]
+	instances[instanceID] = instance
+	f.dataLock.Unlock()
+
+	f.pusher.Trigger()
+	return &model.InstanceRegisterResponse{InstanceID: instanceID, Existed: existed}, nil
+}
+
+// DeregisterInstance 删除通过RegisterInstance注册的实例，文件中定义的实例不允许反注册
+func (f *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) error {
+	svcKey := model.ServiceKey{Namespace: req.Namespace, Service: req.Service}
+	f.dataLock.Lock()
+	instanceID := f.lookupRegisteredID(svcKey, req.InstanceID, req.Host, req.Port)
+	if len(instanceID) > 0 {
+		delete(f.registered[svcKey], instanceID)
+		if len(f.registered[svcKey]) == 0 {
+			delete(f.registered, svcKey)
+		}
+	}
+	f.dataLock.Unlock()
+	if len(instanceID) == 0 {
+		return model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"fail to deregisterInstance, instance %s:%d of service %s is not registered",
+			req.Host, req.Port, svcKey)
+	}
+	f.pusher.Trigger()
+	return nil
+}
+
+// Heartbeat 心跳上报，只校验实例是否已经通过RegisterInstance注册
+func (f *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
+	svcKey := model.ServiceKey{Namespace: req.Namespace, Service: req.Service}
+	f.dataLock.RLock()
+	instanceID := f.lookupRegisteredID(svcKey, req.InstanceID, req.Host, req.Port)
+	f.dataLock.RUnlock()
+	if len(instanceID) == 0 {
+		return model.NewSDKError(model.ErrCodeAPIInstanceNotFound, nil,
+			"fail to heartbeat, instance %s:%d of service %s is not registered", req.Host, req.Port, svcKey)
+	}
+	return nil
+}
+
+// ReportClient 上报客户端信息，离线环境下没有服务端可以提供地域信息
+func (f *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportClientResponse, error) {
+	return &model.ReportClientResponse{Version: req.Version}, nil
+}
+
+// UpdateServers 更新服务端地址，文件连接器没有服务端，无需处理
+func (f *Connector) UpdateServers(key *model.ServiceEventKey) error {
+	return nil
+}
+
+// lookupRegisteredID 按照ID或者IP端口查找已注册的实例，调用方需要持有读锁
+func (f *Connector) lookupRegisteredID(svcKey model.ServiceKey, id string, host string, port int) string {
+	instances := f.registered[svcKey]
+	if len(id) > 0 {
+		if _, ok := instances[id]; ok {
+			return id
+		}
+		return ""
+	}
+	for instanceID, instance := range instances {
+		if instance.GetHost().GetValue() == host && instance.GetPort().GetValue() == uint32(port) {
+			return instanceID
+		}
+	}
+	return ""
+}
+
+// watchLoop 定时检查文件变更，重新加载后触发事件推送
+func (f *Connector) watchLoop() {
+	defer f.waiter.Done()
+	ticker := time.NewTicker(*f.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.Done():
+			log.GetBaseLogger().Infof("%s, file connector: context done, exit watch loop", f.GetSDKContextID())
+			return
+		case <-ticker.C:
+			changed, err := f.reload()
+			if err != nil {
+				log.GetBaseLogger().Errorf("%s, file connector: fail to reload services from %s, "+
+					"keep using the last loaded data, err is %v", f.GetSDKContextID(), f.cfg.Path, err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+			log.GetBaseLogger().Infof("%s, file connector: services reloaded from %s",
+				f.GetSDKContextID(), f.cfg.Path)
+			f.pusher.Trigger()
+		}
+	}
+}
+
+// reload 文件发生变更时重新加载服务数据，返回数据是否已经重新加载
+func (f *Connector) reload() (bool, error) {
+	files, err := listDataFiles(f.cfg.Path)
+	if err != nil {
+		return false, err
+	}
+	signature, err := fileSignature(files)
+	if err != nil {
+		return false, err
+	}
+	if signature == f.signature {
+		return false, nil
+	}
+	services, err := loadServices(files)
+	if err != nil {
+		return false, err
+	}
+	f.dataLock.Lock()
+	f.services = services
+	f.dataLock.Unlock()
+	f.signature = signature
+	return true, nil
+}
+
+// buildResponse 根据当前的服务数据构建服务发现应答，服务不存在时返回资源不存在
+func (f *Connector) buildResponse(svcEventKey model.ServiceEventKey) *apiservice.DiscoverResponse {
+	resp := &apiservice.DiscoverResponse{
+		Type: eventTypeToResponseType[svcEventKey.Type],
+		Service: &apiservice.Service{
+			Namespace: &wrappers.StringValue{Value: svcEventKey.Namespace},
+			Name:      &wrappers.StringValue{Value: svcEventKey.Service},
+		},
+	}
+	f.dataLock.RLock()
+	defer f.dataLock.RUnlock()
+	if svcEventKey.Type == model.EventServices {
+		resp.Services = f.listServices(svcEventKey.Namespace)
+		resp.Service.Revision = &wrappers.StringValue{Value: servicesRevision(resp.Services)}
+		resp.Code = &wrappers.UInt32Value{Value: uint32(apimodel.Code_ExecuteSuccess)}
+		return resp
+	}
+	svcKey := svcEventKey.ServiceKey
+	data, inFile := f.services[svcKey]
+	registered, inMemory := f.registered[svcKey]
+	if !inFile && !inMemory {
+		resp.Code = &wrappers.UInt32Value{Value: uint32(apimodel.Code_NotFoundResource)}
+		resp.Info = &wrappers.StringValue{Value: fmt.Sprintf("service %s not found in %s", svcKey, f.cfg.Path)}
+		return resp
+	}
+	if inFile {
+		resp.Service = proto.Clone(data.service).(*apiservice.Service)
+	}
+	var revision string
+	switch svcEventKey.Type {
+	case model.EventInstances:
+		resp.Instances = mergeInstances(data, registered)
+		revision = instancesRevision(resp.Service, resp.Instances)
+	case model.EventRouting:
+		if inFile && data.routing != nil {
+			resp.Routing = data.routing
+			revision = data.routing.GetRevision().GetValue()
+		}
+	case model.EventRateLimiting:
+		if inFile && data.rateLimit != nil {
+			resp.RateLimit = data.rateLimit
+			revision = data.rateLimit.GetRevision().GetValue()
+		}
+	}
+	if len(revision) == 0 {
+		revision = connector.MessagesRevision(resp.Service)
+	}
+	resp.Service.Revision = &wrappers.StringValue{Value: revision}
+	resp.Code = &wrappers.UInt32Value{Value: uint32(apimodel.Code_ExecuteSuccess)}
+	return resp
+}
+
+// listServices 获取命名空间下的服务，命名空间为空时返回所有服务，调用方需要持有读锁
+func (f *Connector) listServices(namespace string) []*apiservice.Service {
+	svcKeys := make(map[model.ServiceKey]struct{}, len(f.services)+len(f.registered))
+	for svcKey := range f.services {
+		svcKeys[svcKey] = struct{}{}
+	}
+	for svcKey := range f.registered {
+		svcKeys[svcKey] = struct{}{}
+	}
+	services := make([]*apiservice.Service, 0, len(svcKeys))
+	for svcKey := range svcKeys {
+		if len(namespace) > 0 && svcKey.Namespace != namespace {
+			continue
+		}
+		if data, ok := f.services[svcKey]; ok {
+			services = append(services, data.service)
+			continue
+		}
+		services = append(services, &apiservice.Service{
+			Namespace: &wrappers.StringValue{Value: svcKey.Namespace},
+			Name:      &wrappers.StringValue{Value: svcKey.Service},
+		})
+	}
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].GetNamespace().GetValue() != services[j].GetNamespace().GetValue() {
+			return services[i].GetNamespace().GetValue() < services[j].GetNamespace().GetValue()
+		}
+		return services[i].GetName().GetValue() < services[j].GetName().GetValue()
+	})
+	return services
+}
+
+// mergeInstances 合并文件中定义的实例以及注册到内存中的实例，ID相同时以注册的实例为准
+func mergeInstances(data *serviceData, registered map[string]*apiservice.Instance) []*apiservice.Instance {
+	var instances []*apiservice.Instance
+	if data != nil {
+		instances = make([]*apiservice.Instance, 0, len(data.instances)+len(registered))
+		for _, instance := range data.instances {
+			if _, ok := registered[instance.GetId().GetValue()]; !ok {
+				instances = append(instances, instance)
+			}
+		}
+	}
+	ids := make([]string, 0, len(registered))
+	for id := range registered {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		instances = append(instances, registered[id])
+	}
+	return instances
+}
+
+// servicesRevision 根据服务列表计算版本号
+func servicesRevision(services []*apiservice.Service) string {
+	messages := make([]proto.Message, 0, len(services))
+	for _, service := range services {
+		messages = append(messages, service)
+	}
+	return connector.MessagesRevision(messages...)
+}
+
+// instancesRevision 根据服务以及实例列表计算版本号
+func instancesRevision(service *apiservice.Service, instances []*apiservice.Instance) string {
+	messages := make([]proto.Message, 0, len(instances)+1)
+	messages = append(messages, service)
+	for _, instance := range instances {
+		messages = append(messages, instance)
+	}
+	return connector.MessagesRevision(messages...)
+}
+
+// init 注册插件信息
+func init() {
+	plugin.RegisterConfigurablePlugin(&Connector{}, &Config{})
+}
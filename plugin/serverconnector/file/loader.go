@@ -0,0 +1,265 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+)
+
+const (
+	// 实例未配置权重时的默认权重
+	defaultInstanceWeight = 100
+)
+
+// serviceDocument 服务数据文件的内容
+type serviceDocument struct {
+	Services []*serviceSpec `json:"services"`
+}
+
+// serviceSpec 文件中定义的服务，实例、路由规则以及限流规则使用北极星服务端API的JSON格式
+type serviceSpec struct {
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Metadata  map[string]string `json:"metadata"`
+	Instances []json.RawMessage `json:"instances"`
+	Routing   json.RawMessage   `json:"routing"`
+	RateLimit json.RawMessage   `json:"rateLimit"`
+}
+
+// serviceData 解析后的服务数据
+type serviceData struct {
+	service   *apiservice.Service
+	instances []*apiservice.Instance
+	routing   *apitraffic.Routing
+	rateLimit *apitraffic.RateLimit
+}
+
+// listDataFiles 获取路径下的所有服务数据文件，按照文件名排序
+func listDataFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !isDataFile(entry.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(path, entry.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func isDataFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// fileSignature 根据文件名、大小以及修改时间计算文件集合的签名，用于判断文件是否发生变更
+func fileSignature(files []string) (string, error) {
+	h := sha1.New()
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(h, "%s|%d|%d\n", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// loadServices 加载所有文件中的服务数据，同一个服务不允许在多个地方重复定义
+func loadServices(files []string) (map[model.ServiceKey]*serviceData, error) {
+	services := make(map[model.ServiceKey]*serviceData)
+	for _, file := range files {
+		doc, err := readDocument(file)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read %s: %v", file, err)
+		}
+		for i, spec := range doc.Services {
+			data, err := spec.toServiceData()
+			if err != nil {
+				return nil, fmt.Errorf("fail to parse services[%d] in %s: %v", i, file, err)
+			}
+			svcKey := model.ServiceKey{
+				Namespace: data.service.GetNamespace().GetValue(),
+				Service:   data.service.GetName().GetValue(),
+			}
+			if _, ok := services[svcKey]; ok {
+				return nil, fmt.Errorf("service %s is duplicated in %s", svcKey, file)
+			}
+			services[svcKey] = data
+		}
+	}
+	return services, nil
+}
+
+// readDocument 读取单个服务数据文件，YAML文件先转换为JSON再解析
+func readDocument(file string) (*serviceDocument, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(filepath.Ext(file)) != ".json" {
+		var root interface{}
+		if err = yaml.Unmarshal(content, &root); err != nil {
+			return nil, err
+		}
+		if content, err = json.Marshal(normalizeYAML(root)); err != nil {
+			return nil, err
+		}
+	}
+	doc := &serviceDocument{}
+	if len(bytes.TrimSpace(content)) == 0 || string(content) == "null" {
+		return doc, nil
+	}
+	if err = json.Unmarshal(content, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// normalizeYAML 将YAML解析出来的map[interface{}]interface{}转换为JSON可以序列化的map[string]interface{}
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		values := make(map[string]interface{}, len(v))
+		for key, subValue := range v {
+			values[fmt.Sprint(key)] = normalizeYAML(subValue)
+		}
+		return values
+	case []interface{}:
+		for i, subValue := range v {
+			v[i] = normalizeYAML(subValue)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// toServiceData 校验并转换服务定义，补齐实例和规则中缺省的字段
+func (s *serviceSpec) toServiceData() (*serviceData, error) {
+	if len(s.Namespace) == 0 || len(s.Name) == 0 {
+		return nil, fmt.Errorf("namespace and name are required")
+	}
+	data := &serviceData{
+		service: &apiservice.Service{
+			Namespace: &wrappers.StringValue{Value: s.Namespace},
+			Name:      &wrappers.StringValue{Value: s.Name},
+			Metadata:  s.Metadata,
+		},
+	}
+	for i, raw := range s.Instances {
+		instance := &apiservice.Instance{}
+		if err := unmarshalMessage(raw, instance); err != nil {
+			return nil, fmt.Errorf("instances[%d]: %v", i, err)
+		}
+		if len(instance.GetHost().GetValue()) == 0 || instance.GetPort().GetValue() == 0 {
+			return nil, fmt.Errorf("instances[%d]: host and port are required", i)
+		}
+		fillInstance(s.Namespace, s.Name, instance)
+		data.instances = append(data.instances, instance)
+	}
+	if !isNullJSON(s.Routing) {
+		data.routing = &apitraffic.Routing{}
+		if err := unmarshalMessage(s.Routing, data.routing); err != nil {
+			return nil, fmt.Errorf("routing: %v", err)
+		}
+		data.routing.Namespace = &wrappers.StringValue{Value: s.Namespace}
+		data.routing.Service = &wrappers.StringValue{Value: s.Name}
+		data.routing.Revision = nil
+		data.routing.Revision = &wrappers.StringValue{Value: connector.MessagesRevision(data.routing)}
+	}
+	if !isNullJSON(s.RateLimit) {
+		data.rateLimit = &apitraffic.RateLimit{}
+		if err := unmarshalMessage(s.RateLimit, data.rateLimit); err != nil {
+			return nil, fmt.Errorf("rateLimit: %v", err)
+		}
+		for i, rule := range data.rateLimit.GetRules() {
+			rule.Namespace = &wrappers.StringValue{Value: s.Namespace}
+			rule.Service = &wrappers.StringValue{Value: s.Name}
+			if len(rule.GetId().GetValue()) == 0 {
+				rule.Id = &wrappers.StringValue{Value: fmt.Sprintf("%s.%s.%d", s.Namespace, s.Name, i)}
+			}
+			rule.Revision = nil
+			rule.Revision = &wrappers.StringValue{Value: connector.MessagesRevision(rule)}
+		}
+		data.rateLimit.Revision = nil
+		data.rateLimit.Revision = &wrappers.StringValue{Value: connector.MessagesRevision(data.rateLimit)}
+	}
+	return data, nil
+}
+
+// fillInstance 补齐实例的服务信息、ID、健康状态、权重以及版本号
+func fillInstance(namespace, service string, instance *apiservice.Instance) {
+	instance.Namespace = &wrappers.StringValue{Value: namespace}
+	instance.Service = &wrappers.StringValue{Value: service}
+	instance.ServiceToken = nil
+	if len(instance.GetId().GetValue()) == 0 {
+		instance.Id = &wrappers.StringValue{
+			Value: connector.GenInstanceID(namespace, service, instance.GetHost().GetValue(), instance.GetPort().GetValue())}
+	}
+	if nil == instance.Healthy {
+		instance.Healthy = &wrappers.BoolValue{Value: true}
+	}
+	if nil == instance.Weight {
+		instance.Weight = &wrappers.UInt32Value{Value: defaultInstanceWeight}
+	}
+	instance.Revision = nil
+	instance.Revision = &wrappers.StringValue{Value: connector.MessagesRevision(instance)}
+}
+
+func isNullJSON(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) == 0 || string(trimmed) == "null"
+}
+
+func unmarshalMessage(raw json.RawMessage, message proto.Message) error {
+	return jsonpb.Unmarshal(bytes.NewReader(raw), message)
+}
@@ -0,0 +1,106 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const testServicesYAML = `
+services:
+  - namespace: default
+    name: echo
+    metadata:
+      owner: test
+    instances:
+      - host: 127.0.0.1
+        port: 8080
+      - host: 127.0.0.1
+        port: 8081
+        weight: 50
+        healthy: false
+    rateLimit:
+      rules:
+        - amounts:
+            - maxAmount: 10
+              validDuration: 1s
+`
+
+const testServicesJSON = `{"services": [{"namespace": "default", "name": "echo2",
+  "instances": [{"host": "127.0.0.2", "port": 9090}]}]}`
+
+func writeTestFile(t *testing.T, dir string, name string, content string) {
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func TestLoadServices(t *testing.T) {
+	dir, err := ioutil.TempDir("", "polaris-file-connector")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	writeTestFile(t, dir, "echo.yaml", testServicesYAML)
+	writeTestFile(t, dir, "echo2.json", testServicesJSON)
+	writeTestFile(t, dir, "README.md", "not a data file")
+
+	files, err := listDataFiles(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(files))
+	services, err := loadServices(files)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(services))
+
+	echo := services[model.ServiceKey{Namespace: "default", Service: "echo"}]
+	assert.NotNil(t, echo)
+	assert.Equal(t, "test", echo.service.GetMetadata()["owner"])
+	assert.Equal(t, 2, len(echo.instances))
+	assert.True(t, echo.instances[0].GetHealthy().GetValue())
+	assert.Equal(t, uint32(defaultInstanceWeight), echo.instances[0].GetWeight().GetValue())
+	assert.False(t, echo.instances[1].GetHealthy().GetValue())
+	assert.Equal(t, uint32(50), echo.instances[1].GetWeight().GetValue())
+	assert.NotEqual(t, echo.instances[0].GetId().GetValue(), echo.instances[1].GetId().GetValue())
+	assert.Equal(t, 1, len(echo.rateLimit.GetRules()))
+	assert.Equal(t, "echo", echo.rateLimit.GetRules()[0].GetService().GetValue())
+	assert.NotEmpty(t, echo.rateLimit.GetRules()[0].GetId().GetValue())
+
+	// 内容不变时版本号不变，内容变化时版本号变化
+	reloaded, err := loadServices(files)
+	assert.Nil(t, err)
+	reloadedEcho := reloaded[model.ServiceKey{Namespace: "default", Service: "echo"}]
+	assert.Equal(t, echo.rateLimit.GetRevision().GetValue(), reloadedEcho.rateLimit.GetRevision().GetValue())
+	assert.Equal(t, echo.instances[0].GetRevision().GetValue(), reloadedEcho.instances[0].GetRevision().GetValue())
+	assert.NotEqual(t, echo.instances[0].GetRevision().GetValue(), echo.instances[1].GetRevision().GetValue())
+}
+
+func TestLoadServices_Duplicated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "polaris-file-connector")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	writeTestFile(t, dir, "a.json", testServicesJSON)
+	writeTestFile(t, dir, "b.json", testServicesJSON)
+
+	files, err := listDataFiles(dir)
+	assert.Nil(t, err)
+	_, err = loadServices(files)
+	assert.NotNil(t, err)
+}
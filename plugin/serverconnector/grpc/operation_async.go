@@ -22,6 +22,7 @@ import (
 	"time"
 
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
@@ -50,6 +51,10 @@ type Connector struct {
 	connectionIdleTimeout time.Duration
 	valueCtx              model.ValueContext
 	discoverConnector     *connector.DiscoverConnector
+	// 与server通信的传输层凭证
+	creds credentials.TransportCredentials
+	// 访问server的鉴权凭证
+	tokenCreds credentials.PerRPCCredentials
 	// 有没有打印过connManager ready的信息，用于避免重复打印
 	hasPrintedReady uint32
 }
@@ -75,6 +80,12 @@ func (g *Connector) Init(ctx *plugin.InitContext) error {
 	g.connManager = ctx.ConnManager
 	g.connectionIdleTimeout = ctx.Config.GetGlobal().GetServerConnector().GetConnectionIdleTimeout()
 	g.valueCtx = ctx.ValueCtx
+	g.creds = network.NewTransportCredentials(ctx.Config.GetGlobal().GetServerConnector().GetTLS())
+	tokenProvider, err := network.NewTokenProvider(ctx.Config.GetGlobal().GetServerConnector())
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create token provider")
+	}
+	g.tokenCreds = network.NewTokenCredentials(tokenProvider)
 	protocol := ctx.Config.GetGlobal().GetServerConnector().GetProtocol()
 	if protocol == g.Name() {
 		log.GetBaseLogger().Infof("set %s plugin as connectionCreator", g.Name())
@@ -56,7 +56,8 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextRegisterInstanceReqID()
-		ctx, cancel  = connector.CreateHeaderContext(*req.Timeout, connector.AppendHeaderWithReqId(header, reqID))
+		ctx, cancel  = connector.CreateHeaderContextWithParent(req.GetContext(), *req.Timeout,
+			connector.AppendHeaderWithReqId(header, reqID))
 	)
 
 	if cancel != nil {
@@ -70,6 +71,9 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 	}
 	pbResp, err := namingClient.RegisterInstance(ctx, reqProto)
 	endTime := clock.GetClock().Now()
+	if ctxErr := connector.CallerContextError(req.GetContext(), err, "registerInstance is interrupted by caller"); ctxErr != nil {
+		return nil, ctxErr
+	}
 	if err != nil {
 		return nil, connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to registerInstance, request %s, "+
@@ -93,7 +97,7 @@ func (g *Connector) RegisterInstance(req *model.InstanceRegisterRequest, header
 			return nil, model.NewSDKError(model.ErrCodeServerException, nil, errMsg)
 		}
 		g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
-		return nil, model.NewSDKError(model.ErrCodeServerUserError, nil, errMsg)
+		return nil, model.NewSDKError(connector.ServerUserErrCode(serverCodeType), nil, errMsg)
 	}
 	g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
 	resp := &model.InstanceRegisterResponse{InstanceID: pbResp.GetInstance().GetId().GetValue(),
@@ -120,7 +124,8 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextDeRegisterInstanceReqID()
-		ctx, cancel  = connector.CreateHeaderContextWithReqId(*req.Timeout, reqID)
+		ctx, cancel  = connector.CreateHeaderContextWithParent(req.GetContext(), *req.Timeout,
+			connector.AppendHeaderWithReqId(nil, reqID))
 	)
 	if cancel != nil {
 		defer cancel()
@@ -133,6 +138,9 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 	}
 	pbResp, err := namingClient.DeregisterInstance(ctx, reqProto)
 	endTime := clock.GetClock().Now()
+	if ctxErr := connector.CallerContextError(req.GetContext(), err, "deregisterInstance is interrupted by caller"); ctxErr != nil {
+		return ctxErr
+	}
 	if err != nil {
 		return connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to deregisterInstance, request %s, "+
@@ -156,7 +164,7 @@ func (g *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) err
 			return model.NewSDKError(model.ErrCodeServerException, nil, errMsg)
 		}
 		g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
-		return model.NewSDKError(model.ErrCodeServerUserError, nil, errMsg)
+		return model.NewSDKError(connector.ServerUserErrCode(serverCodeType), nil, errMsg)
 	}
 	g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
 	return nil
@@ -181,7 +189,8 @@ func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
 	var (
 		namingClient = apiservice.NewPolarisGRPCClient(network.ToGRPCConn(conn.Conn))
 		reqID        = connector.NextHeartbeatReqID()
-		ctx, cancel  = connector.CreateHeaderContextWithReqId(*req.Timeout, reqID)
+		ctx, cancel  = connector.CreateHeaderContextWithParent(req.GetContext(), *req.Timeout,
+			connector.AppendHeaderWithReqId(nil, reqID))
 	)
 	if cancel != nil {
 		defer cancel()
@@ -194,6 +203,9 @@ func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
 	}
 	pbResp, err := namingClient.Heartbeat(ctx, reqProto)
 	endTime := clock.GetClock().Now()
+	if ctxErr := connector.CallerContextError(req.GetContext(), err, "heartbeat is interrupted by caller"); ctxErr != nil {
+		return ctxErr
+	}
 	if err != nil {
 		return connector.NetworkError(g.connManager, conn, int32(model.ErrorCodeRpcError), err, startTime,
 			fmt.Sprintf("fail to heartbeat, request %s, reason is fail to send request, reqID %s, server %s",
@@ -217,7 +229,7 @@ func (g *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
 			return model.NewSDKErrorWithServerInfo(model.ErrCodeServerException, nil, pbResp.GetCode().GetValue(), pbResp.GetInfo().GetValue(), errMsg)
 		}
 		g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
-		return model.NewSDKErrorWithServerInfo(model.ErrCodeServerUserError, nil, pbResp.GetCode().GetValue(), pbResp.GetInfo().GetValue(), errMsg)
+		return model.NewSDKErrorWithServerInfo(connector.ServerUserErrCode(serverCodeType), nil, pbResp.GetCode().GetValue(), pbResp.GetInfo().GetValue(), errMsg)
 	}
 	g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
 	return nil
@@ -306,7 +318,7 @@ func (g *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportC
 			return nil, model.NewSDKError(model.ErrCodeServerException, nil, errMsg)
 		}
 		g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
-		return nil, model.NewSDKError(model.ErrCodeServerUserError, nil, errMsg)
+		return nil, model.NewSDKError(connector.ServerUserErrCode(serverCodeType), nil, errMsg)
 	}
 	g.connManager.ReportSuccess(conn.ConnID, int32(serverCodeType), endTime.Sub(startTime))
 	// 持久化本地信息
@@ -0,0 +1,274 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xds
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// 订阅请求的发送顺序，先订阅集群再订阅端点和路由
+var subscribeTypes = []string{resourcev3.ClusterType, resourcev3.EndpointType, resourcev3.RouteType}
+
+// typeState 单个资源类型的订阅状态
+type typeState struct {
+	// 最近一次接受的资源版本
+	version string
+	// 最近一次收到的应答nonce
+	nonce string
+	// 最近一次发送的订阅资源名
+	names []string
+	// 是否已经发送过订阅请求
+	subscribed bool
+}
+
+// adsStream 一条ADS双向流，所有资源类型复用同一条流
+type adsStream struct {
+	stream discoveryv3.AggregatedDiscoveryService_StreamAggregatedResourcesClient
+	node   *corev3.Node
+	// 发送锁，grpc流不支持并发发送
+	sendLock sync.Mutex
+	states   map[string]*typeState
+}
+
+// openStream 连接控制面并创建ADS流，资源版本在重连之间保留，避免控制面重复推送
+func (x *Connector) openStream(ctx context.Context, address string) (*grpc.ClientConn, *adsStream, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, x.connectTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(dialCtx, address, grpc.WithTransportCredentials(x.creds),
+		grpc.WithPerRPCCredentials(x.tokenCreds), grpc.WithBlock())
+	if err != nil {
+		return nil, nil, err
+	}
+	stream, err := discoveryv3.NewAggregatedDiscoveryServiceClient(conn).StreamAggregatedResources(ctx)
+	if err != nil {
+		_ = conn.Close()
+		return nil, nil, err
+	}
+	ads := &adsStream{stream: stream, node: x.node, states: make(map[string]*typeState)}
+	x.cacheLock.RLock()
+	for typeURL, version := range x.versions {
+		ads.states[typeURL] = &typeState{version: version}
+	}
+	x.cacheLock.RUnlock()
+	return conn, ads, nil
+}
+
+// subscribe 订阅资源，资源名与上次发送的一致时不重复发送
+func (a *adsStream) subscribe(typeURL string, names []string) error {
+	a.sendLock.Lock()
+	defer a.sendLock.Unlock()
+	state := a.getState(typeURL)
+	if state.subscribed && equalNames(state.names, names) {
+		return nil
+	}
+	state.names = names
+	state.subscribed = true
+	return a.sendLocked(typeURL, state, nil)
+}
+
+// ack 确认或者拒绝控制面推送的资源，拒绝时沿用上次接受的版本
+func (a *adsStream) ack(resp *discoveryv3.DiscoveryResponse, names []string, nackErr error) error {
+	a.sendLock.Lock()
+	defer a.sendLock.Unlock()
+	state := a.getState(resp.GetTypeUrl())
+	state.nonce = resp.GetNonce()
+	if resp.GetTypeUrl() == resourcev3.ClusterType || len(names) > 0 {
+		state.names = names
+	}
+	state.subscribed = true
+	if nackErr == nil {
+		state.version = resp.GetVersionInfo()
+	}
+	return a.sendLocked(resp.GetTypeUrl(), state, nackErr)
+}
+
+func (a *adsStream) getState(typeURL string) *typeState {
+	state, ok := a.states[typeURL]
+	if !ok {
+		state = &typeState{}
+		a.states[typeURL] = state
+	}
+	return state
+}
+
+func (a *adsStream) sendLocked(typeURL string, state *typeState, nackErr error) error {
+	req := &discoveryv3.DiscoveryRequest{
+		VersionInfo:   state.version,
+		Node:          a.node,
+		ResourceNames: state.names,
+		TypeUrl:       typeURL,
+		ResponseNonce: state.nonce,
+	}
+	if nackErr != nil {
+		req.ErrorDetail = status.New(codes.InvalidArgument, nackErr.Error()).Proto()
+	}
+	return a.stream.Send(req)
+}
+
+func equalNames(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// subscriptions 根据已注册的监听器计算各类型需要订阅的资源名，CDS始终使用通配订阅
+func (x *Connector) subscriptions() map[string][]string {
+	endpointNames := make(map[string]struct{})
+	routeNames := make(map[string]struct{})
+	x.cacheLock.RLock()
+	for _, key := range x.pusher.Keys() {
+		if key.Namespace != x.cfg.Namespace {
+			continue
+		}
+		switch key.Type {
+		case model.EventInstances:
+			endpointNames[x.edsResourceName(key.Service)] = struct{}{}
+		case model.EventRouting:
+			routeNames[key.Service] = struct{}{}
+		}
+	}
+	x.cacheLock.RUnlock()
+	return map[string][]string{
+		resourcev3.ClusterType:  nil,
+		resourcev3.EndpointType: sortedNames(endpointNames),
+		resourcev3.RouteType:    sortedNames(routeNames),
+	}
+}
+
+func sortedNames(values map[string]struct{}) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// updateSubscriptions 向当前的ADS流发送变化的订阅
+// xDS协议中空的资源名列表表示通配订阅，因此EDS和RDS没有需要订阅的资源时不发送请求
+func (x *Connector) updateSubscriptions(ads *adsStream) error {
+	subscriptions := x.subscriptions()
+	for _, typeURL := range subscribeTypes {
+		names := subscriptions[typeURL]
+		if typeURL != resourcev3.ClusterType && len(names) == 0 {
+			continue
+		}
+		if err := ads.subscribe(typeURL, names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleResponse 处理控制面推送的资源，解析成功后更新缓存并确认，解析失败则拒绝
+func (x *Connector) handleResponse(ads *adsStream, resp *discoveryv3.DiscoveryResponse) error {
+	err := x.applyResources(resp)
+	if err != nil {
+		log.GetBaseLogger().Errorf("%s, xds connector: reject %s version %s, err is %v",
+			x.GetSDKContextID(), resp.GetTypeUrl(), resp.GetVersionInfo(), err)
+	}
+	if ackErr := ads.ack(resp, x.subscriptions()[resp.GetTypeUrl()], err); ackErr != nil {
+		return ackErr
+	}
+	if err != nil {
+		return nil
+	}
+	if resp.GetTypeUrl() == resourcev3.ClusterType {
+		// 集群变化后EDS的资源名可能发生变化
+		if err = x.updateSubscriptions(ads); err != nil {
+			return err
+		}
+	}
+	x.pusher.Trigger()
+	return nil
+}
+
+// applyResources 解析资源并更新缓存，CDS为全量推送，EDS和RDS为按资源名增量推送
+func (x *Connector) applyResources(resp *discoveryv3.DiscoveryResponse) error {
+	switch resp.GetTypeUrl() {
+	case resourcev3.ClusterType:
+		clusters := make(map[string]*clusterv3.Cluster, len(resp.GetResources()))
+		for _, resource := range resp.GetResources() {
+			cluster := &clusterv3.Cluster{}
+			if err := resource.UnmarshalTo(cluster); err != nil {
+				return err
+			}
+			clusters[cluster.GetName()] = cluster
+		}
+		x.cacheLock.Lock()
+		x.clusters = clusters
+		x.clustersReceived = true
+		x.versions[resp.GetTypeUrl()] = resp.GetVersionInfo()
+		x.cacheLock.Unlock()
+	case resourcev3.EndpointType:
+		assignments := make([]*endpointv3.ClusterLoadAssignment, 0, len(resp.GetResources()))
+		for _, resource := range resp.GetResources() {
+			assignment := &endpointv3.ClusterLoadAssignment{}
+			if err := resource.UnmarshalTo(assignment); err != nil {
+				return err
+			}
+			assignments = append(assignments, assignment)
+		}
+		x.cacheLock.Lock()
+		for _, assignment := range assignments {
+			x.endpoints[assignment.GetClusterName()] = assignment
+		}
+		x.versions[resp.GetTypeUrl()] = resp.GetVersionInfo()
+		x.cacheLock.Unlock()
+	case resourcev3.RouteType:
+		routeConfigs := make([]*routev3.RouteConfiguration, 0, len(resp.GetResources()))
+		for _, resource := range resp.GetResources() {
+			routeConfig := &routev3.RouteConfiguration{}
+			if err := resource.UnmarshalTo(routeConfig); err != nil {
+				return err
+			}
+			routeConfigs = append(routeConfigs, routeConfig)
+		}
+		x.cacheLock.Lock()
+		for _, routeConfig := range routeConfigs {
+			x.routes[routeConfig.GetName()] = routeConfig
+		}
+		x.versions[resp.GetTypeUrl()] = resp.GetVersionInfo()
+		x.cacheLock.Unlock()
+	default:
+		return fmt.Errorf("unsupported resource type %s", resp.GetTypeUrl())
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xds
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// 默认的服务命名空间
+	defaultNamespace = "default"
+	// 默认的断线重连间隔
+	defaultReconnectInterval = 1 * time.Second
+)
+
+// Config xDS连接器插件级配置
+type Config struct {
+	// Namespace xDS集群映射到的服务命名空间，集群名即为服务名
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// NodeID 上报给控制面的节点ID，为空时根据主机名生成
+	NodeID string `yaml:"nodeId" json:"nodeId"`
+	// NodeCluster 上报给控制面的节点集群名
+	NodeCluster string `yaml:"nodeCluster" json:"nodeCluster"`
+	// NodeMetadata 上报给控制面的节点元数据
+	NodeMetadata map[string]string `yaml:"nodeMetadata" json:"nodeMetadata"`
+	// ReconnectInterval ADS流断开后的重连间隔
+	ReconnectInterval *time.Duration `yaml:"reconnectInterval" json:"reconnectInterval"`
+}
+
+// SetDefault 设置默认值
+func (c *Config) SetDefault() {
+	if len(c.Namespace) == 0 {
+		c.Namespace = defaultNamespace
+	}
+	if nil == c.ReconnectInterval {
+		c.ReconnectInterval = model.ToDurationPtr(defaultReconnectInterval)
+	}
+}
+
+// Verify 校验配置值
+func (c *Config) Verify() error {
+	var errs error
+	if len(c.Namespace) == 0 {
+		errs = multierror.Append(errs, fmt.Errorf("xds.namespace is empty"))
+	}
+	if nil == c.ReconnectInterval {
+		errs = multierror.Append(errs, fmt.Errorf("xds.reconnectInterval not configured"))
+	} else if *c.ReconnectInterval < config.DefaultMinTimingInterval {
+		errs = multierror.Append(errs, fmt.Errorf("xds.reconnectInterval %v is less than minimal timing interval %v",
+			*c.ReconnectInterval, config.DefaultMinTimingInterval))
+	}
+	return errs
+}
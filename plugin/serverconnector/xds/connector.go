@@ -0,0 +1,413 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package xds 基于xDS ADS协议的服务端连接器，从Envoy兼容的控制面获取集群、端点以及路由，
+// 转换为北极星的服务、实例以及路由规则，使同一套ConsumerAPI代码可以对接不同的控制面
+package xds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/network"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+	"github.com/polarismesh/polaris-go/pkg/version"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+)
+
+const (
+	// xDS协议名
+	protocolXDS = "xds"
+	// 上报给控制面的客户端名
+	userAgentName = "polaris-go"
+)
+
+// Connector 基于xDS ADS协议的服务端连接器，xDS控制面只提供服务发现，不支持实例注册
+type Connector struct {
+	*plugin.PluginBase
+	*common.RunContext
+	// 插件级配置
+	cfg            *Config
+	addresses      []string
+	connectTimeout time.Duration
+	// 与控制面通信的传输层凭证
+	creds credentials.TransportCredentials
+	// 访问控制面的鉴权凭证
+	tokenCreds credentials.PerRPCCredentials
+	node       *corev3.Node
+
+	cacheLock        sync.RWMutex
+	clusters         map[string]*clusterv3.Cluster
+	clustersReceived bool
+	// EDS资源，key为EDS资源名
+	endpoints map[string]*endpointv3.ClusterLoadAssignment
+	// RDS资源，key为路由配置名
+	routes map[string]*routev3.RouteConfiguration
+	// 各类型资源最近一次接受的版本
+	versions map[string]string
+
+	// 服务事件推送器
+	pusher     *connector.EventPusher
+	streamLock sync.Mutex
+	stream     *adsStream
+	waiter     sync.WaitGroup
+}
+
+// Type 插件类型
+func (x *Connector) Type() common.Type {
+	return common.TypeServerConnector
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (x *Connector) Name() string {
+	return protocolXDS
+}
+
+// Init 初始化插件
+func (x *Connector) Init(ctx *plugin.InitContext) error {
+	x.RunContext = common.NewRunContext()
+	x.PluginBase = plugin.NewPluginBase(ctx)
+	connectorCfg := ctx.Config.GetGlobal().GetServerConnector()
+	cfgValue := connectorCfg.GetPluginConfig(x.Name())
+	if cfgValue != nil {
+		x.cfg = cfgValue.(*Config)
+	}
+	if x.cfg == nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, nil,
+			"global.serverConnector.plugin.%s is not configured", x.Name())
+	}
+	x.addresses = connectorCfg.GetAddresses()
+	x.connectTimeout = connectorCfg.GetConnectTimeout()
+	x.creds = network.NewTransportCredentials(connectorCfg.GetTLS())
+	tokenProvider, err := network.NewTokenProvider(connectorCfg)
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err, "fail to create token provider")
+	}
+	x.tokenCreds = network.NewTokenCredentials(tokenProvider)
+	node, err := x.buildNode()
+	if err != nil {
+		return model.NewSDKError(model.ErrCodeAPIInvalidConfig, err,
+			"invalid global.serverConnector.plugin.%s.nodeMetadata", x.Name())
+	}
+	x.node = node
+	x.clusters = make(map[string]*clusterv3.Cluster)
+	x.endpoints = make(map[string]*endpointv3.ClusterLoadAssignment)
+	x.routes = make(map[string]*routev3.RouteConfiguration)
+	x.versions = make(map[string]string)
+	x.pusher = connector.NewEventPusher(x.buildResponse)
+	return nil
+}
+
+// buildNode 构建上报给控制面的节点信息
+func (x *Connector) buildNode() (*corev3.Node, error) {
+	node := &corev3.Node{
+		Id:                   x.cfg.NodeID,
+		Cluster:              x.cfg.NodeCluster,
+		UserAgentName:        userAgentName,
+		UserAgentVersionType: &corev3.Node_UserAgentVersion{UserAgentVersion: version.Version},
+	}
+	if len(node.Id) == 0 {
+		hostname, _ := os.Hostname()
+		node.Id = fmt.Sprintf("%s~%s~%d", userAgentName, hostname, os.Getpid())
+	}
+	if len(x.cfg.NodeMetadata) > 0 {
+		values := make(map[string]interface{}, len(x.cfg.NodeMetadata))
+		for key, value := range x.cfg.NodeMetadata {
+			values[key] = value
+		}
+		metadata, err := structpb.NewStruct(values)
+		if err != nil {
+			return nil, err
+		}
+		node.Metadata = metadata
+	}
+	return node, nil
+}
+
+// Start 启动ADS流以及事件推送协程
+func (x *Connector) Start() error {
+	x.pusher.Start()
+	x.waiter.Add(1)
+	go x.streamLoop()
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (x *Connector) Destroy() error {
+	_ = x.RunContext.Destroy()
+	x.waiter.Wait()
+	if x.pusher != nil {
+		x.pusher.Stop()
+	}
+	return nil
+}
+
+// IsEnable 只有服务端连接器协议配置为xds时才启用
+func (x *Connector) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetGlobal().GetServerConnector().GetProtocol() == protocolXDS
+}
+
+// RegisterServiceHandler 注册服务监听器，并按需订阅对应的EDS或者RDS资源
+// 异常场景：当sdk已经退出过程中，则返回error
+func (x *Connector) RegisterServiceHandler(svcEventHandler *serverconnector.ServiceEventHandler) error {
+	if x.IsDestroyed() {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+			"RegisterServiceHandler: serverConnector has been destroyed")
+	}
+	log.GetBaseLogger().Infof("%s, xds connector: register handler for %s",
+		x.GetSDKContextID(), *svcEventHandler.ServiceEventKey)
+	x.pusher.AddHandler(svcEventHandler)
+	x.resubscribe()
+	return nil
+}
+
+// DeRegisterServiceHandler 反注册事件监听器，已经订阅的资源不会取消订阅
+// 异常场景：当sdk已经退出过程中，则返回error
+func (x *Connector) DeRegisterServiceHandler(key *model.ServiceEventKey) error {
+	if x.IsDestroyed() {
+		return model.NewSDKError(model.ErrCodeInvalidStateError, nil,
+			"DeRegisterServiceHandler: serverConnector has been destroyed")
+	}
+	x.pusher.RemoveHandler(*key)
+	return nil
+}
+
+// RegisterInstance xDS控制面不支持注册实例
+func (x *Connector) RegisterInstance(req *model.InstanceRegisterRequest,
+	header map[string]string) (*model.InstanceRegisterResponse, error) {
+	return nil, x.unsupportedError("RegisterInstance")
+}
+
+// DeregisterInstance xDS控制面不支持反注册实例
+func (x *Connector) DeregisterInstance(req *model.InstanceDeRegisterRequest) error {
+	return x.unsupportedError("DeregisterInstance")
+}
+
+// Heartbeat xDS控制面不支持心跳上报
+func (x *Connector) Heartbeat(req *model.InstanceHeartbeatRequest) error {
+	return x.unsupportedError("Heartbeat")
+}
+
+// ReportClient 上报客户端信息，xDS控制面不提供地域信息
+func (x *Connector) ReportClient(req *model.ReportClientRequest) (*model.ReportClientResponse, error) {
+	return &model.ReportClientResponse{Version: req.Version}, nil
+}
+
+// UpdateServers 更新服务端地址，xDS连接器直接使用配置的控制面地址，无需处理
+func (x *Connector) UpdateServers(key *model.ServiceEventKey) error {
+	return nil
+}
+
+func (x *Connector) unsupportedError(operation string) error {
+	return model.NewSDKError(model.ErrCodePluginError, nil,
+		"%s is not supported by serverConnector %s, xDS control plane only provides discovery", operation, x.Name())
+}
+
+// streamLoop 维持ADS流，断开后按照配置的间隔轮询控制面地址重连
+func (x *Connector) streamLoop() {
+	defer x.waiter.Done()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-x.Done()
+		cancel()
+	}()
+	for i := 0; ; i++ {
+		address := x.addresses[i%len(x.addresses)]
+		if err := x.runStream(ctx, address); err != nil && !x.IsDestroyed() {
+			log.GetBaseLogger().Errorf("%s, xds connector: ads stream to %s is broken, err is %v",
+				x.GetSDKContextID(), address, err)
+		}
+		select {
+		case <-x.Done():
+			log.GetBaseLogger().Infof("%s, xds connector: context done, exit stream loop", x.GetSDKContextID())
+			return
+		case <-time.After(*x.cfg.ReconnectInterval):
+		}
+	}
+}
+
+// runStream 建立ADS流并处理推送，直到流断开
+func (x *Connector) runStream(ctx context.Context, address string) error {
+	conn, ads, err := x.openStream(ctx, address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	x.setStream(ads)
+	defer x.setStream(nil)
+	if err = x.updateSubscriptions(ads); err != nil {
+		return err
+	}
+	log.GetBaseLogger().Infof("%s, xds connector: ads stream to %s is established, node %s",
+		x.GetSDKContextID(), address, x.node.GetId())
+	for {
+		resp, err := ads.stream.Recv()
+		if err != nil {
+			return err
+		}
+		if err = x.handleResponse(ads, resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (x *Connector) setStream(ads *adsStream) {
+	x.streamLock.Lock()
+	x.stream = ads
+	x.streamLock.Unlock()
+}
+
+// resubscribe 监听器变化后更新当前ADS流的订阅，流断开时在重连后统一订阅
+func (x *Connector) resubscribe() {
+	x.streamLock.Lock()
+	ads := x.stream
+	x.streamLock.Unlock()
+	if ads == nil {
+		return
+	}
+	if err := x.updateSubscriptions(ads); err != nil {
+		log.GetBaseLogger().Errorf("%s, xds connector: fail to update subscriptions, err is %v",
+			x.GetSDKContextID(), err)
+	}
+}
+
+// edsResourceName 获取集群对应的EDS资源名，调用方需要持有读锁
+func (x *Connector) edsResourceName(clusterName string) string {
+	if cluster, ok := x.clusters[clusterName]; ok {
+		if serviceName := cluster.GetEdsClusterConfig().GetServiceName(); len(serviceName) > 0 {
+			return serviceName
+		}
+	}
+	return clusterName
+}
+
+// buildResponse 根据缓存的xDS资源构建服务发现应答，资源尚未推送时返回nil，集群不存在时返回资源不存在
+func (x *Connector) buildResponse(svcEventKey model.ServiceEventKey) *apiservice.DiscoverResponse {
+	resp := &apiservice.DiscoverResponse{
+		Service: &apiservice.Service{
+			Namespace: &wrappers.StringValue{Value: svcEventKey.Namespace},
+			Name:      &wrappers.StringValue{Value: svcEventKey.Service},
+		},
+	}
+	if svcEventKey.Namespace != x.cfg.Namespace && svcEventKey.Type != model.EventServices {
+		return notFoundResponse(resp, fmt.Sprintf("namespace %s is not served by xds", svcEventKey.Namespace))
+	}
+	x.cacheLock.RLock()
+	defer x.cacheLock.RUnlock()
+	if !x.clustersReceived {
+		return nil
+	}
+	var revision string
+	switch svcEventKey.Type {
+	case model.EventServices:
+		resp.Type = apiservice.DiscoverResponse_SERVICES
+		resp.Services = x.listServices(svcEventKey.Namespace)
+		messages := make([]proto.Message, 0, len(resp.Services))
+		for _, service := range resp.Services {
+			messages = append(messages, service)
+		}
+		revision = connector.MessagesRevision(messages...)
+	case model.EventInstances:
+		if _, ok := x.clusters[svcEventKey.Service]; !ok {
+			return notFoundResponse(resp, fmt.Sprintf("cluster %s not found", svcEventKey.Service))
+		}
+		assignment, ok := x.endpoints[x.edsResourceName(svcEventKey.Service)]
+		if !ok {
+			return nil
+		}
+		resp.Type = apiservice.DiscoverResponse_INSTANCE
+		resp.Instances = toInstances(svcEventKey.Namespace, svcEventKey.Service, assignment)
+		messages := make([]proto.Message, 0, len(resp.Instances)+1)
+		messages = append(messages, resp.Service)
+		for _, instance := range resp.Instances {
+			messages = append(messages, instance)
+		}
+		revision = connector.MessagesRevision(messages...)
+	case model.EventRouting:
+		if _, ok := x.clusters[svcEventKey.Service]; !ok {
+			return notFoundResponse(resp, fmt.Sprintf("cluster %s not found", svcEventKey.Service))
+		}
+		resp.Type = apiservice.DiscoverResponse_ROUTING
+		if routeConfig, ok := x.routes[svcEventKey.Service]; ok {
+			resp.Routing = toRouting(svcEventKey.Namespace, svcEventKey.Service, routeConfig)
+			revision = resp.Routing.GetRevision().GetValue()
+		}
+	default:
+		// 熔断、限流等规则在xDS中没有对应的资源，返回空规则
+		if _, ok := x.clusters[svcEventKey.Service]; !ok {
+			return notFoundResponse(resp, fmt.Sprintf("cluster %s not found", svcEventKey.Service))
+		}
+	}
+	if len(revision) == 0 {
+		revision = connector.MessagesRevision(resp.Service)
+	}
+	resp.Service.Revision = &wrappers.StringValue{Value: revision}
+	resp.Code = &wrappers.UInt32Value{Value: uint32(apimodel.Code_ExecuteSuccess)}
+	return resp
+}
+
+// listServices 将所有集群转换为服务列表，命名空间不匹配时返回空列表，调用方需要持有读锁
+func (x *Connector) listServices(namespace string) []*apiservice.Service {
+	if len(namespace) > 0 && namespace != x.cfg.Namespace {
+		return nil
+	}
+	names := make([]string, 0, len(x.clusters))
+	for name := range x.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	services := make([]*apiservice.Service, 0, len(names))
+	for _, name := range names {
+		services = append(services, &apiservice.Service{
+			Namespace: &wrappers.StringValue{Value: x.cfg.Namespace},
+			Name:      &wrappers.StringValue{Value: name},
+		})
+	}
+	return services
+}
+
+func notFoundResponse(resp *apiservice.DiscoverResponse, info string) *apiservice.DiscoverResponse {
+	resp.Code = &wrappers.UInt32Value{Value: uint32(apimodel.Code_NotFoundResource)}
+	resp.Info = &wrappers.StringValue{Value: info}
+	return resp
+}
+
+// init 注册插件信息
+func init() {
+	plugin.RegisterConfigurablePlugin(&Connector{}, &Config{})
+}
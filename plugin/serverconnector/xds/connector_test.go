@@ -0,0 +1,310 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xds
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	discoveryv3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/google/uuid"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log/logtest"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/serverconnector"
+)
+
+func init() {
+	logtest.DiscardLoggers()
+}
+
+// fakeADSServer 只推送一次固定资源的ADS服务端
+type fakeADSServer struct {
+	resources map[string][]proto.Message
+}
+
+func (s *fakeADSServer) StreamAggregatedResources(
+	stream discoveryv3.AggregatedDiscoveryService_StreamAggregatedResourcesServer) error {
+	nonce := 0
+	sentNames := make(map[string]string)
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		names := fmt.Sprint(req.GetResourceNames())
+		if sent, ok := sentNames[req.GetTypeUrl()]; ok && sent == names {
+			continue
+		}
+		sentNames[req.GetTypeUrl()] = names
+		requested := make(map[string]bool)
+		for _, name := range req.GetResourceNames() {
+			requested[name] = true
+		}
+		nonce++
+		resp := &discoveryv3.DiscoveryResponse{
+			VersionInfo: "1",
+			TypeUrl:     req.GetTypeUrl(),
+			Nonce:       fmt.Sprint(nonce),
+		}
+		for _, resource := range s.resources[req.GetTypeUrl()] {
+			if len(requested) > 0 && !requested[resourceName(resource)] {
+				continue
+			}
+			value, err := anypb.New(resource)
+			if err != nil {
+				return err
+			}
+			resp.Resources = append(resp.Resources, value)
+		}
+		if err = stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *fakeADSServer) DeltaAggregatedResources(
+	discoveryv3.AggregatedDiscoveryService_DeltaAggregatedResourcesServer) error {
+	return fmt.Errorf("delta xds is not supported")
+}
+
+func resourceName(resource proto.Message) string {
+	switch value := resource.(type) {
+	case *clusterv3.Cluster:
+		return value.GetName()
+	case *endpointv3.ClusterLoadAssignment:
+		return value.GetClusterName()
+	case *routev3.RouteConfiguration:
+		return value.GetName()
+	}
+	return ""
+}
+
+// eventRecorder 记录收到的服务事件
+type eventRecorder struct {
+	events chan *serverconnector.ServiceEvent
+}
+
+func (e *eventRecorder) OnServiceUpdate(event *serverconnector.ServiceEvent) {
+	e.events <- event
+}
+
+func (e *eventRecorder) GetRevision() string {
+	return ""
+}
+
+func (e *eventRecorder) GetBusiness() string {
+	return ""
+}
+
+func startFakeADSServer(t *testing.T, server *fakeADSServer) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	grpcServer := grpc.NewServer()
+	discoveryv3.RegisterAggregatedDiscoveryServiceServer(grpcServer, server)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+	return listener.Addr().String()
+}
+
+func newLbEndpoint(host string, port uint32, weight uint32, status corev3.HealthStatus) *endpointv3.LbEndpoint {
+	return &endpointv3.LbEndpoint{
+		HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+			Endpoint: &endpointv3.Endpoint{
+				Address: &corev3.Address{
+					Address: &corev3.Address_SocketAddress{
+						SocketAddress: &corev3.SocketAddress{
+							Address:       host,
+							PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: port},
+						},
+					},
+				},
+			},
+		},
+		HealthStatus:        status,
+		LoadBalancingWeight: &wrappers.UInt32Value{Value: weight},
+	}
+}
+
+// TestConnector_DiscoverInstances 通过本地的ADS服务端获取实例
+func TestConnector_DiscoverInstances(t *testing.T) {
+	address := startFakeADSServer(t, &fakeADSServer{resources: map[string][]proto.Message{
+		resourcev3.ClusterType: {
+			&clusterv3.Cluster{
+				Name:                 "echo",
+				ClusterDiscoveryType: &clusterv3.Cluster_Type{Type: clusterv3.Cluster_EDS},
+				EdsClusterConfig:     &clusterv3.Cluster_EdsClusterConfig{ServiceName: "echo-eds"},
+			},
+		},
+		resourcev3.EndpointType: {
+			&endpointv3.ClusterLoadAssignment{
+				ClusterName: "echo-eds",
+				Endpoints: []*endpointv3.LocalityLbEndpoints{
+					{
+						Locality: &corev3.Locality{Region: "south", Zone: "sz", SubZone: "sz-1"},
+						LbEndpoints: []*endpointv3.LbEndpoint{
+							newLbEndpoint("10.0.0.1", 8080, 80, corev3.HealthStatus_HEALTHY),
+							newLbEndpoint("10.0.0.2", 8080, 20, corev3.HealthStatus_UNHEALTHY),
+						},
+					},
+					{
+						Locality:    &corev3.Locality{Region: "north", Zone: "bj"},
+						Priority:    1,
+						LbEndpoints: []*endpointv3.LbEndpoint{newLbEndpoint("10.0.1.1", 8080, 10, corev3.HealthStatus_DRAINING)},
+					},
+				},
+			},
+		},
+	}})
+
+	cfg := config.NewDefaultConfiguration([]string{address})
+	cfg.GetGlobal().GetServerConnector().SetProtocol(protocolXDS)
+	x := &Connector{}
+	assert.True(t, x.IsEnable(cfg))
+	assert.Nil(t, x.Init(&plugin.InitContext{Config: cfg, SDKContextID: uuid.NewString()}))
+	assert.Nil(t, x.Start())
+	defer x.Destroy()
+
+	recorder := &eventRecorder{events: make(chan *serverconnector.ServiceEvent, 10)}
+	key := &model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: defaultNamespace, Service: "echo"},
+		Type:       model.EventInstances,
+	}
+	assert.Nil(t, x.RegisterServiceHandler(&serverconnector.ServiceEventHandler{
+		ServiceEventKey: key,
+		Handler:         recorder,
+	}))
+
+	var event *serverconnector.ServiceEvent
+	select {
+	case event = <-recorder.events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("wait instances event timeout")
+	}
+	resp := event.Value.(*apiservice.DiscoverResponse)
+	assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), resp.GetCode().GetValue())
+	assert.NotEmpty(t, resp.GetService().GetRevision().GetValue())
+	instances := make(map[string]*apiservice.Instance)
+	for _, instance := range resp.GetInstances() {
+		instances[instance.GetHost().GetValue()] = instance
+	}
+	assert.Equal(t, 3, len(instances))
+
+	first := instances["10.0.0.1"]
+	assert.Equal(t, uint32(80), first.GetWeight().GetValue())
+	assert.True(t, first.GetHealthy().GetValue())
+	assert.Equal(t, "south", first.GetLocation().GetRegion().GetValue())
+	assert.Equal(t, "sz", first.GetLocation().GetZone().GetValue())
+	assert.Equal(t, "sz-1", first.GetLocation().GetCampus().GetValue())
+
+	assert.False(t, instances["10.0.0.2"].GetHealthy().GetValue())
+
+	draining := instances["10.0.1.1"]
+	assert.True(t, draining.GetIsolate().GetValue())
+	assert.Equal(t, uint32(1), draining.GetPriority().GetValue())
+	assert.Equal(t, "north", draining.GetLocation().GetRegion().GetValue())
+
+	// 不存在的集群返回资源不存在
+	missing := x.buildResponse(model.ServiceEventKey{
+		ServiceKey: model.ServiceKey{Namespace: defaultNamespace, Service: "missing"},
+		Type:       model.EventInstances,
+	})
+	assert.Equal(t, uint32(apimodel.Code_NotFoundResource), missing.GetCode().GetValue())
+
+	_, err := x.RegisterInstance(&model.InstanceRegisterRequest{}, nil)
+	assert.NotNil(t, err)
+}
+
+// TestToRouting 请求头匹配转换为入流量路由规则
+func TestToRouting(t *testing.T) {
+	routeConfig := &routev3.RouteConfiguration{
+		Name: "echo",
+		VirtualHosts: []*routev3.VirtualHost{
+			{
+				Name:    "echo",
+				Domains: []string{"*"},
+				Routes: []*routev3.Route{
+					{
+						Name: "canary",
+						Match: &routev3.RouteMatch{
+							PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"},
+							Headers: []*routev3.HeaderMatcher{
+								{
+									Name:                 "x-env",
+									HeaderMatchSpecifier: &routev3.HeaderMatcher_ExactMatch{ExactMatch: "canary"},
+								},
+								{
+									Name:                 "x-user",
+									HeaderMatchSpecifier: &routev3.HeaderMatcher_PrefixMatch{PrefixMatch: "vip."},
+								},
+							},
+						},
+						Action: &routev3.Route_Route{Route: &routev3.RouteAction{
+							ClusterSpecifier: &routev3.RouteAction_Cluster{Cluster: "echo"},
+						}},
+					},
+					{
+						Name: "path",
+						Match: &routev3.RouteMatch{
+							PathSpecifier: &routev3.RouteMatch_Path{Path: "/echo"},
+						},
+						Action: &routev3.Route_Route{Route: &routev3.RouteAction{
+							ClusterSpecifier: &routev3.RouteAction_Cluster{Cluster: "echo"},
+						}},
+					},
+					{
+						Name:  "other",
+						Match: &routev3.RouteMatch{PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"}},
+						Action: &routev3.Route_Route{Route: &routev3.RouteAction{
+							ClusterSpecifier: &routev3.RouteAction_Cluster{Cluster: "other"},
+						}},
+					},
+				},
+			},
+		},
+	}
+	routing := toRouting(defaultNamespace, "echo", routeConfig)
+	assert.Equal(t, 1, len(routing.GetInbounds()))
+	assert.NotEmpty(t, routing.GetRevision().GetValue())
+	metadata := routing.GetInbounds()[0].GetSources()[0].GetMetadata()
+	assert.Equal(t, apimodel.MatchString_EXACT, metadata["x-env"].GetType())
+	assert.Equal(t, "canary", metadata["x-env"].GetValue().GetValue())
+	assert.Equal(t, apimodel.MatchString_REGEX, metadata["x-user"].GetType())
+	assert.Equal(t, `^vip\.`, metadata["x-user"].GetValue().GetValue())
+	destinations := routing.GetInbounds()[0].GetDestinations()
+	assert.Equal(t, 1, len(destinations))
+	assert.Equal(t, "echo", destinations[0].GetService().GetValue())
+}
@@ -0,0 +1,291 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xds
+
+import (
+	"fmt"
+	"regexp"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	connector "github.com/polarismesh/polaris-go/plugin/serverconnector/common"
+)
+
+const (
+	// 负载均衡子集元数据所在的filter名，与Envoy的子集负载均衡保持一致
+	lbMetadataFilter = "envoy.lb"
+	// Envoy未配置权重时的默认端点权重
+	defaultEndpointWeight = 1
+	// 路由目标的默认权重
+	defaultDestinationWeight = 100
+	// 匹配任意服务
+	matchAll = "*"
+)
+
+// toInstances 将EDS的集群端点转换为北极星实例，保留地域、权重、优先级以及健康状态
+func toInstances(namespace, service string, assignment *endpointv3.ClusterLoadAssignment) []*apiservice.Instance {
+	var instances []*apiservice.Instance
+	for _, localityEndpoints := range assignment.GetEndpoints() {
+		locality := localityEndpoints.GetLocality()
+		for _, lbEndpoint := range localityEndpoints.GetLbEndpoints() {
+			socketAddress := lbEndpoint.GetEndpoint().GetAddress().GetSocketAddress()
+			if socketAddress == nil || socketAddress.GetPortValue() == 0 {
+				continue
+			}
+			host, port := socketAddress.GetAddress(), socketAddress.GetPortValue()
+			weight := uint32(defaultEndpointWeight)
+			if lbEndpoint.GetLoadBalancingWeight() != nil {
+				weight = lbEndpoint.GetLoadBalancingWeight().GetValue()
+			}
+			healthy, isolate := toHealthStatus(lbEndpoint.GetHealthStatus())
+			instance := &apiservice.Instance{
+				Id:        &wrappers.StringValue{Value: connector.GenInstanceID(namespace, service, host, port)},
+				Namespace: &wrappers.StringValue{Value: namespace},
+				Service:   &wrappers.StringValue{Value: service},
+				Host:      &wrappers.StringValue{Value: host},
+				Port:      &wrappers.UInt32Value{Value: port},
+				Weight:    &wrappers.UInt32Value{Value: weight},
+				Priority:  &wrappers.UInt32Value{Value: localityEndpoints.GetPriority()},
+				Healthy:   &wrappers.BoolValue{Value: healthy},
+				Isolate:   &wrappers.BoolValue{Value: isolate},
+				Metadata:  toStringMap(lbEndpoint.GetMetadata().GetFilterMetadata()[lbMetadataFilter]),
+			}
+			if locality != nil {
+				instance.Location = &apimodel.Location{
+					Region: &wrappers.StringValue{Value: locality.GetRegion()},
+					Zone:   &wrappers.StringValue{Value: locality.GetZone()},
+					Campus: &wrappers.StringValue{Value: locality.GetSubZone()},
+				}
+			}
+			instance.Revision = &wrappers.StringValue{Value: connector.MessagesRevision(instance)}
+			instances = append(instances, instance)
+		}
+	}
+	return instances
+}
+
+// toHealthStatus 将端点健康状态转换为北极星实例的健康以及隔离状态
+// DRAINING的端点不再接收新请求，映射为隔离；UNHEALTHY和TIMEOUT映射为不健康
+func toHealthStatus(status corev3.HealthStatus) (healthy bool, isolate bool) {
+	switch status {
+	case corev3.HealthStatus_UNHEALTHY, corev3.HealthStatus_TIMEOUT:
+		return false, false
+	case corev3.HealthStatus_DRAINING:
+		return true, true
+	default:
+		return true, false
+	}
+}
+
+// toStringMap 将结构化元数据转换为字符串键值对
+func toStringMap(value *structpb.Struct) map[string]string {
+	if len(value.GetFields()) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(value.GetFields()))
+	for key, field := range value.GetFields() {
+		switch v := field.GetKind().(type) {
+		case *structpb.Value_StringValue:
+			values[key] = v.StringValue
+		case *structpb.Value_NumberValue:
+			values[key] = fmt.Sprint(v.NumberValue)
+		case *structpb.Value_BoolValue:
+			values[key] = fmt.Sprint(v.BoolValue)
+		}
+	}
+	return values
+}
+
+// toRouting 将RDS路由配置转换为北极星入流量路由规则
+// 请求头匹配映射为主调服务元数据匹配，调用方需要将请求头放入SourceService的元数据中；
+// 目标集群必须为当前服务，子集通过metadata_match中envoy.lb的元数据映射为实例元数据匹配。
+// 存在无法表达的匹配条件（如非根路径匹配、反向匹配）或目标集群的路由会被忽略
+func toRouting(namespace, service string, routeConfig *routev3.RouteConfiguration) *apitraffic.Routing {
+	routing := &apitraffic.Routing{
+		Namespace: &wrappers.StringValue{Value: namespace},
+		Service:   &wrappers.StringValue{Value: service},
+	}
+	for _, virtualHost := range routeConfig.GetVirtualHosts() {
+		for _, route := range virtualHost.GetRoutes() {
+			inbound, err := toInboundRoute(namespace, service, route)
+			if err != nil {
+				log.GetBaseLogger().Warnf("xds connector: route %s in %s/%s is ignored, %v",
+					route.GetName(), routeConfig.GetName(), virtualHost.GetName(), err)
+				continue
+			}
+			routing.Inbounds = append(routing.Inbounds, inbound)
+		}
+	}
+	routing.Revision = &wrappers.StringValue{Value: connector.MessagesRevision(routing)}
+	return routing
+}
+
+func toInboundRoute(namespace, service string, route *routev3.Route) (*apitraffic.Route, error) {
+	match := route.GetMatch()
+	switch match.GetPathSpecifier().(type) {
+	case nil:
+	case *routev3.RouteMatch_Prefix:
+		if prefix := match.GetPrefix(); len(prefix) > 0 && prefix != "/" {
+			return nil, fmt.Errorf("path prefix %s is not supported", prefix)
+		}
+	default:
+		return nil, fmt.Errorf("path match %v is not supported", match.GetPathSpecifier())
+	}
+	source := &apitraffic.Source{
+		Service:   &wrappers.StringValue{Value: matchAll},
+		Namespace: &wrappers.StringValue{Value: matchAll},
+	}
+	for _, header := range match.GetHeaders() {
+		matchString, err := toMatchString(header)
+		if err != nil {
+			return nil, err
+		}
+		if source.Metadata == nil {
+			source.Metadata = make(map[string]*apimodel.MatchString)
+		}
+		source.Metadata[header.GetName()] = matchString
+	}
+	action := route.GetRoute()
+	if action == nil {
+		return nil, fmt.Errorf("only route action is supported")
+	}
+	var destinations []*apitraffic.Destination
+	switch {
+	case len(action.GetCluster()) > 0:
+		if action.GetCluster() != service {
+			return nil, fmt.Errorf("target cluster %s is not service %s", action.GetCluster(), service)
+		}
+		destinations = append(destinations, toDestination(namespace, service, defaultDestinationWeight,
+			action.GetMetadataMatch(), nil))
+	case action.GetWeightedClusters() != nil:
+		for _, cluster := range action.GetWeightedClusters().GetClusters() {
+			if cluster.GetName() != service {
+				return nil, fmt.Errorf("target cluster %s is not service %s", cluster.GetName(), service)
+			}
+			destinations = append(destinations, toDestination(namespace, service, cluster.GetWeight().GetValue(),
+				action.GetMetadataMatch(), cluster.GetMetadataMatch()))
+		}
+	default:
+		return nil, fmt.Errorf("cluster specifier %v is not supported", action.GetClusterSpecifier())
+	}
+	if len(destinations) == 0 {
+		return nil, fmt.Errorf("no destination")
+	}
+	return &apitraffic.Route{Sources: []*apitraffic.Source{source}, Destinations: destinations}, nil
+}
+
+// toDestination 构建路由目标，集群级别的metadata_match覆盖路由级别的同名元数据
+func toDestination(namespace, service string, weight uint32, metadataMatches ...*corev3.Metadata) *apitraffic.Destination {
+	destination := &apitraffic.Destination{
+		Namespace: &wrappers.StringValue{Value: namespace},
+		Service:   &wrappers.StringValue{Value: service},
+		Weight:    &wrappers.UInt32Value{Value: weight},
+	}
+	for _, metadataMatch := range metadataMatches {
+		for key, value := range toStringMap(metadataMatch.GetFilterMetadata()[lbMetadataFilter]) {
+			if destination.Metadata == nil {
+				destination.Metadata = make(map[string]*apimodel.MatchString)
+			}
+			destination.Metadata[key] = &apimodel.MatchString{
+				Type:  apimodel.MatchString_EXACT,
+				Value: &wrappers.StringValue{Value: value},
+			}
+		}
+	}
+	return destination
+}
+
+// toMatchString 将请求头匹配转换为北极星的字符串匹配
+func toMatchString(header *routev3.HeaderMatcher) (*apimodel.MatchString, error) {
+	var (
+		matchType = apimodel.MatchString_EXACT
+		value     string
+	)
+	switch specifier := header.GetHeaderMatchSpecifier().(type) {
+	case *routev3.HeaderMatcher_ExactMatch:
+		value = specifier.ExactMatch
+	case *routev3.HeaderMatcher_SafeRegexMatch:
+		matchType, value = apimodel.MatchString_REGEX, specifier.SafeRegexMatch.GetRegex()
+	case *routev3.HeaderMatcher_PrefixMatch:
+		matchType, value = apimodel.MatchString_REGEX, "^"+regexp.QuoteMeta(specifier.PrefixMatch)
+	case *routev3.HeaderMatcher_SuffixMatch:
+		matchType, value = apimodel.MatchString_REGEX, regexp.QuoteMeta(specifier.SuffixMatch)+"$"
+	case *routev3.HeaderMatcher_ContainsMatch:
+		matchType, value = apimodel.MatchString_REGEX, regexp.QuoteMeta(specifier.ContainsMatch)
+	case *routev3.HeaderMatcher_PresentMatch:
+		if !specifier.PresentMatch {
+			return nil, fmt.Errorf("absent match of header %s is not supported", header.GetName())
+		}
+		matchType, value = apimodel.MatchString_REGEX, ".*"
+	case *routev3.HeaderMatcher_StringMatch:
+		var err error
+		if matchType, value, err = fromStringMatcher(specifier.StringMatch); err != nil {
+			return nil, fmt.Errorf("header %s: %v", header.GetName(), err)
+		}
+	default:
+		return nil, fmt.Errorf("match of header %s is not supported", header.GetName())
+	}
+	if header.GetInvertMatch() {
+		if matchType != apimodel.MatchString_EXACT {
+			return nil, fmt.Errorf("invert match of header %s is only supported for exact match", header.GetName())
+		}
+		matchType = apimodel.MatchString_NOT_EQUALS
+	}
+	return &apimodel.MatchString{
+		Type:      matchType,
+		Value:     &wrappers.StringValue{Value: value},
+		ValueType: apimodel.MatchString_TEXT,
+	}, nil
+}
+
+func fromStringMatcher(matcher *matcherv3.StringMatcher) (apimodel.MatchString_MatchStringType, string, error) {
+	var (
+		matchType = apimodel.MatchString_REGEX
+		value     string
+	)
+	switch pattern := matcher.GetMatchPattern().(type) {
+	case *matcherv3.StringMatcher_Exact:
+		if !matcher.GetIgnoreCase() {
+			return apimodel.MatchString_EXACT, pattern.Exact, nil
+		}
+		value = "^" + regexp.QuoteMeta(pattern.Exact) + "$"
+	case *matcherv3.StringMatcher_Prefix:
+		value = "^" + regexp.QuoteMeta(pattern.Prefix)
+	case *matcherv3.StringMatcher_Suffix:
+		value = regexp.QuoteMeta(pattern.Suffix) + "$"
+	case *matcherv3.StringMatcher_Contains:
+		value = regexp.QuoteMeta(pattern.Contains)
+	case *matcherv3.StringMatcher_SafeRegex:
+		value = pattern.SafeRegex.GetRegex()
+	default:
+		return matchType, "", fmt.Errorf("string match %v is not supported", matcher.GetMatchPattern())
+	}
+	if matcher.GetIgnoreCase() {
+		value = "(?i)" + value
+	}
+	return matchType, value, nil
+}
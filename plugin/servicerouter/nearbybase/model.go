@@ -30,6 +30,10 @@ type nearbyConfig struct {
 	StrictNearby                    bool   `yaml:"strictNearby" json:"strictNearby"`
 	EnableDegradeByUnhealthyPercent *bool  `yaml:"enableDegradeByUnhealthyPercent" json:"enableDegradeByUnhealthyPercent"`
 	UnhealthyPercentToDegrade       int    `yaml:"unhealthyPercentToDegrade" json:"unhealthyPercentToDegrade"`
+	// Strategy 就近匹配策略，filter为按照级别过滤，localityWeighted为按照各地域健康容量分配流量
+	Strategy string `yaml:"strategy" json:"strategy"`
+	// OverprovisioningFactor localityWeighted策略下的容量超配系数
+	OverprovisioningFactor *float64 `yaml:"overprovisioningFactor" json:"overprovisioningFactor"`
 }
 
 // SetMatchLevel 设置配置级别
@@ -95,6 +99,32 @@ func (n *nearbyConfig) SetUnhealthyPercentToDegrade(u int) {
 	n.UnhealthyPercentToDegrade = u
 }
 
+// GetStrategy 获取就近匹配策略，未设置时为filter
+func (n *nearbyConfig) GetStrategy() string {
+	if n.Strategy == "" {
+		return config.NearbyStrategyFilter
+	}
+	return n.Strategy
+}
+
+// SetStrategy 设置就近匹配策略
+func (n *nearbyConfig) SetStrategy(strategy string) {
+	n.Strategy = strategy
+}
+
+// GetOverprovisioningFactor 获取容量超配系数
+func (n *nearbyConfig) GetOverprovisioningFactor() float64 {
+	if nil == n.OverprovisioningFactor {
+		return config.DefaultOverprovisioningFactor
+	}
+	return *n.OverprovisioningFactor
+}
+
+// SetOverprovisioningFactor 设置容量超配系数
+func (n *nearbyConfig) SetOverprovisioningFactor(factor float64) {
+	n.OverprovisioningFactor = &factor
+}
+
 // SetDefault 设置默认值
 func (n *nearbyConfig) SetDefault() {
 	if n.MatchLevel == "" {
@@ -107,6 +137,7 @@ func (n *nearbyConfig) SetDefault() {
 		defaultEnable := true
 		n.EnableDegradeByUnhealthyPercent = &defaultEnable
 	}
+	// strategy和overprovisioningFactor不设置默认值，服务级配置未设置时继承全局配置
 }
 
 // 就近级别转换
@@ -136,5 +167,14 @@ func (n *nearbyConfig) Verify() error {
 		return fmt.Errorf("unhealthyPercentToDegrade must be in the range of (0,100],"+
 			" but provided value is %v", n.UnhealthyPercentToDegrade)
 	}
+	if n.Strategy != "" && config.NearbyStrategyFilter != n.Strategy &&
+		config.NearbyStrategyLocalityWeighted != n.Strategy {
+		return fmt.Errorf("invalid strategy for nearby router: %s, it must be one of %s and %s",
+			n.Strategy, config.NearbyStrategyFilter, config.NearbyStrategyLocalityWeighted)
+	}
+	if nil != n.OverprovisioningFactor && *n.OverprovisioningFactor < 1 {
+		return fmt.Errorf("overprovisioningFactor must not be less than 1, but provided value is %v",
+			*n.OverprovisioningFactor)
+	}
 	return nil
 }
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
 	"github.com/polarismesh/polaris-go/pkg/config"
 	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
@@ -43,6 +44,7 @@ type NearbyBasedInstancesFilter struct {
 	maxMatchLevel         int
 	unHealthyRatio        float64
 	locationReadyTimeout  time.Duration
+	scalableRand          *rand.ScalableRand
 }
 
 // Type 插件类型
@@ -71,6 +73,7 @@ func (g *NearbyBasedInstancesFilter) Init(ctx *plugin.InitContext) error {
 	g.matchLevel = nearbyLevels[g.cfg.MatchLevel]
 	g.maxMatchLevel = nearbyLevels[g.cfg.MaxMatchLevel]
 	g.unHealthyRatio = float64(g.cfg.UnhealthyPercentToDegrade) / 100
+	g.scalableRand = rand.NewScalableRand()
 	g.locationReadyTimeout = (ctx.Config.GetGlobal().GetAPI().GetRetryInterval() +
 		ctx.Config.GetGlobal().GetServerConnector().GetConnectTimeout()) *
 		time.Duration(ctx.Config.GetGlobal().GetAPI().GetMaxRetryTimes()+1)
@@ -216,9 +219,34 @@ func (g *NearbyBasedInstancesFilter) GetLevel(clusters model.ServiceClusters) (i
 	return matchLevel, maxMatchLevel
 }
 
+// getStrategy 获取服务生效的就近匹配策略以及容量超配系数，服务级配置优先，未设置时继承全局配置
+func (g *NearbyBasedInstancesFilter) getStrategy(clusters model.ServiceClusters) (string, float64) {
+	strategy := g.cfg.GetStrategy()
+	factor := g.cfg.GetOverprovisioningFactor()
+	svcKey := clusters.GetServiceKey()
+	serviceSp := g.wholeCfg.GetConsumer().GetServiceSpecific(svcKey.Namespace, svcKey.Service)
+	if serviceSp == nil {
+		return strategy, factor
+	}
+	nearbySp, ok := serviceSp.GetServiceRouter().GetNearbyConfig().(*nearbyConfig)
+	if !ok || nearbySp == nil {
+		return strategy, factor
+	}
+	if nearbySp.Strategy != "" {
+		strategy = nearbySp.Strategy
+	}
+	if nearbySp.OverprovisioningFactor != nil {
+		factor = *nearbySp.OverprovisioningFactor
+	}
+	return strategy, factor
+}
+
 // GetFilteredInstances 进行服务实例过滤，并返回过滤后的实例列表
 func (g *NearbyBasedInstancesFilter) GetFilteredInstances(rInfo *servicerouter.RouteInfo,
 	clusters model.ServiceClusters, withinCluster *model.Cluster) (*servicerouter.RouteResult, error) {
+	if strategy, factor := g.getStrategy(clusters); strategy == config.NearbyStrategyLocalityWeighted {
+		return g.getLocalityWeightedInstances(rInfo, clusters, withinCluster, factor)
+	}
 	// 记录各个匹配级别的实例数量
 	var allLevelsCount [4]nearbyLevelInstanceCount
 	var outCluster *model.Cluster
@@ -0,0 +1,163 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nearbybase
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// 溢出概率计算的随机精度
+const spillRandRange = 10000
+
+// 一个匹配级别的cluster的健康容量和全部容量，容量为实例权重之和
+type localityCapacity struct {
+	healthWeight int
+	allWeight    int
+}
+
+// localityLevels 从priorityLevelAll到priorityLevelCampus四个级别的cluster以及容量
+type localityLevels struct {
+	capacities [4]localityCapacity
+	clusters   [4]*model.Cluster
+}
+
+// localityWeightedCache 缓存在服务集群中的各级别容量，服务实例变化后服务集群重建，缓存随之失效
+type localityWeightedCache struct {
+	location model.Location
+	// 按照withinCluster的ClusterKey缓存，值为*localityLevels
+	levels sync.Map
+}
+
+// 获取一个cluster的健康容量和全部容量
+func getClusterCapacity(c *model.Cluster, capacity *localityCapacity) {
+	capacity.allWeight = c.GetClusterValue().GetInstancesSetWhenSkipRouteFilter(true, true).TotalWeight()
+	capacity.healthWeight = c.GetClusterValue().GetInstancesSet(false, false).TotalWeight()
+}
+
+// buildLocalityLevels 构建从priorityLevelAll到priorityLevelCampus四个级别的cluster并计算容量
+func buildLocalityLevels(clusters model.ServiceClusters, withinCluster *model.Cluster,
+	location *model.Location) *localityLevels {
+	levels := &localityLevels{}
+	for level := priorityLevelAll; level <= priorityLevelCampus; level++ {
+		cluster := model.NewCluster(clusters, withinCluster)
+		if level >= priorityLevelRegion {
+			cluster.Location.Region = location.Region
+		}
+		if level >= priorityLevelZone {
+			cluster.Location.Zone = location.Zone
+		}
+		if level >= priorityLevelCampus {
+			cluster.Location.Campus = location.Campus
+		}
+		// 常驻缓存的cluster，无需进行回收
+		cluster.SetReuse(false)
+		getClusterCapacity(cluster, &levels.capacities[level])
+		levels.clusters[level] = cluster
+	}
+	return levels
+}
+
+// getLocalityLevels 获取缓存的各级别cluster以及容量，不存在或者地域信息变化时重新构建
+func (g *NearbyBasedInstancesFilter) getLocalityLevels(clusters model.ServiceClusters, withinCluster *model.Cluster,
+	location *model.Location) *localityLevels {
+	var cache *localityWeightedCache
+	if value := clusters.GetExtendedCacheValue(int(g.ID())); nil != value {
+		cache = value.(*localityWeightedCache)
+	}
+	if nil == cache || cache.location != *location {
+		cache = &localityWeightedCache{location: *location}
+		clusters.SetExtendedCacheValue(int(g.ID()), cache)
+	}
+	if value, ok := cache.levels.Load(withinCluster.ClusterKey); ok {
+		return value.(*localityLevels)
+	}
+	value, _ := cache.levels.LoadOrStore(withinCluster.ClusterKey,
+		buildLocalityLevels(clusters, withinCluster, location))
+	return value.(*localityLevels)
+}
+
+// calcSpillPercent 计算流量从当前级别溢出到上一级别的比例
+// 当前级别保留的流量比例为健康容量占比乘以超配系数（不超过1），剩余部分按照邻近地域的健康容量溢出；
+// 由于上一级别的集群同时包含当前级别的实例，溢出比例需要扣除这部分实例分到的流量
+func calcSpillPercent(current localityCapacity, parent localityCapacity, overprovisioningFactor float64) float64 {
+	if current.allWeight == 0 || current.healthWeight == 0 {
+		return 1
+	}
+	keep := float64(current.healthWeight) / float64(current.allWeight) * overprovisioningFactor
+	if keep >= 1 || parent.healthWeight <= current.healthWeight {
+		// 本地容量充足，或者邻近地域没有健康容量可以分担
+		return 0
+	}
+	localShare := float64(current.healthWeight) / float64(parent.healthWeight)
+	spill := (1 - keep) / (1 - localShare)
+	if spill > 1 {
+		return 1
+	}
+	return spill
+}
+
+// selectWeightedLevel 从matchLevel开始逐级计算溢出比例，随机决定本次请求使用的就近级别
+func (g *NearbyBasedInstancesFilter) selectWeightedLevel(allLevelsCapacity *[4]localityCapacity,
+	matchLevel int, maxMatchLevel int, overprovisioningFactor float64) int {
+	level := matchLevel
+	for level > maxMatchLevel {
+		spill := calcSpillPercent(allLevelsCapacity[level], allLevelsCapacity[level-1], overprovisioningFactor)
+		if spill <= 0 || float64(g.scalableRand.Intn(spillRandRange)) >= spill*spillRandRange {
+			break
+		}
+		level--
+	}
+	return level
+}
+
+// getLocalityWeightedInstances localityWeighted策略下的实例过滤，各级别的容量按照服务集群缓存，
+// 每次请求只根据溢出比例随机选择就近级别
+func (g *NearbyBasedInstancesFilter) getLocalityWeightedInstances(rInfo *servicerouter.RouteInfo,
+	clusters model.ServiceClusters, withinCluster *model.Cluster,
+	overprovisioningFactor float64) (*servicerouter.RouteResult, error) {
+	location := g.valueCtx.GetCurrentLocation().GetLocation()
+	matchLevel, maxMatchLevel := g.GetLevel(clusters)
+	levels := g.getLocalityLevels(clusters, withinCluster, location)
+	finalLevel := g.selectWeightedLevel(&levels.capacities, matchLevel, maxMatchLevel, overprovisioningFactor)
+	// 根据是否开启recoverall，决定是否要进行兜底过滤
+	rInfo.SetIgnoreFilterOnlyOnEndChain(!g.recoverAll)
+	if levels.capacities[finalLevel].allWeight == 0 {
+		outCluster := model.NewCluster(clusters, levels.clusters[finalLevel])
+		outCluster.MissLocationInstances = true
+		outCluster.LocationMatchInfo = allLevelsCapacityToString(&levels.capacities)
+		return nil, g.misMatchError(location, outCluster)
+	}
+	result := servicerouter.PoolGetRouteResult(g.valueCtx)
+	result.OutputCluster = levels.clusters[finalLevel]
+	result.Status = checkNearbyStatus(matchLevel, finalLevel)
+	return result, nil
+}
+
+// 将allLevelsCapacity转化为字符串
+func allLevelsCapacityToString(allLevelsCapacity *[4]localityCapacity) string {
+	return fmt.Sprintf("location matched capacity：[ all Level:{health:%d, all:%d}, region Level:{health:%d, all:%d}, "+
+		"zone Level:{health:%d, all:%d}, campus Level:{health:%d, all:%d} ]",
+		allLevelsCapacity[priorityLevelAll].healthWeight, allLevelsCapacity[priorityLevelAll].allWeight,
+		allLevelsCapacity[priorityLevelRegion].healthWeight, allLevelsCapacity[priorityLevelRegion].allWeight,
+		allLevelsCapacity[priorityLevelZone].healthWeight, allLevelsCapacity[priorityLevelZone].allWeight,
+		allLevelsCapacity[priorityLevelCampus].healthWeight, allLevelsCapacity[priorityLevelCampus].allWeight)
+}
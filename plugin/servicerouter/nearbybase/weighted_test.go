@@ -0,0 +1,133 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package nearbybase
+
+import (
+	"fmt"
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+)
+
+// TestCalcSpillPercent 测试流量溢出到邻近地域的比例计算
+func TestCalcSpillPercent(t *testing.T) {
+	// 本地健康容量乘以超配系数超过100%，流量全部留在本地
+	assert.Equal(t, float64(0), calcSpillPercent(localityCapacity{healthWeight: 80, allWeight: 100},
+		localityCapacity{healthWeight: 300, allWeight: 300}, 1.4))
+	// 本地没有健康容量，流量全部溢出
+	assert.Equal(t, float64(1), calcSpillPercent(localityCapacity{healthWeight: 0, allWeight: 100},
+		localityCapacity{healthWeight: 200, allWeight: 300}, 1.4))
+	// 本地保留50%的流量，上一级别中本地实例占1/3，溢出比例为(1-0.5)/(1-1/3)=0.75，本地实际承接0.5
+	spill := calcSpillPercent(localityCapacity{healthWeight: 50, allWeight: 100},
+		localityCapacity{healthWeight: 150, allWeight: 200}, 1)
+	assert.InDelta(t, 0.75, spill, 1e-9)
+	assert.InDelta(t, 0.5, (1-spill)+spill*50/150, 1e-9)
+	// 邻近地域没有健康容量时无法分担
+	assert.Equal(t, float64(0), calcSpillPercent(localityCapacity{healthWeight: 10, allWeight: 100},
+		localityCapacity{healthWeight: 10, allWeight: 100}, 1.4))
+}
+
+// buildLocalityClusters 构建服务集群，zone1的两个实例一个不健康，zone2的两个实例都健康
+func buildLocalityClusters(svcKey model.ServiceKey) model.ServiceClusters {
+	var instances []model.Instance
+	for i, zone := range []string{"zone1", "zone1", "zone2", "zone2"} {
+		instances = append(instances, pb.NewInstanceInProto(&service_manage.Instance{
+			Id:        wrapperspb.String(fmt.Sprintf("instance-%d", i)),
+			Service:   wrapperspb.String(svcKey.Service),
+			Namespace: wrapperspb.String(svcKey.Namespace),
+			Host:      wrapperspb.String(fmt.Sprintf("127.0.0.%d", i+1)),
+			Port:      wrapperspb.UInt32(8080),
+			Weight:    wrapperspb.UInt32(100),
+			Healthy:   wrapperspb.Bool(i != 0),
+			Location: &apimodel.Location{
+				Region: wrapperspb.String("region1"),
+				Zone:   wrapperspb.String(zone),
+				Campus: wrapperspb.String("campus1"),
+			},
+		}, &svcKey, local.NewInstanceLocalValue()))
+	}
+	return model.NewServiceClusters(model.NewDefaultServiceInstances(model.ServiceInfo{
+		Service:   svcKey.Service,
+		Namespace: svcKey.Namespace,
+	}, instances))
+}
+
+// TestGetLocalityWeightedInstances 测试服务级配置开启localityWeighted策略后按照容量比例溢出到邻近地域
+func TestGetLocalityWeightedInstances(t *testing.T) {
+	weightedSvc := model.ServiceKey{Namespace: "default", Service: "weighted"}
+	filterSvc := model.ServiceKey{Namespace: "default", Service: "filter"}
+	cfg := config.NewDefaultConfigurationWithDomain()
+	specific := &config.ServiceSpecific{Namespace: weightedSvc.Namespace, Service: weightedSvc.Service}
+	specific.Init()
+	specific.ServiceRouter.GetNearbyConfig().SetStrategy(config.NearbyStrategyLocalityWeighted)
+	consumerCfg := cfg.GetConsumer().(*config.ConsumerConfigImpl)
+	consumerCfg.ServicesSpecific = append(consumerCfg.ServicesSpecific, specific)
+
+	valueCtx := model.NewValueContext()
+	valueCtx.SetCurrentLocation(&model.Location{Region: "region1", Zone: "zone1", Campus: "campus1"}, nil)
+	nearbyCfg := cfg.GetConsumer().GetServiceRouter().GetNearbyConfig().(*nearbyConfig)
+	filter := &NearbyBasedInstancesFilter{
+		PluginBase:    plugin.NewPluginBase(&plugin.InitContext{PluginIndex: 1}),
+		wholeCfg:      cfg,
+		valueCtx:      valueCtx,
+		cfg:           nearbyCfg,
+		matchLevel:    nearbyLevels[nearbyCfg.MatchLevel],
+		maxMatchLevel: nearbyLevels[nearbyCfg.MaxMatchLevel],
+		scalableRand:  rand.NewScalableRand(),
+	}
+	// 全局为filter策略，zone1存在健康实例，流量全部留在本地
+	clusters := buildLocalityClusters(filterSvc)
+	for i := 0; i < 100; i++ {
+		result, err := filter.GetFilteredInstances(&servicerouter.RouteInfo{}, clusters, model.NewCluster(clusters, nil))
+		assert.Nil(t, err)
+		assert.Equal(t, servicerouter.Normal, result.Status)
+	}
+
+	// zone1保留 0.5*1.4=70% 的流量，溢出比例为 (1-0.7)/(1-1/3)=45%
+	clusters = buildLocalityClusters(weightedSvc)
+	outClusters := make(map[*model.Cluster]struct{})
+	const total = 10000
+	var degraded int
+	for i := 0; i < total; i++ {
+		result, err := filter.GetFilteredInstances(&servicerouter.RouteInfo{}, clusters, model.NewCluster(clusters, nil))
+		assert.Nil(t, err)
+		outClusters[result.OutputCluster] = struct{}{}
+		if result.Status == servicerouter.DegradeToRegion {
+			degraded++
+			assert.Equal(t, 3, result.OutputCluster.GetClusterValue().GetInstancesSet(false, false).Count())
+		} else {
+			assert.Equal(t, servicerouter.Normal, result.Status)
+			assert.Equal(t, 1, result.OutputCluster.GetClusterValue().GetInstancesSet(false, false).Count())
+		}
+	}
+	assert.InDelta(t, 0.45, float64(degraded)/total, 0.03)
+	// 各级别的cluster缓存在服务集群中，不会每次请求重新构建
+	assert.Equal(t, 2, len(outClusters))
+	assert.NotNil(t, clusters.GetExtendedCacheValue(int(filter.ID())))
+}
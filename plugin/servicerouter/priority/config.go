@@ -0,0 +1,48 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package priority
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultHealthyPercentThreshold 默认的健康实例比例阈值，高优先级分组的健康比例不低于该值时承接全部流量
+	DefaultHealthyPercentThreshold = 70
+)
+
+// Config 优先级路由的配置
+type Config struct {
+	// HealthyPercentThreshold 健康实例比例阈值（百分比），低于该值时按比例溢出到低优先级分组
+	HealthyPercentThreshold int `yaml:"healthyPercentThreshold" json:"healthyPercentThreshold"`
+}
+
+// Verify 检验优先级路由配置
+func (c *Config) Verify() error {
+	if c.HealthyPercentThreshold <= 0 || c.HealthyPercentThreshold > 100 {
+		return fmt.Errorf("priorityRouter.healthyPercentThreshold must be in the range of (0,100],"+
+			" but provided value is %v", c.HealthyPercentThreshold)
+	}
+	return nil
+}
+
+// SetDefault 设置优先级路由配置默认值
+func (c *Config) SetDefault() {
+	if c.HealthyPercentThreshold == 0 {
+		c.HealthyPercentThreshold = DefaultHealthyPercentThreshold
+	}
+}
@@ -0,0 +1,196 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package priority
+
+import (
+	"sort"
+
+	"github.com/polarismesh/polaris-go/pkg/algorithm/rand"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	"github.com/polarismesh/polaris-go/pkg/plugin/servicerouter"
+	"github.com/polarismesh/polaris-go/plugin/servicerouter/filteronly"
+)
+
+// 选择优先级分组的随机精度
+const tierRandRange = 10000
+
+// InstancesFilter 基于实例优先级的服务实例过滤器，实例优先级数值越小优先级越高
+// 高优先级分组的健康实例比例不低于阈值时承接全部流量，低于阈值时按照健康比例将流量溢出到低优先级分组；
+// 由于输出的集群只包含选中分组的实例，该路由需要配置在路由链的最后
+type InstancesFilter struct {
+	*plugin.PluginBase
+	valueCtx              model.ValueContext
+	cfg                   *Config
+	threshold             float64
+	percentOfMinInstances float64
+	recoverAll            bool
+	scalableRand          *rand.ScalableRand
+}
+
+// Type 插件类型
+func (g *InstancesFilter) Type() common.Type {
+	return common.TypeServiceRouter
+}
+
+// Name 插件名，一个类型下插件名唯一
+func (g *InstancesFilter) Name() string {
+	return config.DefaultServiceRouterPriority
+}
+
+// Init 初始化插件
+func (g *InstancesFilter) Init(ctx *plugin.InitContext) error {
+	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.valueCtx = ctx.ValueCtx
+	g.cfg = ctx.Config.GetConsumer().GetServiceRouter().GetPluginConfig(g.Name()).(*Config)
+	g.threshold = float64(g.cfg.HealthyPercentThreshold) / 100
+	g.percentOfMinInstances = ctx.Config.GetConsumer().GetServiceRouter().GetPercentOfMinInstances()
+	g.recoverAll = ctx.Config.GetConsumer().GetServiceRouter().IsEnableRecoverAll()
+	g.scalableRand = rand.NewScalableRand()
+	return nil
+}
+
+// Destroy 销毁插件，可用于释放资源
+func (g *InstancesFilter) Destroy() error {
+	return nil
+}
+
+// Enable 是否需要启动优先级路由
+func (g *InstancesFilter) Enable(routeInfo *servicerouter.RouteInfo, clusters model.ServiceClusters) bool {
+	return true
+}
+
+// priorityTier 同一优先级的实例分组
+type priorityTier struct {
+	priority uint32
+	clusters model.ServiceClusters
+}
+
+// getPriorityTiers 获取按照优先级从高到低排列的实例分组，分组结果缓存在服务集群中，服务实例变化后重新计算
+func (g *InstancesFilter) getPriorityTiers(clusters model.ServiceClusters) []*priorityTier {
+	if value := clusters.GetExtendedCacheValue(int(g.ID())); nil != value {
+		return value.([]*priorityTier)
+	}
+	svcInstances := clusters.GetServiceInstances()
+	tierInstances := make(map[uint32][]model.Instance)
+	for _, instance := range svcInstances.GetInstances() {
+		tierInstances[instance.GetPriority()] = append(tierInstances[instance.GetPriority()], instance)
+	}
+	tiers := make([]*priorityTier, 0, len(tierInstances))
+	if len(tierInstances) > 1 {
+		for priority, instances := range tierInstances {
+			tierSvcInstances := model.NewDefaultServiceInstancesWithRegistryValue(model.ServiceInfo{
+				Service:   svcInstances.GetService(),
+				Namespace: svcInstances.GetNamespace(),
+				Metadata:  svcInstances.GetMetadata(),
+			}, svcInstances, instances)
+			tiers = append(tiers, &priorityTier{priority: priority, clusters: tierSvcInstances.GetServiceClusters()})
+		}
+		sort.Slice(tiers, func(i, j int) bool {
+			return tiers[i].priority < tiers[j].priority
+		})
+	}
+	clusters.SetExtendedCacheValue(int(g.ID()), tiers)
+	return tiers
+}
+
+// calcTierLoads 根据各分组的健康比例计算流量分配比例
+// 分组健康比例不低于阈值时承接剩余的全部流量，否则承接剩余流量乘以健康比例与阈值之比，其余继续溢出到下一分组；
+// 所有分组都无法承接全部流量时，按照已分配的比例归一化；没有任何健康实例时返回nil
+func calcTierLoads(healthyRatios []float64, threshold float64) []float64 {
+	loads := make([]float64, len(healthyRatios))
+	remaining := 1.0
+	for i, ratio := range healthyRatios {
+		if ratio >= threshold {
+			loads[i] = remaining
+			remaining = 0
+			break
+		}
+		loads[i] = remaining * ratio / threshold
+		remaining -= loads[i]
+	}
+	assigned := 1 - remaining
+	if assigned <= 0 {
+		return nil
+	}
+	if remaining > 0 {
+		for i := range loads {
+			loads[i] /= assigned
+		}
+	}
+	return loads
+}
+
+// selectTier 按照流量分配比例随机选择分组
+func (g *InstancesFilter) selectTier(loads []float64) int {
+	value := float64(g.scalableRand.Intn(tierRandRange)) / tierRandRange
+	lastNonZero := 0
+	for i, load := range loads {
+		if load <= 0 {
+			continue
+		}
+		lastNonZero = i
+		if value < load {
+			return i
+		}
+		value -= load
+	}
+	return lastNonZero
+}
+
+// GetFilteredInstances 进行服务实例过滤，并返回选中优先级分组的实例集群
+func (g *InstancesFilter) GetFilteredInstances(routeInfo *servicerouter.RouteInfo,
+	clusters model.ServiceClusters, withinCluster *model.Cluster) (*servicerouter.RouteResult, error) {
+	tiers := g.getPriorityTiers(clusters)
+	if len(tiers) == 0 {
+		// 只有一个优先级，无需处理
+		result := servicerouter.PoolGetRouteResult(g.valueCtx)
+		result.OutputCluster = model.NewCluster(clusters, withinCluster)
+		return result, nil
+	}
+	healthyRatios := make([]float64, len(tiers))
+	firstNotEmpty := -1
+	for i, tier := range tiers {
+		tierCluster := model.NewCluster(tier.clusters, withinCluster)
+		clsValue := tierCluster.GetClusterValue()
+		allWeight := clsValue.GetInstancesSetWhenSkipRouteFilter(true, true).TotalWeight()
+		if allWeight > 0 {
+			healthyRatios[i] = float64(clsValue.GetInstancesSet(false, false).TotalWeight()) / float64(allWeight)
+			if firstNotEmpty < 0 {
+				firstNotEmpty = i
+			}
+		}
+		tierCluster.PoolPut()
+	}
+	selected := firstNotEmpty
+	if loads := calcTierLoads(healthyRatios, g.threshold); nil != loads {
+		selected = g.selectTier(loads)
+	}
+	if selected < 0 {
+		selected = 0
+	}
+	// 在选中的分组内执行全死全活逻辑，输出的集群基于分组的实例，因此不再执行路由链末尾的全死全活
+	return filteronly.GetFilteredInstances(g.valueCtx, routeInfo, tiers[selected].clusters,
+		g.percentOfMinInstances, withinCluster, g.recoverAll)
+}
+
+// init 注册插件
+func init() {
+	plugin.RegisterConfigurablePlugin(&InstancesFilter{}, &Config{})
+}
@@ -0,0 +1,39 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+package priority
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCalcTierLoads 测试各优先级分组的流量分配比例
+func TestCalcTierLoads(t *testing.T) {
+	// 最高优先级分组健康比例达到阈值，承接全部流量
+	assert.Equal(t, []float64{1, 0, 0}, calcTierLoads([]float64{0.8, 1, 1}, 0.7))
+	// 健康比例低于阈值，按比例溢出到下一分组
+	loads := calcTierLoads([]float64{0.35, 1}, 0.7)
+	assert.InDelta(t, 0.5, loads[0], 1e-9)
+	assert.InDelta(t, 0.5, loads[1], 1e-9)
+	// 所有分组都无法承接全部流量时归一化
+	loads = calcTierLoads([]float64{0.35, 0.35}, 0.7)
+	assert.InDelta(t, 2.0/3, loads[0], 1e-9)
+	assert.InDelta(t, 1.0/3, loads[1], 1e-9)
+	// 没有健康实例
+	assert.Nil(t, calcTierLoads([]float64{0, 0}, 0.7))
+}
@@ -18,14 +18,31 @@
 package ratedelay
 
 import (
+	"math"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log"
 	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
 	"github.com/polarismesh/polaris-go/pkg/plugin"
 	"github.com/polarismesh/polaris-go/pkg/plugin/common"
 )
 
+const (
+	// 周期内最少的调用次数，低于该值的周期不参与统计
+	minRequestsPerPeriod = 5
+	// 实时触发权重调整的错误率阈值
+	realTimeFailRateThreshold = 0.5
+	// 单次调整权重的最大步长，为静态权重的百分比，用于平滑地摘除和恢复流量
+	maxAdjustStepPercent = 20
+	// 单次调整权重的最小步长，为静态权重的百分比，避免权重细微抖动导致频繁重建缓存索引
+	minAdjustStepPercent = 5
+)
+
 // Adjuster 根据错误率和时延来进行动态权重调整
 type Adjuster struct {
 	*plugin.PluginBase
+	cfg config.WeightAdjusterConfig
 }
 
 // Type 插件类型
@@ -35,12 +52,16 @@ func (g *Adjuster) Type() common.Type {
 
 // Name 插件名，一个类型下插件名唯一
 func (g *Adjuster) Name() string {
-	return "rateDelayAdjuster"
+	return config.DefaultWeightAdjuster
 }
 
 // Init 初始化插件
 func (g *Adjuster) Init(ctx *plugin.InitContext) error {
 	g.PluginBase = plugin.NewPluginBase(ctx)
+	g.cfg = ctx.Config.GetConsumer().GetWeightAdjuster()
+	ctx.Plugins.RegisterEventSubscriber(common.OnInstanceLocalValueCreated, common.PluginEventHandler{
+		Callback: g.generateInstanceStat,
+	})
 	return nil
 }
 
@@ -49,14 +70,139 @@ func (g *Adjuster) Destroy() error {
 	return nil
 }
 
+// IsEnable enable
+func (g *Adjuster) IsEnable(cfg config.Configuration) bool {
+	return cfg.GetConsumer().GetWeightAdjuster().IsEnable()
+}
+
+// 为新创建的实例生成统计数据
+func (g *Adjuster) generateInstanceStat(event *common.PluginEvent) error {
+	localValue := event.EventObject.(*local.DefaultInstanceLocalValue)
+	localValue.SetExtendedData(g.ID(), &instanceStat{})
+	return nil
+}
+
+// 获取实例的统计数据
+func (g *Adjuster) getInstanceStat(instance model.Instance) *instanceStat {
+	localValue, ok := instance.(local.InstanceLocalValue)
+	if !ok {
+		return nil
+	}
+	stat, ok := localValue.GetExtendedData(g.ID()).(*instanceStat)
+	if !ok {
+		return nil
+	}
+	return stat
+}
+
 // RealTimeAdjustDynamicWeight 实时上报健康状态，并判断是否需要立刻进行动态权重调整，用于流量削峰
-func (g *Adjuster) RealTimeAdjustDynamicWeight(model.InstanceGauge) (bool, error) {
-	return false, nil
+func (g *Adjuster) RealTimeAdjustDynamicWeight(gauge model.InstanceGauge) (bool, error) {
+	instance := gauge.GetCalledInstance()
+	if nil == instance {
+		return false, nil
+	}
+	stat := g.getInstanceStat(instance)
+	if nil == stat {
+		return false, nil
+	}
+	var delay float64
+	if nil != gauge.GetDelay() {
+		delay = toMilliseconds(*gauge.GetDelay())
+	}
+	return stat.add(delay, gauge.GetRetStatus() == model.RetFail), nil
 }
 
 // TimingAdjustDynamicWeight 进行动态权重调整，返回调整后的动态权重
 func (g *Adjuster) TimingAdjustDynamicWeight(service model.ServiceInstances) ([]*model.InstanceWeight, error) {
-	return nil, nil
+	instances := service.GetInstances()
+	if len(instances) == 0 {
+		return nil, nil
+	}
+	// 先将本周期的统计数据合入移动平均值，并找出时延最低的实例作为基准
+	snapshots := make([]statSnapshot, len(instances))
+	baseDelay := math.MaxFloat64
+	for i, instance := range instances {
+		stat := g.getInstanceStat(instance)
+		if nil == stat {
+			continue
+		}
+		snapshots[i] = stat.roll()
+		if snapshots[i].valid && snapshots[i].avgDelay < baseDelay {
+			baseDelay = snapshots[i].avgDelay
+		}
+	}
+	var weights []*model.InstanceWeight
+	for i, instance := range instances {
+		staticWeight := instance.GetWeight()
+		if staticWeight == 0 || instance.IsIsolated() {
+			continue
+		}
+		cbStatus := instance.GetCircuitBreakerStatus()
+		if nil != cbStatus && cbStatus.GetStatus() != model.Close {
+			// 熔断中的实例交由熔断器处理
+			continue
+		}
+		target := staticWeight
+		if snapshots[i].valid {
+			target = g.calcTargetWeight(staticWeight, snapshots[i], baseDelay)
+		}
+		// 预热的折算在负载均衡时叠加，调整基于不含预热折算的动态权重，避免重复折算
+		current := model.GetDynamicWeight(instance)
+		next := smoothWeight(staticWeight, current, target)
+		if next == current || (next != staticWeight && abs(next-current)*100 < staticWeight*minAdjustStepPercent) {
+			continue
+		}
+		weights = append(weights, &model.InstanceWeight{
+			InstanceID:    instance.GetId(),
+			DynamicWeight: uint32(next),
+		})
+	}
+	if len(weights) > 0 {
+		log.GetBaseLogger().Debugf("%s: adjust dynamic weight for %s::%s, weights %v",
+			g.Name(), service.GetNamespace(), service.GetService(), weights)
+	}
+	return weights, nil
+}
+
+// calcTargetWeight 根据时延相对于基准时延的倍数以及错误率，计算实例的目标权重
+func (g *Adjuster) calcTargetWeight(staticWeight int, snapshot statSnapshot, baseDelay float64) int {
+	delayRatio := 1.0
+	if baseDelay > 0 && snapshot.avgDelay > baseDelay {
+		delayRatio = snapshot.avgDelay / baseDelay
+	}
+	penalty := 1 + g.cfg.GetDelayFactor()*(delayRatio-1) + g.cfg.GetRateFactor()*snapshot.failRate
+	target := int(math.Round(float64(staticWeight) / penalty))
+	minWeight := staticWeight * g.cfg.GetMinWeightPercent() / 100
+	if minWeight < 1 {
+		minWeight = 1
+	}
+	if target < minWeight {
+		target = minWeight
+	}
+	return target
+}
+
+// smoothWeight 限制单次调整的步长，使实例的流量逐步减少或者恢复
+func smoothWeight(staticWeight int, current int, target int) int {
+	maxStep := staticWeight * maxAdjustStepPercent / 100
+	if maxStep < 1 {
+		maxStep = 1
+	}
+	switch {
+	case target > current+maxStep:
+		return current + maxStep
+	case target < current-maxStep:
+		return current - maxStep
+	default:
+		return target
+	}
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
 }
 
 // init 注册插件
@@ -0,0 +1,192 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package ratedelay
+
+import (
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/log/logtest"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/pkg/model/local"
+	"github.com/polarismesh/polaris-go/pkg/model/pb"
+	"github.com/polarismesh/polaris-go/pkg/plugin"
+	"github.com/polarismesh/polaris-go/pkg/plugin/common"
+	// 注册动态权重调整的插件接口
+	_ "github.com/polarismesh/polaris-go/pkg/plugin/weightadjuster"
+)
+
+func init() {
+	logtest.DiscardLoggers()
+}
+
+// TestInstanceStatRoll 测试周期统计合入移动平均值
+func TestInstanceStatRoll(t *testing.T) {
+	stat := &instanceStat{}
+	for i := 0; i < minRequestsPerPeriod-1; i++ {
+		stat.add(10, false)
+	}
+	if snapshot := stat.roll(); snapshot.valid {
+		t.Fatalf("snapshot should be invalid when requests less than %d", minRequestsPerPeriod)
+	}
+	for i := 0; i < 10; i++ {
+		stat.add(10, false)
+	}
+	snapshot := stat.roll()
+	if !snapshot.valid || snapshot.avgDelay != 10 || snapshot.failRate != 0 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+	for i := 0; i < 10; i++ {
+		stat.add(30, true)
+	}
+	snapshot = stat.roll()
+	if snapshot.avgDelay != 20 || snapshot.failRate != 0.5 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+}
+
+// TestInstanceStatRealTime 测试错误率过高时实时触发调整，且每个周期只触发一次
+func TestInstanceStatRealTime(t *testing.T) {
+	stat := &instanceStat{}
+	var triggered int
+	for i := 0; i < 20; i++ {
+		if stat.add(10, true) {
+			triggered++
+		}
+	}
+	if triggered != 1 {
+		t.Fatalf("triggered %d times, expect 1", triggered)
+	}
+	stat.roll()
+	triggered = 0
+	for i := 0; i < minRequestsPerPeriod; i++ {
+		if stat.add(10, true) {
+			triggered++
+		}
+	}
+	if triggered != 1 {
+		t.Fatalf("triggered %d times in new period, expect 1", triggered)
+	}
+}
+
+// TestCalcTargetWeight 测试根据时延和错误率计算目标权重
+func TestCalcTargetWeight(t *testing.T) {
+	cfg := &config.WeightAdjusterConfigImpl{}
+	cfg.SetDefault()
+	adjuster := &Adjuster{cfg: cfg}
+	if weight := adjuster.calcTargetWeight(100, statSnapshot{valid: true, avgDelay: 10}, 10); weight != 100 {
+		t.Fatalf("weight is %d, expect 100", weight)
+	}
+	if weight := adjuster.calcTargetWeight(100, statSnapshot{valid: true, avgDelay: 20}, 10); weight != 50 {
+		t.Fatalf("weight is %d, expect 50", weight)
+	}
+	if weight := adjuster.calcTargetWeight(100, statSnapshot{valid: true, avgDelay: 10, failRate: 1}, 10); weight != 13 {
+		t.Fatalf("weight is %d, expect 13", weight)
+	}
+	if weight := adjuster.calcTargetWeight(100, statSnapshot{valid: true, avgDelay: 1000}, 10); weight != 10 {
+		t.Fatalf("weight is %d, expect min weight 10", weight)
+	}
+}
+
+// TestSmoothWeight 测试权重逐步调整
+func TestSmoothWeight(t *testing.T) {
+	if weight := smoothWeight(100, 100, 10); weight != 80 {
+		t.Fatalf("weight is %d, expect 80", weight)
+	}
+	if weight := smoothWeight(100, 10, 100); weight != 30 {
+		t.Fatalf("weight is %d, expect 30", weight)
+	}
+	if weight := smoothWeight(100, 90, 100); weight != 100 {
+		t.Fatalf("weight is %d, expect 100", weight)
+	}
+	if weight := smoothWeight(3, 3, 1); weight != 2 {
+		t.Fatalf("weight is %d, expect 2", weight)
+	}
+}
+
+// newTestInstances 创建带有统计数据的服务实例，返回实例ID到实例本地信息的映射
+func newTestInstances(adjuster *Adjuster, ids ...string) (
+	model.ServiceInstances, map[string]*local.DefaultInstanceLocalValue) {
+	resp := &apiservice.DiscoverResponse{
+		Service: &apiservice.Service{Namespace: wrapperspb.String("default"), Name: wrapperspb.String("echo")},
+	}
+	for i, id := range ids {
+		resp.Instances = append(resp.Instances, &apiservice.Instance{
+			Id:      wrapperspb.String(id),
+			Host:    wrapperspb.String("127.0.0.1"),
+			Port:    wrapperspb.UInt32(uint32(8001 + i)),
+			Weight:  wrapperspb.UInt32(100),
+			Healthy: wrapperspb.Bool(true),
+		})
+	}
+	localValues := make(map[string]*local.DefaultInstanceLocalValue)
+	svcInstances := pb.NewServiceInstancesInProto(resp, func(instID string) local.InstanceLocalValue {
+		localValue := local.NewInstanceLocalValue().(*local.DefaultInstanceLocalValue)
+		_ = adjuster.generateInstanceStat(&common.PluginEvent{
+			EventType: common.OnInstanceLocalValueCreated, EventObject: localValue})
+		localValues[instID] = localValue
+		return localValue
+	}, nil, nil)
+	return svcInstances, localValues
+}
+
+// TestAdjustWithWarmup 测试预热中的实例按照不含预热折算的动态权重调整，预热折算只在负载均衡时叠加一次
+func TestAdjustWithWarmup(t *testing.T) {
+	cfg := &config.WeightAdjusterConfigImpl{}
+	cfg.SetDefault()
+	adjuster := &Adjuster{PluginBase: plugin.NewPluginBase(&plugin.InitContext{PluginIndex: 1}), cfg: cfg}
+	svcInstances, localValues := newTestInstances(adjuster, "a", "b")
+	localValues["b"].SetWarmupWeightPercent(20)
+	addCalls := func(delays map[string]float64) {
+		for _, instance := range svcInstances.GetInstances() {
+			for i := 0; i < minRequestsPerPeriod; i++ {
+				adjuster.getInstanceStat(instance).add(delays[instance.GetId()], false)
+			}
+		}
+	}
+
+	// 时延相同的实例不因为预热而被调整动态权重
+	addCalls(map[string]float64{"a": 10, "b": 10})
+	weights, err := adjuster.TimingAdjustDynamicWeight(svcInstances)
+	if err != nil || len(weights) != 0 {
+		t.Fatalf("unexpected weights %v, err %v", weights, err)
+	}
+	b := svcInstances.GetInstance("b")
+	if weight := model.GetEffectiveWeight(b); weight != 20 {
+		t.Fatalf("effective weight is %d, expect 20", weight)
+	}
+
+	// 时延升高的预热实例从完整权重开始逐步调整
+	addCalls(map[string]float64{"a": 10, "b": 20})
+	weights, err = adjuster.TimingAdjustDynamicWeight(svcInstances)
+	if err != nil || len(weights) != 1 || weights[0].InstanceID != "b" || weights[0].DynamicWeight != 80 {
+		t.Fatalf("unexpected weights %v, err %v", weights, err)
+	}
+	localValues["b"].SetDynamicWeight(weights[0])
+	if weight := model.GetEffectiveWeight(b); weight != 16 {
+		t.Fatalf("effective weight is %d, expect 16", weight)
+	}
+	// 预热结束后恢复为动态权重
+	localValues["b"].SetWarmupWeightPercent(100)
+	if weight := model.GetEffectiveWeight(b); weight != 80 {
+		t.Fatalf("effective weight is %d, expect 80", weight)
+	}
+}
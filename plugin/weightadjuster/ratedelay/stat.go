@@ -0,0 +1,106 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package ratedelay
+
+import (
+	"sync"
+	"time"
+)
+
+// 移动平均值中本周期数据所占的比重
+const ewmaAlpha = 0.5
+
+// instanceStat 实例的调用统计，按周期累加，并在每个调整周期合入指数加权移动平均值
+type instanceStat struct {
+	mutex sync.Mutex
+	// 本周期的调用次数
+	count int64
+	// 本周期的失败次数
+	failCount int64
+	// 本周期的时延总和，单位毫秒
+	totalDelay float64
+	// 本周期是否已经触发过实时调整
+	triggered bool
+	// 时延的移动平均值，单位毫秒
+	avgDelay float64
+	// 错误率的移动平均值
+	failRate float64
+	// 移动平均值是否已经初始化
+	initialized bool
+}
+
+// statSnapshot 实例统计数据的快照
+type statSnapshot struct {
+	// 是否有足够的数据用于计算权重
+	valid bool
+	// 时延的移动平均值，单位毫秒
+	avgDelay float64
+	// 错误率的移动平均值
+	failRate float64
+}
+
+// add 累加一次调用结果，返回是否需要立刻进行权重调整
+func (s *instanceStat) add(delay float64, fail bool) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.count++
+	s.totalDelay += delay
+	if fail {
+		s.failCount++
+	}
+	if s.triggered || s.count < minRequestsPerPeriod {
+		return false
+	}
+	if float64(s.failCount)/float64(s.count) >= realTimeFailRateThreshold {
+		s.triggered = true
+		return true
+	}
+	return false
+}
+
+// roll 将本周期的统计数据合入移动平均值，并开启新的统计周期
+func (s *instanceStat) roll() statSnapshot {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.count >= minRequestsPerPeriod {
+		periodDelay := s.totalDelay / float64(s.count)
+		periodFailRate := float64(s.failCount) / float64(s.count)
+		if s.initialized {
+			s.avgDelay = ewmaAlpha*periodDelay + (1-ewmaAlpha)*s.avgDelay
+			s.failRate = ewmaAlpha*periodFailRate + (1-ewmaAlpha)*s.failRate
+		} else {
+			s.avgDelay = periodDelay
+			s.failRate = periodFailRate
+			s.initialized = true
+		}
+	}
+	s.count = 0
+	s.failCount = 0
+	s.totalDelay = 0
+	s.triggered = false
+	return statSnapshot{
+		valid:    s.initialized,
+		avgDelay: s.avgDelay,
+		failRate: s.failRate,
+	}
+}
+
+// toMilliseconds 将时延转换为毫秒
+func toMilliseconds(delay time.Duration) float64 {
+	return float64(delay) / float64(time.Millisecond)
+}
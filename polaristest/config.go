@@ -0,0 +1,362 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaristest
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/config_manage"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// configFileKey 配置文件的唯一标识
+type configFileKey struct {
+	namespace string
+	group     string
+	fileName  string
+}
+
+// releasedConfigFile 已发布的配置文件
+type releasedConfigFile struct {
+	content string
+	md5     string
+	tags    map[string]string
+}
+
+// configFileEntry 配置文件的草稿以及发布内容，删除后保留版本号，保证重新发布后版本号递增
+type configFileEntry struct {
+	draft    *config_manage.ConfigFile
+	released *releasedConfigFile
+	version  uint64
+}
+
+// configServer 配置中心接口的模拟实现
+type configServer struct {
+	server *Server
+	lock   sync.RWMutex
+	files  map[configFileKey]*configFileEntry
+	// changed 配置文件发布或者删除时关闭并重新创建，用于唤醒挂起的长轮询请求
+	changed chan struct{}
+}
+
+func newConfigServer(server *Server) *configServer {
+	return &configServer{
+		server:  server,
+		files:   make(map[configFileKey]*configFileEntry),
+		changed: make(chan struct{}),
+	}
+}
+
+// PublishConfigFile 发布配置文件，返回发布后的版本号，正在监听该文件的SDK会立即收到变更通知
+func (s *Server) PublishConfigFile(namespace, group, fileName, content string) uint64 {
+	return s.PublishConfigFileWithTags(namespace, group, fileName, content, nil)
+}
+
+// PublishConfigFileWithTags 发布带标签的配置文件，返回发布后的版本号
+func (s *Server) PublishConfigFileWithTags(namespace, group, fileName, content string, tags map[string]string) uint64 {
+	c := s.configSvr
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.publish(configFileKey{namespace: namespace, group: group, fileName: fileName}, content, tags)
+}
+
+// DeleteConfigFile 删除已发布的配置文件，正在监听该文件的SDK会收到变更通知并读取到文件不存在
+func (s *Server) DeleteConfigFile(namespace, group, fileName string) {
+	c := s.configSvr
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.files[configFileKey{namespace: namespace, group: group, fileName: fileName}]
+	if !ok || entry.released == nil {
+		return
+	}
+	entry.draft = nil
+	entry.released = nil
+	entry.version++
+	c.notifyChanged()
+}
+
+// GetConfigFile 获取已发布的配置文件
+func (c *configServer) GetConfigFile(ctx context.Context,
+	req *config_manage.ClientConfigFileInfo) (*config_manage.ConfigClientResponse, error) {
+	if code := c.server.intercept(ctx, OperationGetConfigFile); code != 0 {
+		return configErrorResponse(code, "injected error"), nil
+	}
+	key := configFileKey{
+		namespace: req.GetNamespace().GetValue(),
+		group:     req.GetGroup().GetValue(),
+		fileName:  req.GetFileName().GetValue(),
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	entry, ok := c.files[key]
+	if !ok || entry.released == nil {
+		return configErrorResponse(uint32(apimodel.Code_NotFoundResource), "config file not found"), nil
+	}
+	code, info := successCodeInfo()
+	return &config_manage.ConfigClientResponse{
+		Code:       code,
+		Info:       info,
+		ConfigFile: entry.toClientInfo(key),
+	}, nil
+}
+
+// WatchConfigFiles 长轮询监听配置文件，有文件的版本号大于客户端版本号时立即返回，否则挂起到超时
+func (c *configServer) WatchConfigFiles(ctx context.Context,
+	req *config_manage.ClientWatchConfigFileRequest) (*config_manage.ConfigClientResponse, error) {
+	if code := c.server.intercept(ctx, OperationWatchConfigFiles); code != 0 {
+		return configErrorResponse(code, "injected error"), nil
+	}
+	timer := time.NewTimer(c.server.opts.watchTimeout)
+	defer timer.Stop()
+	for {
+		c.lock.RLock()
+		changed := c.changed
+		info := c.findChanged(req.GetWatchFiles())
+		c.lock.RUnlock()
+		if info != nil {
+			code, msg := successCodeInfo()
+			return &config_manage.ConfigClientResponse{Code: code, Info: msg, ConfigFile: info}, nil
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			return configErrorResponse(uint32(apimodel.Code_DataNoChange), "data no change"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.server.done:
+			return configErrorResponse(uint32(apimodel.Code_DataNoChange), "data no change"), nil
+		}
+	}
+}
+
+// CreateConfigFile 创建配置文件草稿
+func (c *configServer) CreateConfigFile(ctx context.Context,
+	req *config_manage.ConfigFile) (*config_manage.ConfigClientResponse, error) {
+	if code := c.server.intercept(ctx, OperationWriteConfigFile); code != 0 {
+		return configErrorResponse(code, "injected error"), nil
+	}
+	key := configFileKey{
+		namespace: req.GetNamespace().GetValue(),
+		group:     req.GetGroup().GetValue(),
+		fileName:  req.GetName().GetValue(),
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.files[key]
+	if ok && entry.draft != nil {
+		return configErrorResponse(uint32(apimodel.Code_ExistedResource), "config file existed"), nil
+	}
+	if !ok {
+		entry = &configFileEntry{}
+		c.files[key] = entry
+	}
+	entry.draft = req
+	code, info := successCodeInfo()
+	return &config_manage.ConfigClientResponse{Code: code, Info: info}, nil
+}
+
+// UpdateConfigFile 更新配置文件草稿
+func (c *configServer) UpdateConfigFile(ctx context.Context,
+	req *config_manage.ConfigFile) (*config_manage.ConfigClientResponse, error) {
+	if code := c.server.intercept(ctx, OperationWriteConfigFile); code != 0 {
+		return configErrorResponse(code, "injected error"), nil
+	}
+	key := configFileKey{
+		namespace: req.GetNamespace().GetValue(),
+		group:     req.GetGroup().GetValue(),
+		fileName:  req.GetName().GetValue(),
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.files[key]
+	if !ok || entry.draft == nil {
+		return configErrorResponse(uint32(apimodel.Code_NotFoundResource), "config file not found"), nil
+	}
+	entry.draft = req
+	code, info := successCodeInfo()
+	return &config_manage.ConfigClientResponse{Code: code, Info: info}, nil
+}
+
+// PublishConfigFile 发布配置文件草稿
+func (c *configServer) PublishConfigFile(ctx context.Context,
+	req *config_manage.ConfigFileRelease) (*config_manage.ConfigClientResponse, error) {
+	if code := c.server.intercept(ctx, OperationWriteConfigFile); code != 0 {
+		return configErrorResponse(code, "injected error"), nil
+	}
+	key := configFileKey{
+		namespace: req.GetNamespace().GetValue(),
+		group:     req.GetGroup().GetValue(),
+		fileName:  req.GetFileName().GetValue(),
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.files[key]
+	if !ok || entry.draft == nil {
+		return configErrorResponse(uint32(apimodel.Code_NotFoundResource), "config file not found"), nil
+	}
+	tags := make(map[string]string, len(entry.draft.GetTags()))
+	for _, tag := range entry.draft.GetTags() {
+		tags[tag.GetKey().GetValue()] = tag.GetValue().GetValue()
+	}
+	c.publish(key, entry.draft.GetContent().GetValue(), tags)
+	code, info := successCodeInfo()
+	return &config_manage.ConfigClientResponse{Code: code, Info: info}, nil
+}
+
+// GetConfigFileMetadataList 获取配置分组下已发布的配置文件列表，分组版本号未变化时返回 DataNoChange
+func (c *configServer) GetConfigFileMetadataList(ctx context.Context,
+	req *config_manage.ConfigFileGroupRequest) (*config_manage.ConfigClientListResponse, error) {
+	if code := c.server.intercept(ctx, OperationGetConfigGroup); code != 0 {
+		return &config_manage.ConfigClientListResponse{
+			Code: wrapperspb.UInt32(code),
+			Info: wrapperspb.String("injected error"),
+		}, nil
+	}
+	namespace := req.GetConfigFileGroup().GetNamespace().GetValue()
+	group := req.GetConfigFileGroup().GetName().GetValue()
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	infos := make([]*config_manage.ClientConfigFileInfo, 0)
+	for key, entry := range c.files {
+		if key.namespace != namespace || key.group != group || entry.released == nil {
+			continue
+		}
+		info := entry.toClientInfo(key)
+		info.Content = nil
+		infos = append(infos, info)
+	}
+	if len(infos) == 0 {
+		return &config_manage.ConfigClientListResponse{
+			Code: wrapperspb.UInt32(uint32(apimodel.Code_NotFoundResource)),
+			Info: wrapperspb.String("config group not found"),
+		}, nil
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].GetFileName().GetValue() < infos[j].GetFileName().GetValue()
+	})
+	revision := groupRevision(infos)
+	if revision == req.GetRevision().GetValue() {
+		return &config_manage.ConfigClientListResponse{
+			Code:     wrapperspb.UInt32(uint32(apimodel.Code_DataNoChange)),
+			Info:     wrapperspb.String("data no change"),
+			Revision: wrapperspb.String(revision),
+		}, nil
+	}
+	code, info := successCodeInfo()
+	return &config_manage.ConfigClientListResponse{
+		Code:            code,
+		Info:            info,
+		Revision:        wrapperspb.String(revision),
+		ConfigFileInfos: infos,
+	}, nil
+}
+
+// publish 发布配置文件并唤醒挂起的长轮询请求，调用方需要持有写锁
+func (c *configServer) publish(key configFileKey, content string, tags map[string]string) uint64 {
+	entry, ok := c.files[key]
+	if !ok {
+		entry = &configFileEntry{}
+		c.files[key] = entry
+	}
+	sum := md5.Sum([]byte(content))
+	entry.released = &releasedConfigFile{
+		content: content,
+		md5:     hex.EncodeToString(sum[:]),
+		tags:    tags,
+	}
+	entry.version++
+	c.notifyChanged()
+	return entry.version
+}
+
+// notifyChanged 唤醒挂起的长轮询请求，调用方需要持有写锁
+func (c *configServer) notifyChanged() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// findChanged 查找版本号大于客户端版本号的配置文件，调用方需要持有读锁
+func (c *configServer) findChanged(watchFiles []*config_manage.ClientConfigFileInfo) *config_manage.ClientConfigFileInfo {
+	for _, watchFile := range watchFiles {
+		key := configFileKey{
+			namespace: watchFile.GetNamespace().GetValue(),
+			group:     watchFile.GetGroup().GetValue(),
+			fileName:  watchFile.GetFileName().GetValue(),
+		}
+		entry, ok := c.files[key]
+		if !ok || entry.version <= watchFile.GetVersion().GetValue() {
+			continue
+		}
+		return &config_manage.ClientConfigFileInfo{
+			Namespace: wrapperspb.String(key.namespace),
+			Group:     wrapperspb.String(key.group),
+			FileName:  wrapperspb.String(key.fileName),
+			Version:   wrapperspb.UInt64(entry.version),
+		}
+	}
+	return nil
+}
+
+func (e *configFileEntry) toClientInfo(key configFileKey) *config_manage.ClientConfigFileInfo {
+	tagKeys := make([]string, 0, len(e.released.tags))
+	for k := range e.released.tags {
+		tagKeys = append(tagKeys, k)
+	}
+	sort.Strings(tagKeys)
+	tags := make([]*config_manage.ConfigFileTag, 0, len(tagKeys))
+	for _, k := range tagKeys {
+		tags = append(tags, &config_manage.ConfigFileTag{
+			Key:   wrapperspb.String(k),
+			Value: wrapperspb.String(e.released.tags[k]),
+		})
+	}
+	return &config_manage.ClientConfigFileInfo{
+		Namespace: wrapperspb.String(key.namespace),
+		Group:     wrapperspb.String(key.group),
+		FileName:  wrapperspb.String(key.fileName),
+		Content:   wrapperspb.String(e.released.content),
+		Version:   wrapperspb.UInt64(e.version),
+		Md5:       wrapperspb.String(e.released.md5),
+		Tags:      tags,
+	}
+}
+
+// groupRevision 根据分组下配置文件的名称和版本号计算分组的版本号
+func groupRevision(infos []*config_manage.ClientConfigFileInfo) string {
+	h := sha1.New()
+	for _, info := range infos {
+		_, _ = h.Write([]byte(fmt.Sprintf("%s:%d;", info.GetFileName().GetValue(), info.GetVersion().GetValue())))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func configErrorResponse(code uint32, info string) *config_manage.ConfigClientResponse {
+	return &config_manage.ConfigClientResponse{
+		Code: wrapperspb.UInt32(code),
+		Info: wrapperspb.String(info),
+	}
+}
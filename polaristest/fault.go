@@ -0,0 +1,127 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaristest
+
+import (
+	"context"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+)
+
+// Operation 服务端接口，用于错误注入以及请求计数
+type Operation string
+
+const (
+	// OperationDiscover 服务发现，包括实例、路由规则、限流规则等所有类型的资源
+	OperationDiscover Operation = "discover"
+	// OperationRegister 注册实例
+	OperationRegister Operation = "register"
+	// OperationDeregister 反注册实例
+	OperationDeregister Operation = "deregister"
+	// OperationHeartbeat 实例心跳
+	OperationHeartbeat Operation = "heartbeat"
+	// OperationReportClient 上报客户端信息
+	OperationReportClient Operation = "reportClient"
+	// OperationGetConfigFile 拉取配置文件
+	OperationGetConfigFile Operation = "getConfigFile"
+	// OperationWatchConfigFiles 长轮询监听配置文件
+	OperationWatchConfigFiles Operation = "watchConfigFiles"
+	// OperationWriteConfigFile 创建、更新以及发布配置文件
+	OperationWriteConfigFile Operation = "writeConfigFile"
+	// OperationGetConfigGroup 获取配置分组下的配置文件列表
+	OperationGetConfigGroup Operation = "getConfigGroup"
+	// OperationRateLimit 分布式限流的初始化以及配额上报
+	OperationRateLimit Operation = "rateLimit"
+)
+
+// fault 注入到接口上的故障
+type fault struct {
+	code    uint32
+	latency time.Duration
+}
+
+// InjectError 使接口返回指定的错误码，code为 apimodel.Code_ExecuteSuccess 时取消错误注入
+func (s *Server) InjectError(op Operation, code apimodel.Code) {
+	s.faultLock.Lock()
+	defer s.faultLock.Unlock()
+	s.getOrCreateFault(op).code = uint32(code)
+}
+
+// InjectLatency 使接口在处理请求前等待指定的时间，latency为0时取消时延注入
+func (s *Server) InjectLatency(op Operation, latency time.Duration) {
+	s.faultLock.Lock()
+	defer s.faultLock.Unlock()
+	s.getOrCreateFault(op).latency = latency
+}
+
+// ClearFaults 取消所有接口上注入的错误和时延
+func (s *Server) ClearFaults() {
+	s.faultLock.Lock()
+	defer s.faultLock.Unlock()
+	s.faults = make(map[Operation]*fault)
+}
+
+// RequestCount 获取接口收到的请求数，流式接口按照请求消息计数
+func (s *Server) RequestCount(op Operation) int {
+	s.faultLock.RLock()
+	defer s.faultLock.RUnlock()
+	return s.requests[op]
+}
+
+// ResetRequestCount 清空所有接口的请求计数
+func (s *Server) ResetRequestCount() {
+	s.faultLock.Lock()
+	defer s.faultLock.Unlock()
+	s.requests = make(map[Operation]int)
+}
+
+func (s *Server) getOrCreateFault(op Operation) *fault {
+	f, ok := s.faults[op]
+	if !ok {
+		f = &fault{code: uint32(apimodel.Code_ExecuteSuccess)}
+		s.faults[op] = f
+	}
+	return f
+}
+
+// intercept 记录请求并执行注入的故障，返回需要应答的错误码，没有注入错误时返回0
+func (s *Server) intercept(ctx context.Context, op Operation) uint32 {
+	s.faultLock.Lock()
+	s.requests[op]++
+	var f fault
+	if injected, ok := s.faults[op]; ok {
+		f = *injected
+	}
+	s.faultLock.Unlock()
+
+	if f.latency > 0 {
+		timer := time.NewTimer(f.latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		case <-s.done:
+			timer.Stop()
+		}
+	}
+	if f.code == 0 || f.code == uint32(apimodel.Code_ExecuteSuccess) {
+		return 0
+	}
+	return f.code
+}
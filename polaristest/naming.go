@@ -0,0 +1,545 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaristest
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+// discoverTypes 服务发现请求与应答的类型转换
+var discoverTypes = map[service_manage.DiscoverRequest_DiscoverRequestType]service_manage.DiscoverResponse_DiscoverResponseType{
+	service_manage.DiscoverRequest_UNKNOWN:         service_manage.DiscoverResponse_UNKNOWN,
+	service_manage.DiscoverRequest_ROUTING:         service_manage.DiscoverResponse_ROUTING,
+	service_manage.DiscoverRequest_CLUSTER:         service_manage.DiscoverResponse_CLUSTER,
+	service_manage.DiscoverRequest_INSTANCE:        service_manage.DiscoverResponse_INSTANCE,
+	service_manage.DiscoverRequest_RATE_LIMIT:      service_manage.DiscoverResponse_RATE_LIMIT,
+	service_manage.DiscoverRequest_SERVICES:        service_manage.DiscoverResponse_SERVICES,
+	service_manage.DiscoverRequest_CIRCUIT_BREAKER: service_manage.DiscoverResponse_CIRCUIT_BREAKER,
+	service_manage.DiscoverRequest_FAULT_DETECTOR:  service_manage.DiscoverResponse_FAULT_DETECTOR,
+}
+
+// Instance 测试用的服务实例，未设置权重时默认为100
+type Instance struct {
+	// ID 实例ID，为空时根据命名空间、服务名、IP和端口生成
+	ID        string
+	Host      string
+	Port      uint32
+	Protocol  string
+	Version   string
+	Weight    uint32
+	Unhealthy bool
+	Isolated  bool
+	Metadata  map[string]string
+	Region    string
+	Zone      string
+	Campus    string
+}
+
+// serviceEntry 服务及其实例和规则，所有字段只整体替换不原地修改，应答中可以直接引用
+type serviceEntry struct {
+	service   *service_manage.Service
+	instances []*service_manage.Instance
+	routing   *traffic_manage.Routing
+	rateLimit *traffic_manage.RateLimit
+}
+
+// namingServer 服务发现、注册以及心跳接口的模拟实现
+type namingServer struct {
+	server   *Server
+	lock     sync.RWMutex
+	services map[model.ServiceKey]*serviceEntry
+}
+
+func newNamingServer(server *Server) *namingServer {
+	return &namingServer{
+		server:   server,
+		services: make(map[model.ServiceKey]*serviceEntry),
+	}
+}
+
+// AddService 添加服务，服务已存在时更新服务的元数据
+func (s *Server) AddService(namespace, service string, metadata map[string]string) {
+	n := s.naming
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	entry := n.getOrCreateService(namespace, service)
+	svc := proto.Clone(entry.service).(*service_manage.Service)
+	svc.Metadata = metadata
+	svc.Revision = newRevision()
+	entry.service = svc
+}
+
+// DeleteService 删除服务及其所有实例和规则
+func (s *Server) DeleteService(namespace, service string) {
+	n := s.naming
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	delete(n.services, serviceKey(namespace, service))
+}
+
+// AddInstances 为服务添加实例，服务不存在时自动创建，相同ID的实例会被覆盖，返回实例的ID
+func (s *Server) AddInstances(namespace, service string, instances ...Instance) []string {
+	n := s.naming
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	ids := make([]string, 0, len(instances))
+	for i := range instances {
+		instance := instances[i].toProto(namespace, service)
+		n.upsertInstance(instance)
+		ids = append(ids, instance.GetId().GetValue())
+	}
+	return ids
+}
+
+// UpdateInstance 按照ID更新服务实例，实例不存在时返回错误
+func (s *Server) UpdateInstance(namespace, service string, instance Instance) error {
+	n := s.naming
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	value := instance.toProto(namespace, service)
+	entry, ok := n.services[serviceKey(namespace, service)]
+	if !ok || indexOfInstance(entry.instances, value) < 0 {
+		return fmt.Errorf("instance %s of service %s/%s not found", value.GetId().GetValue(), namespace, service)
+	}
+	n.upsertInstance(value)
+	return nil
+}
+
+// RemoveInstance 按照ID删除服务实例
+func (s *Server) RemoveInstance(namespace, service, id string) {
+	n := s.naming
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.removeInstance(namespace, service, &service_manage.Instance{Id: wrapperspb.String(id)})
+}
+
+// GetInstances 获取服务当前的所有实例，包括SDK注册的实例
+func (s *Server) GetInstances(namespace, service string) []Instance {
+	n := s.naming
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	entry, ok := n.services[serviceKey(namespace, service)]
+	if !ok {
+		return nil
+	}
+	instances := make([]Instance, 0, len(entry.instances))
+	for _, instance := range entry.instances {
+		instances = append(instances, instanceFromProto(instance))
+	}
+	return instances
+}
+
+// SetRouteRule 设置服务的路由规则，routing为nil时删除路由规则
+func (s *Server) SetRouteRule(namespace, service string, routing *traffic_manage.Routing) {
+	if routing != nil {
+		routing = proto.Clone(routing).(*traffic_manage.Routing)
+		routing.Namespace = wrapperspb.String(namespace)
+		routing.Service = wrapperspb.String(service)
+		routing.Revision = newRevision()
+	}
+	n := s.naming
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	entry := n.getOrCreateService(namespace, service)
+	entry.routing = routing
+	n.touchService(entry)
+}
+
+// SetRateLimitRule 设置服务的限流规则，未设置ID的规则会生成ID，rateLimit为nil时删除限流规则
+func (s *Server) SetRateLimitRule(namespace, service string, rateLimit *traffic_manage.RateLimit) {
+	if rateLimit != nil {
+		rateLimit = proto.Clone(rateLimit).(*traffic_manage.RateLimit)
+		for _, rule := range rateLimit.GetRules() {
+			if len(rule.GetId().GetValue()) == 0 {
+				rule.Id = wrapperspb.String(uuid.New().String())
+			}
+			rule.Namespace = wrapperspb.String(namespace)
+			rule.Service = wrapperspb.String(service)
+			rule.Revision = newRevision()
+		}
+		rateLimit.Revision = newRevision()
+	}
+	n := s.naming
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	entry := n.getOrCreateService(namespace, service)
+	entry.rateLimit = rateLimit
+	n.touchService(entry)
+}
+
+// Discover 服务发现，每个请求对应一个应答
+func (n *namingServer) Discover(stream service_manage.PolarisGRPC_DiscoverServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err = stream.Send(n.discover(stream.Context(), req)); err != nil {
+			return err
+		}
+	}
+}
+
+func (n *namingServer) discover(ctx context.Context,
+	req *service_manage.DiscoverRequest) *service_manage.DiscoverResponse {
+	resp := &service_manage.DiscoverResponse{
+		Type:    discoverTypes[req.GetType()],
+		Service: req.GetService(),
+	}
+	if code := n.server.intercept(ctx, OperationDiscover); code != 0 {
+		resp.Code, resp.Info = wrapperspb.UInt32(code), wrapperspb.String("injected error")
+		return resp
+	}
+	namespace := req.GetService().GetNamespace().GetValue()
+	name := req.GetService().GetName().GetValue()
+
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	if req.GetType() == service_manage.DiscoverRequest_SERVICES {
+		resp.Services = n.listServices(namespace)
+		resp.Code, resp.Info = successCodeInfo()
+		return resp
+	}
+	entry, ok := n.services[serviceKey(namespace, name)]
+	if !ok {
+		resp.Code = wrapperspb.UInt32(uint32(apimodel.Code_NotFoundResource))
+		resp.Info = wrapperspb.String(fmt.Sprintf("service %s/%s not found", namespace, name))
+		return resp
+	}
+	resp.Service = entry.service
+	switch req.GetType() {
+	case service_manage.DiscoverRequest_INSTANCE:
+		resp.Instances = entry.instances
+	case service_manage.DiscoverRequest_ROUTING:
+		resp.Routing = entry.routing
+	case service_manage.DiscoverRequest_RATE_LIMIT:
+		resp.RateLimit = entry.rateLimit
+	}
+	resp.Code, resp.Info = successCodeInfo()
+	return resp
+}
+
+// RegisterInstance 注册实例，服务不存在时自动创建，实例已存在时进行覆盖
+func (n *namingServer) RegisterInstance(ctx context.Context,
+	req *service_manage.Instance) (*service_manage.Response, error) {
+	if code := n.server.intercept(ctx, OperationRegister); code != 0 {
+		return errorResponse(code), nil
+	}
+	instance := proto.Clone(req).(*service_manage.Instance)
+	instance.ServiceToken = nil
+	if len(instance.GetId().GetValue()) == 0 {
+		instance.Id = wrapperspb.String(instanceID(instance.GetNamespace().GetValue(),
+			instance.GetService().GetValue(), instance.GetHost().GetValue(), instance.GetPort().GetValue()))
+	}
+	if instance.Healthy == nil {
+		instance.Healthy = wrapperspb.Bool(true)
+	}
+	if instance.Weight == nil {
+		instance.Weight = wrapperspb.UInt32(100)
+	}
+	n.lock.Lock()
+	n.upsertInstance(instance)
+	n.lock.Unlock()
+	resp := successResponse()
+	resp.Instance = instance
+	return resp, nil
+}
+
+// DeregisterInstance 反注册实例，可以按照ID或者IP端口进行匹配
+func (n *namingServer) DeregisterInstance(ctx context.Context,
+	req *service_manage.Instance) (*service_manage.Response, error) {
+	if code := n.server.intercept(ctx, OperationDeregister); code != 0 {
+		return errorResponse(code), nil
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if !n.removeInstance(req.GetNamespace().GetValue(), req.GetService().GetValue(), req) {
+		return notFoundResponse("instance not found"), nil
+	}
+	return successResponse(), nil
+}
+
+// Heartbeat 实例心跳，心跳成功后实例变为健康状态
+func (n *namingServer) Heartbeat(ctx context.Context, req *service_manage.Instance) (*service_manage.Response, error) {
+	if code := n.server.intercept(ctx, OperationHeartbeat); code != 0 {
+		return errorResponse(code), nil
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	key := serviceKey(req.GetNamespace().GetValue(), req.GetService().GetValue())
+	entry, ok := n.services[key]
+	if !ok {
+		return notFoundResponse("service not found"), nil
+	}
+	idx := indexOfInstance(entry.instances, req)
+	if idx < 0 {
+		return notFoundResponse("instance not found"), nil
+	}
+	if !entry.instances[idx].GetHealthy().GetValue() {
+		instance := proto.Clone(entry.instances[idx]).(*service_manage.Instance)
+		instance.Healthy = wrapperspb.Bool(true)
+		n.upsertInstance(instance)
+	}
+	return successResponse(), nil
+}
+
+// BatchHeartbeat 批量心跳，SDK不使用该接口
+func (n *namingServer) BatchHeartbeat(server service_manage.PolarisGRPC_BatchHeartbeatServer) error {
+	return status.Error(codes.Unimplemented, "method BatchHeartbeat not implemented")
+}
+
+// BatchGetHeartbeat 批量查询心跳，SDK不使用该接口
+func (n *namingServer) BatchGetHeartbeat(ctx context.Context,
+	req *service_manage.GetHeartbeatsRequest) (*service_manage.GetHeartbeatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchGetHeartbeat not implemented")
+}
+
+// BatchDelHeartbeat 批量删除心跳，SDK不使用该接口
+func (n *namingServer) BatchDelHeartbeat(ctx context.Context,
+	req *service_manage.DelHeartbeatsRequest) (*service_manage.DelHeartbeatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchDelHeartbeat not implemented")
+}
+
+// ReportClient 上报客户端信息，返回通过 WithLocation 设置的地域信息
+func (n *namingServer) ReportClient(ctx context.Context, req *service_manage.Client) (*service_manage.Response, error) {
+	if code := n.server.intercept(ctx, OperationReportClient); code != 0 {
+		return errorResponse(code), nil
+	}
+	resp := successResponse()
+	resp.Client = &service_manage.Client{
+		Host:    req.GetHost(),
+		Type:    req.GetType(),
+		Version: req.GetVersion(),
+	}
+	opts := n.server.opts
+	if len(opts.region) > 0 || len(opts.zone) > 0 || len(opts.campus) > 0 {
+		resp.Client.Location = &apimodel.Location{
+			Region: wrapperspb.String(opts.region),
+			Zone:   wrapperspb.String(opts.zone),
+			Campus: wrapperspb.String(opts.campus),
+		}
+	}
+	return resp, nil
+}
+
+// getOrCreateService 获取服务，不存在时创建，调用方需要持有写锁
+func (n *namingServer) getOrCreateService(namespace, service string) *serviceEntry {
+	key := serviceKey(namespace, service)
+	entry, ok := n.services[key]
+	if !ok {
+		entry = &serviceEntry{
+			service: &service_manage.Service{
+				Namespace: wrapperspb.String(namespace),
+				Name:      wrapperspb.String(service),
+				Revision:  newRevision(),
+			},
+		}
+		n.services[key] = entry
+	}
+	return entry
+}
+
+// touchService 更新服务版本号，使SDK感知到服务数据的变更，调用方需要持有写锁
+func (n *namingServer) touchService(entry *serviceEntry) {
+	svc := proto.Clone(entry.service).(*service_manage.Service)
+	svc.Revision = newRevision()
+	entry.service = svc
+}
+
+// upsertInstance 添加或者覆盖实例，调用方需要持有写锁
+func (n *namingServer) upsertInstance(instance *service_manage.Instance) {
+	entry := n.getOrCreateService(instance.GetNamespace().GetValue(), instance.GetService().GetValue())
+	instance.Revision = newRevision()
+	instances := make([]*service_manage.Instance, 0, len(entry.instances)+1)
+	replaced := false
+	for _, existed := range entry.instances {
+		if !replaced && existed.GetId().GetValue() == instance.GetId().GetValue() {
+			instances = append(instances, instance)
+			replaced = true
+			continue
+		}
+		instances = append(instances, existed)
+	}
+	if !replaced {
+		instances = append(instances, instance)
+	}
+	entry.instances = instances
+	n.touchService(entry)
+}
+
+// removeInstance 删除实例，返回实例是否存在，调用方需要持有写锁
+func (n *namingServer) removeInstance(namespace, service string, target *service_manage.Instance) bool {
+	entry, ok := n.services[serviceKey(namespace, service)]
+	if !ok {
+		return false
+	}
+	idx := indexOfInstance(entry.instances, target)
+	if idx < 0 {
+		return false
+	}
+	instances := make([]*service_manage.Instance, 0, len(entry.instances)-1)
+	instances = append(instances, entry.instances[:idx]...)
+	instances = append(instances, entry.instances[idx+1:]...)
+	entry.instances = instances
+	n.touchService(entry)
+	return true
+}
+
+// listServices 获取命名空间下的服务，命名空间为空时返回所有服务，调用方需要持有读锁
+func (n *namingServer) listServices(namespace string) []*service_manage.Service {
+	services := make([]*service_manage.Service, 0, len(n.services))
+	for key, entry := range n.services {
+		if len(namespace) == 0 || key.Namespace == namespace {
+			services = append(services, entry.service)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].GetNamespace().GetValue() != services[j].GetNamespace().GetValue() {
+			return services[i].GetNamespace().GetValue() < services[j].GetNamespace().GetValue()
+		}
+		return services[i].GetName().GetValue() < services[j].GetName().GetValue()
+	})
+	return services
+}
+
+// indexOfInstance 查找实例的下标，优先按照ID匹配，ID为空时按照IP端口匹配
+func indexOfInstance(instances []*service_manage.Instance, target *service_manage.Instance) int {
+	id := target.GetId().GetValue()
+	for i, instance := range instances {
+		if len(id) > 0 {
+			if instance.GetId().GetValue() == id {
+				return i
+			}
+			continue
+		}
+		if instance.GetHost().GetValue() == target.GetHost().GetValue() &&
+			instance.GetPort().GetValue() == target.GetPort().GetValue() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (i *Instance) toProto(namespace, service string) *service_manage.Instance {
+	id := i.ID
+	if len(id) == 0 {
+		id = instanceID(namespace, service, i.Host, i.Port)
+	}
+	weight := i.Weight
+	if weight == 0 {
+		weight = 100
+	}
+	metadata := make(map[string]string, len(i.Metadata))
+	for k, v := range i.Metadata {
+		metadata[k] = v
+	}
+	return &service_manage.Instance{
+		Id:        wrapperspb.String(id),
+		Namespace: wrapperspb.String(namespace),
+		Service:   wrapperspb.String(service),
+		Host:      wrapperspb.String(i.Host),
+		Port:      wrapperspb.UInt32(i.Port),
+		Protocol:  wrapperspb.String(i.Protocol),
+		Version:   wrapperspb.String(i.Version),
+		Weight:    wrapperspb.UInt32(weight),
+		Healthy:   wrapperspb.Bool(!i.Unhealthy),
+		Isolate:   wrapperspb.Bool(i.Isolated),
+		Metadata:  metadata,
+		Location: &apimodel.Location{
+			Region: wrapperspb.String(i.Region),
+			Zone:   wrapperspb.String(i.Zone),
+			Campus: wrapperspb.String(i.Campus),
+		},
+	}
+}
+
+func instanceFromProto(instance *service_manage.Instance) Instance {
+	metadata := make(map[string]string, len(instance.GetMetadata()))
+	for k, v := range instance.GetMetadata() {
+		metadata[k] = v
+	}
+	return Instance{
+		ID:        instance.GetId().GetValue(),
+		Host:      instance.GetHost().GetValue(),
+		Port:      instance.GetPort().GetValue(),
+		Protocol:  instance.GetProtocol().GetValue(),
+		Version:   instance.GetVersion().GetValue(),
+		Weight:    instance.GetWeight().GetValue(),
+		Unhealthy: !instance.GetHealthy().GetValue(),
+		Isolated:  instance.GetIsolate().GetValue(),
+		Metadata:  metadata,
+		Region:    instance.GetLocation().GetRegion().GetValue(),
+		Zone:      instance.GetLocation().GetZone().GetValue(),
+		Campus:    instance.GetLocation().GetCampus().GetValue(),
+	}
+}
+
+// instanceID 按照服务端的规则生成实例ID
+func instanceID(namespace, service, host string, port uint32) string {
+	h := sha1.New()
+	_, _ = h.Write([]byte(fmt.Sprintf("%s##%s##%s##%d", namespace, service, host, port)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// newRevision 生成新的资源版本号
+func newRevision() *wrapperspb.StringValue {
+	return wrapperspb.String(uuid.New().String())
+}
+
+func successCodeInfo() (*wrapperspb.UInt32Value, *wrapperspb.StringValue) {
+	return wrapperspb.UInt32(uint32(apimodel.Code_ExecuteSuccess)), wrapperspb.String("execute success")
+}
+
+func successResponse() *service_manage.Response {
+	code, info := successCodeInfo()
+	return &service_manage.Response{Code: code, Info: info}
+}
+
+func notFoundResponse(info string) *service_manage.Response {
+	return &service_manage.Response{
+		Code: wrapperspb.UInt32(uint32(apimodel.Code_NotFoundResource)),
+		Info: wrapperspb.String(info),
+	}
+}
+
+func errorResponse(code uint32) *service_manage.Response {
+	return &service_manage.Response{
+		Code: wrapperspb.UInt32(code),
+		Info: wrapperspb.String("injected error"),
+	}
+}
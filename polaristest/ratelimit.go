@@ -0,0 +1,197 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaristest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris-go/pkg/model"
+	rlimitV2 "github.com/polarismesh/polaris-go/pkg/model/pb/metric/v2"
+)
+
+// quotaCounter 分布式限流的配额计数器，按照固定时间窗口统计所有客户端的配额使用量
+type quotaCounter struct {
+	key         uint32
+	durationMs  int64
+	maxAmount   int64
+	mode        rlimitV2.Mode
+	windowStart int64
+	used        int64
+	clients     map[string]struct{}
+}
+
+// left 获取当前时间窗口的剩余配额
+func (q *quotaCounter) left(nowMs int64) int64 {
+	windowStart := nowMs - nowMs%q.durationMs
+	if windowStart != q.windowStart {
+		q.windowStart = windowStart
+		q.used = 0
+	}
+	return q.maxAmount - q.used
+}
+
+// rateLimitServer 分布式限流接口的模拟实现
+type rateLimitServer struct {
+	server     *Server
+	lock       sync.Mutex
+	counters   map[uint32]*quotaCounter
+	counterIDs map[string]uint32
+	clientKeys map[string]uint32
+}
+
+func newRateLimitServer(server *Server) *rateLimitServer {
+	return &rateLimitServer{
+		server:     server,
+		counters:   make(map[uint32]*quotaCounter),
+		counterIDs: make(map[string]uint32),
+		clientKeys: make(map[string]uint32),
+	}
+}
+
+// Service 限流消息处理，每个请求对应一个应答
+func (r *rateLimitServer) Service(stream rlimitV2.RateLimitGRPCV2_ServiceServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		resp := r.process(stream.Context(), req)
+		if resp == nil {
+			continue
+		}
+		if err = stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// TimeAdjust 时间对齐
+func (r *rateLimitServer) TimeAdjust(context.Context, *rlimitV2.TimeAdjustRequest) (*rlimitV2.TimeAdjustResponse, error) {
+	return &rlimitV2.TimeAdjustResponse{ServerTimestamp: model.CurrentMillisecond()}, nil
+}
+
+func (r *rateLimitServer) process(ctx context.Context, req *rlimitV2.RateLimitRequest) *rlimitV2.RateLimitResponse {
+	code := r.server.intercept(ctx, OperationRateLimit)
+	if code == 0 {
+		code = uint32(apimodel.Code_ExecuteSuccess)
+	}
+	nowMs := model.CurrentMillisecond()
+	switch req.GetCmd() {
+	case rlimitV2.RateLimitCmd_INIT:
+		initReq := req.GetRateLimitInitRequest()
+		initResp := &rlimitV2.RateLimitInitResponse{
+			Code:      code,
+			Target:    initReq.GetTarget(),
+			Timestamp: nowMs,
+		}
+		if code == uint32(apimodel.Code_ExecuteSuccess) {
+			initResp.ClientKey, initResp.Counters = r.init(initReq, nowMs)
+			initResp.SlideCount = initReq.GetSlideCount()
+		}
+		return &rlimitV2.RateLimitResponse{
+			Cmd:                   rlimitV2.RateLimitCmd_INIT,
+			RateLimitInitResponse: initResp,
+		}
+	case rlimitV2.RateLimitCmd_ACQUIRE:
+		reportResp := &rlimitV2.RateLimitReportResponse{
+			Code:      code,
+			Timestamp: nowMs,
+		}
+		if code == uint32(apimodel.Code_ExecuteSuccess) {
+			reportResp.QuotaLefts = r.report(req.GetRateLimitReportRequest(), nowMs)
+		}
+		return &rlimitV2.RateLimitResponse{
+			Cmd:                     rlimitV2.RateLimitCmd_ACQUIRE,
+			RateLimitReportResponse: reportResp,
+		}
+	}
+	return nil
+}
+
+// init 初始化限流窗口，相同限流目标和时长的配额在所有客户端之间共享
+func (r *rateLimitServer) init(req *rlimitV2.RateLimitInitRequest, nowMs int64) (uint32, []*rlimitV2.QuotaCounter) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	clientKey, ok := r.clientKeys[req.GetClientId()]
+	if !ok {
+		clientKey = uint32(len(r.clientKeys) + 1)
+		r.clientKeys[req.GetClientId()] = clientKey
+	}
+	target := req.GetTarget()
+	counters := make([]*rlimitV2.QuotaCounter, 0, len(req.GetTotals()))
+	for _, total := range req.GetTotals() {
+		counterID := fmt.Sprintf("%s#%s#%s#%d", target.GetNamespace(), target.GetService(),
+			target.GetLabels(), total.GetDuration())
+		key, ok := r.counterIDs[counterID]
+		if !ok {
+			key = uint32(len(r.counterIDs) + 1)
+			r.counterIDs[counterID] = key
+			durationMs := int64(total.GetDuration()) * 1000
+			if durationMs <= 0 {
+				durationMs = 1000
+			}
+			r.counters[key] = &quotaCounter{
+				key:        key,
+				durationMs: durationMs,
+				clients:    make(map[string]struct{}),
+			}
+		}
+		counter := r.counters[key]
+		counter.maxAmount = int64(total.GetMaxAmount())
+		counter.mode = req.GetMode()
+		counter.clients[req.GetClientId()] = struct{}{}
+		counters = append(counters, &rlimitV2.QuotaCounter{
+			Duration:    total.GetDuration(),
+			CounterKey:  key,
+			Left:        counter.left(nowMs),
+			Mode:        counter.mode,
+			ClientCount: uint32(len(counter.clients)),
+		})
+	}
+	return clientKey, counters
+}
+
+// report 扣除客户端上报的配额使用量，返回剩余配额
+func (r *rateLimitServer) report(req *rlimitV2.RateLimitReportRequest, nowMs int64) []*rlimitV2.QuotaLeft {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	lefts := make([]*rlimitV2.QuotaLeft, 0, len(req.GetQuotaUses()))
+	for _, quotaUse := range req.GetQuotaUses() {
+		counter, ok := r.counters[quotaUse.GetCounterKey()]
+		if !ok {
+			continue
+		}
+		counter.left(nowMs)
+		counter.used += int64(quotaUse.GetUsed())
+		lefts = append(lefts, &rlimitV2.QuotaLeft{
+			CounterKey:  counter.key,
+			Left:        counter.left(nowMs),
+			Mode:        counter.mode,
+			ClientCount: uint32(len(counter.clients)),
+		})
+	}
+	return lefts
+}
@@ -0,0 +1,179 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+// Package polaristest 提供进程内的北极星服务端模拟，用于基于polaris-go的集成测试。
+// 服务端监听随机端口，提供服务发现、注册、心跳、配置中心以及分布式限流接口，
+// 测试代码通过 Server 的方法设置服务、实例、规则和配置文件，并可以注入错误码和时延。
+package polaristest
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/specification/source/go/api/v1/config_manage"
+	"github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"google.golang.org/grpc"
+
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	rlimitV2 "github.com/polarismesh/polaris-go/pkg/model/pb/metric/v2"
+)
+
+const (
+	// defaultListenAddress 默认监听地址，端口由系统随机分配
+	defaultListenAddress = "127.0.0.1:0"
+	// defaultWatchTimeout 配置文件长轮询的默认挂起时间
+	defaultWatchTimeout = 30 * time.Second
+)
+
+// Option 服务端的可选配置
+type Option func(*options)
+
+type options struct {
+	listenAddress string
+	watchTimeout  time.Duration
+	region        string
+	zone          string
+	campus        string
+}
+
+// WithListenAddress 设置监听地址，默认为127.0.0.1:0
+func WithListenAddress(address string) Option {
+	return func(o *options) {
+		o.listenAddress = address
+	}
+}
+
+// WithWatchTimeout 设置配置文件长轮询在没有变更时的挂起时间，默认为30s
+func WithWatchTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.watchTimeout = timeout
+	}
+}
+
+// WithLocation 设置上报客户端时返回的客户端地域信息
+func WithLocation(region, zone, campus string) Option {
+	return func(o *options) {
+		o.region = region
+		o.zone = zone
+		o.campus = campus
+	}
+}
+
+// Server 进程内的北极星服务端模拟，所有方法都是并发安全的
+type Server struct {
+	opts     *options
+	listener net.Listener
+	server   *grpc.Server
+	done     chan struct{}
+	stopOnce sync.Once
+
+	naming    *namingServer
+	configSvr *configServer
+	limiter   *rateLimitServer
+
+	faultLock sync.RWMutex
+	faults    map[Operation]*fault
+	requests  map[Operation]int
+}
+
+// NewServer 创建并启动服务端，测试结束时需要调用 Close 释放端口
+func NewServer(opts ...Option) (*Server, error) {
+	o := &options{
+		listenAddress: defaultListenAddress,
+		watchTimeout:  defaultWatchTimeout,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	listener, err := net.Listen("tcp", o.listenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("fail to listen %s: %v", o.listenAddress, err)
+	}
+	s := &Server{
+		opts:     o,
+		listener: listener,
+		server:   grpc.NewServer(),
+		done:     make(chan struct{}),
+		faults:   make(map[Operation]*fault),
+		requests: make(map[Operation]int),
+	}
+	s.naming = newNamingServer(s)
+	s.configSvr = newConfigServer(s)
+	s.limiter = newRateLimitServer(s)
+	s.registerSystemServices()
+
+	service_manage.RegisterPolarisGRPCServer(s.server, s.naming)
+	config_manage.RegisterPolarisConfigGRPCServer(s.server, s.configSvr)
+	rlimitV2.RegisterRateLimitGRPCV2Server(s.server, s.limiter)
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+	return s, nil
+}
+
+// Address 获取服务端的监听地址，格式为<host>:<port>
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// Configuration 创建指向当前服务端的SDK配置，服务发现、配置中心以及分布式限流均连接到当前服务端，
+// 并关闭本地缓存持久化以及监控上报，调用方可以在创建SDK之前继续修改返回的配置
+func (s *Server) Configuration() config.Configuration {
+	addresses := []string{s.Address()}
+	cfg := config.NewDefaultConfiguration(addresses)
+	cfg.GetConfigFile().GetConfigConnectorConfig().SetAddresses(addresses)
+	cfg.GetConsumer().GetLocalCache().SetPersistEnable(false)
+	cfg.GetGlobal().GetStatReporter().SetEnable(false)
+	cfg.GetProvider().GetRateLimit().SetLimiterNamespace(config.ServerNamespace)
+	cfg.GetProvider().GetRateLimit().SetLimiterService(config.DefaultLimiterService)
+	return cfg
+}
+
+// Close 停止服务端，挂起中的长轮询请求会立即返回
+func (s *Server) Close() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		s.server.Stop()
+	})
+}
+
+// registerSystemServices 注册北极星系统服务，系统服务的实例均指向当前服务端
+func (s *Server) registerSystemServices() {
+	host, port := s.hostPort()
+	for _, name := range []string{config.ServerDiscoverService, config.ServerHeartBeatService,
+		config.ServerConfigService, config.DefaultLimiterService} {
+		s.AddInstances(config.ServerNamespace, name, Instance{
+			Host:     host,
+			Port:     port,
+			Protocol: "grpc",
+			Metadata: map[string]string{"protocol": "grpc"},
+		})
+	}
+}
+
+func (s *Server) hostPort() (string, uint32) {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), uint32(addr.Port)
+}
+
+// serviceKey 构建服务的唯一标识
+func serviceKey(namespace, service string) model.ServiceKey {
+	return model.ServiceKey{Namespace: namespace, Service: service}
+}
@@ -0,0 +1,98 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaristest_test
+
+import (
+	"testing"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/polaristest"
+)
+
+func TestServer_GetInstances(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+
+	server.AddInstances("default", "echo",
+		polaristest.Instance{Host: "127.0.0.1", Port: 8080},
+		polaristest.Instance{Host: "127.0.0.1", Port: 8081, Isolated: true},
+	)
+	consumer, err := polaris.NewConsumerAPIByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer consumer.Destroy()
+
+	req := &polaris.GetInstancesRequest{}
+	req.Namespace = "default"
+	req.Service = "echo"
+	resp, err := consumer.GetInstances(req)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.GetInstances()))
+	assert.Equal(t, uint32(8080), resp.GetInstances()[0].GetPort())
+
+	// 注入错误期间无法获取尚未缓存的服务，取消注入后恢复
+	server.AddInstances("default", "echo2", polaristest.Instance{Host: "127.0.0.1", Port: 9090})
+	server.InjectError(polaristest.OperationDiscover, apimodel.Code_StoreLayerException)
+	req.Service = "echo2"
+	_, err = consumer.GetInstances(req)
+	assert.NotNil(t, err)
+	server.ClearFaults()
+	// 取消注入后，SDK在后台重试服务发现成功前仍然返回之前的错误
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		resp, err = consumer.GetInstances(req)
+		if err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Nil(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, 1, len(resp.GetInstances()))
+	}
+}
+
+func TestServer_ConfigFile(t *testing.T) {
+	server, err := polaristest.NewServer(polaristest.WithWatchTimeout(time.Second))
+	assert.Nil(t, err)
+	defer server.Close()
+
+	server.PublishConfigFile("default", "group", "app.yaml", "key: v1")
+	configAPI, err := polaris.NewConfigAPIByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer configAPI.SDKContext().Destroy()
+
+	configFile, err := configAPI.GetConfigFile("default", "group", "app.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "key: v1", configFile.GetContent())
+
+	changes := configFile.AddChangeListenerWithChannel()
+	server.PublishConfigFile("default", "group", "app.yaml", "key: v2")
+	select {
+	case event := <-changes:
+		assert.Equal(t, model.Modified, event.ChangeType)
+		assert.Equal(t, "key: v2", event.NewValue)
+	case <-time.After(10 * time.Second):
+		t.Fatal("config file change is not notified")
+	}
+}
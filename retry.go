@@ -0,0 +1,131 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris
+
+import (
+	"context"
+	"time"
+
+	"github.com/polarismesh/polaris-go/pkg/log"
+	"github.com/polarismesh/polaris-go/pkg/model"
+)
+
+const (
+	// DefaultRetryAttempts 默认的最大尝试次数
+	DefaultRetryAttempts = 3
+	// 调用未返回错误码时上报的返回码
+	retCodeCallFailed = -1
+)
+
+// RetryRequest 带重试的调用请求，每次尝试都会排除之前已经调用过的实例
+type RetryRequest struct {
+	GetOneInstanceRequest
+	// 可选，最大尝试次数（包括第一次调用），默认为DefaultRetryAttempts
+	Attempts int
+	// 可选，单次尝试的超时时间，为0时只受总超时时间限制
+	PerAttemptTimeout time.Duration
+	// 可选，所有尝试的总超时时间，为0时只受调用方context限制
+	Timeout time.Duration
+	// 可选，判断调用失败后是否需要重试，默认所有错误都重试
+	Retryable func(err error) bool
+}
+
+// RetryCall 对选中的实例发起一次调用，返回用于上报的返回码以及调用错误，错误不为空时视为调用失败
+type RetryCall func(ctx context.Context, instance model.Instance) (int32, error)
+
+// CallWithRetry 选择实例并发起调用，失败时排除已经调用过的实例后重新选择，直到成功、
+// 达到最大尝试次数、没有可以选择的实例或者超过总超时时间；每次尝试的结果都会上报。
+// 返回最后一次调用的实例以及错误
+func CallWithRetry(ctx context.Context, consumer ConsumerAPI, req *RetryRequest,
+	call RetryCall) (model.Instance, error) {
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+	attempts := req.Attempts
+	if attempts <= 0 {
+		attempts = DefaultRetryAttempts
+	}
+	excludes := make([]model.Instance, 0, len(req.ExcludeInstances)+attempts)
+	excludes = append(excludes, req.ExcludeInstances...)
+	var (
+		instance model.Instance
+		lastErr  error
+	)
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			if lastErr != nil {
+				return instance, lastErr
+			}
+			return nil, err
+		}
+		getOneReq := req.GetOneInstanceRequest
+		getOneReq.ExcludeInstances = excludes
+		resp, err := consumer.GetOneInstanceWithContext(ctx, &getOneReq)
+		if err != nil {
+			if lastErr != nil {
+				// 没有可以重试的实例，返回上一次调用的错误
+				return instance, lastErr
+			}
+			return nil, err
+		}
+		instance = resp.GetInstance()
+		lastErr = callOnce(ctx, consumer, req, instance, call)
+		if lastErr == nil {
+			return instance, nil
+		}
+		if req.Retryable != nil && !req.Retryable(lastErr) {
+			return instance, lastErr
+		}
+		excludes = append(excludes, instance)
+	}
+	return instance, lastErr
+}
+
+// callOnce 在单次超时时间内发起调用并上报结果
+func callOnce(ctx context.Context, consumer ConsumerAPI, req *RetryRequest, instance model.Instance,
+	call RetryCall) error {
+	attemptCtx := ctx
+	if req.PerAttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, req.PerAttemptTimeout)
+		defer cancel()
+	}
+	startTime := time.Now()
+	retCode, err := call(attemptCtx, instance)
+	result := &ServiceCallResult{}
+	result.SetCalledInstance(instance)
+	result.SetDelay(time.Since(startTime))
+	result.Method = req.Method
+	result.SourceService = req.SourceService
+	if err != nil {
+		if retCode == 0 {
+			retCode = retCodeCallFailed
+		}
+		result.SetRetStatus(model.RetFail)
+	} else {
+		result.SetRetStatus(model.RetSuccess)
+	}
+	result.SetRetCode(retCode)
+	if reportErr := consumer.UpdateServiceCallResult(result); reportErr != nil {
+		log.GetBaseLogger().Warnf("fail to report call result of %s:%d, err is %v",
+			instance.GetHost(), instance.GetPort(), reportErr)
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+/**
+ * Tencent is pleased to support the open source community by making polaris-go available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package polaris_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris-go"
+	"github.com/polarismesh/polaris-go/pkg/config"
+	"github.com/polarismesh/polaris-go/pkg/model"
+	"github.com/polarismesh/polaris-go/polaristest"
+)
+
+func TestCallWithRetry_DistinctInstances(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+	consumer, err := polaris.NewConsumerAPIByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer consumer.Destroy()
+
+	for _, lbPolicy := range []string{config.DefaultLoadBalancerWR, config.DefaultLoadBalancerRingHash,
+		config.DefaultLoadBalancerMaglev, config.DefaultLoadBalancerHash, config.DefaultLoadBalancerL5CST} {
+		// 每种负载均衡使用单独的服务，避免上报的失败结果触发熔断
+		service := "echo-" + lbPolicy
+		server.AddInstances("default", service,
+			polaristest.Instance{Host: "127.0.0.1", Port: 8001},
+			polaristest.Instance{Host: "127.0.0.1", Port: 8002},
+			polaristest.Instance{Host: "127.0.0.1", Port: 8003},
+		)
+		req := &polaris.RetryRequest{Attempts: 3, Timeout: 5 * time.Second}
+		req.Namespace = "default"
+		req.Service = service
+		req.LbPolicy = lbPolicy
+		req.HashKey = []byte("retry-key")
+		called := make(map[uint32]bool)
+		// 前两次调用都失败，每次都应该选择不同的实例
+		instance, err := polaris.CallWithRetry(context.Background(), consumer, req,
+			func(ctx context.Context, instance model.Instance) (int32, error) {
+				assert.False(t, called[instance.GetPort()])
+				called[instance.GetPort()] = true
+				if len(called) < 3 {
+					return 500, errors.New("unavailable")
+				}
+				return 0, nil
+			})
+		assert.Nil(t, err)
+		assert.NotNil(t, instance)
+		assert.Equal(t, 3, len(called))
+
+		// 所有实例都被排除时返回最后一次调用的错误
+		req.Attempts = 5
+		called = make(map[uint32]bool)
+		_, err = polaris.CallWithRetry(context.Background(), consumer, req,
+			func(ctx context.Context, instance model.Instance) (int32, error) {
+				assert.False(t, called[instance.GetPort()])
+				called[instance.GetPort()] = true
+				return 500, errors.New("unavailable")
+			})
+		assert.NotNil(t, err)
+		assert.Equal(t, 3, len(called))
+	}
+}
+
+func TestCallWithRetry_ContextDeadline(t *testing.T) {
+	server, err := polaristest.NewServer()
+	assert.Nil(t, err)
+	defer server.Close()
+	server.AddInstances("default", "echo", polaristest.Instance{Host: "127.0.0.1", Port: 8080})
+	consumer, err := polaris.NewConsumerAPIByConfig(server.Configuration())
+	assert.Nil(t, err)
+	defer consumer.Destroy()
+
+	server.InjectLatency(polaristest.OperationDiscover, 3*time.Second)
+	timeout := 10 * time.Second
+	req := &polaris.RetryRequest{}
+	req.Namespace = "default"
+	req.Service = "echo"
+	req.GetOneInstanceRequest.Timeout = &timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	startTime := time.Now()
+	// 调用方ctx到期后不再等待服务发现
+	_, err = polaris.CallWithRetry(ctx, consumer, req, func(ctx context.Context, instance model.Instance) (int32, error) {
+		t.Fatal("no instance should be called")
+		return 0, nil
+	})
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(startTime) < 2*time.Second)
+}
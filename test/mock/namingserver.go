@@ -31,6 +31,7 @@ import (
 
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/google/uuid"
+	"github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	"github.com/polarismesh/specification/source/go/api/v1/service_manage"
 	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
@@ -50,12 +51,14 @@ const (
 var (
 	// 请求与应答的类型转换
 	namingTypeReqToResp = map[service_manage.DiscoverRequest_DiscoverRequestType]service_manage.DiscoverResponse_DiscoverResponseType{
-		service_manage.DiscoverRequest_UNKNOWN:    service_manage.DiscoverResponse_UNKNOWN,
-		service_manage.DiscoverRequest_ROUTING:    service_manage.DiscoverResponse_ROUTING,
-		service_manage.DiscoverRequest_CLUSTER:    service_manage.DiscoverResponse_CLUSTER,
-		service_manage.DiscoverRequest_INSTANCE:   service_manage.DiscoverResponse_INSTANCE,
-		service_manage.DiscoverRequest_RATE_LIMIT: service_manage.DiscoverResponse_RATE_LIMIT,
-		service_manage.DiscoverRequest_SERVICES:   service_manage.DiscoverResponse_SERVICES,
+		service_manage.DiscoverRequest_UNKNOWN:         service_manage.DiscoverResponse_UNKNOWN,
+		service_manage.DiscoverRequest_ROUTING:         service_manage.DiscoverResponse_ROUTING,
+		service_manage.DiscoverRequest_CLUSTER:         service_manage.DiscoverResponse_CLUSTER,
+		service_manage.DiscoverRequest_INSTANCE:        service_manage.DiscoverResponse_INSTANCE,
+		service_manage.DiscoverRequest_RATE_LIMIT:      service_manage.DiscoverResponse_RATE_LIMIT,
+		service_manage.DiscoverRequest_SERVICES:        service_manage.DiscoverResponse_SERVICES,
+		service_manage.DiscoverRequest_CIRCUIT_BREAKER: service_manage.DiscoverResponse_CIRCUIT_BREAKER,
+		service_manage.DiscoverRequest_FAULT_DETECTOR:  service_manage.DiscoverResponse_FAULT_DETECTOR,
 	}
 )
 
@@ -100,6 +103,14 @@ type NamingServer interface {
 	RegisterRateLimitRule(svc *service_manage.Service, rateLimit *traffic_manage.RateLimit) error
 	// DeRegisterRateLimitRule 注销限流规则
 	DeRegisterRateLimitRule(svc *service_manage.Service)
+	// RegisterCircuitBreakerRule 注册熔断规则
+	RegisterCircuitBreakerRule(svc *service_manage.Service, circuitBreaker *fault_tolerance.CircuitBreaker) error
+	// DeRegisterCircuitBreakerRule 注销熔断规则
+	DeRegisterCircuitBreakerRule(svc *service_manage.Service)
+	// RegisterFaultDetectRule 注册主动探测规则
+	RegisterFaultDetectRule(svc *service_manage.Service, faultDetector *fault_tolerance.FaultDetector) error
+	// DeRegisterFaultDetectRule 注销主动探测规则
+	DeRegisterFaultDetectRule(svc *service_manage.Service)
 	// RegisterRouteRule 注册路由规则
 	RegisterRouteRule(svc *service_manage.Service, routing *traffic_manage.Routing) error
 	// DeregisterRouteRule 反注册路由规则
@@ -180,6 +191,8 @@ type namingServer struct {
 	serviceTokens         map[model.ServiceKey]string
 	serviceRoutes         map[model.ServiceKey]*traffic_manage.Routing
 	serviceRateLimits     map[model.ServiceKey]*traffic_manage.RateLimit
+	serviceCircuitBreaker map[model.ServiceKey]*fault_tolerance.CircuitBreaker
+	serviceFaultDetector  map[model.ServiceKey]*fault_tolerance.FaultDetector
 	serviceRequests       map[model.ServiceKey]int
 	timeoutOperation      map[OperationType]bool
 	timeoutIndex          map[OperationType]int
@@ -200,16 +213,18 @@ func NewNamingServer() NamingServer {
 	//
 	//
 	ns := &namingServer{
-		scalableRand:      rand.NewScalableRand(),
-		svcInstances:      make(map[model.ServiceKey][]*service_manage.Instance, 0),
-		namespaces:        make(map[string]*apimodel.Namespace, 0),
-		services:          make(map[string]*service_manage.Service, 0),
-		serviceTokens:     make(map[model.ServiceKey]string, 0),
-		serviceRequests:   make(map[model.ServiceKey]int, 0),
-		instances:         make(map[string]*service_manage.Instance, 0),
-		serviceRoutes:     make(map[model.ServiceKey]*traffic_manage.Routing, 0),
-		serviceRateLimits: make(map[model.ServiceKey]*traffic_manage.RateLimit, 0),
-		timeoutOperation:  make(map[OperationType]bool, 0),
+		scalableRand:          rand.NewScalableRand(),
+		svcInstances:          make(map[model.ServiceKey][]*service_manage.Instance, 0),
+		namespaces:            make(map[string]*apimodel.Namespace, 0),
+		services:              make(map[string]*service_manage.Service, 0),
+		serviceTokens:         make(map[model.ServiceKey]string, 0),
+		serviceRequests:       make(map[model.ServiceKey]int, 0),
+		instances:             make(map[string]*service_manage.Instance, 0),
+		serviceRoutes:         make(map[model.ServiceKey]*traffic_manage.Routing, 0),
+		serviceRateLimits:     make(map[model.ServiceKey]*traffic_manage.RateLimit, 0),
+		serviceCircuitBreaker: make(map[model.ServiceKey]*fault_tolerance.CircuitBreaker, 0),
+		serviceFaultDetector:  make(map[model.ServiceKey]*fault_tolerance.FaultDetector, 0),
+		timeoutOperation:      make(map[OperationType]bool, 0),
 		timeoutIndex: map[OperationType]int{
 			OperationDiscoverInstance: -1,
 			OperationDiscoverRouting:  -1,
@@ -487,10 +502,12 @@ func (n *namingServer) skipRequest(operation OperationType) bool {
 }
 
 var pbTypeToEvent = map[service_manage.DiscoverRequest_DiscoverRequestType]model.EventType{
-	service_manage.DiscoverRequest_ROUTING:    model.EventRouting,
-	service_manage.DiscoverRequest_INSTANCE:   model.EventInstances,
-	service_manage.DiscoverRequest_RATE_LIMIT: model.EventRateLimiting,
-	service_manage.DiscoverRequest_SERVICES:   model.EventServices,
+	service_manage.DiscoverRequest_ROUTING:         model.EventRouting,
+	service_manage.DiscoverRequest_INSTANCE:        model.EventInstances,
+	service_manage.DiscoverRequest_RATE_LIMIT:      model.EventRateLimiting,
+	service_manage.DiscoverRequest_SERVICES:        model.EventServices,
+	service_manage.DiscoverRequest_CIRCUIT_BREAKER: model.EventCircuitBreaker,
+	service_manage.DiscoverRequest_FAULT_DETECTOR:  model.EventFaultDetect,
 }
 
 // 检验是否首次不返回
@@ -622,6 +639,8 @@ func (n *namingServer) Discover(server service_manage.PolarisGRPC_DiscoverServer
 		var instances []*service_manage.Instance
 		var routing *traffic_manage.Routing
 		var ratelimit *traffic_manage.RateLimit
+		var circuitBreaker *fault_tolerance.CircuitBreaker
+		var faultDetector *fault_tolerance.FaultDetector
 		var code uint32 = uint32(apimodel.Code_ExecuteSuccess)
 		var info = "execute success"
 		var services []*service_manage.Service
@@ -646,6 +665,14 @@ func (n *namingServer) Discover(server service_manage.PolarisGRPC_DiscoverServer
 			n.rwMutex.RLock()
 			ratelimit = n.serviceRateLimits[*key]
 			n.rwMutex.RUnlock()
+		case service_manage.DiscoverRequest_CIRCUIT_BREAKER:
+			n.rwMutex.RLock()
+			circuitBreaker = n.serviceCircuitBreaker[*key]
+			n.rwMutex.RUnlock()
+		case service_manage.DiscoverRequest_FAULT_DETECTOR:
+			n.rwMutex.RLock()
+			faultDetector = n.serviceFaultDetector[*key]
+			n.rwMutex.RUnlock()
 		case service_manage.DiscoverRequest_SERVICES:
 			busi := req.Service.Business.GetValue()
 			n.rwMutex.RLock()
@@ -673,14 +700,16 @@ func (n *namingServer) Discover(server service_manage.PolarisGRPC_DiscoverServer
 		svc.Revision = wrapperspb.String(revision)
 
 		resp := &service_manage.DiscoverResponse{
-			Type:      namingTypeReqToResp[req.Type],
-			Code:      &wrappers.UInt32Value{Value: code},
-			Info:      &wrappers.StringValue{Value: info},
-			Service:   svc,
-			Instances: instances,
-			Routing:   routing,
-			RateLimit: ratelimit,
-			Services:  services,
+			Type:           namingTypeReqToResp[req.Type],
+			Code:           &wrappers.UInt32Value{Value: code},
+			Info:           &wrappers.StringValue{Value: info},
+			Service:        svc,
+			Instances:      instances,
+			Routing:        routing,
+			RateLimit:      ratelimit,
+			Services:       services,
+			CircuitBreaker: circuitBreaker,
+			FaultDetector:  faultDetector,
 		}
 		log.GetBaseLogger().Debugf("Discover: server send response for %s, type %v\n", *key, req.Type)
 		switch req.Type {
@@ -786,6 +815,72 @@ func (n *namingServer) DeRegisterRateLimitRule(svc *service_manage.Service) {
 	log2.Printf("deRegister RateLimit Rule: %v", svc)
 }
 
+// RegisterCircuitBreakerRule 注册熔断规则
+func (n *namingServer) RegisterCircuitBreakerRule(
+	svc *service_manage.Service, circuitBreaker *fault_tolerance.CircuitBreaker) error {
+	n.rwMutex.Lock()
+	defer n.rwMutex.Unlock()
+	key := &model.ServiceKey{
+		Namespace: svc.Namespace.GetValue(),
+		Service:   svc.Name.GetValue(),
+	}
+
+	_, ok := n.serviceTokens[*key]
+	if !ok {
+		return errors.New("no service found")
+	}
+
+	n.serviceCircuitBreaker[*key] = circuitBreaker
+	log2.Printf("register CircuitBreaker Rule: %v", circuitBreaker)
+	return nil
+}
+
+// DeRegisterCircuitBreakerRule 注销熔断规则
+func (n *namingServer) DeRegisterCircuitBreakerRule(svc *service_manage.Service) {
+	n.rwMutex.Lock()
+	defer n.rwMutex.Unlock()
+	key := &model.ServiceKey{
+		Namespace: svc.Namespace.GetValue(),
+		Service:   svc.Name.GetValue(),
+	}
+
+	delete(n.serviceCircuitBreaker, *key)
+	log2.Printf("deRegister CircuitBreaker Rule: %v", svc)
+}
+
+// RegisterFaultDetectRule 注册主动探测规则
+func (n *namingServer) RegisterFaultDetectRule(
+	svc *service_manage.Service, faultDetector *fault_tolerance.FaultDetector) error {
+	n.rwMutex.Lock()
+	defer n.rwMutex.Unlock()
+	key := &model.ServiceKey{
+		Namespace: svc.Namespace.GetValue(),
+		Service:   svc.Name.GetValue(),
+	}
+
+	_, ok := n.serviceTokens[*key]
+	if !ok {
+		return errors.New("no service found")
+	}
+
+	n.serviceFaultDetector[*key] = faultDetector
+	log2.Printf("register FaultDetect Rule: %v", faultDetector)
+	return nil
+}
+
+// DeRegisterFaultDetectRule 注销主动探测规则
+func (n *namingServer) DeRegisterFaultDetectRule(svc *service_manage.Service) {
+	n.rwMutex.Lock()
+	defer n.rwMutex.Unlock()
+	key := &model.ServiceKey{
+		Namespace: svc.Namespace.GetValue(),
+		Service:   svc.Name.GetValue(),
+	}
+
+	delete(n.serviceFaultDetector, *key)
+	log2.Printf("deRegister FaultDetect Rule: %v", svc)
+}
+
 // RegisterRouteRule 注册服务路由
 func (n *namingServer) RegisterRouteRule(svc *service_manage.Service, routing *traffic_manage.Routing) error {
 	//